@@ -6,17 +6,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/decompiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+	"github.com/wavesplatform/gowaves/pkg/ride/testrunner"
 )
 
 var usage = `
 Usage:
   compiler -f <script path> [options]
+  compiler -test <suite path> [-script <dApp script path>]
 
 Options:
 	-compaction	Compaction mode
     -remove-unused      Remove unused code
+    -decompile          Decompile base64 encoded script from the file
+    -test               Run tests from the YAML or JSON suite, the script replaces the script of the suite dApp
 `
 
 func main() {
@@ -24,16 +31,25 @@ func main() {
 		scriptPath   string
 		compaction   bool
 		removeUnused bool
+		decompile    bool
+		testSuite    string
 	)
 	flag.StringVar(&scriptPath, "script", "", "Path to script file")
 	flag.BoolVar(&compaction, "compaction", false, "Compaction mode")
 	flag.BoolVar(&removeUnused, "remove-unused", false, "Remove unused code")
+	flag.BoolVar(&decompile, "decompile", false, "Decompile base64 encoded script from the file")
+	flag.StringVar(&testSuite, "test", "", "Path to test suite file")
 
 	flag.Usage = func() {
 		fmt.Println(usage)
 	}
 	flag.Parse()
 
+	if testSuite != "" {
+		runTests(testSuite, scriptPath)
+		return
+	}
+
 	if scriptPath == "" {
 		fmt.Printf("Script path is not specified")
 		flag.Usage()
@@ -46,6 +62,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	if decompile {
+		decompileScript(string(b))
+		return
+	}
+
 	treeBytes, errors := compiler.Compile(string(b), compaction, removeUnused)
 	if len(errors) > 0 {
 		fmt.Println("Failed to compile script")
@@ -56,3 +77,56 @@ func main() {
 	}
 	fmt.Println(base64.StdEncoding.EncodeToString(treeBytes))
 }
+
+func decompileScript(s string) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "base64:")
+	scriptBytes, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		fmt.Printf("Failed to decode script: %s", err)
+		os.Exit(0)
+	}
+	tree, err := serialization.Parse(scriptBytes)
+	if err != nil {
+		fmt.Printf("Failed to parse script: %s", err)
+		os.Exit(0)
+	}
+	src, err := decompiler.Decompile(tree)
+	if err != nil {
+		fmt.Printf("Failed to decompile script: %s", err)
+		os.Exit(0)
+	}
+	fmt.Print(src)
+}
+
+func runTests(suitePath, scriptPath string) {
+	suite, err := testrunner.LoadSuite(suitePath)
+	if err != nil {
+		fmt.Printf("Failed to load test suite: %s\n", err)
+		os.Exit(1)
+	}
+	if scriptPath != "" {
+		if err := suite.SetScript(suite.DApp, scriptPath); err != nil {
+			fmt.Printf("Failed to set script: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	runner, err := testrunner.NewRunner(suite)
+	if err != nil {
+		fmt.Printf("Failed to prepare tests: %s\n", err)
+		os.Exit(1)
+	}
+	failed := 0
+	for _, r := range runner.Run() {
+		if r.Passed() {
+			fmt.Printf("PASS\t%s (complexity %d)\n", r.Name, r.Complexity)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL\t%s: %s\n", r.Name, r.Failure)
+	}
+	if failed > 0 {
+		fmt.Printf("%d of %d tests failed\n", failed, len(suite.Tests))
+		os.Exit(1)
+	}
+	fmt.Printf("All %d tests passed\n", len(suite.Tests))
+}
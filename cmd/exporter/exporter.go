@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/importer"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"github.com/wavesplatform/gowaves/pkg/util/common"
+	"github.com/wavesplatform/gowaves/pkg/util/fdlimit"
+	"github.com/wavesplatform/gowaves/pkg/versioning"
+)
+
+var (
+	logLevel                = flag.String("log-level", "INFO", "Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
+	cfgPath                 = flag.String("cfg-path", "", "Path to blockchain settings JSON file for custom blockchains. Not set by default.")
+	blockchainType          = flag.String("blockchain-type", "mainnet", "Blockchain type. Allowed values: mainnet/testnet/stagenet/custom. Default is 'mainnet'.")
+	blockchainPath          = flag.String("blockchain-path", "", "Path to binary blockchain file to create.")
+	dataDirPath             = flag.String("data-path", "", "Path to directory with state.")
+	startHeight             = flag.Uint64("start-height", 2, "Height of the first exported block. The genesis block is not exported, files for importer must start at height 2.")
+	endHeight               = flag.Uint64("end-height", 0, "Height of the last exported block. Default is the current height of the state.")
+	format                  = flag.String("format", "binary", "Blocks format. Allowed values: binary/protobuf. The 'binary' format can be imported with importer. Default is 'binary'.")
+	buildDataForExtendedApi = flag.Bool("build-extended-api", false, "The state was built with extended API data.")
+	buildStateHashes        = flag.Bool("build-state-hashes", false, "The state was built with state hashes.")
+)
+
+func main() {
+	flag.Parse()
+
+	common.SetupLogger(*logLevel)
+	zap.S().Infof("Gowaves Exporter version: %s", versioning.Version)
+
+	maxFDs, err := fdlimit.MaxFDs()
+	if err != nil {
+		zap.S().Fatalf("Initialization error: %v", err)
+	}
+	_, err = fdlimit.RaiseMaxFDs(maxFDs)
+	if err != nil {
+		zap.S().Fatalf("Initialization error: %v", err)
+	}
+
+	if *blockchainPath == "" {
+		zap.S().Fatalf("You must specify blockchain-path option.")
+	}
+	if *dataDirPath == "" {
+		zap.S().Fatalf("You must specify data-path option.")
+	}
+	f, err := importer.NewFormatFromString(*format)
+	if err != nil {
+		zap.S().Fatalf("Invalid format option: %v", err)
+	}
+
+	var ss *settings.BlockchainSettings
+	if strings.ToLower(*blockchainType) == "custom" && *cfgPath != "" {
+		f, err := os.Open(*cfgPath)
+		if err != nil {
+			zap.S().Fatalf("Failed to open custom blockchain settings: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		ss, err = settings.ReadBlockchainSettings(f)
+		if err != nil {
+			zap.S().Fatalf("Failed to read custom blockchain settings: %v", err)
+		}
+	} else {
+		ss, err = settings.BlockchainSettingsByTypeName(*blockchainType)
+		if err != nil {
+			zap.S().Fatalf("Failed to load blockchain settings: %v", err)
+		}
+	}
+	params := state.DefaultStateParams()
+	params.StorageParams.DbParams.OpenFilesCacheCapacity = int(maxFDs - 10)
+	params.StoreExtendedApiData = *buildDataForExtendedApi
+	params.BuildStateHashes = *buildStateHashes
+	params.ProvideExtendedApi = false
+
+	st, err := state.NewState(*dataDirPath, false, params, ss)
+	if err != nil {
+		zap.S().Fatalf("Failed to open state: %v", err)
+	}
+	exportErr := export(st, ss.AddressSchemeCharacter, f)
+	if err := st.Close(); err != nil {
+		zap.S().Fatalf("Failed to close State: %v", err)
+	}
+	if exportErr != nil {
+		zap.S().Fatalf("Failed to export blocks: %v", exportErr)
+	}
+}
+
+func export(st state.State, scheme byte, f importer.Format) error {
+	file, err := os.Create(filepath.Clean(*blockchainPath))
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	n, err := importer.WriteToFile(file, st, scheme, f, *startHeight, *endHeight)
+	if err != nil {
+		_ = file.Close()
+		removePartialFile()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		removePartialFile()
+		return err
+	}
+	zap.S().Infof("Exported %d blocks in %s format to '%s', took %s", n, f.String(), *blockchainPath, time.Since(start))
+	return nil
+}
+
+// removePartialFile removes the incomplete blockchain file, so it couldn't be mistaken for a valid one.
+func removePartialFile() {
+	if err := os.Remove(filepath.Clean(*blockchainPath)); err != nil {
+		zap.S().Errorf("Failed to remove incomplete blockchain file: %v", err)
+	}
+}
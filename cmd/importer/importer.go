@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/wavesplatform/gowaves/pkg/importer"
+	"github.com/wavesplatform/gowaves/pkg/ride"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/state"
 	"github.com/wavesplatform/gowaves/pkg/util/common"
@@ -34,6 +35,7 @@ var (
 	writeBufferSize           = flag.Int("write-buffer", 16, "Write buffer size in MiB.")
 	buildDataForExtendedApi   = flag.Bool("build-extended-api", false, "Build and store additional data required for extended API in state. WARNING: this slows down the import, use only if you do really need extended API.")
 	buildStateHashes          = flag.Bool("build-state-hashes", false, "Calculate and store state hashes for each block height.")
+	rideEngine                = flag.String("ride-engine", "tree", "Engine to execute Ride scripts: 'tree' evaluator, bytecode 'vm' or 'differential' to execute scripts by both engines and log divergences of results.")
 	// Debug.
 	cpuProfilePath = flag.String("cpuprofile", "", "Write cpu profile to this file.")
 	memProfilePath = flag.String("memprofile", "", "Write memory profile to this file.")
@@ -94,7 +96,12 @@ func main() {
 			zap.S().Fatalf("Failed to load blockchain settings: %v", err)
 		}
 	}
+	engine, err := ride.NewEngine(*rideEngine)
+	if err != nil {
+		zap.S().Fatalf("Failed to parse '-ride-engine': %v", err)
+	}
 	params := state.DefaultStateParams()
+	params.RideEngine = engine
 	params.StorageParams.DbParams.OpenFilesCacheCapacity = int(maxFDs - 10)
 	params.VerificationGoroutinesNum = *verificationGoroutinesNum
 	params.DbParams.WriteBuffer = *writeBufferSize * MiB
@@ -128,6 +135,9 @@ func main() {
 	}
 	elapsed := time.Since(start)
 	zap.S().Infof("Import took %s", elapsed)
+	if engine == ride.DifferentialEngine {
+		zap.S().Infof("Ride engines diverged %d times", ride.Divergences())
+	}
 	if len(*balancesPath) != 0 {
 		if err := importer.CheckBalances(st, *balancesPath); err != nil {
 			zap.S().Fatalf("Balances check failed: %v", err)
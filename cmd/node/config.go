@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+// configKeys maps the configuration file paths to the command line flags.
+// Paths follow the Scala node's waves.conf where it has the same parameter.
+var configKeys = []settings.ConfigKey{
+	{Path: "logging-level", Flag: "log-level"},
+	{Path: "blockchain.type", Flag: "blockchain-type"},
+	{Path: "blockchain.custom-config", Flag: "cfg-path"},
+
+	{Path: "db.directory", Flag: "state-path"},
+	{Path: "db.store-state-hashes", Flag: "build-state-hashes"},
+	{Path: "db.build-extended-api", Flag: "build-extended-api"},
+	{Path: "db.serve-extended-api", Flag: "serve-extended-api"},
+	{Path: "db.disable-bloom-filter", Flag: "disable-bloom"},
+	{Path: "db.max-open-files", Flag: "db-file-descriptors"},
+
+	{Path: "network.known-peers", Flag: "peers", List: true},
+	{Path: "network.declared-address", Flag: "declared-address"},
+	{Path: "network.bind-address", Flag: "bind-address"},
+	{Path: "network.node-name", Flag: "name"},
+	{Path: "network.max-connections", Flag: "limit-connections"},
+	{Path: "network.new-connections-limit", Flag: "new-connections-limit"},
+	{Path: "network.black-list-residence-time", Flag: "blacklist-residence-time"},
+	{Path: "network.disable-outgoing-connections", Flag: "no-connections"},
+	{Path: "network.drop-peers", Flag: "drop-peers"},
+	{Path: "network.disable-ntp", Flag: "disable-ntp"},
+
+	{Path: "rest-api.address", Flag: "api-address"},
+	{Path: "rest-api.api-key", Flag: "api-key", Secret: true},
+	{Path: "rest-api.max-connections", Flag: "api-max-connections"},
+	{Path: "rest-api.rate-limiter", Flag: "rate-limiter-opts"},
+	{Path: "rest-api.enable-metamask", Flag: "enable-metamask"},
+	{Path: "rest-api.enable-metamask-log", Flag: "enable-metamask-log"},
+
+	{Path: "grpc.enable", Flag: "enable-grpc-api"},
+	{Path: "grpc.address", Flag: "grpc-address"},
+	{Path: "grpc.max-connections", Flag: "grpc-api-max-connections"},
+
+	{Path: "miner.enable", Flag: "disable-miner", Invert: true},
+	{Path: "miner.quorum", Flag: "min-peers-mining"},
+	{Path: "miner.micro-block-interval", Flag: "microblock-interval"},
+	{Path: "miner.interval-after-last-block-then-generation-is-allowed", Flag: "obsolescence"},
+	{Path: "utx.max-bytes-size", Flag: "utx-max-bytes-size"},
+	{Path: "utx.max-per-sender", Flag: "utx-max-per-sender"},
+	{Path: "utx.persist", Flag: "utx-persist"},
+	{Path: "features.supported", Flag: "vote", List: true},
+	{Path: "rewards.desired", Flag: "reward"},
+	{Path: "ride.engine", Flag: "ride-engine"},
+
+	{Path: "wallet.file", Flag: "wallet-path"},
+	{Path: "wallet.password", Flag: "wallet-password", Secret: true},
+
+	{Path: "metrics.node-id", Flag: "metrics-id"},
+	{Path: "metrics.influx-db.url", Flag: "metrics-url"},
+	{Path: "metrics.prometheus", Flag: "prometheus"},
+	{Path: "metrics.profiler", Flag: "profiler"},
+
+	{Path: "light.enable", Flag: "light"},
+	{Path: "light.source", Flag: "light-source"},
+	{Path: "light.sync-interval", Flag: "light-sync-interval"},
+}
+
+// applyConfig applies the configuration file and the WAVES_OPTS environment variable to the flags,
+// explicitly set flags take precedence.
+func applyConfig(fs *flag.FlagSet, path string) error {
+	file := settings.NodeConfig{}
+	if path != "" {
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return errors.Wrap(err, "failed to open configuration file")
+		}
+		defer func() { _ = f.Close() }()
+		file, err = settings.ReadNodeConfig(f)
+		if err != nil {
+			return err
+		}
+	}
+	opts, _ := os.LookupEnv("WAVES_OPTS")
+	return settings.ApplyNodeConfig(fs, configKeys, file, settings.NodeConfigFromJavaOptions(opts))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/wavesplatform/gowaves/pkg/light"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+// runLightNode follows the chain of the full nodes from `-light-source` by block headers and serves the light API
+// until the context is canceled.
+func runLightNode(ctx context.Context, cfg *settings.BlockchainSettings, path string, tm types.Time, apiAddr string) error {
+	if *lightSource == "" {
+		return errors.New("source of light node is not set, use '-light-source' flag")
+	}
+	storage, err := light.NewStorage(filepath.Join(path, light.StorageDir), cfg.AddressSchemeCharacter)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			zap.S().Errorf("Failed to close headers storage: %v", err)
+		}
+	}()
+	addrs := strings.Split(*lightSource, ",")
+	sources := make([]light.Source, 0, len(addrs))
+	for _, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect to '%s'", addr)
+		}
+		defer func() { _ = conn.Close() }()
+		sources = append(sources, light.NewGRPCSource(conn, cfg.AddressSchemeCharacter))
+	}
+	source := light.NewCrossCheckedSource(sources...)
+	chain, err := light.NewChain(storage, source, cfg, tm)
+	if err != nil {
+		return err
+	}
+
+	s := &http.Server{
+		Addr:              apiAddr,
+		Handler:           light.NewAPI(chain, cfg.AddressSchemeCharacter).Routes(),
+		ReadHeaderTimeout: defaultTimeout,
+		ReadTimeout:       defaultTimeout,
+	}
+	go func() {
+		zap.S().Infof("Starting light node HTTP API on '%v'", apiAddr)
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.S().Errorf("Failed to start light node API: %v", err)
+		}
+	}()
+
+	zap.S().Infof("Light node is following '%s'", *lightSource)
+	light.NewSyncer(chain, source, *lightSyncInterval).Run(ctx)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return s.Shutdown(shutdownCtx)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"flag"
+	"fmt"
 	"math"
 	"math/big"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -31,8 +33,10 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/node/messages"
 	"github.com/wavesplatform/gowaves/pkg/node/peer_manager"
 	peersPersistentStorage "github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
+	"github.com/wavesplatform/gowaves/pkg/node/state_changed"
 	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
 	"github.com/wavesplatform/gowaves/pkg/services"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/state"
@@ -66,6 +70,7 @@ var (
 	grpcApiMaxConnections      = flag.Int("grpc-api-max-connections", server.DefaultMaxConnections, "Max number of simultaneous connections for gRPC API.")
 	enableMetaMaskAPI          = flag.Bool("enable-metamask", true, "Enables/disables metamask API.")
 	enableMetaMaskAPILog       = flag.Bool("enable-metamask-log", false, "Enables/disables metamask API logging.")
+	metaMaskAllowedOrigins     = flag.String("metamask-allowed-origins", "*", "Comma separated list of origins allowed to make cross-origin and WebSocket requests to metamask API, '*' allows any origin.")
 	enableGrpcApi              = flag.Bool("enable-grpc-api", false, "Enables/disables gRPC API.")
 	blackListResidenceTime     = flag.Duration("blacklist-residence-time", 5*time.Minute, "Period of time for which the information about external peer stays in the blacklist. Default value is 5 min. To disable blacklisting pass zero value.")
 	buildExtendedApi           = flag.Bool("build-extended-api", false, "Builds extended API. Note that state must be re-imported in case it wasn't imported with similar flag set.")
@@ -91,6 +96,15 @@ var (
 	newConnectionsLimit        = flag.Int("new-connections-limit", 10, "Number of new outbound connections established simultaneously, defaults to 10. Should be positive. Big numbers can badly affect file descriptors consumption.")
 	disableNTP                 = flag.Bool("disable-ntp", false, "Disable NTP synchronization. Useful when running the node in a docker container.")
 	microblockInterval         = flag.Duration("microblock-interval", 5*time.Second, "Interval between microblocks.")
+	utxMaxBytesSize            = flag.Uint64("utx-max-bytes-size", 1024*mb, "Maximum total size of transactions in UTX pool in bytes.")
+	utxMaxPerSender            = flag.Int("utx-max-per-sender", 0, "Maximum number of transactions of one sender in UTX pool. Default value is 0, that means no limit.")
+	utxPersist                 = flag.Bool("utx-persist", false, "Persist UTX pool in the state directory, so unconfirmed transactions survive restarts of the node.")
+	configPath                 = flag.String("config", "", "Path to node configuration YAML file. Command line flags and WAVES_OPTS environment variable override the values from the file.")
+	printConfig                = flag.Bool("print-config", false, "Print effective node configuration in YAML and exit.")
+	lightMode                  = flag.Bool("light", false, "Run light node, which follows the chain by validating block headers only and keeps no state.")
+	lightSource                = flag.String("light-source", "", "Comma separated addresses of gRPC API of the full nodes used by light node as sources of blocks, generating balances and features activations. Generating balances and features activations are accepted only if all sources agree on them.")
+	lightSyncInterval          = flag.Duration("light-sync-interval", 10*time.Second, "Interval between synchronizations of light node with the source.")
+	rideEngine                 = flag.String("ride-engine", "tree", "Engine to execute Ride scripts: 'tree' evaluator, bytecode 'vm' or 'differential' to execute scripts by both engines and log divergences of results.")
 )
 
 var defaultPeers = map[string]string{
@@ -133,12 +147,27 @@ func debugCommandLineParameters() {
 	zap.S().Debugf("db-file-descriptors: %v", *dbFileDescriptors)
 	zap.S().Debugf("new-connections-limit: %v", *newConnectionsLimit)
 	zap.S().Debugf("enable-metamask: %t", *enableMetaMaskAPI)
+	zap.S().Debugf("metamask-allowed-origins: %s", *metaMaskAllowedOrigins)
 	zap.S().Debugf("disable-ntp: %t", *disableNTP)
 	zap.S().Debugf("microblock-interval: %s", *microblockInterval)
+	zap.S().Debugf("ride-engine: %s", *rideEngine)
 }
 
 func main() {
 	flag.Parse()
+	if err := applyConfig(flag.CommandLine, *configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to apply node configuration: %v\n", err)
+		os.Exit(2)
+	}
+	if *printConfig {
+		b, err := settings.DumpNodeConfig(flag.CommandLine, configKeys)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print node configuration: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Print(string(b))
+		return
+	}
 	common.SetupLogger(*logLevel)
 
 	zap.S().Infof("Gowaves Node version: %s", versioning.Version)
@@ -205,7 +234,8 @@ func main() {
 	}
 
 	conf := &settings.NodeSettings{}
-	if err := settings.ApplySettings(conf, FromArgs(cfg.AddressSchemeCharacter), settings.FromJavaEnviron); err != nil {
+	// WAVES_OPTS environment variable is already applied to the flags together with the configuration file.
+	if err := settings.ApplySettings(conf, FromArgs(cfg.AddressSchemeCharacter)); err != nil {
 		zap.S().Errorf("Failed to apply node settings: %v", err)
 		return
 	}
@@ -246,7 +276,21 @@ func main() {
 		return
 	}
 
+	if *lightMode {
+		if err := runLightNode(ctx, cfg, path, ntpTime, conf.HttpAddr); err != nil {
+			zap.S().Errorf("Light node failed: %v", err)
+		}
+		return
+	}
+
+	engine, err := ride.NewEngine(*rideEngine)
+	if err != nil {
+		zap.S().Errorf("Failed to parse '-ride-engine': %v", err)
+		return
+	}
+
 	params := state.DefaultStateParams()
+	params.RideEngine = engine
 	params.StorageParams.DbParams.OpenFilesCacheCapacity = *dbFileDescriptors
 	params.StoreExtendedApiData = *buildExtendedApi
 	params.ProvideExtendedApi = *serveExtendedApi
@@ -289,7 +333,33 @@ func main() {
 		zap.S().Errorf("Failed to initialize UTX: %v", err)
 		return
 	}
-	utx := utxpool.New(uint64(1024*mb), utxValidator, cfg)
+	utxParams := utxpool.Params{
+		MaxSize:      *utxMaxBytesSize,
+		MaxPerSender: *utxMaxPerSender,
+		Time:         ntpTime,
+		Assets:       st,
+	}
+	if *utxPersist {
+		utxStorage, err := utxpool.NewStorage(filepath.Join(path, utxpool.StorageDir), cfg.AddressSchemeCharacter)
+		if err != nil {
+			zap.S().Errorf("Failed to initialize UTX: %v", err)
+			return
+		}
+		defer func() {
+			if err := utxStorage.Close(); err != nil {
+				zap.S().Errorf("Failed to close UTX pool storage: %v", err)
+			}
+		}()
+		utxParams.Storage = utxStorage
+	}
+	utx := utxpool.NewWithParams(utxParams, utxValidator, cfg)
+	if n, err := utx.Restore(); err != nil {
+		zap.S().Errorf("Failed to restore UTX pool: %v", err)
+		return
+	} else if n > 0 {
+		utxpool.NewCleaner(st, utx, ntpTime).Clean()
+		zap.S().Infof("%d transactions restored to UTX pool, %d of them are still valid", n, utx.Count())
+	}
 	parent := peer.NewParent()
 
 	nodeNonce, err := rand.Int(rand.Reader, new(big.Int).SetUint64(math.MaxInt32))
@@ -340,7 +410,10 @@ func main() {
 			return
 		}
 	}
-	blockApplier := blocks_applier.NewBlocksApplier()
+	var blockApplier services.BlocksApplier = blocks_applier.NewBlocksApplier()
+	if *enableGrpcApi {
+		blockApplier = server.NewBlockchainUpdatesApplier(blockApplier, cfg.AddressSchemeCharacter)
+	}
 
 	svs := services.Services{
 		State:           st,
@@ -356,6 +429,9 @@ func main() {
 		InternalChannel: messages.NewInternalChannel(),
 		MinPeersMining:  *minPeersMining,
 		SkipMessageList: parent.SkipMessageList,
+		StateChanged:    state_changed.NewStateChanged(),
+		NewTransactions: state_changed.NewNewTransactions(),
+		VoteFeatures:    features,
 	}
 
 	mine := miner.NewMicroblockMiner(svs, features, reward, maxTransactionTimeForwardOffset)
@@ -435,7 +511,7 @@ func FromArgs(scheme proto.Scheme) func(s *settings.NodeSettings) error {
 		s.WavesNetwork = proto.NetworkStrFromScheme(scheme)
 		s.Addresses = *peerAddresses
 		if *peerAddresses == "" {
-			s.Addresses = defaultPeers[*blockchainType]
+			s.Addresses = defaultPeers[strings.ToLower(*blockchainType)]
 		}
 		return nil
 	}
@@ -449,6 +525,7 @@ func apiRunOptsFromCLIFlags() *api.RunOptions {
 		if *buildExtendedApi {
 			opts.EnableMetaMaskAPI = *enableMetaMaskAPI
 			opts.EnableMetaMaskAPILog = *enableMetaMaskAPILog
+			opts.MetaMaskAllowedOrigins = splitAllowedOrigins(*metaMaskAllowedOrigins)
 		} else {
 			zap.S().Warn("'enable-metamask' flag requires activated 'build-extended-api' flag")
 		}
@@ -464,6 +541,16 @@ func apiRunOptsFromCLIFlags() *api.RunOptions {
 	return opts
 }
 
+func splitAllowedOrigins(s string) []string {
+	var origins []string
+	for _, o := range strings.Split(s, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	return origins
+}
+
 func grpcApiRunOptsFromCLIFlags() *server.RunOptions {
 	opts := server.DefaultRunOptions()
 	opts.MaxConnections = *grpcApiMaxConnections
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/lsp"
+)
+
+var usage = `
+Usage:
+  ride-lsp [options]
+
+Language server for Ride, communicates with the editor over stdin and stdout.
+
+Options:
+    -log	Path to the log file, logging is disabled by default
+`
+
+func main() {
+	var logPath string
+	flag.StringVar(&logPath, "log", "", "Path to the log file, logging is disabled by default")
+	flag.Usage = func() {
+		fmt.Println(usage)
+	}
+	flag.Parse()
+
+	// Stdout is used by the protocol, so the log is written to the file only.
+	if logPath != "" {
+		cfg := zap.NewDevelopmentConfig()
+		cfg.OutputPaths = []string{logPath}
+		cfg.ErrorOutputPaths = []string{logPath}
+		logger, err := cfg.Build()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create logger: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() {
+			_ = logger.Sync()
+		}()
+		zap.ReplaceGlobals(logger)
+	}
+
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Run(); err != nil {
+		zap.S().Errorf("Language server failed: %v", err)
+		os.Exit(1)
+	}
+}
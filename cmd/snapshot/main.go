@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"github.com/wavesplatform/gowaves/pkg/util/common"
+	"github.com/wavesplatform/gowaves/pkg/util/fdlimit"
+	"github.com/wavesplatform/gowaves/pkg/versioning"
+)
+
+var (
+	logLevel         = flag.String("log-level", "INFO", "Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
+	statePath        = flag.String("state-path", "", "Path to node's state directory. On import the directory must be empty or absent.")
+	blockchainType   = flag.String("blockchain-type", "mainnet", "Blockchain type: mainnet/testnet/stagenet")
+	cfgPath          = flag.String("cfg-path", "", "Path to configuration JSON file, only for custom blockchain.")
+	exportPath       = flag.String("export", "", "Export snapshot of the state to the file.")
+	importPath       = flag.String("import", "", "Import state from the snapshot file.")
+	height           = flag.Uint64("height", 0, "Height of the exported snapshot, defaults to the current height of the state.")
+	stateHash        = flag.String("state-hash", "", "Expected state hash (hex) of the imported snapshot, required on import. Take it from a trusted node: /debug/stateHash/{height}.")
+	buildExtendedApi = flag.Bool("build-extended-api", false, "The state was built with extended API data.")
+)
+
+func main() {
+	flag.Parse()
+
+	common.SetupLogger(*logLevel)
+	zap.S().Infof("Gowaves Snapshot version: %s", versioning.Version)
+
+	maxFDs, err := fdlimit.MaxFDs()
+	if err != nil {
+		zap.S().Fatalf("Initialization error: %v", err)
+	}
+	_, err = fdlimit.RaiseMaxFDs(maxFDs)
+	if err != nil {
+		zap.S().Fatalf("Initialization error: %v", err)
+	}
+
+	if *statePath == "" {
+		zap.S().Fatal("You must specify state-path option.")
+	}
+	if (*exportPath == "") == (*importPath == "") {
+		zap.S().Fatal("You must specify either export or import option.")
+	}
+
+	var cfg *settings.BlockchainSettings
+	if *cfgPath != "" {
+		f, err := os.Open(*cfgPath)
+		if err != nil {
+			zap.S().Fatalf("Failed to open configuration file: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		cfg, err = settings.ReadBlockchainSettings(f)
+		if err != nil {
+			zap.S().Fatalf("Failed to read configuration file: %v", err)
+		}
+	} else {
+		cfg, err = settings.BlockchainSettingsByTypeName(*blockchainType)
+		if err != nil {
+			zap.S().Fatalf("Failed to load blockchain settings: %v", err)
+		}
+	}
+
+	params := state.DefaultStateParams()
+	params.StorageParams.DbParams.OpenFilesCacheCapacity = int(maxFDs - 10)
+	params.BuildStateHashes = true
+	params.StoreExtendedApiData = *buildExtendedApi
+
+	if *exportPath != "" {
+		if err := exportSnapshot(params, cfg); err != nil {
+			zap.S().Fatalf("Failed to export snapshot: %v", err)
+		}
+		return
+	}
+	if err := importSnapshot(params, cfg); err != nil {
+		zap.S().Fatalf("Failed to import snapshot: %v", err)
+	}
+}
+
+func exportSnapshot(params state.StateParams, cfg *settings.BlockchainSettings) error {
+	f, err := os.Create(filepath.Clean(*exportPath))
+	if err != nil {
+		return err
+	}
+	h, err := state.ExportSnapshot(*statePath, params, cfg, *height, f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	zap.S().Infof("Snapshot at height %d (block %s) exported to '%s'", h.Height, h.BlockID.String(), *exportPath)
+	zap.S().Infof("State hash: %s", h.StateHash.SumHash.Hex())
+	return nil
+}
+
+func importSnapshot(params state.StateParams, cfg *settings.BlockchainSettings) error {
+	if *stateHash == "" {
+		return errors.New("expected state hash is required")
+	}
+	b, err := hex.DecodeString(*stateHash)
+	if err != nil {
+		return err
+	}
+	expected, err := crypto.NewDigestFromBytes(b)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Clean(*importPath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	h, err := state.ImportSnapshot(*statePath, params, cfg, bufio.NewReader(f), expected)
+	if err != nil {
+		return err
+	}
+	zap.S().Infof("State imported and verified at height %d (block %s)", h.Height, h.BlockID.String())
+	zap.S().Infof("State hash: %s", h.StateHash.SumHash.Hex())
+	return nil
+}
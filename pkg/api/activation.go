@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+)
+
+const (
+	blockchainFeatureStatusVoting    = "VOTING"
+	blockchainFeatureStatusApproved  = "APPROVED"
+	blockchainFeatureStatusActivated = "ACTIVATED"
+
+	nodeFeatureStatusNotImplemented = "NOT_IMPLEMENTED"
+	nodeFeatureStatusImplemented    = "IMPLEMENTED"
+	nodeFeatureStatusVoted          = "VOTED"
+)
+
+// ActivationStatus is the state of features voting in the form of Scala node's API.
+type ActivationStatus struct {
+	Height          proto.Height              `json:"height"`
+	VotingInterval  uint64                    `json:"votingInterval"`
+	VotingThreshold uint64                    `json:"votingThreshold"`
+	NextCheck       proto.Height              `json:"nextCheck"`
+	Features        []FeatureActivationStatus `json:"features"`
+}
+
+type FeatureActivationStatus struct {
+	ID               int16         `json:"id"`
+	Description      string        `json:"description"`
+	BlockchainStatus string        `json:"blockchainStatus"`
+	NodeStatus       string        `json:"nodeStatus"`
+	ActivationHeight *proto.Height `json:"activationHeight,omitempty"` // Estimated for features that are not activated yet
+	SupportingBlocks *uint64       `json:"supportingBlocks,omitempty"` // Only for features on voting
+}
+
+func (a *App) ActivationStatus() (*ActivationStatus, error) {
+	height, err := a.state.Height()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get height")
+	}
+	as, err := services.FeaturesActivationStatus(a.state, height, a.services.VoteFeatures)
+	if err != nil {
+		return nil, err
+	}
+	res := &ActivationStatus{
+		Height:          as.Height,
+		VotingInterval:  as.VotingInterval,
+		VotingThreshold: as.VotingThreshold,
+		NextCheck:       as.NextCheck,
+		Features:        make([]FeatureActivationStatus, len(as.Features)),
+	}
+	for i, f := range as.Features {
+		res.Features[i] = newFeatureActivationStatus(f)
+	}
+	return res, nil
+}
+
+func newFeatureActivationStatus(f services.FeatureActivationStatus) FeatureActivationStatus {
+	res := FeatureActivationStatus{ID: f.ID, Description: f.Description}
+	switch f.NodeStatus {
+	case services.FeatureVoted:
+		res.NodeStatus = nodeFeatureStatusVoted
+	case services.FeatureImplemented:
+		res.NodeStatus = nodeFeatureStatusImplemented
+	default:
+		res.NodeStatus = nodeFeatureStatusNotImplemented
+	}
+	switch f.BlockchainStatus {
+	case services.FeatureActivated:
+		res.BlockchainStatus = blockchainFeatureStatusActivated
+	case services.FeatureApproved:
+		res.BlockchainStatus = blockchainFeatureStatusApproved
+	default:
+		res.BlockchainStatus = blockchainFeatureStatusVoting
+		votes := f.SupportingBlocks
+		res.SupportingBlocks = &votes
+	}
+	if f.ActivationHeight != 0 {
+		h := f.ActivationHeight
+		res.ActivationHeight = &h
+	}
+	return res
+}
+
+func (a *NodeApi) activationStatus(w http.ResponseWriter, _ *http.Request) error {
+	status, err := a.app.ActivationStatus()
+	if err != nil {
+		return errors.Wrap(err, "failed to get activation status")
+	}
+	if err := trySendJson(w, status); err != nil {
+		return errors.Wrap(err, "activationStatus")
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestApp_ActivationStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sets := *settings.MainNetSettings
+	sets.FeaturesVotingPeriod = 1000
+	sets.VotesForFeatureActivation = 800
+	sets.DoubleFeaturesPeriodsAfterHeight = 0
+	const (
+		activated   = int16(settings.SmallerMinimalGeneratingBalance)
+		approved    = int16(settings.BlockReward)
+		voting      = int16(settings.BlockV5)
+		elected     = int16(settings.RideV5)
+		unknown     = int16(999)
+		notVoted    = int16(settings.RideV6)
+		approvalAt  = proto.Height(4000)
+		height      = proto.Height(4500)
+		activatedAt = proto.Height(1000)
+	)
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().Height().Return(height, nil)
+	s.EXPECT().BlockchainSettings().Return(&sets, nil)
+	s.EXPECT().AllFeatures().Return([]int16{activated, unknown}, nil)
+	s.EXPECT().IsActiveAtHeight(gomock.Any(), height).DoAndReturn(func(id int16, _ proto.Height) (bool, error) {
+		return id == activated, nil
+	}).AnyTimes()
+	s.EXPECT().IsApprovedAtHeight(gomock.Any(), height).DoAndReturn(func(id int16, _ proto.Height) (bool, error) {
+		return id == approved, nil
+	}).AnyTimes()
+	s.EXPECT().ActivationHeight(activated).Return(activatedAt, nil)
+	s.EXPECT().ApprovalHeight(approved).Return(approvalAt, nil)
+	s.EXPECT().VotesNumAtHeight(gomock.Any(), height).DoAndReturn(func(id int16, _ proto.Height) (uint64, error) {
+		switch id {
+		case voting:
+			return 100, nil
+		case elected:
+			return 1700, nil
+		default:
+			return 0, nil
+		}
+	}).AnyTimes()
+
+	app, err := NewApp("", nil, services.Services{
+		State:        s,
+		VoteFeatures: []settings.Feature{settings.BlockV5, settings.RideV5},
+	})
+	require.NoError(t, err)
+	as, err := app.ActivationStatus()
+	require.NoError(t, err)
+	assert.Equal(t, height, as.Height)
+	assert.Equal(t, uint64(2000), as.VotingInterval)
+	assert.Equal(t, uint64(1600), as.VotingThreshold)
+	assert.Equal(t, proto.Height(6000), as.NextCheck)
+	require.Len(t, as.Features, len(settings.FeaturesInfo)+1)
+
+	statuses := make(map[int16]FeatureActivationStatus)
+	for i, f := range as.Features {
+		if i > 0 {
+			assert.Less(t, as.Features[i-1].ID, f.ID)
+		}
+		statuses[f.ID] = f
+	}
+	heightPtr := func(h proto.Height) *proto.Height { return &h }
+	votesPtr := func(v uint64) *uint64 { return &v }
+	for _, test := range []struct {
+		id               int16
+		blockchainStatus string
+		nodeStatus       string
+		activationHeight *proto.Height
+		supportingBlocks *uint64
+	}{
+		{activated, blockchainFeatureStatusActivated, nodeFeatureStatusImplemented, heightPtr(activatedAt), nil},
+		{approved, blockchainFeatureStatusApproved, nodeFeatureStatusImplemented, heightPtr(approvalAt + 2000), nil},
+		{voting, blockchainFeatureStatusVoting, nodeFeatureStatusVoted, nil, votesPtr(100)},
+		{elected, blockchainFeatureStatusVoting, nodeFeatureStatusVoted, heightPtr(8000), votesPtr(1700)},
+		{notVoted, blockchainFeatureStatusVoting, nodeFeatureStatusImplemented, nil, votesPtr(0)},
+		{unknown, blockchainFeatureStatusVoting, nodeFeatureStatusNotImplemented, nil, votesPtr(0)},
+	} {
+		f, ok := statuses[test.id]
+		require.True(t, ok, test.id)
+		assert.Equal(t, test.blockchainStatus, f.BlockchainStatus, test.id)
+		assert.Equal(t, test.nodeStatus, f.NodeStatus, test.id)
+		assert.Equal(t, test.activationHeight, f.ActivationHeight, test.id)
+		assert.Equal(t, test.supportingBlocks, f.SupportingBlocks, test.id)
+	}
+}
+
+func TestNodeApi_ActivationStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sets := *settings.MainNetSettings
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().Height().Return(proto.Height(10), nil)
+	s.EXPECT().BlockchainSettings().Return(&sets, nil)
+	s.EXPECT().AllFeatures().Return(nil, nil)
+	s.EXPECT().IsActiveAtHeight(gomock.Any(), proto.Height(10)).Return(false, nil).AnyTimes()
+	s.EXPECT().IsApprovedAtHeight(gomock.Any(), proto.Height(10)).Return(false, nil).AnyTimes()
+	s.EXPECT().VotesNumAtHeight(gomock.Any(), proto.Height(10)).Return(uint64(3), nil).AnyTimes()
+
+	app, err := NewApp("", nil, services.Services{State: s})
+	require.NoError(t, err)
+	a := &NodeApi{app: app, state: s}
+	w := httptest.NewRecorder()
+	err = a.activationStatus(w, httptest.NewRequest(http.MethodGet, "/activation/status", nil))
+	require.NoError(t, err)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, float64(10), res["height"])
+	assert.Equal(t, float64(sets.FeaturesVotingPeriod), res["votingInterval"])
+	assert.Equal(t, float64(sets.VotesForFeatureActivation), res["votingThreshold"])
+	assert.Equal(t, float64(sets.FeaturesVotingPeriod), res["nextCheck"])
+	features, ok := res["features"].([]interface{})
+	require.True(t, ok)
+	require.NotEmpty(t, features)
+	assert.Equal(t, map[string]interface{}{
+		"id":               float64(1),
+		"description":      settings.FeaturesInfo[1].Description,
+		"blockchainStatus": "VOTING",
+		"nodeStatus":       "IMPLEMENTED",
+		"supportingBlocks": float64(3),
+	}, features[0])
+}
@@ -1,6 +1,21 @@
 package api
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	apiErrs "github.com/wavesplatform/gowaves/pkg/api/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/decompiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+// smartAccountExtraFee is the extra fee for transactions sent from an account with verifier script.
+const smartAccountExtraFee = 400000
 
 func (a *App) Addresses() ([]string, error) {
 	accounts, err := a.Accounts()
@@ -15,3 +30,192 @@ func (a *App) Addresses() ([]string, error) {
 
 	return addresses, nil
 }
+
+type AddressBalance struct {
+	Address       proto.WavesAddress `json:"address"`
+	Confirmations uint64             `json:"confirmations"`
+	Balance       uint64             `json:"balance"`
+}
+
+func (a *App) AddressBalance(addr proto.WavesAddress) (*AddressBalance, error) {
+	balance, err := a.state.WavesBalance(proto.NewRecipientFromAddress(addr))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get balance of address %q", addr.String())
+	}
+	return &AddressBalance{Address: addr, Confirmations: 0, Balance: balance}, nil
+}
+
+// AddressBalanceAfterConfirmations returns the minimal regular balance of the address over the last `confirmations`
+// blocks. Balances are kept only for the heights within the rollback window, so confirmations are limited by it.
+func (a *App) AddressBalanceAfterConfirmations(addr proto.WavesAddress, confirmations uint64) (*AddressBalance, error) {
+	height, err := a.state.Height()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get state height")
+	}
+	minHeight, err := a.state.MinRollbackHeight()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get minimal rollback height")
+	}
+	if confirmations > height-minHeight {
+		return nil, apiErrs.NewCustomValidationError(
+			fmt.Sprintf("confirmations must be in the range [0, %d]", height-minHeight),
+		)
+	}
+	rcp := proto.NewRecipientFromAddress(addr)
+	var balance uint64
+	for h := height - confirmations; h <= height; h++ {
+		profile, err := a.state.WavesBalanceProfileAtHeight(rcp, h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get balance of address %q at height %d", addr.String(), h)
+		}
+		if h == height-confirmations || profile.Balance < balance {
+			balance = profile.Balance
+		}
+	}
+	return &AddressBalance{Address: addr, Confirmations: confirmations, Balance: balance}, nil
+}
+
+// AddressEffectiveBalance returns the minimal effective balance of the address over
+// the last `confirmations` blocks.
+func (a *App) AddressEffectiveBalance(addr proto.WavesAddress, confirmations uint64) (*AddressBalance, error) {
+	height, err := a.state.Height()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get state height")
+	}
+	start := uint64(1)
+	if confirmations < height {
+		start = height - confirmations
+	}
+	balance, err := a.state.EffectiveBalance(proto.NewRecipientFromAddress(addr), start, height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get effective balance of address %q", addr.String())
+	}
+	return &AddressBalance{Address: addr, Confirmations: confirmations, Balance: balance}, nil
+}
+
+type AddressBalanceDetails struct {
+	Address    proto.WavesAddress `json:"address"`
+	Regular    uint64             `json:"regular"`
+	Generating uint64             `json:"generating"`
+	Available  uint64             `json:"available"`
+	Effective  uint64             `json:"effective"`
+}
+
+func (a *App) AddressBalanceDetails(addr proto.WavesAddress) (*AddressBalanceDetails, error) {
+	balance, err := a.state.FullWavesBalance(proto.NewRecipientFromAddress(addr))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get balance details of address %q", addr.String())
+	}
+	return &AddressBalanceDetails{
+		Address:    addr,
+		Regular:    balance.Regular,
+		Generating: balance.Generating,
+		Available:  balance.Available,
+		Effective:  balance.Effective,
+	}, nil
+}
+
+// AddressData returns data entries of the address. If keys are given, only entries with these keys are returned
+// in the same order, missing keys are skipped. Otherwise, all entries matching the regular expression are returned.
+func (a *App) AddressData(addr proto.WavesAddress, keys []string, matches *regexp.Regexp) (proto.DataEntries, error) {
+	rcp := proto.NewRecipientFromAddress(addr)
+	if len(keys) != 0 {
+		entries := make(proto.DataEntries, 0, len(keys))
+		for _, key := range keys {
+			entry, err := a.AddressDataEntry(addr, key)
+			if err != nil {
+				if state.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+	all, err := a.state.RetrieveEntries(rcp)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return proto.DataEntries{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get data entries of address %q", addr.String())
+	}
+	entries := make(proto.DataEntries, 0, len(all))
+	for _, entry := range all {
+		if matches != nil && !matches.MatchString(entry.GetKey()) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (a *App) AddressDataEntry(addr proto.WavesAddress, key string) (proto.DataEntry, error) {
+	entry, err := a.state.RetrieveEntry(proto.NewRecipientFromAddress(addr), key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get data entry %q of address %q", key, addr.String())
+	}
+	if entry.GetValueType() == proto.DataDelete {
+		return nil, errors.Wrapf(state.NewStateError(state.NotFoundError, proto.ErrNotFound),
+			"data entry %q of address %q is deleted", key, addr.String())
+	}
+	return entry, nil
+}
+
+type AddressScriptInfo struct {
+	Address              proto.WavesAddress `json:"address"`
+	Script               *proto.B64Bytes    `json:"script,omitempty"`
+	ScriptText           string             `json:"scriptText,omitempty"`
+	Version              int32              `json:"version,omitempty"`
+	Complexity           uint64             `json:"complexity"`
+	VerifierComplexity   uint64             `json:"verifierComplexity"`
+	CallableComplexities map[string]uint64  `json:"callableComplexities"`
+	ExtraFee             uint64             `json:"extraFee"`
+}
+
+func (a *App) AddressScriptInfo(addr proto.WavesAddress) (*AddressScriptInfo, error) {
+	res := &AddressScriptInfo{Address: addr, CallableComplexities: map[string]uint64{}}
+	info, err := a.state.ScriptInfoByAccount(proto.NewRecipientFromAddress(addr))
+	if err != nil {
+		if state.IsNotFound(err) {
+			return res, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get script info of address %q", addr.String())
+	}
+	if len(info.Bytes) == 0 {
+		return res, nil
+	}
+	tree, err := serialization.Parse(info.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse script of address %q", addr.String())
+	}
+	ev, err := a.state.EstimatorVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get estimator version")
+	}
+	est, err := ride.EstimateTree(tree, ev)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to estimate script of address %q", addr.String())
+	}
+	script := proto.B64Bytes(info.Bytes)
+	res.Script = &script
+	if text, err := decompiler.Decompile(tree); err == nil {
+		res.ScriptText = text
+	}
+	res.Version = info.Version
+	res.Complexity = info.Complexity
+	if tree.HasVerifier() {
+		res.VerifierComplexity = uint64(est.Verifier)
+		rideV5, err := a.state.IsActivated(int16(settings.RideV5))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check RideV5 activation")
+		}
+		if !rideV5 || est.Verifier > state.FreeVerifierComplexity {
+			res.ExtraFee = smartAccountExtraFee
+		}
+	}
+	for name, c := range est.Functions {
+		res.CallableComplexities[name] = uint64(c)
+	}
+	return res, nil
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiErrs "github.com/wavesplatform/gowaves/pkg/api/errors"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+func TestApp_AddressEffectiveBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	rcp := proto.NewRecipientFromAddress(addr)
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().Height().Return(proto.Height(100), nil).Times(2)
+	s.EXPECT().EffectiveBalance(rcp, proto.Height(100), proto.Height(100)).Return(uint64(10), nil)
+	s.EXPECT().EffectiveBalance(rcp, proto.Height(1), proto.Height(100)).Return(uint64(5), nil)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s})
+	require.NoError(t, err)
+
+	b, err := app.AddressEffectiveBalance(addr, 0)
+	require.NoError(t, err)
+	assert.Equal(t, &AddressBalance{Address: addr, Confirmations: 0, Balance: 10}, b)
+
+	b, err = app.AddressEffectiveBalance(addr, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, &AddressBalance{Address: addr, Confirmations: 1000, Balance: 5}, b)
+}
+
+func TestNodeApi_AddressBalanceAfterConfirmations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	rcp := proto.NewRecipientFromAddress(addr)
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().Height().Return(proto.Height(100), nil).Times(2)
+	s.EXPECT().MinRollbackHeight().Return(proto.Height(90), nil).Times(2)
+	balances := map[proto.Height]uint64{97: 30, 98: 10, 99: 20, 100: 40}
+	for h, b := range balances {
+		s.EXPECT().WavesBalanceProfileAtHeight(rcp, h).Return(&types.WavesBalanceProfile{Balance: b}, nil)
+	}
+
+	app, err := NewApp("api-key", nil, services.Services{State: s})
+	require.NoError(t, err)
+	a := &NodeApi{app: app}
+
+	request := func(confirmations string) (*httptest.ResponseRecorder, error) {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("address", addr.String())
+		rctx.URLParams.Add("confirmations", confirmations)
+		r := httptest.NewRequest(http.MethodGet, "/addresses/balance/"+addr.String()+"/"+confirmations, nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		return w, a.AddressBalanceAfterConfirmations(w, r)
+	}
+
+	w, err := request("3")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"address": "3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ", "confirmations": 3, "balance": 10}`, w.Body.String())
+
+	_, err = request("11")
+	var validationErr *apiErrs.CustomValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "confirmations must be in the range [0, 10]", validationErr.Message)
+}
+
+func TestApp_AddressData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	rcp := proto.NewRecipientFromAddress(addr)
+	entries := []proto.DataEntry{
+		&proto.IntegerDataEntry{Key: "int", Value: 1},
+		&proto.StringDataEntry{Key: "str", Value: "value"},
+		&proto.BooleanDataEntry{Key: "bool", Value: true},
+	}
+	notFound := state.NewStateError(state.RetrievalError, keyvalue.ErrNotFound)
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().RetrieveEntries(rcp).Return(entries, nil).Times(2)
+	s.EXPECT().RetrieveEntry(rcp, "str").Return(entries[1], nil)
+	s.EXPECT().RetrieveEntry(rcp, "missing").Return(nil, notFound)
+	s.EXPECT().RetrieveEntry(rcp, "deleted").Return(&proto.DeleteDataEntry{Key: "deleted"}, nil).Times(2)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s})
+	require.NoError(t, err)
+
+	res, err := app.AddressData(addr, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, proto.DataEntries(entries), res)
+
+	res, err = app.AddressData(addr, nil, regexp.MustCompile("^(int|bool)$"))
+	require.NoError(t, err)
+	assert.Equal(t, proto.DataEntries{entries[0], entries[2]}, res)
+
+	res, err = app.AddressData(addr, []string{"str", "missing", "deleted"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, proto.DataEntries{entries[1]}, res)
+
+	_, err = app.AddressDataEntry(addr, "deleted")
+	assert.True(t, state.IsNotFound(err))
+}
+
+func TestApp_AddressScriptInfoNoScript(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	rcp := proto.NewRecipientFromAddress(addr)
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().ScriptInfoByAccount(rcp).Return(nil, state.NewStateError(state.RetrievalError, keyvalue.ErrNotFound))
+
+	app, err := NewApp("api-key", nil, services.Services{State: s})
+	require.NoError(t, err)
+
+	info, err := app.AddressScriptInfo(addr)
+	require.NoError(t, err)
+	assert.Equal(t, &AddressScriptInfo{Address: addr, CallableComplexities: map[string]uint64{}}, info)
+}
+
+func TestNodeApi_AddressDataKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	rcp := proto.NewRecipientFromAddress(addr)
+
+	s := mock.NewMockState(ctrl)
+	app, err := NewApp("api-key", nil, services.Services{State: s})
+	require.NoError(t, err)
+	a := &NodeApi{app: app}
+
+	var handlerErr error
+	router := chi.NewRouter()
+	router.Get("/addresses/data/{address}/{key}", func(w http.ResponseWriter, r *http.Request) {
+		handlerErr = a.AddressDataKey(w, r)
+	})
+
+	for _, test := range []struct {
+		path string
+		key  string
+	}{
+		{"a+b", "a+b"},
+		{"a%2Bb", "a+b"},
+		{"100%25", "100%"},
+		{"a%2Fb", "a/b"},
+		{"a%2Fb%25", "a/b%"},
+		{"a%20b", "a b"},
+	} {
+		entry := &proto.StringDataEntry{Key: test.key, Value: "value"}
+		s.EXPECT().RetrieveEntry(rcp, test.key).Return(entry, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/addresses/data/"+addr.String()+"/"+test.path, nil))
+		require.NoError(t, handlerErr, test.path)
+		assert.Equal(t, http.StatusOK, w.Code, test.path)
+	}
+}
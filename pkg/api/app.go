@@ -29,17 +29,23 @@ type SchedulerEmits interface {
 const (
 	defaultBlockRequestLimit = 100
 	defaultAssetDetailsLimit = 100
+	defaultDataKeysLimit     = 1000
+	defaultTransactionsLimit = 1000
 )
 
 type appSettings struct {
-	BlockRequestLimit uint64
-	AssetDetailsLimit int
+	BlockRequestLimit    uint64
+	AssetDetailsLimit    int
+	DataKeysRequestLimit int
+	TransactionsLimit    int
 }
 
 func defaultAppSettings() *appSettings {
 	return &appSettings{
-		BlockRequestLimit: defaultBlockRequestLimit,
-		AssetDetailsLimit: defaultAssetDetailsLimit,
+		BlockRequestLimit:    defaultBlockRequestLimit,
+		AssetDetailsLimit:    defaultAssetDetailsLimit,
+		DataKeysRequestLimit: defaultDataKeysLimit,
+		TransactionsLimit:    defaultTransactionsLimit,
 	}
 }
 
@@ -80,7 +86,8 @@ func newApp(apiKey string, scheduler SchedulerEmits, services services.Services,
 	}, nil
 }
 
-func (a *App) TransactionsBroadcast(ctx context.Context, b []byte) (proto.Transaction, error) {
+// unmarshalTransaction decodes transaction of any type from JSON.
+func (a *App) unmarshalTransaction(b []byte) (proto.Transaction, error) {
 	tt := proto.TransactionTypeVersion{}
 	err := json.Unmarshal(b, &tt)
 	if err != nil {
@@ -96,6 +103,14 @@ func (a *App) TransactionsBroadcast(ctx context.Context, b []byte) (proto.Transa
 	if err != nil {
 		return nil, &BadRequestError{err}
 	}
+	return realType, nil
+}
+
+func (a *App) TransactionsBroadcast(ctx context.Context, b []byte) (proto.Transaction, error) {
+	realType, err := a.unmarshalTransaction(b)
+	if err != nil {
+		return nil, err
+	}
 
 	respCh := make(chan error, 1)
 
@@ -30,6 +30,54 @@ func newAPIBlock(block *proto.Block, scheme proto.Scheme, height proto.Height) (
 	}, nil
 }
 
+// BlockInfo is the block with the application statuses of its transactions
+// and, optionally, the state changes of invoke transactions.
+type BlockInfo struct {
+	*Block
+	Transactions []TransactionInfo `json:"transactions"`
+}
+
+// newBlockInfo extends the block with transactions info. Transactions of blocks prior to version 5 can't fail,
+// so their statuses are not looked up in the state. State changes are looked up only if requested.
+func (a *App) newBlockInfo(block *Block, withStateChanges bool) (*BlockInfo, error) {
+	if withStateChanges {
+		if err := a.checkExtendedAPI(); err != nil {
+			return nil, err
+		}
+	}
+	txs := make([]TransactionInfo, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txs[i] = TransactionInfo{Transaction: tx}
+	}
+	if block.Version >= proto.ProtobufBlockVersion && len(block.Transactions) > 0 {
+		ids := make([][]byte, len(block.Transactions))
+		for i, tx := range block.Transactions {
+			id, err := tx.GetID(a.scheme())
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get transaction ID")
+			}
+			ids[i] = id
+		}
+		failed, err := a.state.TransactionsFailed(ids)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get statuses of transactions")
+		}
+		for i := range txs {
+			txs[i].Failed = failed[i]
+		}
+	}
+	if withStateChanges {
+		for i, tx := range block.Transactions {
+			sc, err := a.stateChanges(tx)
+			if err != nil {
+				return nil, err
+			}
+			txs[i].StateChanges = sc
+		}
+	}
+	return &BlockInfo{Block: block, Transactions: txs}, nil
+}
+
 func newAPIBlockFromHeader(header proto.BlockHeader, scheme proto.Scheme, height proto.Height) (*Block, error) {
 	block := &proto.Block{
 		BlockHeader:  header,
@@ -1,5 +1,54 @@
 package api
 
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
 func (a *App) DebugSyncEnabled(enabled bool) {
 	a.sync.SetEnabled(enabled)
 }
+
+// DebugValidation is the result of transaction validation against the current state.
+type DebugValidation struct {
+	Valid          bool        `json:"valid"`
+	ValidationTime int64       `json:"validationTime"` // Milliseconds.
+	Trace          *ride.Trace `json:"trace"`
+	Error          string      `json:"error,omitempty"`
+}
+
+// DebugValidate validates the transaction against the current state without putting it to UTX pool.
+// Evaluation steps of all scripts called during the validation are returned as a trace.
+func (a *App) DebugValidate(b []byte) (*DebugValidation, error) {
+	tx, err := a.unmarshalTransaction(b)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	var (
+		trace         *ride.Trace
+		validationErr error
+	)
+	err = a.state.TxValidation(func(validation state.TxValidation) error {
+		lastBlock := a.state.TopBlock()
+		now := uint64(a.services.Time.Now().UnixMilli())
+		trace, validationErr = validation.TraceNextTx(tx, now, lastBlock.Timestamp, lastBlock.Version)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate transaction")
+	}
+	res := &DebugValidation{
+		Valid:          validationErr == nil,
+		ValidationTime: time.Since(start).Milliseconds(),
+		Trace:          trace,
+	}
+	if validationErr != nil {
+		res.Error = validationErr.Error()
+	}
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/services"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+func TestApp_DebugValidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tx := testTransfers(t, 1)[0]
+	b, err := json.Marshal(tx)
+	require.NoError(t, err)
+	trace := ride.NewTrace()
+	trace.Steps = append(trace.Steps, ride.TraceStep{Kind: ride.TraceEvaluation, Name: "@Verifier", Error: "failed"})
+
+	s := mock.NewMockState(ctrl)
+	v := mock.NewMockTxValidation(ctrl)
+	s.EXPECT().TxValidation(gomock.Any()).DoAndReturn(func(f func(state.TxValidation) error) error {
+		return f(v)
+	}).Times(2)
+	s.EXPECT().TopBlock().Return(&proto.Block{BlockHeader: proto.BlockHeader{Timestamp: 1000, Version: proto.ProtobufBlockVersion}}).Times(2)
+	gomock.InOrder(
+		v.EXPECT().TraceNextTx(gomock.Any(), gomock.Any(), uint64(1000), proto.ProtobufBlockVersion).
+			Return(trace, errors.New("transaction is not allowed by account script")),
+		v.EXPECT().TraceNextTx(gomock.Any(), gomock.Any(), uint64(1000), proto.ProtobufBlockVersion).
+			Return(ride.NewTrace(), nil),
+	)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme, Time: ntptime.Stub{}})
+	require.NoError(t, err)
+
+	res, err := app.DebugValidate(b)
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Equal(t, "transaction is not allowed by account script", res.Error)
+	assert.Equal(t, trace, res.Trace)
+
+	res, err = app.DebugValidate(b)
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Error)
+
+	_, err = app.DebugValidate([]byte("{}"))
+	assert.IsType(t, &BadRequestError{}, err)
+}
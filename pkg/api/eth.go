@@ -1,6 +1,8 @@
 package api
 
 import (
+	"encoding/json"
+
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
@@ -25,3 +27,64 @@ func (a *App) EthereumDAppMethods(addr proto.WavesAddress) (ethabi.MethodsMap, e
 	}
 	return ethabi.NewMethodsMapFromRideDAppMeta(tree.Meta)
 }
+
+// EthereumTypedDataHash is the EIP-712 hash of the typed data.
+type EthereumTypedDataHash struct {
+	Hash proto.EthereumHash `json:"hash"`
+}
+
+// EthereumTypedDataVerificationRequest is a request to recover the signer of the typed data signed
+// by eth_signTypedData_v4. If Address is set, the signer is compared with it.
+type EthereumTypedDataVerificationRequest struct {
+	TypedData proto.EthereumTypedData `json:"typedData"`
+	Signature proto.EthereumSignature `json:"signature"`
+	Address   *proto.EthereumAddress  `json:"address,omitempty"`
+}
+
+// EthereumTypedDataVerification is the result of the typed data signature verification.
+// Valid is set only if the expected signer address was given in the request.
+type EthereumTypedDataVerification struct {
+	Hash            proto.EthereumHash       `json:"hash"`
+	Signer          proto.EthereumAddress    `json:"signer"`
+	SignerPublicKey *proto.EthereumPublicKey `json:"signerPublicKey"`
+	SignerAddress   proto.WavesAddress       `json:"signerAddress"`
+	Valid           *bool                    `json:"valid,omitempty"`
+}
+
+func (a *App) EthereumTypedDataHash(b []byte) (*EthereumTypedDataHash, error) {
+	typedData, err := proto.NewEthereumTypedDataFromJSON(b)
+	if err != nil {
+		return nil, &BadRequestError{err}
+	}
+	hash, err := typedData.Hash()
+	if err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "failed to hash typed data")}
+	}
+	return &EthereumTypedDataHash{Hash: hash}, nil
+}
+
+func (a *App) EthereumTypedDataVerify(b []byte) (*EthereumTypedDataVerification, error) {
+	req := new(EthereumTypedDataVerificationRequest)
+	if err := json.Unmarshal(b, req); err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "invalid typed data verification request")}
+	}
+	hash, err := req.TypedData.Hash()
+	if err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "failed to hash typed data")}
+	}
+	pk, err := req.Signature.RecoverEthereumPublicKey(hash.Bytes())
+	if err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "failed to recover signer")}
+	}
+	signer := pk.EthereumAddress()
+	addr, err := signer.ToWavesAddress(a.services.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	res := &EthereumTypedDataVerification{Hash: hash, Signer: signer, SignerPublicKey: pk, SignerAddress: addr}
+	if req.Address != nil {
+		valid := *req.Address == signer
+		res.Valid = &valid
+	}
+	return res, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+)
+
+const testMailTypedData = `{"types":{"EIP712Domain":[{"name":"name","type":"string"},{"name":"version","type":"string"},
+{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"}],
+"Person":[{"name":"name","type":"string"},{"name":"wallet","type":"address"}],
+"Mail":[{"name":"from","type":"Person"},{"name":"to","type":"Person"},{"name":"contents","type":"string"}]},
+"primaryType":"Mail","domain":{"name":"Ether Mail","version":"1","chainId":1,
+"verifyingContract":"0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+"message":{"from":{"name":"Cow","wallet":"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+"to":{"name":"Bob","wallet":"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},"contents":"Hello, Bob!"}}`
+
+const testMailSignature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+	"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+
+func TestApp_EthereumTypedData(t *testing.T) {
+	app, err := NewApp("api-key", nil, services.Services{Scheme: proto.MainNetScheme, Time: ntptime.Stub{}})
+	require.NoError(t, err)
+
+	h, err := app.EthereumTypedDataHash([]byte(testMailTypedData))
+	require.NoError(t, err)
+	assert.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", h.Hash.String())
+
+	_, err = app.EthereumTypedDataHash([]byte(`{"types":{}}`))
+	assert.IsType(t, &BadRequestError{}, err)
+
+	signer, err := proto.NewEthereumAddressFromHexString("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	require.NoError(t, err)
+	signerAddr, err := signer.ToWavesAddress(proto.MainNetScheme)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		address string
+		valid   *bool
+	}{
+		{"", nil},
+		{`,"address":"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"`, newBool(true)},
+		{`,"address":"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"`, newBool(false)},
+	} {
+		req := fmt.Sprintf(`{"typedData":%s,"signature":%q%s}`, testMailTypedData, testMailSignature, tc.address)
+		res, err := app.EthereumTypedDataVerify([]byte(req))
+		require.NoError(t, err)
+		assert.Equal(t, h.Hash, res.Hash)
+		assert.Equal(t, signer, res.Signer)
+		assert.Equal(t, signerAddr, res.SignerAddress)
+		assert.Equal(t, tc.valid, res.Valid)
+	}
+
+	_, err = app.EthereumTypedDataVerify([]byte(`{"typedData":` + testMailTypedData + `,"signature":"0x00"}`))
+	assert.IsType(t, &BadRequestError{}, err)
+}
+
+func newBool(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,281 @@
+package metamask
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const (
+	// maxLogsBlocksRange limits the number of blocks scanned by a single logs request.
+	maxLogsBlocksRange = 1000
+	// filterTimeout is the time after which the filter that was not polled is uninstalled.
+	filterTimeout = 5 * time.Minute
+	// maxInstalledFilters limits the number of filters installed by all clients.
+	maxInstalledFilters = 1000
+	filterIDSize        = 16
+)
+
+// filterAddresses is the `address` field of the logs filter, it can be a single address or a list of addresses.
+type filterAddresses []proto.EthereumAddress
+
+func (a *filterAddresses) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*a = nil
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var addresses []proto.EthereumAddress
+		if err := json.Unmarshal(data, &addresses); err != nil {
+			return errors.Wrap(err, "invalid addresses list")
+		}
+		*a = addresses
+		return nil
+	default:
+		var addr proto.EthereumAddress
+		if err := json.Unmarshal(data, &addr); err != nil {
+			return errors.Wrap(err, "invalid address")
+		}
+		*a = filterAddresses{addr}
+		return nil
+	}
+}
+
+// filterTopics is the set of alternatives for the topic at the position. Empty set matches any topic.
+type filterTopics []proto.EthereumHash
+
+func (t *filterTopics) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*t = nil
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var topics []proto.EthereumHash
+		if err := json.Unmarshal(data, &topics); err != nil {
+			return errors.Wrap(err, "invalid topics list")
+		}
+		*t = topics
+		return nil
+	default:
+		var topic proto.EthereumHash
+		if err := json.Unmarshal(data, &topic); err != nil {
+			return errors.Wrap(err, "invalid topic")
+		}
+		*t = filterTopics{topic}
+		return nil
+	}
+}
+
+type logsFilter struct {
+	FromBlock *string         `json:"fromBlock"`
+	ToBlock   *string         `json:"toBlock"`
+	Address   filterAddresses `json:"address"`
+	Topics    []filterTopics  `json:"topics"`
+	BlockHash *proto.HexBytes `json:"blockHash"`
+}
+
+func (f logsFilter) matches(l Log) bool {
+	if len(f.Address) > 0 {
+		found := false
+		for _, a := range f.Address {
+			if a == l.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Topics) > len(l.Topics) {
+		return false
+	}
+	for i, alternatives := range f.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, t := range alternatives {
+			if t == l.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// parseBlockTag parses block number or tag. Tags "latest" and "pending" are the same because Waves has
+// no pending blocks accessible by the API.
+func parseBlockTag(tag *string, latest proto.Height) (proto.Height, error) {
+	if tag == nil {
+		return latest, nil
+	}
+	switch *tag {
+	case "", "latest", "pending":
+		return latest, nil
+	case "earliest":
+		return 1, nil
+	default:
+		h, err := hexUintToUint64(*tag)
+		if err != nil {
+			return 0, errors.Errorf("invalid block number %q", *tag)
+		}
+		return h, nil
+	}
+}
+
+// heightsRange returns the range of heights to search the logs in.
+func (s RPCService) heightsRange(f logsFilter) (proto.Height, proto.Height, error) {
+	if f.BlockHash != nil {
+		if f.FromBlock != nil || f.ToBlock != nil {
+			return 0, 0, errors.New("'blockHash' can't be used with 'fromBlock' or 'toBlock'")
+		}
+		blockID, err := proto.NewBlockIDFromBytes(*f.BlockHash)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "invalid 'blockHash'")
+		}
+		h, err := s.nodeRPCApp.State.BlockIDToHeight(blockID)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to get height of block %q", blockID.String())
+		}
+		return h, h, nil
+	}
+	latest, err := s.nodeRPCApp.State.Height()
+	if err != nil {
+		return 0, 0, err
+	}
+	from, err := parseBlockTag(f.FromBlock, latest)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := parseBlockTag(f.ToBlock, latest)
+	if err != nil {
+		return 0, 0, err
+	}
+	if to > latest {
+		to = latest
+	}
+	if from < 1 {
+		from = 1
+	}
+	return from, to, nil
+}
+
+// logs returns the logs matching the filter in the blocks from `from` to `to` inclusively.
+func (s RPCService) logs(f logsFilter, from, to proto.Height) ([]Log, error) {
+	res := make([]Log, 0)
+	if from > to {
+		return res, nil
+	}
+	if to-from+1 > maxLogsBlocksRange {
+		return nil, errors.Errorf("too many blocks requested: %d, max range is %d", to-from+1, maxLogsBlocksRange)
+	}
+	for h := from; h <= to; h++ {
+		logs, err := s.blockLogs(h)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range logs {
+			if f.matches(l) {
+				res = append(res, l)
+			}
+		}
+	}
+	return res, nil
+}
+
+type filterKind byte
+
+const (
+	logsFilterKind filterKind = iota
+	blocksFilterKind
+)
+
+type installedFilter struct {
+	kind     filterKind
+	criteria logsFilter
+	// height is the height of the last block that was returned by the filter.
+	height   proto.Height
+	lastPoll time.Time
+}
+
+// filters keeps the filters installed by eth_newFilter and eth_newBlockFilter.
+// Filter IDs are random, so filters of other clients can't be guessed.
+type filters struct {
+	mu   sync.Mutex
+	byID map[string]*installedFilter
+}
+
+func newFilters() *filters {
+	return &filters{byID: make(map[string]*installedFilter)}
+}
+
+func (fs *filters) install(f *installedFilter) (string, error) {
+	var b [filterIDSize]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", errors.Wrap(err, "failed to generate filter id")
+	}
+	id := proto.EncodeToHexString(b[:])
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.removeExpired(f.lastPoll)
+	if len(fs.byID) >= maxInstalledFilters {
+		return "", errors.Errorf("too many filters installed, max is %d", maxInstalledFilters)
+	}
+	fs.byID[id] = f
+	return id, nil
+}
+
+func (fs *filters) uninstall(id string) bool {
+	id = strings.ToLower(id)
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	_, ok := fs.byID[id]
+	delete(fs.byID, id)
+	return ok
+}
+
+// poll returns the copy of the filter by its ID and prolongs its life.
+// Filter must be scanned without the lock and then advanced with the new height.
+func (fs *filters) poll(id string, now time.Time) (installedFilter, error) {
+	id = strings.ToLower(id)
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.removeExpired(now)
+	f, ok := fs.byID[id]
+	if !ok {
+		return installedFilter{}, errors.New("filter not found")
+	}
+	f.lastPoll = now
+	return *f, nil
+}
+
+// advance sets the height of the last block returned by the filter, if the filter is still installed.
+func (fs *filters) advance(id string, height proto.Height) {
+	id = strings.ToLower(id)
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if f, ok := fs.byID[id]; ok {
+		f.height = height
+	}
+}
+
+func (fs *filters) removeExpired(now time.Time) {
+	for id, f := range fs.byID {
+		if now.Sub(f.lastPoll) > filterTimeout {
+			delete(fs.byID, id)
+		}
+	}
+}
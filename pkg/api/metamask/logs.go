@@ -0,0 +1,260 @@
+package metamask
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"go.uber.org/zap"
+)
+
+const (
+	abiSlotSize   = 32
+	logsBloomSize = 256
+)
+
+var (
+	// erc20TransferTopic is the topic of the standard ERC-20 event `Transfer(address indexed from, address indexed to, uint256 value)`.
+	erc20TransferTopic = eventTopic("Transfer(address,address,uint256)")
+	// invokeTopic is the topic of the event `Invoke(address indexed caller, bytes4 selector)`
+	// which is emitted by a dApp on every successful invocation made by an Ethereum transaction.
+	invokeTopic = eventTopic("Invoke(address,bytes4)")
+)
+
+func eventTopic(signature string) proto.EthereumHash {
+	return proto.BytesToEthereumHash(crypto.MustKeccak256([]byte(signature)).Bytes())
+}
+
+// Log is an Ethereum event log. Waves has no EVM events, so the logs are synthesized from the transactions
+// and the results of invocations.
+type Log struct {
+	Address          proto.EthereumAddress `json:"address"`
+	Topics           []proto.EthereumHash  `json:"topics"`
+	Data             string                `json:"data"`
+	BlockNumber      string                `json:"blockNumber"`
+	BlockHash        string                `json:"blockHash"`
+	TransactionHash  proto.EthereumHash    `json:"transactionHash"`
+	TransactionIndex string                `json:"transactionIndex"`
+	LogIndex         string                `json:"logIndex"`
+	Removed          bool                  `json:"removed"`
+}
+
+// addressTopic returns the address as indexed event argument, it's left padded with zeros to 32 bytes.
+func addressTopic(addr proto.EthereumAddress) proto.EthereumHash {
+	var h proto.EthereumHash
+	copy(h[len(h)-proto.EthereumAddressSize:], addr.Bytes())
+	return h
+}
+
+func uint256Data(v uint64) string {
+	b := make([]byte, abiSlotSize)
+	binary.BigEndian.PutUint64(b[abiSlotSize-8:], v)
+	return proto.EncodeToHexString(b)
+}
+
+func erc20TransferLog(asset, from, to proto.EthereumAddress, amount int64) Log {
+	return Log{
+		Address: asset,
+		Topics:  []proto.EthereumHash{erc20TransferTopic, addressTopic(from), addressTopic(to)},
+		Data:    uint256Data(uint64(amount)),
+	}
+}
+
+func invokeLog(dApp, caller proto.EthereumAddress, selector ethabi.Selector) Log {
+	data := make([]byte, abiSlotSize) // bytes4 is right padded
+	copy(data, selector[:])
+	return Log{
+		Address: dApp,
+		Topics:  []proto.EthereumHash{invokeTopic, addressTopic(caller)},
+		Data:    proto.EncodeToHexString(data),
+	}
+}
+
+// logsBloom builds the 2048 bits bloom filter of the logs as it's done by Ethereum nodes.
+func logsBloom(logs []Log) string {
+	bloom := make([]byte, logsBloomSize)
+	add := func(b []byte) {
+		h := crypto.MustKeccak256(b)
+		for i := 0; i < 6; i += 2 {
+			bit := (uint(h[i])<<8 | uint(h[i+1])) & (logsBloomSize*8 - 1)
+			bloom[logsBloomSize-1-bit/8] |= 1 << (bit % 8)
+		}
+	}
+	for _, l := range logs {
+		add(l.Address.Bytes())
+		for _, t := range l.Topics {
+			add(t.Bytes())
+		}
+	}
+	return proto.EncodeToHexString(bloom)
+}
+
+func wavesToEthereumAddress(addr proto.WavesAddress) (proto.EthereumAddress, error) {
+	return proto.NewEthereumAddressFromBytes(addr.Body())
+}
+
+// transactionLogs synthesizes the logs of the successful Ethereum transaction. Fields related to the position
+// of the log in the blockchain are not set.
+func (s RPCService) transactionLogs(tx *proto.EthereumTransaction, txID crypto.Digest) ([]Log, error) {
+	from, err := tx.From()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sender")
+	}
+	to := tx.To()
+	if to == nil {
+		return nil, nil
+	}
+	kind, err := state.GuessEthereumTransactionKind(tx.Data())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to guess ethereum tx kind")
+	}
+	switch kind {
+	case state.EthereumTransferWavesKind:
+		// Transfers of the native currency have no logs
+		return nil, nil
+	case state.EthereumTransferAssetsKind:
+		decodedData, err := ethabi.NewErc20MethodsMap().ParseCallDataRide(tx.Data(), false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse ethereum data")
+		}
+		args, err := ethabi.GetERC20TransferArguments(decodedData)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get erc20 arguments from decoded data")
+		}
+		return []Log{erc20TransferLog(*to, from, args.Recipient, args.Amount)}, nil
+	case state.EthereumInvokeKind:
+		selector, err := ethabi.NewSelectorFromBytes(tx.Data()[:ethabi.SelectorSize])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse selector from call data")
+		}
+		logs := []Log{invokeLog(*to, from, selector)}
+		res, err := s.nodeRPCApp.State.InvokeResultByID(txID)
+		if err != nil {
+			// Invoke results are available only with extended API
+			zap.S().Debugf("Failed to get invoke result of tx with ID=%q: %v", txID.String(), err)
+			return logs, nil
+		}
+		transfers, err := s.invokeTransferLogs(*to, res)
+		if err != nil {
+			return nil, err
+		}
+		return append(logs, transfers...), nil
+	default:
+		return nil, errors.New("unexpected ethereum tx kind")
+	}
+}
+
+// invokeTransferLogs returns ERC-20 Transfer logs for the asset transfers made by the dApp and the dApps invoked by it.
+func (s RPCService) invokeTransferLogs(dApp proto.EthereumAddress, res *proto.ScriptResult) ([]Log, error) {
+	logs := make([]Log, 0, len(res.Transfers))
+	for _, tr := range res.Transfers {
+		if !tr.Asset.Present {
+			continue
+		}
+		sender := dApp
+		if tr.Sender != nil {
+			addr, err := proto.NewAddressFromPublicKey(s.nodeRPCApp.Scheme, *tr.Sender)
+			if err != nil {
+				return nil, err
+			}
+			if sender, err = wavesToEthereumAddress(addr); err != nil {
+				return nil, err
+			}
+		}
+		addr, err := s.resolveRecipient(tr.Recipient)
+		if err != nil {
+			return nil, err
+		}
+		recipient, err := wavesToEthereumAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		asset := proto.EthereumAddress(proto.AssetIDFromDigest(tr.Asset.ID))
+		logs = append(logs, erc20TransferLog(asset, sender, recipient, tr.Amount))
+	}
+	for _, inv := range res.Invokes {
+		nested, err := wavesToEthereumAddress(inv.DApp)
+		if err != nil {
+			return nil, err
+		}
+		nestedLogs, err := s.invokeTransferLogs(nested, inv.Result)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, nestedLogs...)
+	}
+	return logs, nil
+}
+
+func (s RPCService) resolveRecipient(rcp proto.Recipient) (proto.WavesAddress, error) {
+	if addr := rcp.Address(); addr != nil {
+		return *addr, nil
+	}
+	if alias := rcp.Alias(); alias != nil {
+		addr, err := s.nodeRPCApp.State.AddrByAlias(*alias)
+		if err != nil {
+			return proto.WavesAddress{}, errors.Wrapf(err, "failed to resolve alias %q", alias.String())
+		}
+		return addr, nil
+	}
+	return proto.WavesAddress{}, errors.New("empty recipient")
+}
+
+// blockLogs returns the logs of all successful Ethereum transactions of the block.
+func (s RPCService) blockLogs(height proto.Height) ([]Log, error) {
+	block, err := s.nodeRPCApp.State.BlockByHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block at height %d", height)
+	}
+	return s.blockLogsFrom(block, height, 0)
+}
+
+// blockLogsFrom returns the logs of successful Ethereum transactions of the block starting from
+// the transaction with index `first`. Indexes of the logs are the same as if all the logs were returned.
+func (s RPCService) blockLogsFrom(block *proto.Block, height proto.Height, first int) ([]Log, error) {
+	var (
+		logs      []Log
+		logIndex  uint64
+		blockHash = proto.EncodeToHexString(block.BlockID().Bytes())
+	)
+	for i, tx := range block.Transactions {
+		ethTx, ok := tx.(*proto.EthereumTransaction)
+		if !ok {
+			continue
+		}
+		id, err := ethTx.GetID(s.nodeRPCApp.Scheme)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ID of ethereum transaction")
+		}
+		txID, err := crypto.NewDigestFromBytes(id)
+		if err != nil {
+			return nil, err
+		}
+		_, failed, err := s.nodeRPCApp.State.TransactionByIDWithStatus(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get status of tx with ID=%q", txID.String())
+		}
+		if failed {
+			continue
+		}
+		txLogs, err := s.transactionLogs(ethTx, txID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to build logs of tx with ID=%q", txID.String())
+		}
+		for _, l := range txLogs {
+			l.BlockNumber = uint64ToHexString(height)
+			l.BlockHash = blockHash
+			l.TransactionHash = proto.BytesToEthereumHash(id)
+			l.TransactionIndex = uint64ToHexString(uint64(i))
+			l.LogIndex = uint64ToHexString(logIndex)
+			logIndex++
+			if i >= first {
+				logs = append(logs, l)
+			}
+		}
+	}
+	return logs, nil
+}
@@ -0,0 +1,181 @@
+package metamask
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+)
+
+func TestEventTopics(t *testing.T) {
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", erc20TransferTopic.String())
+}
+
+func TestLogsBloom(t *testing.T) {
+	empty, err := proto.DecodeFromHexString(logsBloom(nil))
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, logsBloomSize), empty)
+
+	l := Log{Topics: []proto.EthereumHash{erc20TransferTopic}}
+	bloom, err := proto.DecodeFromHexString(logsBloom([]Log{l}))
+	require.NoError(t, err)
+	bits := 0
+	for _, b := range bloom {
+		for ; b != 0; b &= b - 1 {
+			bits++
+		}
+	}
+	assert.True(t, bits > 0 && bits <= 6) // 3 bits for the address and 3 bits for the topic, they may intersect
+}
+
+func TestLogsFilterUnmarshalAndMatch(t *testing.T) {
+	const js = `{"fromBlock":"0x1","address":"0x9a1989946ae4249aac19ac7a038d24aab03c3d8c",
+		"topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",null,
+		["0x0000000000000000000000000000000000000000000000000000000000000001",
+		 "0x0000000000000000000000000000000000000000000000000000000000000002"]]}`
+	var f logsFilter
+	require.NoError(t, json.Unmarshal([]byte(js), &f))
+	require.Len(t, f.Address, 1)
+	require.Len(t, f.Topics, 3)
+	assert.Len(t, f.Topics[1], 0)
+	assert.Len(t, f.Topics[2], 2)
+
+	addr := f.Address[0]
+	one := proto.BytesToEthereumHash(big.NewInt(1).FillBytes(make([]byte, 32)))
+	three := proto.BytesToEthereumHash(big.NewInt(3).FillBytes(make([]byte, 32)))
+	for _, tc := range []struct {
+		log   Log
+		match bool
+	}{
+		{Log{Address: addr, Topics: []proto.EthereumHash{erc20TransferTopic, three, one}}, true},
+		{Log{Address: addr, Topics: []proto.EthereumHash{erc20TransferTopic, three, three}}, false},
+		{Log{Address: addr, Topics: []proto.EthereumHash{erc20TransferTopic, three}}, false},
+		{Log{Topics: []proto.EthereumHash{erc20TransferTopic, three, one}}, false},
+	} {
+		assert.Equal(t, tc.match, f.matches(tc.log))
+	}
+}
+
+func newTestAssetTransfer(t *testing.T) (*proto.EthereumTransaction, proto.EthereumAddress) {
+	senderPK, err := proto.NewEthereumPublicKeyFromHexString("c4f926702fee2456ac5f3d91c9b7aa578ff191d0792fa80b6e65200f2485d9810a89c1bb5830e6618119fb3f2036db47fac027f7883108cbc7b2953539b9cb53")
+	require.NoError(t, err)
+	// transfer(0x9a1989946ae4249AAC19ac7a038d24Aab03c3D8c, 1000)
+	data, err := proto.DecodeFromHexString("0xa9059cbb0000000000000000000000009a1989946ae4249aac19ac7a038d24aab03c3d8c00000000000000000000000000000000000000000000000000000000000003e8")
+	require.NoError(t, err)
+	asset := proto.BytesToEthereumAddress(crypto.MustFastHash([]byte("asset")).Bytes()[:proto.EthereumAddressSize])
+	v := big.NewInt(int64(proto.TestNetScheme))
+	v.Mul(v, big.NewInt(2))
+	v.Add(v, big.NewInt(35))
+	inner := &proto.EthereumLegacyTx{
+		Value:    big.NewInt(0),
+		To:       &asset,
+		Data:     data,
+		GasPrice: big.NewInt(int64(proto.EthereumGasPrice)),
+		Nonce:    1479168000000,
+		Gas:      100000,
+		V:        v,
+	}
+	tx := proto.NewEthereumTransaction(inner, nil, nil, &senderPK, 0)
+	return &tx, asset
+}
+
+func TestGetLogsAndFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tx, asset := newTestAssetTransfer(t)
+	id, err := tx.GetID(proto.TestNetScheme)
+	require.NoError(t, err)
+	block := &proto.Block{Transactions: proto.Transactions{tx}}
+
+	st := mock.NewMockState(ctrl)
+	st.EXPECT().Height().Return(uint64(10), nil).AnyTimes()
+	st.EXPECT().BlockByHeight(gomock.Any()).DoAndReturn(func(h uint64) (*proto.Block, error) {
+		if h == 10 || h == 11 {
+			return block, nil
+		}
+		return &proto.Block{}, nil
+	}).AnyTimes()
+	st.EXPECT().TransactionByIDWithStatus(id).Return(tx, false, nil).AnyTimes()
+
+	s := NewRPCService(&services.Services{State: st, Scheme: proto.TestNetScheme, Time: ntptime.Stub{}})
+
+	from := "0x8"
+	logs, err := s.Eth_GetLogs(logsFilter{FromBlock: &from, Address: filterAddresses{asset}})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	l := logs[0]
+	assert.Equal(t, asset, l.Address)
+	assert.Equal(t, "0xa", l.BlockNumber)
+	assert.Equal(t, proto.BytesToEthereumHash(id), l.TransactionHash)
+	assert.Equal(t, "0x0", l.LogIndex)
+	require.Len(t, l.Topics, 3)
+	assert.Equal(t, erc20TransferTopic, l.Topics[0])
+	sender, err := tx.From()
+	require.NoError(t, err)
+	assert.Equal(t, addressTopic(sender), l.Topics[1])
+	assert.Equal(t, uint256Data(1000), l.Data)
+
+	logs, err = s.Eth_GetLogs(logsFilter{FromBlock: &from, Topics: []filterTopics{{invokeTopic}}})
+	require.NoError(t, err)
+	assert.Len(t, logs, 0)
+
+	earliest := "earliest"
+	_, err = s.Eth_GetLogs(logsFilter{FromBlock: &earliest, Address: filterAddresses{asset}})
+	assert.NoError(t, err)
+
+	// Filter returns only the logs of new blocks
+	filterID, err := s.Eth_NewFilter(logsFilter{Address: filterAddresses{asset}})
+	require.NoError(t, err)
+	changes, err := s.Eth_GetFilterChanges(filterID)
+	require.NoError(t, err)
+	assert.Len(t, changes, 0)
+	logs, err = s.Eth_GetFilterLogs(filterID)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+
+	blockFilterID, err := s.Eth_NewBlockFilter()
+	require.NoError(t, err)
+	_, err = s.Eth_GetFilterLogs(blockFilterID)
+	assert.Error(t, err)
+
+	assert.True(t, s.Eth_UninstallFilter(filterID))
+	assert.False(t, s.Eth_UninstallFilter(filterID))
+	_, err = s.Eth_GetFilterChanges(filterID)
+	assert.Error(t, err)
+}
+
+func TestFiltersInstall(t *testing.T) {
+	fs := newFilters()
+	now := time.Now()
+	ids := make(map[string]struct{}, maxInstalledFilters)
+	for i := 0; i < maxInstalledFilters; i++ {
+		id, err := fs.install(&installedFilter{lastPoll: now})
+		require.NoError(t, err)
+		ids[id] = struct{}{}
+	}
+	assert.Len(t, ids, maxInstalledFilters)
+	_, err := fs.install(&installedFilter{lastPoll: now})
+	assert.Error(t, err)
+
+	// Expired filters are removed before the limit is checked
+	id, err := fs.install(&installedFilter{lastPoll: now.Add(filterTimeout + time.Second)})
+	require.NoError(t, err)
+	f, err := fs.poll(strings.ToUpper(id), now.Add(filterTimeout+time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, proto.Height(0), f.height)
+	fs.advance(id, 10)
+	f, err = fs.poll(id, now.Add(filterTimeout+time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, proto.Height(10), f.height)
+}
@@ -13,9 +13,9 @@ import (
 )
 
 var RPC = struct {
-	RPCService struct{ Eth_BlockNumber, Net_Version, Eth_ChainId, Eth_GetBalance, Eth_GetBlockByNumber, Eth_GetBlockByHash, Eth_GasPrice, Eth_EstimateGas, Eth_Call, Eth_GetCode, Eth_GetTransactionCount, Eth_SendRawTransaction, Eth_GetTransactionReceipt, Eth_GetTransactionByHash string }
+	RPCService struct{ Eth_BlockNumber, Net_Version, Eth_ChainId, Eth_GetBalance, Eth_GetBlockByNumber, Eth_GetBlockByHash, Eth_GasPrice, Eth_EstimateGas, Eth_Call, Eth_GetCode, Eth_GetTransactionCount, Eth_SendRawTransaction, Eth_GetTransactionReceipt, Eth_GetLogs, Eth_NewFilter, Eth_NewBlockFilter, Eth_GetFilterChanges, Eth_GetFilterLogs, Eth_UninstallFilter, Eth_RecoverTypedData, Eth_GetTransactionByHash string }
 }{
-	RPCService: struct{ Eth_BlockNumber, Net_Version, Eth_ChainId, Eth_GetBalance, Eth_GetBlockByNumber, Eth_GetBlockByHash, Eth_GasPrice, Eth_EstimateGas, Eth_Call, Eth_GetCode, Eth_GetTransactionCount, Eth_SendRawTransaction, Eth_GetTransactionReceipt, Eth_GetTransactionByHash string }{
+	RPCService: struct{ Eth_BlockNumber, Net_Version, Eth_ChainId, Eth_GetBalance, Eth_GetBlockByNumber, Eth_GetBlockByHash, Eth_GasPrice, Eth_EstimateGas, Eth_Call, Eth_GetCode, Eth_GetTransactionCount, Eth_SendRawTransaction, Eth_GetTransactionReceipt, Eth_GetLogs, Eth_NewFilter, Eth_NewBlockFilter, Eth_GetFilterChanges, Eth_GetFilterLogs, Eth_UninstallFilter, Eth_RecoverTypedData, Eth_GetTransactionByHash string }{
 		Eth_BlockNumber:           "eth_blocknumber",
 		Net_Version:               "net_version",
 		Eth_ChainId:               "eth_chainid",
@@ -29,6 +29,13 @@ var RPC = struct {
 		Eth_GetTransactionCount:   "eth_gettransactioncount",
 		Eth_SendRawTransaction:    "eth_sendrawtransaction",
 		Eth_GetTransactionReceipt: "eth_gettransactionreceipt",
+		Eth_GetLogs:               "eth_getlogs",
+		Eth_NewFilter:             "eth_newfilter",
+		Eth_NewBlockFilter:        "eth_newblockfilter",
+		Eth_GetFilterChanges:      "eth_getfilterchanges",
+		Eth_GetFilterLogs:         "eth_getfilterlogs",
+		Eth_UninstallFilter:       "eth_uninstallfilter",
+		Eth_RecoverTypedData:      "eth_recovertypeddata",
 		Eth_GetTransactionByHash:  "eth_gettransactionbyhash",
 	},
 }
@@ -364,13 +371,12 @@ func (RPCService) SMD() smd.ServiceInfo {
 							Description: ``,
 							Type:        smd.Array,
 							Items: map[string]string{
-								"type": smd.String,
+								"$ref": "#/definitions/Log",
 							},
 						},
 						"logsBloom": {
 							Description: ``,
-							Ref:         "#/definitions/proto.EthereumHash",
-							Type:        smd.Object,
+							Type:        smd.String,
 						},
 						"status": {
 							Description: ``,
@@ -386,9 +392,379 @@ func (RPCService) SMD() smd.ServiceInfo {
 							Type:       "object",
 							Properties: map[string]smd.Property{},
 						},
+						"Log": {
+							Type: "object",
+							Properties: map[string]smd.Property{
+								"address": {
+									Description: ``,
+									Ref:         "#/definitions/proto.EthereumAddress",
+									Type:        smd.Object,
+								},
+								"topics": {
+									Description: ``,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/proto.EthereumHash",
+									},
+								},
+								"data": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"blockNumber": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"blockHash": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"transactionHash": {
+									Description: ``,
+									Ref:         "#/definitions/proto.EthereumHash",
+									Type:        smd.Object,
+								},
+								"transactionIndex": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"logIndex": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"removed": {
+									Description: ``,
+									Type:        smd.Boolean,
+								},
+							},
+						},
 					},
 				},
 			},
+			"Eth_GetLogs": {
+				Description: `Eth_GetLogs returns an array of all logs matching a given filter object.
+- filter: the filter object with optional fields "fromBlock", "toBlock", "address", "topics" and "blockHash"`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filter",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Object,
+						Properties: map[string]smd.Property{
+							"fromBlock": {
+								Description: ``,
+								Type:        smd.String,
+							},
+							"toBlock": {
+								Description: ``,
+								Type:        smd.String,
+							},
+							"address": {
+								Description: ``,
+								Ref:         "#/definitions/filterAddresses",
+								Type:        smd.Object,
+							},
+							"topics": {
+								Description: ``,
+								Type:        smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/filterTopics",
+								},
+							},
+							"blockHash": {
+								Description: ``,
+								Ref:         "#/definitions/proto.HexBytes",
+								Type:        smd.Object,
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"filterAddresses": {
+								Type:       "object",
+								Properties: map[string]smd.Property{},
+							},
+							"filterTopics": {
+								Type:       "object",
+								Properties: map[string]smd.Property{},
+							},
+							"proto.HexBytes": {
+								Type:       "object",
+								Properties: map[string]smd.Property{},
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Array,
+					Items: map[string]string{
+						"$ref": "#/definitions/Log",
+					},
+					Definitions: map[string]smd.Definition{
+						"Log": {
+							Type: "object",
+							Properties: map[string]smd.Property{
+								"address": {
+									Description: ``,
+									Ref:         "#/definitions/proto.EthereumAddress",
+									Type:        smd.Object,
+								},
+								"topics": {
+									Description: ``,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/proto.EthereumHash",
+									},
+								},
+								"data": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"blockNumber": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"blockHash": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"transactionHash": {
+									Description: ``,
+									Ref:         "#/definitions/proto.EthereumHash",
+									Type:        smd.Object,
+								},
+								"transactionIndex": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"logIndex": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"removed": {
+									Description: ``,
+									Type:        smd.Boolean,
+								},
+							},
+						},
+						"proto.EthereumAddress": {
+							Type:       "object",
+							Properties: map[string]smd.Property{},
+						},
+						"proto.EthereumHash": {
+							Type:       "object",
+							Properties: map[string]smd.Property{},
+						},
+					},
+				},
+			},
+			"Eth_NewFilter": {
+				Description: `Eth_NewFilter creates a filter object, based on filter options, to notify when the state changes (logs).
+To check if the state has changed, call Eth_GetFilterChanges.
+- filter: the filter object with optional fields "fromBlock", "toBlock", "address" and "topics"`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filter",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Object,
+						Properties: map[string]smd.Property{
+							"fromBlock": {
+								Description: ``,
+								Type:        smd.String,
+							},
+							"toBlock": {
+								Description: ``,
+								Type:        smd.String,
+							},
+							"address": {
+								Description: ``,
+								Ref:         "#/definitions/filterAddresses",
+								Type:        smd.Object,
+							},
+							"topics": {
+								Description: ``,
+								Type:        smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/filterTopics",
+								},
+							},
+							"blockHash": {
+								Description: ``,
+								Ref:         "#/definitions/proto.HexBytes",
+								Type:        smd.Object,
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"filterAddresses": {
+								Type:       "object",
+								Properties: map[string]smd.Property{},
+							},
+							"filterTopics": {
+								Type:       "object",
+								Properties: map[string]smd.Property{},
+							},
+							"proto.HexBytes": {
+								Type:       "object",
+								Properties: map[string]smd.Property{},
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.String,
+				},
+			},
+			"Eth_NewBlockFilter": {
+				Description: `Eth_NewBlockFilter creates a filter in the node, to notify when a new block arrives.
+To check if the state has changed, call Eth_GetFilterChanges.`,
+				Parameters: []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.String,
+				},
+			},
+			"Eth_GetFilterChanges": {
+				Description: `Eth_GetFilterChanges polling method for a filter, which returns an array of logs or block hashes
+which occurred since last poll.
+- filterID: the filter id`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filterID",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Object,
+				},
+			},
+			"Eth_GetFilterLogs": {
+				Description: `Eth_GetFilterLogs returns an array of all logs matching filter with given id.
+- filterID: the filter id`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filterID",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Array,
+					Items: map[string]string{
+						"$ref": "#/definitions/Log",
+					},
+					Definitions: map[string]smd.Definition{
+						"Log": {
+							Type: "object",
+							Properties: map[string]smd.Property{
+								"address": {
+									Description: ``,
+									Ref:         "#/definitions/proto.EthereumAddress",
+									Type:        smd.Object,
+								},
+								"topics": {
+									Description: ``,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/proto.EthereumHash",
+									},
+								},
+								"data": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"blockNumber": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"blockHash": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"transactionHash": {
+									Description: ``,
+									Ref:         "#/definitions/proto.EthereumHash",
+									Type:        smd.Object,
+								},
+								"transactionIndex": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"logIndex": {
+									Description: ``,
+									Type:        smd.String,
+								},
+								"removed": {
+									Description: ``,
+									Type:        smd.Boolean,
+								},
+							},
+						},
+						"proto.EthereumAddress": {
+							Type:       "object",
+							Properties: map[string]smd.Property{},
+						},
+						"proto.EthereumHash": {
+							Type:       "object",
+							Properties: map[string]smd.Property{},
+						},
+					},
+				},
+			},
+			"Eth_UninstallFilter": {
+				Description: `Eth_UninstallFilter uninstalls a filter with given id. Returns true if the filter was successfully uninstalled.
+- filterID: the filter id`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filterID",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Boolean,
+				},
+			},
+			"Eth_RecoverTypedData": {
+				Description: `Eth_RecoverTypedData returns the address of the account which signed the typed data with eth_signTypedData_v4.
+- typedData: EIP-712 typed data as object or as JSON string
+- signature: 65 bytes signature in hex`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "typedData",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Object,
+						Properties:  map[string]smd.Property{},
+					},
+					{
+						Name:        "signature",
+						Optional:    false,
+						Description: ``,
+						Type:        smd.Object,
+						Properties:  map[string]smd.Property{},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: ``,
+					Optional:    false,
+					Type:        smd.Object,
+					Properties:  map[string]smd.Property{},
+				},
+			},
 			"Eth_GetTransactionByHash": {
 				Description: ``,
 				Parameters: []smd.JSONSchema{
@@ -689,6 +1065,124 @@ func (s RPCService) Invoke(ctx context.Context, method string, params json.RawMe
 
 		resp.Set(s.Eth_GetTransactionReceipt(args.EthTxID))
 
+	case RPC.RPCService.Eth_GetLogs:
+		var args = struct {
+			Filter logsFilter `json:"filter"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filter"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Eth_GetLogs(args.Filter))
+
+	case RPC.RPCService.Eth_NewFilter:
+		var args = struct {
+			Filter logsFilter `json:"filter"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filter"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Eth_NewFilter(args.Filter))
+
+	case RPC.RPCService.Eth_NewBlockFilter:
+		resp.Set(s.Eth_NewBlockFilter())
+
+	case RPC.RPCService.Eth_GetFilterChanges:
+		var args = struct {
+			FilterID string `json:"filterID"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filterID"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Eth_GetFilterChanges(args.FilterID))
+
+	case RPC.RPCService.Eth_GetFilterLogs:
+		var args = struct {
+			FilterID string `json:"filterID"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filterID"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Eth_GetFilterLogs(args.FilterID))
+
+	case RPC.RPCService.Eth_UninstallFilter:
+		var args = struct {
+			FilterID string `json:"filterID"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filterID"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Eth_UninstallFilter(args.FilterID))
+
+	case RPC.RPCService.Eth_RecoverTypedData:
+		var args = struct {
+			TypedData proto.EthereumTypedData `json:"typedData"`
+			Signature proto.EthereumSignature `json:"signature"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"typedData", "signature"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Eth_RecoverTypedData(args.TypedData, args.Signature))
+
 	case RPC.RPCService.Eth_GetTransactionByHash:
 		var args = struct {
 			EthTxID proto.EthereumHash `json:"ethTxID"`
@@ -28,6 +28,7 @@ type nodeRPCApp struct {
 type RPCService struct {
 	zenrpc.Service
 	nodeRPCApp nodeRPCApp
+	filters    *filters
 }
 
 // TODO(nickeskov): create error type
@@ -35,6 +36,7 @@ type RPCService struct {
 func NewRPCService(nodeServices *services.Services) RPCService {
 	return RPCService{
 		nodeRPCApp: nodeRPCApp{nodeServices},
+		filters:    newFilters(),
 	}
 }
 
@@ -416,8 +418,8 @@ type GetTransactionReceiptResponse struct {
 	CumulativeGasUsed string                 `json:"cumulativeGasUsed"`
 	GasUsed           string                 `json:"gasUsed"`
 	ContractAddress   *proto.EthereumAddress `json:"contractAddress"`
-	Logs              []string               `json:"logs"`
-	LogsBloom         proto.EthereumHash     `json:"logsBloom"`
+	Logs              []Log                  `json:"logs"`
+	LogsBloom         string                 `json:"logsBloom"`
 	Status            string                 `json:"status"`
 }
 
@@ -459,6 +461,23 @@ func (s RPCService) Eth_GetTransactionReceipt(ethTxID proto.EthereumHash) (*GetT
 		return nil, errors.New("failed to get blockNumber for transaction")
 	}
 
+	logs := make([]Log, 0)
+	if !txIsFailed {
+		blockLogs, err := s.blockLogs(blockHeight)
+		if err != nil {
+			zap.S().Errorf(
+				"Eth_GetTransactionReceipt: failed to get logs for tx with ID=%q or ethID=%q: %v",
+				txID, ethTxID, err,
+			)
+			return nil, errors.New("failed to get logs for transaction")
+		}
+		for _, l := range blockLogs {
+			if l.TransactionHash == ethTxID {
+				logs = append(logs, l)
+			}
+		}
+	}
+
 	lastBlockHeader := s.nodeRPCApp.State.TopBlock()
 	txStatus := "0x1"
 	if txIsFailed {
@@ -476,13 +495,155 @@ func (s RPCService) Eth_GetTransactionReceipt(ethTxID proto.EthereumHash) (*GetT
 		CumulativeGasUsed: gasLimit,
 		GasUsed:           gasLimit,
 		ContractAddress:   nil,
-		Logs:              []string{},
-		LogsBloom:         proto.EthereumHash{},
+		Logs:              logs,
+		LogsBloom:         logsBloom(logs),
 		Status:            txStatus,
 	}
 	return resp, nil
 }
 
+// Eth_GetLogs returns an array of all logs matching a given filter object.
+//   - filter: the filter object with optional fields "fromBlock", "toBlock", "address", "topics" and "blockHash"
+func (s RPCService) Eth_GetLogs(filter logsFilter) ([]Log, error) {
+	zap.S().Debugf("Eth_GetLogs was called: filter %+v", filter)
+	from, to, err := s.heightsRange(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.logs(filter, from, to)
+}
+
+// Eth_NewFilter creates a filter object, based on filter options, to notify when the state changes (logs).
+// To check if the state has changed, call Eth_GetFilterChanges.
+//   - filter: the filter object with optional fields "fromBlock", "toBlock", "address" and "topics"
+func (s RPCService) Eth_NewFilter(filter logsFilter) (string, error) {
+	zap.S().Debugf("Eth_NewFilter was called: filter %+v", filter)
+	if filter.BlockHash != nil {
+		return "", errors.New("'blockHash' is not supported by filters")
+	}
+	if _, _, err := s.heightsRange(filter); err != nil {
+		return "", err
+	}
+	return s.installFilter(logsFilterKind, filter)
+}
+
+// Eth_NewBlockFilter creates a filter in the node, to notify when a new block arrives.
+// To check if the state has changed, call Eth_GetFilterChanges.
+func (s RPCService) Eth_NewBlockFilter() (string, error) {
+	zap.S().Debug("Eth_NewBlockFilter was called")
+	return s.installFilter(blocksFilterKind, logsFilter{})
+}
+
+func (s RPCService) installFilter(kind filterKind, criteria logsFilter) (string, error) {
+	height, err := s.nodeRPCApp.State.Height()
+	if err != nil {
+		return "", err
+	}
+	return s.filters.install(&installedFilter{
+		kind:     kind,
+		criteria: criteria,
+		height:   height,
+		lastPoll: s.nodeRPCApp.Time.Now(),
+	})
+}
+
+// Eth_GetFilterChanges polling method for a filter, which returns an array of logs or block hashes
+// which occurred since last poll.
+//   - filterID: the filter id
+func (s RPCService) Eth_GetFilterChanges(filterID string) (interface{}, error) {
+	zap.S().Debugf("Eth_GetFilterChanges was called: filterID %q", filterID)
+	f, err := s.filters.poll(filterID, s.nodeRPCApp.Time.Now())
+	if err != nil {
+		return nil, err
+	}
+	height, err := s.nodeRPCApp.State.Height()
+	if err != nil {
+		return nil, err
+	}
+	if height < f.height { // Rollback happened
+		f.height = height
+	}
+	from, to := f.height+1, height
+	if to-f.height > maxLogsBlocksRange {
+		to = f.height + maxLogsBlocksRange // The rest will be returned by the next poll
+	}
+	var res interface{}
+	switch f.kind {
+	case logsFilterKind:
+		if f.criteria.ToBlock != nil {
+			limit, err := parseBlockTag(f.criteria.ToBlock, to)
+			if err != nil {
+				return nil, err
+			}
+			if limit < to {
+				to = limit
+			}
+		}
+		if f.criteria.FromBlock != nil {
+			start, err := parseBlockTag(f.criteria.FromBlock, from)
+			if err != nil {
+				return nil, err
+			}
+			if start > from {
+				from = start
+			}
+		}
+		logs, err := s.logs(f.criteria, from, to)
+		if err != nil {
+			return nil, err
+		}
+		res = logs
+	case blocksFilterKind:
+		hashes := make([]string, 0)
+		for h := from; h <= to; h++ {
+			id, err := s.nodeRPCApp.State.HeightToBlockID(h)
+			if err != nil {
+				return nil, err
+			}
+			hashes = append(hashes, proto.EncodeToHexString(id.Bytes()))
+		}
+		res = hashes
+	}
+	if to > f.height {
+		f.height = to
+	}
+	s.filters.advance(filterID, f.height)
+	return res, nil
+}
+
+// Eth_GetFilterLogs returns an array of all logs matching filter with given id.
+//   - filterID: the filter id
+func (s RPCService) Eth_GetFilterLogs(filterID string) ([]Log, error) {
+	zap.S().Debugf("Eth_GetFilterLogs was called: filterID %q", filterID)
+	f, err := s.filters.poll(filterID, s.nodeRPCApp.Time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if f.kind != logsFilterKind {
+		return nil, errors.New("filter is not a logs filter")
+	}
+	return s.Eth_GetLogs(f.criteria)
+}
+
+// Eth_UninstallFilter uninstalls a filter with given id. Returns true if the filter was successfully uninstalled.
+//   - filterID: the filter id
+func (s RPCService) Eth_UninstallFilter(filterID string) bool {
+	zap.S().Debugf("Eth_UninstallFilter was called: filterID %q", filterID)
+	return s.filters.uninstall(filterID)
+}
+
+// Eth_RecoverTypedData returns the address of the account which signed the typed data with eth_signTypedData_v4.
+//   - typedData: EIP-712 typed data as object or as JSON string
+//   - signature: 65 bytes signature in hex
+func (s RPCService) Eth_RecoverTypedData(typedData proto.EthereumTypedData, signature proto.EthereumSignature) (proto.EthereumAddress, error) {
+	zap.S().Debugf("Eth_RecoverTypedData was called: primaryType %q, signature %s", typedData.PrimaryType, signature.String())
+	pk, err := typedData.RecoverSigner(signature)
+	if err != nil {
+		return proto.EthereumAddress{}, err
+	}
+	return pk.EthereumAddress(), nil
+}
+
 type GetTransactionByHashResponse struct {
 	Hash             proto.EthereumHash      `json:"hash"`
 	Nonce            string                  `json:"nonce"`
@@ -0,0 +1,358 @@
+package metamask
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/semrush/zenrpc/v2"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+)
+
+type subscriptionKind string
+
+// maxRecentBlocks is the number of the last processed blocks whose logs are kept to be sent again
+// as removed if the blocks are rolled back.
+const maxRecentBlocks = 100
+
+const (
+	newHeadsSubscription               subscriptionKind = "newHeads"
+	logsSubscription                   subscriptionKind = "logs"
+	newPendingTransactionsSubscription subscriptionKind = "newPendingTransactions"
+)
+
+// BlockHeader is the block header object of the `newHeads` subscription. Fields which have no meaning
+// in Waves are set to zero values.
+type BlockHeader struct {
+	Number        string                `json:"number"`
+	Hash          string                `json:"hash"`
+	ParentHash    string                `json:"parentHash"`
+	Nonce         string                `json:"nonce"`
+	Difficulty    string                `json:"difficulty"`
+	GasLimit      string                `json:"gasLimit"`
+	GasUsed       string                `json:"gasUsed"`
+	Miner         proto.EthereumAddress `json:"miner"`
+	ExtraData     string                `json:"extraData"`
+	LogsBloom     string                `json:"logsBloom"`
+	Timestamp     string                `json:"timestamp"`
+	BaseFeePerGas string                `json:"baseFeePerGas"`
+}
+
+type subscription struct {
+	id     string
+	seq    uint64
+	kind   subscriptionKind
+	filter logsFilter
+	conn   *wsConnection
+}
+
+type sentLog struct {
+	log Log
+	// seq is the sequence number of the last subscription at the moment the log was sent,
+	// only the subscriptions made before are notified about the removal of the log.
+	seq uint64
+}
+
+// processedBlock is the block for which the notifications were sent.
+type processedBlock struct {
+	height    proto.Height
+	id        proto.BlockID
+	generator crypto.PublicKey
+	timestamp uint64
+	// txs is the number of processed transactions of the block, it grows with the microblocks.
+	txs int
+	// lastTx is the ID of the last processed transaction.
+	lastTx []byte
+	logs   []sentLog
+}
+
+// Subscriptions dispatches the events of the node to the subscriptions made by eth_subscribe.
+// It implements types.Handler to be notified about the state changes and types.TransactionHandler
+// to be notified about new transactions in the UTX pool.
+type Subscriptions struct {
+	service RPCService
+
+	mu     sync.Mutex
+	lastID uint64
+	byID   map[string]*subscription
+
+	// processing is held while the new blocks are processed, so the notifications are sent in order.
+	processing sync.Mutex
+	// recent are the last processed blocks, the last one is the head.
+	recent []processedBlock
+}
+
+func NewSubscriptions(service RPCService) *Subscriptions {
+	return &Subscriptions{service: service, byID: make(map[string]*subscription)}
+}
+
+func (s *Subscriptions) subscribe(conn *wsConnection, kind subscriptionKind, filter logsFilter) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	id := uint64ToHexString(s.lastID)
+	s.byID[id] = &subscription{id: id, seq: s.lastID, kind: kind, filter: filter, conn: conn}
+	return id
+}
+
+func (s *Subscriptions) unsubscribe(conn *wsConnection, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.byID[id]
+	if !ok || sub.conn != conn {
+		return false
+	}
+	delete(s.byID, id)
+	return true
+}
+
+// unsubscribeAll removes all subscriptions of the closed connection.
+func (s *Subscriptions) unsubscribeAll(conn *wsConnection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sub := range s.byID {
+		if sub.conn == conn {
+			delete(s.byID, id)
+		}
+	}
+}
+
+func (s *Subscriptions) subscriptions(kind subscriptionKind) []*subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var r []*subscription
+	for _, sub := range s.byID {
+		if sub.kind == kind {
+			r = append(r, sub)
+		}
+	}
+	return r
+}
+
+// Handle processes the blocks and microblocks applied since the last call.
+func (s *Subscriptions) Handle() {
+	s.processing.Lock()
+	defer s.processing.Unlock()
+	if err := s.processNewBlocks(); err != nil {
+		zap.S().Errorf("Failed to process new blocks for subscriptions: %v", err)
+	}
+}
+
+func (s *Subscriptions) processNewBlocks() error {
+	st := s.service.nodeRPCApp.State
+	height, err := st.Height()
+	if err != nil {
+		return err
+	}
+	heads := s.subscriptions(newHeadsSubscription)
+	logs := s.subscriptions(logsSubscription)
+	if len(s.recent) == 0 { // The first notification, nothing to compare with
+		block, err := st.BlockByHeight(height)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get block at height %d", height)
+		}
+		pb := newProcessedBlock(block, height)
+		if err := s.updateProcessedBlock(&pb, block); err != nil {
+			return err
+		}
+		s.recent = append(s.recent, pb)
+		return nil
+	}
+	removed, err := s.detachOrphaned(height)
+	if err != nil {
+		return err
+	}
+	for _, l := range removed {
+		l.log.Removed = true
+		for _, sub := range logs {
+			if sub.seq <= l.seq && sub.filter.matches(l.log) {
+				sub.conn.notify(sub.id, l.log)
+			}
+		}
+	}
+	var from proto.Height
+	if len(s.recent) == 0 || height-s.recent[len(s.recent)-1].height > maxLogsBlocksRange {
+		// Deep rollback or a lot of blocks applied at once, skip everything but the top block
+		s.recent = nil
+		from = height
+	} else {
+		from = s.recent[len(s.recent)-1].height
+	}
+	var seq uint64
+	for _, sub := range logs {
+		if sub.seq > seq {
+			seq = sub.seq
+		}
+	}
+	for h := from; h <= height; h++ {
+		block, err := st.BlockByHeight(h)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get block at height %d", h)
+		}
+		first := 0
+		if n := len(s.recent); n > 0 && s.recent[n-1].height == h {
+			if block.BlockID() == s.recent[n-1].id {
+				continue // Nothing has changed at this height
+			}
+			first = s.recent[n-1].txs // New microblocks, only the new transactions are processed
+		} else {
+			s.recent = append(s.recent, newProcessedBlock(block, h))
+		}
+		pb := &s.recent[len(s.recent)-1]
+		if len(logs) > 0 {
+			blockLogs, err := s.service.blockLogsFrom(block, h, first)
+			if err != nil {
+				return err
+			}
+			for _, l := range blockLogs {
+				for _, sub := range logs {
+					if sub.filter.matches(l) {
+						sub.conn.notify(sub.id, l)
+					}
+				}
+				pb.logs = append(pb.logs, sentLog{log: l, seq: seq})
+			}
+		}
+		if len(heads) > 0 {
+			header, err := s.blockHeader(block, h)
+			if err != nil {
+				return err
+			}
+			for _, sub := range heads {
+				sub.conn.notify(sub.id, header)
+			}
+		}
+		if err := s.updateProcessedBlock(pb, block); err != nil {
+			return err
+		}
+	}
+	if len(s.recent) > maxRecentBlocks {
+		s.recent = append([]processedBlock(nil), s.recent[len(s.recent)-maxRecentBlocks:]...)
+	}
+	return nil
+}
+
+func newProcessedBlock(block *proto.Block, height proto.Height) processedBlock {
+	return processedBlock{height: height, generator: block.GeneratorPublicKey, timestamp: block.Timestamp}
+}
+
+func (s *Subscriptions) updateProcessedBlock(pb *processedBlock, block *proto.Block) error {
+	pb.id = block.BlockID()
+	pb.txs = len(block.Transactions)
+	if pb.txs > 0 {
+		id, err := block.Transactions[pb.txs-1].GetID(s.service.nodeRPCApp.Scheme)
+		if err != nil {
+			return errors.Wrap(err, "failed to get ID of transaction")
+		}
+		pb.lastTx = id
+	}
+	return nil
+}
+
+// detachOrphaned removes the processed blocks that are not in the blockchain anymore and returns their logs,
+// the logs of the latest blocks go first. The block extended with microblocks is not orphaned.
+func (s *Subscriptions) detachOrphaned(height proto.Height) ([]sentLog, error) {
+	var removed []sentLog
+	for len(s.recent) > 0 {
+		i := len(s.recent) - 1
+		pb := s.recent[i]
+		if pb.height <= height {
+			block, err := s.service.nodeRPCApp.State.BlockByHeight(pb.height)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get block at height %d", pb.height)
+			}
+			ok, err := s.extends(block, pb)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				break
+			}
+		}
+		removed = append(removed, pb.logs...)
+		s.recent = s.recent[:i]
+	}
+	return removed, nil
+}
+
+// extends checks that the block is the processed block or the processed block with more microblocks.
+func (s *Subscriptions) extends(block *proto.Block, pb processedBlock) (bool, error) {
+	if block.BlockID() == pb.id {
+		return true, nil
+	}
+	if block.GeneratorPublicKey != pb.generator || block.Timestamp != pb.timestamp || len(block.Transactions) < pb.txs {
+		return false, nil
+	}
+	if pb.txs == 0 {
+		return true, nil
+	}
+	id, err := block.Transactions[pb.txs-1].GetID(s.service.nodeRPCApp.Scheme)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get ID of transaction")
+	}
+	return bytes.Equal(id, pb.lastTx), nil
+}
+
+func (s *Subscriptions) blockHeader(block *proto.Block, height proto.Height) (*BlockHeader, error) {
+	addr, err := proto.NewAddressFromPublicKey(s.service.nodeRPCApp.Scheme, block.GeneratorPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	miner, err := wavesToEthereumAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockHeader{
+		Number:        uint64ToHexString(height),
+		Hash:          proto.EncodeToHexString(block.BlockID().Bytes()),
+		ParentHash:    proto.EncodeToHexString(block.Parent.Bytes()),
+		Nonce:         "0x0000000000000000",
+		Difficulty:    "0x0",
+		GasLimit:      "0x0",
+		GasUsed:       "0x0",
+		Miner:         miner,
+		ExtraData:     "0x",
+		LogsBloom:     logsBloom(nil),
+		Timestamp:     uint64ToHexString(block.Timestamp / 1000),
+		BaseFeePerGas: "0x0",
+	}, nil
+}
+
+// HandleTransaction notifies `newPendingTransactions` subscriptions about the new transaction.
+func (s *Subscriptions) HandleTransaction(tx proto.Transaction) {
+	subs := s.subscriptions(newPendingTransactionsSubscription)
+	if len(subs) == 0 {
+		return
+	}
+	id, err := tx.GetID(s.service.nodeRPCApp.Scheme)
+	if err != nil {
+		zap.S().Errorf("Failed to get ID of new transaction: %v", err)
+		return
+	}
+	hash := proto.BytesToEthereumHash(id)
+	for _, sub := range subs {
+		sub.conn.notify(sub.id, hash)
+	}
+}
+
+type subscriptionNotification struct {
+	Version string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  subscriptionResult `json:"params"`
+}
+
+type subscriptionResult struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+func newSubscriptionNotification(id string, result interface{}) ([]byte, error) {
+	return json.Marshal(subscriptionNotification{
+		Version: zenrpc.Version,
+		Method:  "eth_subscription",
+		Params:  subscriptionResult{Subscription: id, Result: result},
+	})
+}
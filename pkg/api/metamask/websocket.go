@@ -0,0 +1,261 @@
+package metamask
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/semrush/zenrpc/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// wsSendQueueSize is the number of messages waiting to be sent, the connection of a client that can't
+	// keep up with the notifications is closed.
+	wsSendQueueSize = 1024
+	wsWriteTimeout  = 10 * time.Second
+	wsPingInterval  = 30 * time.Second
+	wsMaxMessageLen = 1 << 20
+
+	ethSubscribeMethod   = "eth_subscribe"
+	ethUnsubscribeMethod = "eth_unsubscribe"
+)
+
+// WebSocketServer serves JSON-RPC over WebSocket. Requests eth_subscribe and eth_unsubscribe are handled
+// by the server itself, all other requests are passed to the RPC server.
+// Requests which are not WebSocket upgrades are served by the RPC server over HTTP.
+// Connections and cross-origin HTTP requests are accepted only from the allowed origins.
+type WebSocketServer struct {
+	rpc      zenrpc.Server
+	subs     *Subscriptions
+	origins  AllowedOrigins
+	upgrader websocket.Upgrader
+}
+
+func NewWebSocketServer(rpc zenrpc.Server, subs *Subscriptions, origins AllowedOrigins) *WebSocketServer {
+	return &WebSocketServer{
+		rpc:     rpc,
+		subs:    subs,
+		origins: origins,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return origins.Allowed(r.Header.Get("Origin")) },
+		},
+	}
+}
+
+func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		if origin := r.Header.Get("Origin"); origin != "" && !s.origins.Any() {
+			// Headers for any origin are set by the RPC server
+			if !s.origins.Allowed(origin) {
+				http.Error(w, "origin is not allowed", http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+				return
+			}
+		}
+		s.rpc.ServeHTTP(w, r)
+		return
+	}
+	c, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		zap.S().Debugf("Failed to upgrade connection to WebSocket: %v", err)
+		return
+	}
+	conn := newWSConnection(c)
+	defer func() {
+		s.subs.unsubscribeAll(conn)
+		conn.close()
+	}()
+	go conn.writeLoop()
+
+	c.SetReadLimit(wsMaxMessageLen)
+	for {
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				zap.S().Debugf("Failed to read WebSocket message: %v", err)
+			}
+			return
+		}
+		resp, err := s.handleMessage(r.Context(), conn, msg)
+		if err != nil {
+			zap.S().Debugf("Failed to handle WebSocket message: %v", err)
+			return
+		}
+		if !conn.send(resp) {
+			return
+		}
+	}
+}
+
+func (s *WebSocketServer) handleMessage(ctx context.Context, conn *wsConnection, msg []byte) ([]byte, error) {
+	var req zenrpc.Request
+	if err := json.Unmarshal(msg, &req); err != nil { // Not a single request, it's passed to the RPC server as is
+		return s.rpc.Do(ctx, msg)
+	}
+	var resp zenrpc.Response
+	switch strings.ToLower(req.Method) {
+	case ethSubscribeMethod:
+		resp = s.subscribe(conn, req.Params)
+	case ethUnsubscribeMethod:
+		resp = s.unsubscribe(conn, req.Params)
+	default:
+		return s.rpc.Do(ctx, msg)
+	}
+	resp.ID = req.ID
+	return json.Marshal(resp)
+}
+
+func (s *WebSocketServer) subscribe(conn *wsConnection, params json.RawMessage) zenrpc.Response {
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 {
+		return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", "subscription type expected")
+	}
+	var kind subscriptionKind
+	if err := json.Unmarshal(args[0], &kind); err != nil {
+		return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+	}
+	var filter logsFilter
+	switch kind {
+	case newHeadsSubscription, newPendingTransactionsSubscription:
+		if len(args) > 1 {
+			return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", "unexpected subscription parameters")
+		}
+	case logsSubscription:
+		if len(args) > 1 {
+			if err := json.Unmarshal(args[1], &filter); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+		if filter.FromBlock != nil || filter.ToBlock != nil || filter.BlockHash != nil {
+			return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", "only 'address' and 'topics' are supported")
+		}
+	default:
+		return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "",
+			errors.Errorf("unsupported subscription type %q", kind).Error())
+	}
+	var resp zenrpc.Response
+	resp.Set(s.subs.subscribe(conn, kind, filter))
+	return resp
+}
+
+func (s *WebSocketServer) unsubscribe(conn *wsConnection, params json.RawMessage) zenrpc.Response {
+	var args []string
+	if err := json.Unmarshal(params, &args); err != nil || len(args) != 1 {
+		return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", "subscription id expected")
+	}
+	var resp zenrpc.Response
+	resp.Set(s.subs.unsubscribe(conn, args[0]))
+	return resp
+}
+
+// wsConnection serializes writes to the WebSocket connection.
+type wsConnection struct {
+	c         *websocket.Conn
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWSConnection(c *websocket.Conn) *wsConnection {
+	return &wsConnection{c: c, queue: make(chan []byte, wsSendQueueSize), done: make(chan struct{})}
+}
+
+// send queues the message, it returns false if the connection is closed or the queue is full.
+func (c *wsConnection) send(msg []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.queue <- msg:
+		return true
+	default:
+		zap.S().Debugf("WebSocket client %s is too slow, closing connection", c.c.RemoteAddr())
+		c.close()
+		return false
+	}
+}
+
+func (c *wsConnection) notify(id string, result interface{}) {
+	msg, err := newSubscriptionNotification(id, result)
+	if err != nil {
+		zap.S().Errorf("Failed to marshal subscription notification: %v", err)
+		return
+	}
+	c.send(msg)
+}
+
+func (c *wsConnection) writeLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.queue:
+			_ = c.c.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := c.c.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.close()
+				return
+			}
+		case <-ticker.C:
+			if err := c.c.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+func (c *wsConnection) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.c.Close()
+	})
+}
+
+// AllowedOrigins is the list of origins allowed to access the API from browsers.
+type AllowedOrigins struct {
+	any     bool
+	origins map[string]struct{}
+}
+
+func NewAllowedOrigins(origins []string) AllowedOrigins {
+	r := AllowedOrigins{origins: make(map[string]struct{}, len(origins))}
+	for _, o := range origins {
+		o = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(o), "/"))
+		if o == "*" {
+			r.any = true
+		}
+		r.origins[o] = struct{}{}
+	}
+	return r
+}
+
+// Any returns true if requests from any origin are allowed.
+func (o AllowedOrigins) Any() bool {
+	return o.any
+}
+
+// Allowed checks the value of the Origin header. Requests without the header are not made by browsers,
+// they are always allowed.
+func (o AllowedOrigins) Allowed(origin string) bool {
+	if origin == "" || o.any {
+		return true
+	}
+	_, ok := o.origins[strings.ToLower(origin)]
+	return ok
+}
@@ -0,0 +1,178 @@
+package metamask
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/semrush/zenrpc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+)
+
+func testBlock(height uint64) *proto.Block {
+	b := &proto.Block{}
+	b.Version = proto.ProtobufBlockVersion
+	b.ID = proto.NewBlockIDFromDigest(crypto.MustFastHash([]byte{byte(height)}))
+	b.Parent = proto.NewBlockIDFromDigest(crypto.MustFastHash([]byte{byte(height - 1)}))
+	b.Timestamp = height * 60000
+	return b
+}
+
+func wsCall(t *testing.T, c *websocket.Conn, req string) map[string]json.RawMessage {
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(req)))
+	return wsRead(t, c)
+}
+
+func wsRead(t *testing.T, c *websocket.Conn) map[string]json.RawMessage {
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	var resp map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(msg, &resp))
+	return resp
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var height uint64 = 5
+	st := mock.NewMockState(ctrl)
+	st.EXPECT().Height().DoAndReturn(func() (uint64, error) { return height, nil }).AnyTimes()
+	st.EXPECT().BlockByHeight(gomock.Any()).DoAndReturn(func(h uint64) (*proto.Block, error) {
+		return testBlock(h), nil
+	}).AnyTimes()
+
+	service := NewRPCService(&services.Services{State: st, Scheme: proto.TestNetScheme, Time: ntptime.Stub{}})
+	rpc := zenrpc.NewServer(zenrpc.Options{})
+	rpc.Register("", service)
+	subs := NewSubscriptions(service)
+	subs.Handle() // Initial state
+
+	srv := httptest.NewServer(NewWebSocketServer(rpc, subs, NewAllowedOrigins([]string{"*"})))
+	defer srv.Close()
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+
+	// Regular requests are passed to the RPC server
+	resp := wsCall(t, c, `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	assert.JSONEq(t, `"0x5"`, string(resp["result"]))
+
+	resp = wsCall(t, c, `{"jsonrpc":"2.0","id":2,"method":"eth_subscribe","params":["newHeads"]}`)
+	assert.JSONEq(t, `2`, string(resp["id"]))
+	var headsID string
+	require.NoError(t, json.Unmarshal(resp["result"], &headsID))
+
+	resp = wsCall(t, c, `{"jsonrpc":"2.0","id":3,"method":"eth_subscribe","params":["newPendingTransactions"]}`)
+	var txsID string
+	require.NoError(t, json.Unmarshal(resp["result"], &txsID))
+
+	resp = wsCall(t, c, `{"jsonrpc":"2.0","id":4,"method":"eth_subscribe","params":["syncing"]}`)
+	assert.Contains(t, resp, "error")
+
+	height = 7
+	subs.Handle()
+	for _, expected := range []string{"0x6", "0x7"} {
+		n := wsRead(t, c)
+		assert.JSONEq(t, `"eth_subscription"`, string(n["method"]))
+		var params struct {
+			Subscription string      `json:"subscription"`
+			Result       BlockHeader `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(n["params"], &params))
+		assert.Equal(t, headsID, params.Subscription)
+		assert.Equal(t, expected, params.Result.Number)
+	}
+
+	tx := proto.NewUnsignedGenesis(proto.MustAddressFromString("3N5GRqzDBhjVXnCn44baHcz2GoZy5qLxtTh"), 100, 1)
+	require.NoError(t, tx.GenerateSigID(proto.TestNetScheme))
+	id, err := tx.GetID(proto.TestNetScheme)
+	require.NoError(t, err)
+	subs.HandleTransaction(tx)
+	n := wsRead(t, c)
+	var params struct {
+		Subscription string             `json:"subscription"`
+		Result       proto.EthereumHash `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(n["params"], &params))
+	assert.Equal(t, txsID, params.Subscription)
+	assert.Equal(t, proto.BytesToEthereumHash(id), params.Result)
+
+	resp = wsCall(t, c, `{"jsonrpc":"2.0","id":5,"method":"eth_unsubscribe","params":["`+headsID+`"]}`)
+	assert.JSONEq(t, `true`, string(resp["result"]))
+	resp = wsCall(t, c, `{"jsonrpc":"2.0","id":6,"method":"eth_unsubscribe","params":["`+headsID+`"]}`)
+	assert.JSONEq(t, `false`, string(resp["result"]))
+}
+
+func TestSubscriptionsRemovedLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	st := mock.NewMockState(ctrl)
+	st.EXPECT().Height().Return(uint64(6), nil).AnyTimes()
+	st.EXPECT().BlockByHeight(gomock.Any()).DoAndReturn(func(h uint64) (*proto.Block, error) {
+		return testBlock(h), nil
+	}).AnyTimes()
+	service := NewRPCService(&services.Services{State: st, Scheme: proto.TestNetScheme, Time: ntptime.Stub{}})
+	subs := NewSubscriptions(service)
+
+	before := &wsConnection{queue: make(chan []byte, 10), done: make(chan struct{})}
+	beforeID := subs.subscribe(before, logsSubscription, logsFilter{})
+	// The log was sent at the block at height 6 which is replaced by another one
+	orphan := testBlock(6)
+	orphan.ID = proto.NewBlockIDFromDigest(crypto.MustFastHash([]byte("orphan")))
+	orphan.Timestamp++
+	sent := Log{LogIndex: "0x0", BlockNumber: "0x6"}
+	subs.recent = []processedBlock{
+		{height: 5, id: testBlock(5).BlockID(), timestamp: testBlock(5).Timestamp},
+		{height: 6, id: orphan.BlockID(), timestamp: orphan.Timestamp, logs: []sentLog{{log: sent, seq: 1}}},
+	}
+	after := &wsConnection{queue: make(chan []byte, 10), done: make(chan struct{})}
+	subs.subscribe(after, logsSubscription, logsFilter{})
+
+	subs.Handle()
+	require.Len(t, before.queue, 1)
+	var n subscriptionNotification
+	var removed Log
+	n.Params.Result = &removed
+	require.NoError(t, json.Unmarshal(<-before.queue, &n))
+	assert.Equal(t, beforeID, n.Params.Subscription)
+	sent.Removed = true
+	assert.Equal(t, sent, removed)
+	assert.Len(t, after.queue, 0)
+	require.Len(t, subs.recent, 2)
+	assert.Equal(t, testBlock(6).BlockID(), subs.recent[1].id)
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	any := NewAllowedOrigins([]string{"*"})
+	assert.True(t, any.Any())
+	assert.True(t, any.Allowed("https://example.com"))
+
+	origins := NewAllowedOrigins([]string{"https://Example.com/", "http://localhost:3000"})
+	assert.False(t, origins.Any())
+	assert.True(t, origins.Allowed(""))
+	assert.True(t, origins.Allowed("https://example.com"))
+	assert.True(t, origins.Allowed("http://localhost:3000"))
+	assert.False(t, origins.Allowed("https://evil.com"))
+
+	srv := httptest.NewServer(NewWebSocketServer(zenrpc.NewServer(zenrpc.Options{}), nil, origins))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.com"}})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_ = resp.Body.Close()
+}
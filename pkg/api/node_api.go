@@ -7,6 +7,8 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -19,14 +21,16 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/node"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/state"
+	"github.com/wavesplatform/gowaves/pkg/types"
 	"github.com/wavesplatform/gowaves/pkg/util/limit_listener"
 	"go.uber.org/zap"
 )
 
 const (
-	defaultTimeout              = 30 * time.Second
-	postMessageSizeLimit  int64 = 1 << 20 // 1 MB
-	maxDebugMessageLength       = 100
+	defaultTimeout                = 30 * time.Second
+	scriptEvaluationTimeout       = 5 * time.Second
+	postMessageSizeLimit    int64 = 1 << 20 // 1 MB
+	maxDebugMessageLength         = 100
 )
 
 type NodeApi struct {
@@ -107,24 +111,158 @@ func (a *NodeApi) TransactionInfo(w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
-func (a *NodeApi) BlocksLast(w http.ResponseWriter, _ *http.Request) error {
+func (a *NodeApi) TransactionsByAddress(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	limit, err := strconv.Atoi(chi.URLParam(r, "limit"))
+	if err != nil || limit <= 0 {
+		return apiErrs.NewCustomValidationError("invalid limit")
+	}
+	if maxLimit := a.app.settings.TransactionsLimit; limit > maxLimit {
+		return apiErrs.NewTooBigArrayAllocationError(maxLimit)
+	}
+	var after *crypto.Digest
+	if s := r.URL.Query().Get("after"); s != "" {
+		id, err := crypto.NewDigestFromBase58(s)
+		if err != nil {
+			return apiErrs.NewCustomValidationError(fmt.Sprintf("Unable to decode transaction id %s", s))
+		}
+		after = &id
+	}
+	txs, err := a.app.TransactionsByAddress(addr, limit, after)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transactions by address")
+	}
+	// Response is wrapped into an additional array for compatibility with Scala implementation.
+	if err := trySendJson(w, [][]TransactionInfo{txs}); err != nil {
+		return errors.Wrap(err, "TransactionsByAddress")
+	}
+	return nil
+}
+
+func (a *NodeApi) stateChangesByID(w http.ResponseWriter, r *http.Request) error {
+	s := chi.URLParam(r, "id")
+	id, err := crypto.NewDigestFromBase58(s)
+	if err != nil {
+		if invalidRune, isInvalid := findFirstInvalidRuneInBase58String(s); isInvalid {
+			return transactionIDAtInvalidCharErr(invalidRune, s)
+		}
+		return transactionIDAtInvalidLenErr(s)
+	}
+	info, err := a.app.TransactionStateChanges(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transaction state changes")
+	}
+	if err := trySendJson(w, info); err != nil {
+		return errors.Wrap(err, "StateChangesByID")
+	}
+	return nil
+}
+
+func (a *NodeApi) stateChangesByAddress(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	limit := a.app.settings.TransactionsLimit
+	if s := chi.URLParam(r, "limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return apiErrs.NewCustomValidationError("invalid limit")
+		}
+	}
+	if maxLimit := a.app.settings.TransactionsLimit; limit > maxLimit {
+		return apiErrs.NewTooBigArrayAllocationError(maxLimit)
+	}
+	var after *crypto.Digest
+	if s := r.URL.Query().Get("after"); s != "" {
+		id, err := crypto.NewDigestFromBase58(s)
+		if err != nil {
+			return apiErrs.NewCustomValidationError(fmt.Sprintf("Unable to decode transaction id %s", s))
+		}
+		after = &id
+	}
+	txs, err := a.app.InvokeTransactionsByAddress(addr, limit, after)
+	if err != nil {
+		return errors.Wrap(err, "failed to get state changes by address")
+	}
+	if err := trySendJson(w, txs); err != nil {
+		return errors.Wrap(err, "StateChangesByAddress")
+	}
+	return nil
+}
+
+func (a *NodeApi) transactionsMerkleProofGet(w http.ResponseWriter, r *http.Request) error {
+	return a.transactionsMerkleProof(w, r.URL.Query()["id"])
+}
+
+func (a *NodeApi) transactionsMerkleProofPost(w http.ResponseWriter, r *http.Request) error {
+	var data struct {
+		IDs []string `json:"ids"`
+	}
+	if err := tryParseJson(r.Body, &data); err != nil {
+		return apiErrs.NewWrongJsonError(err.Error(), nil)
+	}
+	return a.transactionsMerkleProof(w, data.IDs)
+}
+
+func (a *NodeApi) transactionsMerkleProof(w http.ResponseWriter, ids []string) error {
+	if len(ids) == 0 {
+		return apiErrs.NewCustomValidationError("Transaction IDs are not specified")
+	}
+	var (
+		digests    = make([]crypto.Digest, 0, len(ids))
+		invalidIDs []string
+	)
+	for _, id := range ids {
+		d, err := crypto.NewDigestFromBase58(id)
+		if err != nil {
+			invalidIDs = append(invalidIDs, id)
+		} else {
+			digests = append(digests, d)
+		}
+	}
+	if len(invalidIDs) != 0 {
+		return apiErrs.NewInvalidIDsError(invalidIDs)
+	}
+	proofs, err := a.app.TransactionsMerkleProofs(digests)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transactions merkle proofs")
+	}
+	if err := trySendJson(w, proofs); err != nil {
+		return errors.Wrap(err, "TransactionsMerkleProof")
+	}
+	return nil
+}
+
+func (a *NodeApi) BlocksLast(w http.ResponseWriter, r *http.Request) error {
 	apiBlock, err := a.app.BlocksLast()
 	if err != nil {
 		return errors.Wrap(err, "BlocksLast: failed to get last block")
 	}
-	err = trySendJson(w, apiBlock)
+	blockInfo, err := a.blockInfo(r, apiBlock)
+	if err != nil {
+		return err
+	}
+	err = trySendJson(w, blockInfo)
 	if err != nil {
 		return errors.Wrap(err, "BlocksLast")
 	}
 	return nil
 }
 
-func (a *NodeApi) BlocksFirst(w http.ResponseWriter, _ *http.Request) error {
+func (a *NodeApi) BlocksFirst(w http.ResponseWriter, r *http.Request) error {
 	apiBlock, err := a.app.BlocksFirst()
 	if err != nil {
 		return errors.Wrap(err, "BlocksFirst: failed to get first block")
 	}
-	err = trySendJson(w, apiBlock)
+	blockInfo, err := a.blockInfo(r, apiBlock)
+	if err != nil {
+		return err
+	}
+	err = trySendJson(w, blockInfo)
 	if err != nil {
 		return errors.Wrap(err, "BlocksFirst: failed to marshal block to JSON and write to ResponseWriter")
 	}
@@ -259,13 +397,35 @@ func (a *NodeApi) BlockAt(w http.ResponseWriter, r *http.Request) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to create API block")
 	}
-	err = trySendJson(w, apiBlock)
+	blockInfo, err := a.blockInfo(r, apiBlock)
+	if err != nil {
+		return err
+	}
+	err = trySendJson(w, blockInfo)
 	if err != nil {
 		return errors.Wrap(err, "BlockEncodeJson: failed to marshal block to JSON and write to ResponseWriter")
 	}
 	return nil
 }
 
+// blockInfo adds transactions statuses to the block, state changes of invoke transactions are added
+// if `stateChanges` query parameter is set to true.
+func (a *NodeApi) blockInfo(r *http.Request, block *Block) (*BlockInfo, error) {
+	withStateChanges := false
+	if s := r.URL.Query().Get("stateChanges"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, apiErrs.NewCustomValidationError(fmt.Sprintf("invalid stateChanges parameter %q", s))
+		}
+		withStateChanges = v
+	}
+	blockInfo, err := a.app.newBlockInfo(block, withStateChanges)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block transactions info")
+	}
+	return blockInfo, nil
+}
+
 func findFirstInvalidRuneInBase58String(str string) (rune, bool) {
 	for _, r := range str {
 		if _, ok := base58Alphabet[r]; !ok {
@@ -305,7 +465,11 @@ func (a *NodeApi) BlockIDAt(w http.ResponseWriter, r *http.Request) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to create API block")
 	}
-	err = trySendJson(w, apiBlock)
+	blockInfo, err := a.blockInfo(r, apiBlock)
+	if err != nil {
+		return err
+	}
+	err = trySendJson(w, blockInfo)
 	if err != nil {
 		return errors.Wrap(err, "BlockIDAt: failed to marshal block to JSON and write to ResponseWriter")
 	}
@@ -651,6 +815,74 @@ func (a *NodeApi) unconfirmedSize(w http.ResponseWriter, _ *http.Request) error
 	return nil
 }
 
+func (a *NodeApi) unconfirmed(w http.ResponseWriter, r *http.Request) error {
+	filter, err := unconfirmedFilter(r.URL.Query())
+	if err != nil {
+		return err
+	}
+	txs := a.app.UnconfirmedTransactions(filter)
+	if err := trySendJson(w, txs); err != nil {
+		return errors.Wrap(err, "unconfirmed")
+	}
+	return nil
+}
+
+// unconfirmedFilter parses the query parameters `sender`, `recipient`, `type` (may be repeated) and `minFee`.
+func unconfirmedFilter(query url.Values) (*types.UtxFilter, error) {
+	filter := &types.UtxFilter{}
+	if s := query.Get("sender"); s != "" {
+		addr, err := proto.NewAddressFromString(s)
+		if err != nil {
+			return nil, apiErrs.InvalidAddress
+		}
+		filter.Sender = &addr
+	}
+	if s := query.Get("recipient"); s != "" {
+		rcp, err := proto.NewRecipientFromString(s)
+		if err != nil {
+			return nil, apiErrs.NewCustomValidationError(fmt.Sprintf("Invalid recipient %s", s))
+		}
+		filter.Recipient = &rcp
+	}
+	for _, s := range query["type"] {
+		t, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return nil, apiErrs.NewCustomValidationError(fmt.Sprintf("Invalid transaction type %s", s))
+		}
+		filter.Types = append(filter.Types, proto.TransactionType(t))
+	}
+	if s := query.Get("minFee"); s != "" {
+		fee, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, apiErrs.NewCustomValidationError(fmt.Sprintf("Invalid minimal fee %s", s))
+		}
+		filter.MinFee = fee
+	}
+	return filter, nil
+}
+
+func (a *NodeApi) unconfirmedInfo(w http.ResponseWriter, r *http.Request) error {
+	s := chi.URLParam(r, "id")
+	id, err := crypto.NewDigestFromBase58(s)
+	if err != nil {
+		if invalidRune, isInvalid := findFirstInvalidRuneInBase58String(s); isInvalid {
+			return transactionIDAtInvalidCharErr(invalidRune, s)
+		}
+		return transactionIDAtInvalidLenErr(s)
+	}
+	tx, err := a.app.UnconfirmedTransactionByID(id)
+	if err != nil {
+		if errors.Is(err, errUnconfirmedNotFound) {
+			return apiErrs.TransactionDoesNotExist
+		}
+		return errors.Wrap(err, "failed to get unconfirmed transaction")
+	}
+	if err := trySendJson(w, tx); err != nil {
+		return errors.Wrap(err, "unconfirmedInfo")
+	}
+	return nil
+}
+
 type rollbackRequest struct {
 	Height uint64 `json:"height"`
 }
@@ -731,6 +963,170 @@ func (a *NodeApi) Addresses(w http.ResponseWriter, _ *http.Request) error {
 	return nil
 }
 
+func addressFromURLParam(r *http.Request) (proto.WavesAddress, error) {
+	addr, err := proto.NewAddressFromString(chi.URLParam(r, "address"))
+	if err != nil {
+		return proto.WavesAddress{}, apiErrs.InvalidAddress
+	}
+	return addr, nil
+}
+
+func (a *NodeApi) AddressBalance(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	balance, err := a.app.AddressBalance(addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to get address balance")
+	}
+	if err := trySendJson(w, balance); err != nil {
+		return errors.Wrap(err, "AddressBalance")
+	}
+	return nil
+}
+
+func (a *NodeApi) AddressBalanceAfterConfirmations(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	confirmations, err := strconv.ParseUint(chi.URLParam(r, "confirmations"), 10, 64)
+	if err != nil {
+		return &BadRequestError{err}
+	}
+	balance, err := a.app.AddressBalanceAfterConfirmations(addr, confirmations)
+	if err != nil {
+		return errors.Wrap(err, "failed to get address balance after confirmations")
+	}
+	if err := trySendJson(w, balance); err != nil {
+		return errors.Wrap(err, "AddressBalanceAfterConfirmations")
+	}
+	return nil
+}
+
+func (a *NodeApi) AddressBalanceDetails(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	details, err := a.app.AddressBalanceDetails(addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to get address balance details")
+	}
+	if err := trySendJson(w, details); err != nil {
+		return errors.Wrap(err, "AddressBalanceDetails")
+	}
+	return nil
+}
+
+func (a *NodeApi) AddressEffectiveBalance(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	var confirmations uint64
+	if s := chi.URLParam(r, "confirmations"); s != "" {
+		confirmations, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return &BadRequestError{err}
+		}
+	}
+	balance, err := a.app.AddressEffectiveBalance(addr, confirmations)
+	if err != nil {
+		return errors.Wrap(err, "failed to get address effective balance")
+	}
+	if err := trySendJson(w, balance); err != nil {
+		return errors.Wrap(err, "AddressEffectiveBalance")
+	}
+	return nil
+}
+
+func (a *NodeApi) AddressDataGet(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	return a.addressData(w, r, query["key"], query.Get("matches"))
+}
+
+func (a *NodeApi) AddressDataPost(w http.ResponseWriter, r *http.Request) error {
+	var data struct {
+		Keys []string `json:"keys"`
+	}
+	if err := tryParseJson(r.Body, &data); err != nil {
+		return apiErrs.NewWrongJsonError(err.Error(), nil)
+	}
+	return a.addressData(w, r, data.Keys, "")
+}
+
+func (a *NodeApi) addressData(w http.ResponseWriter, r *http.Request, keys []string, matches string) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	if len(keys) != 0 && matches != "" {
+		return apiErrs.NewCustomValidationError("Cannot specify key and matches at the same time")
+	}
+	if limit := a.app.settings.DataKeysRequestLimit; len(keys) > limit {
+		return apiErrs.NewTooBigArrayAllocationError(limit)
+	}
+	var re *regexp.Regexp
+	if matches != "" {
+		re, err = regexp.Compile(matches)
+		if err != nil {
+			return apiErrs.NewCustomValidationError(fmt.Sprintf("Invalid regex: %v", err))
+		}
+	}
+	entries, err := a.app.AddressData(addr, keys, re)
+	if err != nil {
+		return errors.Wrap(err, "failed to get address data")
+	}
+	if err := trySendJson(w, entries); err != nil {
+		return errors.Wrap(err, "AddressData")
+	}
+	return nil
+}
+
+func (a *NodeApi) AddressDataKey(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	// Router matches the decoded path, unless the path contains escaped characters that can't be decoded
+	// without changing its meaning, e.g. an escaped slash. Only in the latter case the key is still escaped.
+	key := chi.URLParam(r, "key")
+	if r.URL.RawPath != "" {
+		key, err = url.PathUnescape(key)
+		if err != nil {
+			return &BadRequestError{err}
+		}
+	}
+	entry, err := a.app.AddressDataEntry(addr, key)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return apiErrs.DataKeyDoesNotExist
+		}
+		return errors.Wrap(err, "failed to get address data entry")
+	}
+	if err := trySendJson(w, entry); err != nil {
+		return errors.Wrap(err, "AddressDataKey")
+	}
+	return nil
+}
+
+func (a *NodeApi) AddressScriptInfo(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	info, err := a.app.AddressScriptInfo(addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to get address script info")
+	}
+	if err := trySendJson(w, info); err != nil {
+		return errors.Wrap(err, "AddressScriptInfo")
+	}
+	return nil
+}
+
 func (a *NodeApi) nodeProcesses(w http.ResponseWriter, _ *http.Request) error {
 	rs := a.app.NodeProcesses()
 	if err := trySendJson(w, rs); err != nil {
@@ -940,3 +1336,84 @@ func (a *NodeApi) debugPrint(_ http.ResponseWriter, r *http.Request) error {
 	zap.S().Debug(safeStr)
 	return nil
 }
+
+func (a *NodeApi) ScriptDecompile(w http.ResponseWriter, r *http.Request) error {
+	b, err := io.ReadAll(io.LimitReader(r.Body, postMessageSizeLimit))
+	if err != nil {
+		return errors.Wrap(err, "ScriptDecompile: failed to read request body")
+	}
+	res, err := a.app.ScriptDecompile(string(b))
+	if err != nil {
+		return errors.Wrap(err, "ScriptDecompile")
+	}
+	if err := trySendJson(w, res); err != nil {
+		return errors.Wrap(err, "ScriptDecompile")
+	}
+	return nil
+}
+
+func (a *NodeApi) ScriptEvaluate(w http.ResponseWriter, r *http.Request) error {
+	addr, err := addressFromURLParam(r)
+	if err != nil {
+		return err
+	}
+	b, err := io.ReadAll(io.LimitReader(r.Body, postMessageSizeLimit))
+	if err != nil {
+		return errors.Wrap(err, "ScriptEvaluate: failed to read request body")
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), scriptEvaluationTimeout)
+	defer cancel()
+	res, err := a.app.ScriptEvaluate(ctx, addr, b)
+	if err != nil {
+		return errors.Wrap(err, "ScriptEvaluate")
+	}
+	if err := trySendJson(w, res); err != nil {
+		return errors.Wrap(err, "ScriptEvaluate")
+	}
+	return nil
+}
+
+func (a *NodeApi) DebugValidate(w http.ResponseWriter, r *http.Request) error {
+	b, err := io.ReadAll(io.LimitReader(r.Body, postMessageSizeLimit))
+	if err != nil {
+		return errors.Wrap(err, "DebugValidate: failed to read request body")
+	}
+	res, err := a.app.DebugValidate(b)
+	if err != nil {
+		return errors.Wrap(err, "DebugValidate")
+	}
+	if err := trySendJson(w, res); err != nil {
+		return errors.Wrap(err, "DebugValidate")
+	}
+	return nil
+}
+
+func (a *NodeApi) EthereumTypedDataHash(w http.ResponseWriter, r *http.Request) error {
+	b, err := io.ReadAll(io.LimitReader(r.Body, postMessageSizeLimit))
+	if err != nil {
+		return errors.Wrap(err, "EthereumTypedDataHash: failed to read request body")
+	}
+	res, err := a.app.EthereumTypedDataHash(b)
+	if err != nil {
+		return errors.Wrap(err, "EthereumTypedDataHash")
+	}
+	if err := trySendJson(w, res); err != nil {
+		return errors.Wrap(err, "EthereumTypedDataHash")
+	}
+	return nil
+}
+
+func (a *NodeApi) EthereumTypedDataVerify(w http.ResponseWriter, r *http.Request) error {
+	b, err := io.ReadAll(io.LimitReader(r.Body, postMessageSizeLimit))
+	if err != nil {
+		return errors.Wrap(err, "EthereumTypedDataVerify: failed to read request body")
+	}
+	res, err := a.app.EthereumTypedDataVerify(b)
+	if err != nil {
+		return errors.Wrap(err, "EthereumTypedDataVerify")
+	}
+	if err := trySendJson(w, res); err != nil {
+		return errors.Wrap(err, "EthereumTypedDataVerify")
+	}
+	return nil
+}
@@ -104,6 +104,7 @@ type PeerInfo struct {
 	PeerNonce          uint64 `json:"peerNonce"`
 	ApplicationName    string `json:"applicationName"`
 	ApplicationVersion string `json:"applicationVersion"`
+	Reputation         int64  `json:"reputation"`
 }
 
 func peerInfoFromPeer(peer peer.Peer) PeerInfo {
@@ -127,7 +128,9 @@ func peerInfoFromPeer(peer peer.Peer) PeerInfo {
 func (a *App) PeersConnected() PeersConnectedResponse {
 	var out []PeerInfo
 	a.peers.EachConnected(func(peer peer.Peer, _ *proto.Score) {
-		out = append(out, peerInfoFromPeer(peer))
+		info := peerInfoFromPeer(peer)
+		info.Reputation = a.peers.Reputation(peer)
+		out = append(out, info)
 	})
 
 	return PeersConnectedResponse{
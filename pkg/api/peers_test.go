@@ -1,12 +1,14 @@
 package api
 
 import (
+	"math/big"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 
 	"github.com/golang/mock/gomock"
@@ -114,3 +116,37 @@ func TestApp_PeersBlackList(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	}
 }
+
+func TestApp_PeersConnected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := mock.NewMockPeer(ctrl)
+	p.EXPECT().RemoteAddr().Return(proto.NewTCPAddrFromString("13.3.4.1:6868"))
+	p.EXPECT().Handshake().Return(proto.Handshake{
+		AppName:      "wavesW",
+		Version:      proto.NewVersion(1, 4, 0),
+		NodeName:     "node",
+		NodeNonce:    10,
+		DeclaredAddr: proto.HandshakeTCPAddr(proto.NewTCPAddrFromString("13.3.4.1:6868")),
+	})
+	peerManager := mock.NewMockPeerManager(ctrl)
+	peerManager.EXPECT().EachConnected(gomock.Any()).Do(func(f func(peer.Peer, *proto.Score)) {
+		f(p, big.NewInt(1))
+	})
+	peerManager.EXPECT().Reputation(p).Return(int64(-120))
+
+	app, err := NewApp("key", nil, services.Services{Peers: peerManager})
+	require.NoError(t, err)
+
+	rs := app.PeersConnected()
+	assert.Equal(t, []PeerInfo{{
+		Address:            "/13.3.4.1:6868",
+		DeclaredAddress:    "/13.3.4.1:6868",
+		PeerName:           "node",
+		PeerNonce:          10,
+		ApplicationName:    "wavesW",
+		ApplicationVersion: "1.4.0",
+		Reputation:         -120,
+	}}, rs.Peers)
+}
@@ -1,5 +1,34 @@
 package api
 
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+var errUnconfirmedNotFound = errors.New("transaction is not in UTX pool")
+
 func (a *App) PoolTransactions() int {
 	return a.utx.Count()
 }
+
+// UnconfirmedTransactions returns the transactions of the UTX pool which match the filter ordered by priority.
+func (a *App) UnconfirmedTransactions(filter *types.UtxFilter) []proto.Transaction {
+	txs := a.utx.Transactions(filter)
+	res := make([]proto.Transaction, len(txs))
+	for i, tx := range txs {
+		res[i] = tx.T
+	}
+	return res
+}
+
+// UnconfirmedTransactionByID returns the transaction of the UTX pool, errUnconfirmedNotFound is returned
+// if there is no such transaction in the pool.
+func (a *App) UnconfirmedTransactionByID(id crypto.Digest) (proto.Transaction, error) {
+	tx, ok := a.utx.TransactionByID(id)
+	if !ok {
+		return nil, errUnconfirmedNotFound
+	}
+	return tx.T, nil
+}
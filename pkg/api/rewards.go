@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	apiErrs "github.com/wavesplatform/gowaves/pkg/api/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+// RewardsStatus is the state of monetary policy in the form of Scala node's API.
+type RewardsStatus struct {
+	Height                 proto.Height        `json:"height"`
+	TotalWavesAmount       uint64              `json:"totalWavesAmount"`
+	CurrentReward          uint64              `json:"currentReward"`
+	MinIncrement           uint64              `json:"minIncrement"`
+	Term                   uint64              `json:"term"`
+	NextCheck              proto.Height        `json:"nextCheck"`
+	VotingIntervalStart    proto.Height        `json:"votingIntervalStart"`
+	VotingInterval         uint64              `json:"votingInterval"`
+	VotingThreshold        uint64              `json:"votingThreshold"`
+	Votes                  RewardVotes         `json:"votes"`
+	DAOAddress             *proto.WavesAddress `json:"daoAddress,omitempty"`
+	XTNBuyBackAddress      *proto.WavesAddress `json:"xtnBuybackAddress,omitempty"`
+	XTNBuyBackRewardPeriod *uint64             `json:"xtnBuybackRewardPeriod,omitempty"`
+}
+
+type RewardVotes struct {
+	Increase uint32 `json:"increase"`
+	Decrease uint32 `json:"decrease"`
+}
+
+func (a *NodeApi) rewardsStatus(height proto.Height) (*RewardsStatus, error) {
+	activated, err := a.state.IsActiveAtHeight(int16(settings.BlockReward), height)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check activation of block reward feature")
+	}
+	if !activated {
+		return nil, apiErrs.NewCustomValidationError("Block reward feature is not activated yet")
+	}
+	info, err := a.state.RewardsInfoAtHeight(height)
+	if err != nil {
+		if state.IsInvalidInput(err) {
+			return nil, apiErrs.NewCustomValidationError(fmt.Sprintf("Invalid height: %d", height))
+		}
+		return nil, errors.Wrapf(err, "failed to get rewards info at height %d", height)
+	}
+	sets, err := a.state.BlockchainSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blockchain settings")
+	}
+	status := &RewardsStatus{
+		Height:              info.Height,
+		TotalWavesAmount:    info.TotalWavesAmount,
+		CurrentReward:       info.CurrentReward,
+		MinIncrement:        info.MinIncrement,
+		Term:                info.Term,
+		NextCheck:           info.NextCheck,
+		VotingIntervalStart: info.VotingIntervalStart,
+		VotingInterval:      info.VotingInterval,
+		VotingThreshold:     info.VotingThreshold,
+		Votes:               RewardVotes{Increase: info.IncreaseVotes, Decrease: info.DecreaseVotes},
+	}
+	// Reward addresses are configured in order: DAO, XTN buy-back
+	if len(sets.RewardAddresses) > 0 {
+		dao := sets.RewardAddresses[0]
+		status.DAOAddress = &dao
+	}
+	if len(sets.RewardAddresses) > 1 {
+		xtn := sets.RewardAddresses[1]
+		period := sets.MinXTNBuyBackPeriod
+		status.XTNBuyBackAddress = &xtn
+		status.XTNBuyBackRewardPeriod = &period
+	}
+	return status, nil
+}
+
+func (a *NodeApi) rewards(w http.ResponseWriter, _ *http.Request) error {
+	height, err := a.state.Height()
+	if err != nil {
+		return errors.Wrap(err, "failed to get last height")
+	}
+	status, err := a.rewardsStatus(height)
+	if err != nil {
+		return err
+	}
+	if err := trySendJson(w, status); err != nil {
+		return errors.Wrap(err, "rewards")
+	}
+	return nil
+}
+
+func (a *NodeApi) rewardsAtHeight(w http.ResponseWriter, r *http.Request) error {
+	s := chi.URLParam(r, "height")
+	height, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return apiErrs.NewCustomValidationError(fmt.Sprintf("Invalid height: %s", s))
+	}
+	status, err := a.rewardsStatus(height)
+	if err != nil {
+		return err
+	}
+	if err := trySendJson(w, status); err != nil {
+		return errors.Wrap(err, "rewardsAtHeight")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiErrs "github.com/wavesplatform/gowaves/pkg/api/errors"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+func TestNodeApi_Rewards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sets := *settings.MainNetSettings
+	sets.RewardAddresses = []proto.WavesAddress{
+		proto.MustAddressFromString("3PEgG7eZHLFhcfsTSaYxgRhZsh4AxMvA4Ms"),
+		proto.MustAddressFromString("3PFjHWuH6WXNJbwnfLHqNFBpwBS5dkYjTfv"),
+	}
+	sets.MinXTNBuyBackPeriod = 100000
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().Height().Return(proto.Height(3_000_000), nil)
+	s.EXPECT().IsActiveAtHeight(int16(settings.BlockReward), proto.Height(3_000_000)).Return(true, nil)
+	s.EXPECT().RewardsInfoAtHeight(proto.Height(3_000_000)).Return(&proto.RewardsInfo{
+		Height:              3_000_000,
+		TotalWavesAmount:    10_001_000_000_000_000,
+		CurrentReward:       600000000,
+		MinIncrement:        50000000,
+		Term:                100000,
+		NextCheck:           3_039_999,
+		VotingIntervalStart: 3_030_000,
+		VotingInterval:      10000,
+		VotingThreshold:     5001,
+		IncreaseVotes:       10,
+		DecreaseVotes:       3,
+	}, nil)
+	s.EXPECT().BlockchainSettings().Return(&sets, nil)
+
+	a := &NodeApi{state: s}
+	w := httptest.NewRecorder()
+	err := a.rewards(w, httptest.NewRequest(http.MethodGet, "/blockchain/rewards", nil))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"height": 3000000,
+		"totalWavesAmount": 10001000000000000,
+		"currentReward": 600000000,
+		"minIncrement": 50000000,
+		"term": 100000,
+		"nextCheck": 3039999,
+		"votingIntervalStart": 3030000,
+		"votingInterval": 10000,
+		"votingThreshold": 5001,
+		"votes": {"increase": 10, "decrease": 3},
+		"daoAddress": "3PEgG7eZHLFhcfsTSaYxgRhZsh4AxMvA4Ms",
+		"xtnBuybackAddress": "3PFjHWuH6WXNJbwnfLHqNFBpwBS5dkYjTfv",
+		"xtnBuybackRewardPeriod": 100000
+	}`, w.Body.String())
+}
+
+func TestNodeApi_RewardsAtHeightErrors(t *testing.T) {
+	for _, test := range []struct {
+		activated bool
+		err       error
+		message   string
+	}{
+		{false, nil, "Block reward feature is not activated yet"},
+		{true, state.NewStateError(state.InvalidInputError, errors.New("invalid height")), "Invalid height: 10"},
+	} {
+		ctrl := gomock.NewController(t)
+		s := mock.NewMockState(ctrl)
+		s.EXPECT().IsActiveAtHeight(int16(settings.BlockReward), proto.Height(10)).Return(test.activated, nil)
+		if test.activated {
+			s.EXPECT().RewardsInfoAtHeight(proto.Height(10)).Return(nil, test.err)
+		}
+
+		a := &NodeApi{state: s}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("height", "10")
+		r := httptest.NewRequest(http.MethodGet, "/blockchain/rewards/10", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		err := a.rewardsAtHeight(httptest.NewRecorder(), r)
+		var validationErr *apiErrs.CustomValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, test.message, validationErr.Message)
+		ctrl.Finish()
+	}
+}
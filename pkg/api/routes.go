@@ -123,6 +123,15 @@ func (a *NodeApi) routes(opts *RunOptions) (chi.Router, error) {
 
 		r.Route("/addresses", func(r chi.Router) {
 			r.Get("/", wrapper(a.Addresses))
+			r.Get("/balance/{address}", wrapper(a.AddressBalance))
+			r.Get("/balance/{address}/{confirmations:\\d+}", wrapper(a.AddressBalanceAfterConfirmations))
+			r.Get("/balance/details/{address}", wrapper(a.AddressBalanceDetails))
+			r.Get("/effectiveBalance/{address}", wrapper(a.AddressEffectiveBalance))
+			r.Get("/effectiveBalance/{address}/{confirmations:\\d+}", wrapper(a.AddressEffectiveBalance))
+			r.Get("/data/{address}", wrapper(a.AddressDataGet))
+			r.Post("/data/{address}", wrapper(a.AddressDataPost))
+			r.Get("/data/{address}/{key}", wrapper(a.AddressDataKey))
+			r.Get("/scriptInfo/{address}", wrapper(a.AddressScriptInfo))
 		})
 
 		r.Route("/alias", func(r chi.Router) {
@@ -131,8 +140,13 @@ func (a *NodeApi) routes(opts *RunOptions) (chi.Router, error) {
 		})
 
 		r.Route("/transactions", func(r chi.Router) {
+			r.Get("/unconfirmed", wrapper(a.unconfirmed))
 			r.Get("/unconfirmed/size", wrapper(a.unconfirmedSize))
+			r.Get("/unconfirmed/info/{id}", wrapper(a.unconfirmedInfo))
 			r.Get("/info/{id}", wrapper(a.TransactionInfo))
+			r.Get("/address/{address}/limit/{limit:\\d+}", wrapper(a.TransactionsByAddress))
+			r.Get("/merkleProof", wrapper(a.transactionsMerkleProofGet))
+			r.Post("/merkleProof", wrapper(a.transactionsMerkleProofPost))
 			r.Post("/broadcast", wrapper(a.TransactionsBroadcast))
 		})
 
@@ -148,11 +162,22 @@ func (a *NodeApi) routes(opts *RunOptions) (chi.Router, error) {
 			rAuth.Post("/clearblacklist", wrapper(a.PeersClearBlackList))
 		})
 
+		r.Route("/utils", func(r chi.Router) {
+			r.Post("/script/decompile", wrapper(a.ScriptDecompile))
+			// Evaluation takes the state lock, so it's available only for the node owner
+			rAuth := r.With(checkAuthMiddleware)
+			rAuth.Post("/script/evaluate/{address}", wrapper(a.ScriptEvaluate))
+		})
+
 		r.Route("/debug", func(r chi.Router) {
 			r.Get("/stateHash/{height:\\d+}", wrapper(a.stateHash))
 			r.Get("/stateHash/last", wrapper(a.stateHashLast))
+			r.Get("/stateChanges/info/{id}", wrapper(a.stateChangesByID))
+			r.Get("/stateChanges/address/{address}", wrapper(a.stateChangesByAddress))
+			r.Get("/stateChanges/address/{address}/limit/{limit:\\d+}", wrapper(a.stateChangesByAddress))
 			rAuth := r.With(checkAuthMiddleware)
 			rAuth.Post("/print", wrapper(a.debugPrint))
+			rAuth.Post("/validate", wrapper(a.DebugValidate))
 
 		})
 		r.Route("/node", func(r chi.Router) {
@@ -160,6 +185,15 @@ func (a *NodeApi) routes(opts *RunOptions) (chi.Router, error) {
 			r.Get("/status", wrapper(a.NodeStatus))
 		})
 
+		r.Route("/activation", func(r chi.Router) {
+			r.Get("/status", wrapper(a.activationStatus))
+		})
+
+		r.Route("/blockchain", func(r chi.Router) {
+			r.Get("/rewards", wrapper(a.rewards))
+			r.Get("/rewards/{height:\\d+}", wrapper(a.rewardsAtHeight))
+		})
+
 		r.Route("/wallet", func(r chi.Router) {
 			rAuth := r.With(checkAuthMiddleware)
 
@@ -168,14 +202,24 @@ func (a *NodeApi) routes(opts *RunOptions) (chi.Router, error) {
 
 		r.Route("/eth", func(r chi.Router) {
 			r.Get("/abi/{address}", wrapper(a.EthereumDAppABI))
+			r.Post("/typedData/hash", wrapper(a.EthereumTypedDataHash))
+			r.Post("/typedData/verify", wrapper(a.EthereumTypedDataVerify))
 			if opts.EnableMetaMaskAPI {
 				service := metamask.NewRPCService(&a.app.services)
-				rpc := zenrpc.NewServer(zenrpc.Options{ExposeSMD: true, AllowCORS: true})
+				origins := metamask.NewAllowedOrigins(opts.MetaMaskAllowedOrigins)
+				rpc := zenrpc.NewServer(zenrpc.Options{ExposeSMD: true, AllowCORS: origins.Any()})
 				if opts.EnableMetaMaskAPILog {
 					rpc.Use(metamask.APILogMiddleware)
 				}
 				rpc.Register("", service)
-				r.Handle("/", rpc)
+				subs := metamask.NewSubscriptions(service)
+				if a.app.services.StateChanged != nil {
+					a.app.services.StateChanged.AddHandler(subs)
+				}
+				if a.app.services.NewTransactions != nil {
+					a.app.services.NewTransactions.AddHandler(subs)
+				}
+				r.Handle("/", metamask.NewWebSocketServer(rpc, subs, origins))
 			}
 		})
 
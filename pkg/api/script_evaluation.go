@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	apiErrs "github.com/wavesplatform/gowaves/pkg/api/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+// expressionDirectives are added to the expression if it has no directives, by default the compiler treats
+// the source without directives as a dApp.
+const expressionDirectives = "{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\n"
+
+// ScriptEvaluationRequest is a request to evaluate an expression or to call a callable function of a dApp.
+// Either Expression or Call should be set.
+type ScriptEvaluationRequest struct {
+	Expression      string               `json:"expr,omitempty"`
+	Call            *proto.FunctionCall  `json:"call,omitempty"`
+	Payments        proto.ScriptPayments `json:"payment,omitempty"`
+	SenderPublicKey *crypto.PublicKey    `json:"senderPublicKey,omitempty"`
+	Fee             uint64               `json:"fee,omitempty"`
+}
+
+// ScriptEvaluation is the result of the script evaluation, state changes are never applied.
+type ScriptEvaluation struct {
+	Address      string              `json:"address"`
+	Expression   string              `json:"expr,omitempty"`
+	Call         *proto.FunctionCall `json:"call,omitempty"`
+	Result       interface{}         `json:"result,omitempty"`
+	Complexity   int                 `json:"complexity"`
+	StateChanges *ScriptStateChanges `json:"stateChanges,omitempty"`
+	Error        string              `json:"error,omitempty"`
+}
+
+type scriptTransfer struct {
+	Address string              `json:"address"`
+	Amount  int64               `json:"amount"`
+	Asset   proto.OptionalAsset `json:"asset"`
+}
+
+type scriptIssue struct {
+	AssetID     crypto.Digest `json:"assetId"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Quantity    int64         `json:"quantity"`
+	Decimals    int32         `json:"decimals"`
+	Reissuable  bool          `json:"isReissuable"`
+	Nonce       int64         `json:"nonce"`
+}
+
+type scriptReissue struct {
+	AssetID    crypto.Digest `json:"assetId"`
+	Quantity   int64         `json:"quantity"`
+	Reissuable bool          `json:"isReissuable"`
+}
+
+type scriptBurn struct {
+	AssetID  crypto.Digest `json:"assetId"`
+	Quantity int64         `json:"quantity"`
+}
+
+type scriptSponsorFee struct {
+	AssetID crypto.Digest `json:"assetId"`
+	MinFee  int64         `json:"minSponsoredAssetFee"`
+}
+
+type scriptLease struct {
+	ID        crypto.Digest `json:"id"`
+	Recipient string        `json:"recipient"`
+	Amount    int64         `json:"amount"`
+	Nonce     int64         `json:"nonce"`
+}
+
+type scriptLeaseCancel struct {
+	LeaseID crypto.Digest `json:"leaseId"`
+}
+
+// ScriptStateChanges are the actions produced by the script grouped by type.
+type ScriptStateChanges struct {
+	Data         []proto.DataEntry   `json:"data"`
+	Transfers    []scriptTransfer    `json:"transfers"`
+	Issues       []scriptIssue       `json:"issues"`
+	Reissues     []scriptReissue     `json:"reissues"`
+	Burns        []scriptBurn        `json:"burns"`
+	SponsorFees  []scriptSponsorFee  `json:"sponsorFees"`
+	Leases       []scriptLease       `json:"leases"`
+	LeaseCancels []scriptLeaseCancel `json:"leaseCancels"`
+}
+
+func newScriptStateChanges(actions []proto.ScriptAction) (*ScriptStateChanges, error) {
+	res, _, err := proto.NewScriptResult(actions, proto.ScriptErrorMessage{})
+	if err != nil {
+		return nil, err
+	}
+	sc := &ScriptStateChanges{
+		Data:         make([]proto.DataEntry, len(res.DataEntries)),
+		Transfers:    make([]scriptTransfer, len(res.Transfers)),
+		Issues:       make([]scriptIssue, len(res.Issues)),
+		Reissues:     make([]scriptReissue, len(res.Reissues)),
+		Burns:        make([]scriptBurn, len(res.Burns)),
+		SponsorFees:  make([]scriptSponsorFee, len(res.Sponsorships)),
+		Leases:       make([]scriptLease, len(res.Leases)),
+		LeaseCancels: make([]scriptLeaseCancel, len(res.LeaseCancels)),
+	}
+	for i, a := range res.DataEntries {
+		sc.Data[i] = a.Entry
+	}
+	for i, a := range res.Transfers {
+		sc.Transfers[i] = scriptTransfer{Address: a.Recipient.String(), Amount: a.Amount, Asset: a.Asset}
+	}
+	for i, a := range res.Issues {
+		sc.Issues[i] = scriptIssue{
+			AssetID:     a.ID,
+			Name:        a.Name,
+			Description: a.Description,
+			Quantity:    a.Quantity,
+			Decimals:    a.Decimals,
+			Reissuable:  a.Reissuable,
+			Nonce:       a.Nonce,
+		}
+	}
+	for i, a := range res.Reissues {
+		sc.Reissues[i] = scriptReissue{AssetID: a.AssetID, Quantity: a.Quantity, Reissuable: a.Reissuable}
+	}
+	for i, a := range res.Burns {
+		sc.Burns[i] = scriptBurn{AssetID: a.AssetID, Quantity: a.Quantity}
+	}
+	for i, a := range res.Sponsorships {
+		sc.SponsorFees[i] = scriptSponsorFee{AssetID: a.AssetID, MinFee: a.MinFee}
+	}
+	for i, a := range res.Leases {
+		sc.Leases[i] = scriptLease{ID: a.ID, Recipient: a.Recipient.String(), Amount: a.Amount, Nonce: a.Nonce}
+	}
+	for i, a := range res.LeaseCancels {
+		sc.LeaseCancels[i] = scriptLeaseCancel{LeaseID: a.LeaseID}
+	}
+	return sc, nil
+}
+
+// ScriptEvaluate evaluates the expression in the context of the account or calls the callable function
+// of the dApp at the address. Nothing is written to the state.
+// Evaluation blocks the state, if it doesn't finish before the context is done an error is returned, while
+// the evaluation itself is completed in background.
+func (a *App) ScriptEvaluate(ctx context.Context, addr proto.WavesAddress, b []byte) (*ScriptEvaluation, error) {
+	req := new(ScriptEvaluationRequest)
+	if err := json.Unmarshal(b, req); err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "invalid evaluation request")}
+	}
+	ev := &state.ScriptEvaluation{Address: addr, Payments: req.Payments, Sender: req.SenderPublicKey, Fee: req.Fee}
+	res := &ScriptEvaluation{Address: addr.String()}
+	switch {
+	case req.Expression != "" && req.Call != nil:
+		return nil, &BadRequestError{errors.New("either expression or call should be set, not both")}
+	case req.Expression != "":
+		src := req.Expression
+		if !strings.Contains(src, "{-#") {
+			src = expressionDirectives + src
+		}
+		tree, errs := compiler.CompileEvaluatedExpression(src)
+		if len(errs) > 0 {
+			return nil, &BadRequestError{errors.Wrap(errs[0], "failed to compile expression")}
+		}
+		ev.Expression = tree
+		res.Expression = req.Expression
+	case req.Call != nil:
+		ev.Call = *req.Call
+		res.Call = req.Call
+	default:
+		return nil, &BadRequestError{errors.New("expression or call should be set")}
+	}
+	type evaluation struct {
+		r       ride.Result
+		evalErr error
+		err     error
+	}
+	done := make(chan evaluation, 1)
+	go func() {
+		var e evaluation
+		e.err = a.state.TxValidation(func(validation state.TxValidation) error {
+			lastBlock := a.state.TopBlock()
+			now := uint64(a.services.Time.Now().UnixMilli())
+			e.r, e.evalErr = validation.EvaluateScript(ev, now, lastBlock.Timestamp, lastBlock.Version)
+			return nil
+		})
+		done <- e
+	}()
+	var e evaluation
+	select {
+	case <-ctx.Done():
+		return nil, apiErrs.NewUnknownErrorWithMsg("script evaluation is not finished in time", ctx.Err())
+	case e = <-done:
+	}
+	if e.err != nil {
+		return nil, errors.Wrap(e.err, "failed to evaluate script")
+	}
+	if e.evalErr != nil {
+		res.Error = e.evalErr.Error()
+		if e.r != nil {
+			res.Complexity = e.r.Complexity()
+		} else {
+			res.Complexity = ride.EvaluationErrorSpentComplexity(errors.Cause(e.evalErr))
+		}
+		return res, nil
+	}
+	res.Result = ride.ResultValue(e.r)
+	res.Complexity = e.r.Complexity()
+	if req.Call != nil {
+		var err error
+		res.StateChanges, err = newScriptStateChanges(e.r.ScriptActions())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build state changes")
+		}
+	}
+	return res, nil
+}
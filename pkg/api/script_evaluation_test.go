@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/services"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+func TestApp_ScriptEvaluate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr, err := proto.NewAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	require.NoError(t, err)
+
+	s := mock.NewMockState(ctrl)
+	v := mock.NewMockTxValidation(ctrl)
+	s.EXPECT().TxValidation(gomock.Any()).DoAndReturn(func(f func(state.TxValidation) error) error {
+		return f(v)
+	}).Times(2)
+	s.EXPECT().TopBlock().Return(&proto.Block{BlockHeader: proto.BlockHeader{Timestamp: 1000, Version: proto.ProtobufBlockVersion}}).Times(2)
+	gomock.InOrder(
+		v.EXPECT().EvaluateScript(gomock.Any(), gomock.Any(), uint64(1000), proto.ProtobufBlockVersion).
+			DoAndReturn(func(ev *state.ScriptEvaluation, _, _ uint64, _ proto.BlockVersion) (ride.Result, error) {
+				assert.Equal(t, addr, ev.Address)
+				require.NotNil(t, ev.Expression)
+				assert.False(t, ev.Expression.IsDApp())
+				return nil, ride.EvaluationErrorSetComplexity(ride.UserError.New("failed"), 10)
+			}),
+		v.EXPECT().EvaluateScript(gomock.Any(), gomock.Any(), uint64(1000), proto.ProtobufBlockVersion).
+			DoAndReturn(func(ev *state.ScriptEvaluation, _, _ uint64, _ proto.BlockVersion) (ride.Result, error) {
+				assert.Nil(t, ev.Expression)
+				assert.Equal(t, "call", ev.Call.Name())
+				assert.Len(t, ev.Payments, 1)
+				assert.Equal(t, uint64(900000), ev.Fee)
+				return nil, errors.Wrap(ride.EvaluationErrorSetComplexity(ride.UserError.New("failed"), 20), "invoke")
+			}),
+	)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme, Time: ntptime.Stub{}})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	res, err := app.ScriptEvaluate(ctx, addr, []byte(`{"expr": "getIntegerValue(this, \"key\") + 1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, addr.String(), res.Address)
+	assert.Equal(t, 10, res.Complexity)
+	assert.Equal(t, "failed", res.Error)
+
+	res, err = app.ScriptEvaluate(ctx, addr, []byte(`{"call": {"function": "call", "args": [{"type": "integer", "value": 1}]},
+		"payment": [{"amount": 100, "assetId": null}], "fee": 900000}`))
+	require.NoError(t, err)
+	assert.Equal(t, 20, res.Complexity)
+	assert.Equal(t, "invoke: failed", res.Error)
+
+	for _, body := range []string{
+		`{}`,
+		`not a json`,
+		`{"expr": "x +"}`,
+		`{"expr": "true", "call": {"function": "call", "args": []}}`,
+	} {
+		_, err = app.ScriptEvaluate(ctx, addr, []byte(body))
+		assert.IsType(t, &BadRequestError{}, err, body)
+	}
+}
+
+func TestApp_ScriptEvaluateTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr, err := proto.NewAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().TxValidation(gomock.Any()).DoAndReturn(func(f func(state.TxValidation) error) error {
+		defer close(finished)
+		<-release
+		return nil
+	})
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme, Time: ntptime.Stub{}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = app.ScriptEvaluate(ctx, addr, []byte(`{"expr": "true"}`))
+	assert.Error(t, err)
+	close(release)
+	<-finished
+}
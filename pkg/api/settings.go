@@ -34,6 +34,9 @@ type RunOptions struct {
 	MaxConnections       int
 	EnableMetaMaskAPI    bool
 	EnableMetaMaskAPILog bool
+	// MetaMaskAllowedOrigins are the origins allowed to make cross-origin requests to the MetaMask API,
+	// including WebSocket connections. The "*" allows any origin.
+	MetaMaskAllowedOrigins []string
 }
 
 type RateLimiterOptions struct {
@@ -57,6 +60,8 @@ func DefaultRunOptions() *RunOptions {
 		MaxConnections:       DefaultMaxConnections,
 		EnableMetaMaskAPI:    false,
 		EnableMetaMaskAPILog: false,
+		// Any origin is allowed by default, the same way as it's done by the Scala node
+		MetaMaskAllowedOrigins: []string{"*"},
 	}
 }
 
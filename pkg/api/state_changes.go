@@ -0,0 +1,162 @@
+package api
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/client"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+func isInvokeTransaction(tx proto.Transaction) bool {
+	switch tx.GetTypeInfo().Type {
+	case proto.InvokeScriptTransaction, proto.InvokeExpressionTransaction, proto.EthereumMetamaskTransaction:
+		return true
+	default:
+		return false
+	}
+}
+
+// stateChanges returns state changes of the transaction or nil if the transaction is not an invoke
+// or its result is absent.
+func (a *App) stateChanges(tx proto.Transaction) (*client.StateChanges, error) {
+	if !isInvokeTransaction(tx) {
+		return nil, nil
+	}
+	id, err := tx.GetID(a.scheme())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transaction ID")
+	}
+	txID, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction ID")
+	}
+	res, err := a.state.InvokeResultByID(txID)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get invoke result of transaction %q", txID.String())
+	}
+	return a.newStateChanges(res)
+}
+
+// newStateChanges converts the invocation result to the form of Scala node's API. Actions of nested invocations
+// are reported in their own state changes.
+func (a *App) newStateChanges(res *proto.ScriptResult) (*client.StateChanges, error) {
+	sc := &client.StateChanges{
+		Data:         make(client.DataEntries, len(res.DataEntries)),
+		Transfers:    make([]client.TransferAction, len(res.Transfers)),
+		Issues:       make([]client.IssueAction, len(res.Issues)),
+		Reissues:     make([]client.ReissueAction, len(res.Reissues)),
+		Burns:        make([]client.BurnAction, len(res.Burns)),
+		SponsorFees:  make([]client.SponsorFeeAction, len(res.Sponsorships)),
+		Leases:       make([]client.LeaseAction, len(res.Leases)),
+		LeaseCancels: make([]client.LeaseCancelAction, len(res.LeaseCancels)),
+		Invokes:      make([]client.InvokeAction, len(res.Invokes)),
+	}
+	for i, d := range res.DataEntries {
+		sc.Data[i] = d.Entry
+	}
+	for i, t := range res.Transfers {
+		addr, err := a.recipientToAddress(t.Recipient)
+		if err != nil {
+			return nil, err
+		}
+		sc.Transfers[i] = client.TransferAction{Address: addr, Asset: t.Asset, Amount: t.Amount}
+	}
+	for i, is := range res.Issues {
+		sc.Issues[i] = client.IssueAction{
+			AssetID:        is.ID,
+			Name:           is.Name,
+			Description:    is.Description,
+			Decimals:       is.Decimals,
+			Quantity:       is.Quantity,
+			Reissuable:     is.Reissuable,
+			CompiledScript: is.Script,
+			Nonce:          is.Nonce,
+		}
+	}
+	for i, r := range res.Reissues {
+		sc.Reissues[i] = client.ReissueAction{AssetID: r.AssetID, Reissuable: r.Reissuable, Quantity: r.Quantity}
+	}
+	for i, b := range res.Burns {
+		sc.Burns[i] = client.BurnAction{AssetID: b.AssetID, Quantity: b.Quantity}
+	}
+	for i, s := range res.Sponsorships {
+		sf := client.SponsorFeeAction{AssetID: s.AssetID}
+		if s.MinFee > 0 {
+			minFee := s.MinFee
+			sf.MinSponsoredAssetFee = &minFee
+		}
+		sc.SponsorFees[i] = sf
+	}
+	for i, l := range res.Leases {
+		la, err := a.leaseAction(l.ID)
+		if err != nil {
+			return nil, err
+		}
+		sc.Leases[i] = la
+	}
+	for i, lc := range res.LeaseCancels {
+		la, err := a.leaseAction(lc.LeaseID)
+		if err != nil {
+			return nil, err
+		}
+		sc.LeaseCancels[i] = client.LeaseCancelAction(la)
+	}
+	for i, inv := range res.Invokes {
+		nested, err := a.newStateChanges(inv.Result)
+		if err != nil {
+			return nil, err
+		}
+		sc.Invokes[i] = client.InvokeAction{
+			DApp:         inv.DApp,
+			Call:         inv.Call,
+			Payments:     append([]proto.ScriptPayment{}, inv.Payments...),
+			StateChanges: *nested,
+		}
+	}
+	if res.ErrorMsg.Code != 0 || res.ErrorMsg.Text != "" {
+		sc.Error = &client.StateChangesError{Code: int(res.ErrorMsg.Code), Text: res.ErrorMsg.Text}
+	}
+	return sc, nil
+}
+
+func (a *App) recipientToAddress(r proto.Recipient) (proto.WavesAddress, error) {
+	if addr := r.Address(); addr != nil {
+		return *addr, nil
+	}
+	addr, err := a.state.AddrByAlias(*r.Alias())
+	if err != nil {
+		return proto.WavesAddress{}, errors.Wrapf(err, "failed to resolve alias %q", r.String())
+	}
+	return addr, nil
+}
+
+// leaseAction describes the lease by its current status like Scala node does, so the lease created by the
+// transaction is reported as canceled if it was canceled later.
+func (a *App) leaseAction(id crypto.Digest) (client.LeaseAction, error) {
+	info, err := a.state.LeasingInfo(id)
+	if err != nil {
+		return client.LeaseAction{}, errors.Wrapf(err, "failed to get leasing info of %q", id.String())
+	}
+	la := client.LeaseAction{
+		ID:        id,
+		Sender:    info.Sender,
+		Recipient: proto.NewRecipientFromAddress(info.Recipient),
+		Amount:    int64(info.LeaseAmount),
+		Height:    uint32(info.Height),
+		Status:    client.LeaseActiveStatus,
+	}
+	if info.OriginTransactionID != nil {
+		la.OriginTransactionId = *info.OriginTransactionID
+	}
+	if !info.IsActive {
+		la.Status = client.LeaseCanceledStatus
+		cancelHeight := uint32(info.CancelHeight)
+		la.CancelHeight = &cancelHeight
+		la.CancelTransactionId = info.CancelTransactionID
+	}
+	return la, nil
+}
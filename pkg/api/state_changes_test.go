@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/client"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+)
+
+func testInvoke(t *testing.T) (*proto.InvokeScriptWithProofs, crypto.Digest) {
+	sk, pk, err := crypto.GenerateKeyPair([]byte("seed"))
+	require.NoError(t, err)
+	dApp := proto.MustAddressFromString("3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3")
+	tx := proto.NewUnsignedInvokeScriptWithProofs(1, pk, proto.NewRecipientFromAddress(dApp),
+		proto.NewFunctionCall("call", proto.Arguments{}), nil, proto.NewOptionalAssetWaves(), 500000, 1000)
+	require.NoError(t, tx.Sign(proto.MainNetScheme, sk))
+	return tx, *tx.ID
+}
+
+func TestApp_TransactionStateChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tx, id := testInvoke(t)
+	alias, err := proto.NewAliasFromString("alias:W:test")
+	require.NoError(t, err)
+	recipient := proto.MustAddressFromString("3P76TmRjfjhdN9KEmwSnzQHpLrMRuf1qV29")
+	sender := proto.MustAddressFromString("3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3")
+	activeLease := crypto.MustDigestFromBase58("DorpHeA8zCBtNT24JfDhuGJXbp5sfs4t9Abk4Eu1ENUY")
+	canceledLease := crypto.MustDigestFromBase58("4KGcGaXbfmFyE5iSBNBDF3CeUSfhcQs7uSz4ygQhy7AC")
+	res := &proto.ScriptResult{
+		DataEntries: []*proto.DataEntryScriptAction{{Entry: &proto.IntegerDataEntry{Key: "k", Value: 7}}},
+		Transfers: []*proto.TransferScriptAction{
+			{Recipient: proto.NewRecipientFromAlias(*alias), Amount: 100, Asset: proto.NewOptionalAssetWaves()},
+		},
+		Leases:       []*proto.LeaseScriptAction{{ID: activeLease, Recipient: proto.NewRecipientFromAddress(recipient), Amount: 10}},
+		LeaseCancels: []*proto.LeaseCancelScriptAction{{LeaseID: canceledLease}},
+		ErrorMsg:     proto.ScriptErrorMessage{Code: proto.DAppError, Text: "failure"},
+		Invokes: []*proto.ScriptInvocation{{
+			DApp:     recipient,
+			Call:     proto.NewFunctionCall("nested", proto.Arguments{proto.NewIntegerArgument(5)}),
+			Payments: proto.ScriptPayments{{Amount: 3, Asset: proto.NewOptionalAssetWaves()}},
+			Result: &proto.ScriptResult{
+				DataEntries: []*proto.DataEntryScriptAction{{Entry: &proto.StringDataEntry{Key: "n", Value: "v"}}},
+			},
+		}},
+	}
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().ProvidesExtendedApi().Return(true, nil)
+	s.EXPECT().TransactionByIDWithStatus(id.Bytes()).Return(tx, true, nil)
+	s.EXPECT().TransactionHeightByID(id.Bytes()).Return(proto.Height(7), nil)
+	s.EXPECT().InvokeResultByID(id).Return(res, nil)
+	s.EXPECT().AddrByAlias(*alias).Return(recipient, nil)
+	s.EXPECT().LeasingInfo(activeLease).Return(&proto.LeaseInfo{
+		IsActive: true, LeaseAmount: 10, Recipient: recipient, Sender: sender, OriginTransactionID: &id, Height: 7,
+	}, nil)
+	s.EXPECT().LeasingInfo(canceledLease).Return(&proto.LeaseInfo{
+		LeaseAmount: 20, Recipient: recipient, Sender: sender, OriginTransactionID: &id, Height: 5,
+		CancelHeight: 7, CancelTransactionID: &id,
+	}, nil)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme})
+	require.NoError(t, err)
+	info, err := app.TransactionStateChanges(id)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, info.Height)
+	assert.True(t, info.Failed)
+	require.NotNil(t, info.StateChanges)
+	require.NotNil(t, info.StateChanges.Error)
+	assert.Equal(t, int(proto.DAppError), info.StateChanges.Error.Code)
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	var out struct {
+		ApplicationStatus string              `json:"applicationStatus"`
+		StateChanges      client.StateChanges `json:"stateChanges"`
+	}
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, applicationStatusScriptExecutionFailed, out.ApplicationStatus)
+	sc := out.StateChanges
+	require.Len(t, sc.Data, 1)
+	assert.Equal(t, "k", sc.Data[0].GetKey())
+	require.Len(t, sc.Transfers, 1)
+	assert.Equal(t, recipient, sc.Transfers[0].Address)
+	require.Len(t, sc.Leases, 1)
+	assert.Equal(t, client.LeaseActiveStatus, sc.Leases[0].Status)
+	assert.Nil(t, sc.Leases[0].CancelHeight)
+	require.Len(t, sc.LeaseCancels, 1)
+	assert.Equal(t, client.LeaseCanceledStatus, sc.LeaseCancels[0].Status)
+	require.NotNil(t, sc.LeaseCancels[0].CancelHeight)
+	assert.EqualValues(t, 7, *sc.LeaseCancels[0].CancelHeight)
+	assert.Empty(t, sc.Issues)
+	require.Len(t, sc.Invokes, 1)
+	nested := sc.Invokes[0]
+	assert.Equal(t, recipient, nested.DApp)
+	assert.Equal(t, "nested", nested.Call.Name())
+	assert.Equal(t, []proto.ScriptPayment{{Amount: 3, Asset: proto.NewOptionalAssetWaves()}}, nested.Payments)
+	require.Len(t, nested.StateChanges.Data, 1)
+	assert.Equal(t, "n", nested.StateChanges.Data[0].GetKey())
+	assert.Empty(t, nested.StateChanges.Transfers)
+	assert.Nil(t, nested.StateChanges.Error)
+}
+
+func TestApp_NewBlockInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invoke, id := testInvoke(t)
+	transfer := testTransfers(t, 1)[0]
+	transferID, err := transfer.GetID(proto.MainNetScheme)
+	require.NoError(t, err)
+	block := &proto.Block{
+		BlockHeader:  proto.BlockHeader{Version: proto.ProtobufBlockVersion},
+		Transactions: proto.Transactions{transfer, invoke},
+	}
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().TransactionsFailed([][]byte{transferID, id.Bytes()}).Return([]bool{false, true}, nil).Times(2)
+	s.EXPECT().ProvidesExtendedApi().Return(true, nil)
+	s.EXPECT().InvokeResultByID(id).Return(&proto.ScriptResult{}, nil)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme})
+	require.NoError(t, err)
+
+	info, err := app.newBlockInfo(&Block{Block: block, Height: 3}, false)
+	require.NoError(t, err)
+	require.Len(t, info.Transactions, 2)
+	assert.False(t, info.Transactions[0].Failed)
+	assert.True(t, info.Transactions[1].Failed)
+	assert.Nil(t, info.Transactions[1].StateChanges)
+
+	info, err = app.newBlockInfo(&Block{Block: block, Height: 3}, true)
+	require.NoError(t, err)
+	assert.Nil(t, info.Transactions[0].StateChanges)
+	assert.NotNil(t, info.Transactions[1].StateChanges)
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.EqualValues(t, 3, raw["height"])
+	txs, ok := raw["transactions"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, txs, 2)
+	first := txs[0].(map[string]interface{})
+	assert.Equal(t, applicationStatusSucceeded, first["applicationStatus"])
+	assert.NotContains(t, first, "height")
+	assert.NotContains(t, first, "stateChanges")
+	second := txs[1].(map[string]interface{})
+	assert.Equal(t, applicationStatusScriptExecutionFailed, second["applicationStatus"])
+	assert.Contains(t, second, "stateChanges")
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apiErrs "github.com/wavesplatform/gowaves/pkg/api/errors"
+	"github.com/wavesplatform/gowaves/pkg/client"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"go.uber.org/zap"
+)
+
+const (
+	applicationStatusSucceeded             = "succeeded"
+	applicationStatusScriptExecutionFailed = "script_execution_failed"
+)
+
+// TransactionInfo is a transaction with the information about its application.
+// It's marshaled to JSON as the transaction itself extended with `height`, `applicationStatus` and
+// `stateChanges` fields. Zero height and nil state changes are omitted.
+type TransactionInfo struct {
+	Transaction  proto.Transaction
+	Height       proto.Height
+	Failed       bool
+	StateChanges *client.StateChanges
+}
+
+func (t TransactionInfo) applicationStatus() string {
+	if t.Failed {
+		return applicationStatusScriptExecutionFailed
+	}
+	return applicationStatusSucceeded
+}
+
+func (t TransactionInfo) MarshalJSON() ([]byte, error) {
+	txJSON, err := json.Marshal(t.Transaction)
+	if err != nil {
+		return nil, errors.Wrap(err, "TransactionInfo.MarshalJSON")
+	}
+	txJSON = bytes.TrimSpace(txJSON)
+	if len(txJSON) < 2 || txJSON[len(txJSON)-1] != '}' {
+		return nil, errors.Errorf("TransactionInfo.MarshalJSON: transaction %T is not marshaled to JSON object", t.Transaction)
+	}
+	extra := fmt.Sprintf(`"applicationStatus":%q`, t.applicationStatus())
+	if t.Height != 0 {
+		extra = fmt.Sprintf(`"height":%d,`, t.Height) + extra
+	}
+	if t.StateChanges != nil {
+		scJSON, err := json.Marshal(t.StateChanges)
+		if err != nil {
+			return nil, errors.Wrap(err, "TransactionInfo.MarshalJSON")
+		}
+		extra += `,"stateChanges":` + string(scJSON)
+	}
+	extra += "}"
+	buf := make([]byte, 0, len(txJSON)+len(extra)+1)
+	buf = append(buf, txJSON[:len(txJSON)-1]...)
+	if len(txJSON) > 2 { // not an empty object
+		buf = append(buf, ',')
+	}
+	buf = append(buf, extra...)
+	return buf, nil
+}
+
+func (a *App) checkExtendedAPI() error {
+	extendedAPI, err := a.state.ProvidesExtendedApi()
+	if err != nil {
+		return errors.Wrap(err, "failed to check extended API availability")
+	}
+	if !extendedAPI {
+		return apiErrs.NewCustomValidationError("Node's state does not have information required for extended API")
+	}
+	return nil
+}
+
+// TransactionsByAddress returns at most limit transactions of the address starting from the most recent one.
+// If after is not nil, transactions are returned starting from the next after the transaction with the given ID.
+func (a *App) TransactionsByAddress(addr proto.WavesAddress, limit int, after *crypto.Digest) ([]TransactionInfo, error) {
+	return a.transactionsByAddress(addr, limit, after, false)
+}
+
+// InvokeTransactionsByAddress returns at most limit invoke transactions of the address with their state changes
+// starting from the most recent one. Parameter after has the same meaning as for TransactionsByAddress.
+func (a *App) InvokeTransactionsByAddress(addr proto.WavesAddress, limit int, after *crypto.Digest) ([]TransactionInfo, error) {
+	return a.transactionsByAddress(addr, limit, after, true)
+}
+
+func (a *App) transactionsByAddress(addr proto.WavesAddress, limit int, after *crypto.Digest, invokesOnly bool) ([]TransactionInfo, error) {
+	if err := a.checkExtendedAPI(); err != nil {
+		return nil, err
+	}
+	iter, err := a.state.NewAddrTransactionsIterator(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create transactions iterator for address %q", addr.String())
+	}
+	defer func() {
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			zap.S().Fatalf("Iterator error: %v", err)
+		}
+	}()
+	res := make([]TransactionInfo, 0, limit)
+	skip := after != nil
+	for len(res) < limit && iter.Next() {
+		tx, failed, err := iter.Transaction()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transaction from iterator")
+		}
+		id, err := tx.GetID(a.scheme())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transaction ID")
+		}
+		if skip {
+			skip = !bytes.Equal(id, after.Bytes())
+			continue
+		}
+		if invokesOnly && !isInvokeTransaction(tx) {
+			continue
+		}
+		height, err := a.state.TransactionHeightByID(id)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transaction height")
+		}
+		info := TransactionInfo{Transaction: tx, Height: height, Failed: failed}
+		if invokesOnly {
+			if info.StateChanges, err = a.stateChanges(tx); err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, info)
+	}
+	return res, nil
+}
+
+// TransactionStateChanges returns the confirmed transaction with its state changes if it is an invoke.
+func (a *App) TransactionStateChanges(id crypto.Digest) (TransactionInfo, error) {
+	if err := a.checkExtendedAPI(); err != nil {
+		return TransactionInfo{}, err
+	}
+	tx, failed, err := a.state.TransactionByIDWithStatus(id.Bytes())
+	if err != nil {
+		if state.IsNotFound(err) {
+			return TransactionInfo{}, apiErrs.TransactionDoesNotExist
+		}
+		return TransactionInfo{}, errors.Wrapf(err, "failed to get transaction %q", id.String())
+	}
+	height, err := a.state.TransactionHeightByID(id.Bytes())
+	if err != nil {
+		return TransactionInfo{}, errors.Wrapf(err, "failed to get height of transaction %q", id.String())
+	}
+	sc, err := a.stateChanges(tx)
+	if err != nil {
+		return TransactionInfo{}, err
+	}
+	return TransactionInfo{Transaction: tx, Height: height, Failed: failed, StateChanges: sc}, nil
+}
+
+// TransactionProof is the merkle proof of transaction inclusion into the transactions root of its block.
+// Proof digests are ordered from the root level to the leaf level.
+type TransactionProof struct {
+	ID               proto.B58Bytes   `json:"id"`
+	TransactionIndex int              `json:"transactionIndex"`
+	MerkleProof      []proto.B58Bytes `json:"merkleProof"`
+}
+
+var errNoTransactionProof = apiErrs.NewCustomValidationError("transactions do not exist or block version < 5")
+
+// TransactionsMerkleProofs returns merkle proofs of confirmed transactions.
+func (a *App) TransactionsMerkleProofs(ids []crypto.Digest) ([]TransactionProof, error) {
+	if limit := a.settings.TransactionsLimit; len(ids) > limit {
+		return nil, apiErrs.NewTooBigArrayAllocationError(limit)
+	}
+	blocks := make(map[proto.Height]*proto.Block)
+	res := make([]TransactionProof, 0, len(ids))
+	for _, id := range ids {
+		height, err := a.state.TransactionHeightByID(id.Bytes())
+		if err != nil {
+			if state.IsNotFound(err) {
+				return nil, errNoTransactionProof
+			}
+			return nil, errors.Wrapf(err, "failed to get height of transaction %q", id.String())
+		}
+		block, ok := blocks[height]
+		if !ok {
+			block, err = a.state.BlockByHeight(height)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get block at height %d", height)
+			}
+			blocks[height] = block
+		}
+		if block.Version < proto.ProtobufBlockVersion {
+			return nil, errNoTransactionProof
+		}
+		index, proofs, err := block.TransactionMerkleProof(a.scheme(), id.Bytes())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to build merkle proof of transaction %q", id.String())
+		}
+		p := TransactionProof{ID: id.Bytes(), TransactionIndex: index, MerkleProof: make([]proto.B58Bytes, len(proofs))}
+		for i := range proofs {
+			p.MerkleProof[i] = proofs[i].Bytes()
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/client"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/miner/utxpool"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+type testTxIterator struct {
+	txs    []proto.Transaction
+	failed []bool
+	pos    int
+}
+
+func (i *testTxIterator) Transaction() (proto.Transaction, bool, error) {
+	return i.txs[i.pos-1], i.failed[i.pos-1], nil
+}
+
+func (i *testTxIterator) Next() bool {
+	i.pos++
+	return i.pos <= len(i.txs)
+}
+
+func (i *testTxIterator) Release() {}
+
+func (i *testTxIterator) Error() error { return nil }
+
+func testTransfers(t *testing.T, n int) []proto.Transaction {
+	sk, pk, err := crypto.GenerateKeyPair([]byte("seed"))
+	require.NoError(t, err)
+	addr, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+	require.NoError(t, err)
+	txs := make([]proto.Transaction, n)
+	for i := range txs {
+		tx := proto.NewUnsignedTransferWithProofs(3, pk, proto.NewOptionalAssetWaves(), proto.NewOptionalAssetWaves(),
+			uint64(1000+i), uint64(i+1), 100000, proto.NewRecipientFromAddress(addr), nil)
+		require.NoError(t, tx.Sign(proto.MainNetScheme, sk))
+		txs[i] = tx
+	}
+	return txs
+}
+
+func TestApp_TransactionsByAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	txs := testTransfers(t, 4)
+	failed := []bool{false, true, false, false}
+	newIter := func() state.TransactionIterator {
+		return &testTxIterator{txs: txs, failed: failed}
+	}
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().ProvidesExtendedApi().Return(true, nil).Times(2)
+	s.EXPECT().NewAddrTransactionsIterator(addr).DoAndReturn(func(proto.Address) (state.TransactionIterator, error) {
+		return newIter(), nil
+	}).Times(2)
+	s.EXPECT().TransactionHeightByID(gomock.Any()).Return(proto.Height(10), nil).AnyTimes()
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme})
+	require.NoError(t, err)
+
+	res, err := app.TransactionsByAddress(addr, 2, nil)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, txs[0], res[0].Transaction)
+	assert.Equal(t, txs[1], res[1].Transaction)
+	assert.True(t, res[1].Failed)
+
+	id, err := txs[1].GetID(proto.MainNetScheme)
+	require.NoError(t, err)
+	after, err := crypto.NewDigestFromBytes(id)
+	require.NoError(t, err)
+	res, err = app.TransactionsByAddress(addr, 10, &after)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, txs[2], res[0].Transaction)
+	assert.Equal(t, txs[3], res[1].Transaction)
+}
+
+func TestApp_TransactionsByAddressNoExtendedAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mock.NewMockState(ctrl)
+	s.EXPECT().ProvidesExtendedApi().Return(false, nil)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme})
+	require.NoError(t, err)
+	_, err = app.TransactionsByAddress(proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ"), 10, nil)
+	assert.Error(t, err)
+}
+
+func TestTransactionInfo_MarshalJSON(t *testing.T) {
+	txs := testTransfers(t, 1)
+	data, err := json.Marshal([][]TransactionInfo{{{Transaction: txs[0], Height: 5, Failed: true}}})
+	require.NoError(t, err)
+
+	var raw [][]map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, float64(5), raw[0][0]["height"])
+	assert.Equal(t, "script_execution_failed", raw[0][0]["applicationStatus"])
+
+	// Response must be readable by the client.
+	var out []client.TransactionsField
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Len(t, out, 1)
+	assert.Equal(t, txs[0], out[0][0])
+}
+
+func TestApp_UnconfirmedTransactions(t *testing.T) {
+	utx := utxpool.New(10000, utxpool.NoOpValidator{}, settings.MainNetSettings)
+	txs := testTransfers(t, 3)
+	for _, tx := range txs {
+		require.NoError(t, utx.Add(tx))
+	}
+	app, err := NewApp("api-key", nil, services.Services{UtxPool: utx, Scheme: proto.MainNetScheme})
+	require.NoError(t, err)
+
+	query, err := url.ParseQuery("type=4&minFee=100000&recipient=3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	require.NoError(t, err)
+	filter, err := unconfirmedFilter(query)
+	require.NoError(t, err)
+	assert.Empty(t, app.UnconfirmedTransactions(filter))
+
+	filter.Recipient = nil
+	assert.Len(t, app.UnconfirmedTransactions(filter), 3)
+
+	_, err = unconfirmedFilter(url.Values{"minFee": []string{"-1"}})
+	assert.Error(t, err)
+
+	id, err := txs[1].GetID(proto.MainNetScheme)
+	require.NoError(t, err)
+	d, err := crypto.NewDigestFromBytes(id)
+	require.NoError(t, err)
+	tx, err := app.UnconfirmedTransactionByID(d)
+	require.NoError(t, err)
+	assert.Equal(t, txs[1], tx)
+	_, err = app.UnconfirmedTransactionByID(crypto.Digest{})
+	assert.ErrorIs(t, err, errUnconfirmedNotFound)
+}
+
+func TestApp_TransactionsMerkleProofs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txs := testTransfers(t, 4)
+	block := &proto.Block{BlockHeader: proto.BlockHeader{Version: proto.ProtobufBlockVersion}, Transactions: txs[:3]}
+	require.NoError(t, block.SetTransactionsRoot(proto.MainNetScheme))
+	legacy := &proto.Block{BlockHeader: proto.BlockHeader{Version: proto.RewardBlockVersion}, Transactions: txs[3:]}
+	ids := make([]crypto.Digest, len(txs))
+	for i, tx := range txs {
+		id, err := tx.GetID(proto.MainNetScheme)
+		require.NoError(t, err)
+		ids[i], err = crypto.NewDigestFromBytes(id)
+		require.NoError(t, err)
+	}
+
+	s := mock.NewMockState(ctrl)
+	for _, id := range ids[:3] {
+		s.EXPECT().TransactionHeightByID(id.Bytes()).Return(proto.Height(10), nil).AnyTimes()
+	}
+	s.EXPECT().TransactionHeightByID(ids[3].Bytes()).Return(proto.Height(5), nil).AnyTimes()
+	s.EXPECT().BlockByHeight(proto.Height(10)).Return(block, nil).Times(1)
+	s.EXPECT().BlockByHeight(proto.Height(5)).Return(legacy, nil).Times(1)
+	unknown := crypto.MustDigestFromBase58("8uUF3jmWCh7Pgmsm8DygJJTVq5g2fDLkqTbQrqoVTGy9")
+	s.EXPECT().TransactionHeightByID(unknown.Bytes()).Return(proto.Height(0), proto.ErrNotFound)
+
+	app, err := NewApp("api-key", nil, services.Services{State: s, Scheme: proto.MainNetScheme})
+	require.NoError(t, err)
+
+	res, err := app.TransactionsMerkleProofs([]crypto.Digest{ids[2], ids[0]})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	tree, err := crypto.NewMerkleTree()
+	require.NoError(t, err)
+	for i, n := range []int{2, 0} {
+		assert.Equal(t, proto.B58Bytes(ids[n].Bytes()), res[i].ID)
+		assert.Equal(t, n, res[i].TransactionIndex)
+		mb, err := txs[n].MerkleBytes(proto.MainNetScheme)
+		require.NoError(t, err)
+		leaf, err := crypto.FastHash(mb)
+		require.NoError(t, err)
+		proofs := make([]crypto.Digest, len(res[i].MerkleProof))
+		for j, p := range res[i].MerkleProof {
+			proofs[j], err = crypto.NewDigestFromBytes(p)
+			require.NoError(t, err)
+		}
+		root := tree.RebuildRoot(leaf, proofs, uint64(n))
+		assert.Equal(t, []byte(block.TransactionsRoot), root.Bytes())
+	}
+
+	_, err = app.TransactionsMerkleProofs([]crypto.Digest{ids[3]})
+	assert.Equal(t, errNoTransactionProof, err)
+	_, err = app.TransactionsMerkleProofs([]crypto.Digest{unknown})
+	assert.Equal(t, errNoTransactionProof, err)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/decompiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+type DecompiledScript struct {
+	StdLibVersion int    `json:"STDLIB_VERSION"`
+	ContentType   string `json:"CONTENT_TYPE"`
+	ScriptType    string `json:"SCRIPT_TYPE,omitempty"`
+	Script        string `json:"script"`
+}
+
+// ScriptDecompile decompiles base64 encoded script, optional "base64:" prefix is allowed.
+func (a *App) ScriptDecompile(encoded string) (*DecompiledScript, error) {
+	encoded = strings.TrimPrefix(strings.TrimSpace(encoded), "base64:")
+	script, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "invalid base64 script")}
+	}
+	tree, err := serialization.Parse(script)
+	if err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "invalid script")}
+	}
+	src, err := decompiler.Decompile(tree)
+	if err != nil {
+		return nil, &BadRequestError{errors.Wrap(err, "failed to decompile script")}
+	}
+	res := &DecompiledScript{StdLibVersion: int(tree.LibVersion), ContentType: "EXPRESSION", Script: src}
+	if tree.IsDApp() {
+		res.ContentType = "DAPP"
+		res.ScriptType = "ACCOUNT"
+	}
+	return res, nil
+}
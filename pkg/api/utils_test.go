@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/services"
+)
+
+func TestApp_ScriptDecompile(t *testing.T) {
+	src := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func call(x: Int) = [IntegerEntry("x", x)]
+`
+	script, errs := compiler.Compile(src, false, false)
+	require.Empty(t, errs)
+
+	app, err := NewApp("api-key", nil, services.Services{})
+	require.NoError(t, err)
+
+	res, err := app.ScriptDecompile("base64:" + base64.StdEncoding.EncodeToString(script))
+	require.NoError(t, err)
+	assert.Equal(t, 6, res.StdLibVersion)
+	assert.Equal(t, "DAPP", res.ContentType)
+	assert.Equal(t, "ACCOUNT", res.ScriptType)
+	assert.Equal(t, src, res.Script)
+
+	_, err = app.ScriptDecompile("not a base64")
+	assert.IsType(t, &BadRequestError{}, err)
+	_, err = app.ScriptDecompile(base64.StdEncoding.EncodeToString([]byte{0xff, 0xff}))
+	assert.IsType(t, &BadRequestError{}, err)
+}
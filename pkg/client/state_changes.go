@@ -113,4 +113,10 @@ type StateChanges struct {
 	Leases       []LeaseAction       `json:"leases"`
 	LeaseCancels []LeaseCancelAction `json:"leaseCancels"`
 	Invokes      []InvokeAction      `json:"invokes"`
+	Error        *StateChangesError  `json:"error,omitempty"` // optional
+}
+
+type StateChangesError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
 }
@@ -77,6 +77,47 @@ func (t *MerkleTree) RebuildRoot(leaf Digest, proofs []Digest, index uint64) Dig
 	return digest
 }
 
+// MerkleProofs builds the tree over the data and returns the digests of sibling nodes on the path from the leaf
+// with the given index to the root. Digests are ordered from the root level to the leaf level as RebuildRoot expects.
+func MerkleProofs(data [][]byte, index uint64) ([]Digest, error) {
+	if index >= uint64(len(data)) {
+		return nil, errors.Errorf("leaf index %d is out of range [0, %d)", index, len(data))
+	}
+	t, err := NewMerkleTree()
+	if err != nil {
+		return nil, err
+	}
+	level := make([]Digest, len(data))
+	for i := range data {
+		level[i] = t.leafDigest(data[i])
+	}
+	var proofs []Digest
+	for {
+		sibling := ZeroDigest
+		if i := index ^ 1; i < uint64(len(level)) {
+			sibling = level[i]
+		}
+		proofs = append(proofs, sibling)
+		next := make([]Digest, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := ZeroDigest
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, t.nodeDigest(level[i], right))
+		}
+		if len(next) == 1 {
+			break
+		}
+		level = next
+		index = index / 2
+	}
+	for i, j := 0, len(proofs)-1; i < j; i, j = i+1, j-1 {
+		proofs[i], proofs[j] = proofs[j], proofs[i]
+	}
+	return proofs, nil
+}
+
 func (t *MerkleTree) leafDigest(data []byte) Digest {
 	t.h.Reset()
 	_, err := t.h.Write(data)
@@ -93,6 +93,33 @@ func TestMerkleTreeRebuildRoot(t *testing.T) {
 	}
 }
 
+func TestMerkleProofs(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		data := make([][]byte, n)
+		tree, err := NewMerkleTree()
+		require.NoError(t, err)
+		for i := range data {
+			data[i] = []byte{byte(i + 1)}
+			tree.Push(data[i])
+		}
+		root := tree.Root()
+		for i := range data {
+			proofs, err := MerkleProofs(data, uint64(i))
+			require.NoError(t, err)
+			leaf, err := FastHash(data[i])
+			require.NoError(t, err)
+			assert.Equal(t, root, tree.RebuildRoot(leaf, proofs, uint64(i)), fmt.Sprintf("leaf %d of %d", i, n))
+		}
+	}
+	proofs, err := MerkleProofs([][]byte{{0x01}, {0x02}, {0x03}, {0x04}, {0x05}}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []Digest{
+		MustDigestFromBase58("2AYMXo9fKWK6swVeAx4DnLuW2wKP8u3S8Ypax6MVWkNh"), ZeroDigest, ZeroDigest,
+	}, proofs)
+	_, err = MerkleProofs([][]byte{{0x01}}, 1)
+	assert.Error(t, err)
+}
+
 func TestStagenetFailure(t *testing.T) {
 	tree, err := NewMerkleTree()
 	require.NoError(t, err)
@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: gowaves/node/grpc/consensus_api.proto
+
+package grpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EffectiveBalanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address    []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	FromHeight int32  `protobuf:"varint,2,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	ToHeight   int32  `protobuf:"varint,3,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+}
+
+func (x *EffectiveBalanceRequest) Reset() {
+	*x = EffectiveBalanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_consensus_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EffectiveBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EffectiveBalanceRequest) ProtoMessage() {}
+
+func (x *EffectiveBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_consensus_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EffectiveBalanceRequest.ProtoReflect.Descriptor instead.
+func (*EffectiveBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_consensus_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EffectiveBalanceRequest) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *EffectiveBalanceRequest) GetFromHeight() int32 {
+	if x != nil {
+		return x.FromHeight
+	}
+	return 0
+}
+
+func (x *EffectiveBalanceRequest) GetToHeight() int32 {
+	if x != nil {
+		return x.ToHeight
+	}
+	return 0
+}
+
+type EffectiveBalanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Balance int64 `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+}
+
+func (x *EffectiveBalanceResponse) Reset() {
+	*x = EffectiveBalanceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_consensus_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EffectiveBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EffectiveBalanceResponse) ProtoMessage() {}
+
+func (x *EffectiveBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_consensus_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EffectiveBalanceResponse.ProtoReflect.Descriptor instead.
+func (*EffectiveBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_consensus_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EffectiveBalanceResponse) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+var File_gowaves_node_grpc_consensus_api_proto protoreflect.FileDescriptor
+
+var file_gowaves_node_grpc_consensus_api_proto_rawDesc = []byte{
+	0x0a, 0x25, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x5f, 0x61, 0x70,
+	0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x22, 0x71, 0x0a, 0x17, 0x45, 0x66,
+	0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x6f, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x6f, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x34, 0x0a,
+	0x18, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x32, 0x7e, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73,
+	0x41, 0x70, 0x69, 0x12, 0x6e, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x2a, 0x2e, 0x67, 0x6f, 0x77,
+	0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45,
+	0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2f,
+	0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63,
+	0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x77, 0x61, 0x76,
+	0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gowaves_node_grpc_consensus_api_proto_rawDescOnce sync.Once
+	file_gowaves_node_grpc_consensus_api_proto_rawDescData = file_gowaves_node_grpc_consensus_api_proto_rawDesc
+)
+
+func file_gowaves_node_grpc_consensus_api_proto_rawDescGZIP() []byte {
+	file_gowaves_node_grpc_consensus_api_proto_rawDescOnce.Do(func() {
+		file_gowaves_node_grpc_consensus_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_gowaves_node_grpc_consensus_api_proto_rawDescData)
+	})
+	return file_gowaves_node_grpc_consensus_api_proto_rawDescData
+}
+
+var file_gowaves_node_grpc_consensus_api_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_gowaves_node_grpc_consensus_api_proto_goTypes = []interface{}{
+	(*EffectiveBalanceRequest)(nil),  // 0: gowaves.node.grpc.EffectiveBalanceRequest
+	(*EffectiveBalanceResponse)(nil), // 1: gowaves.node.grpc.EffectiveBalanceResponse
+}
+var file_gowaves_node_grpc_consensus_api_proto_depIdxs = []int32{
+	0, // 0: gowaves.node.grpc.ConsensusApi.GetEffectiveBalance:input_type -> gowaves.node.grpc.EffectiveBalanceRequest
+	1, // 1: gowaves.node.grpc.ConsensusApi.GetEffectiveBalance:output_type -> gowaves.node.grpc.EffectiveBalanceResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_gowaves_node_grpc_consensus_api_proto_init() }
+func file_gowaves_node_grpc_consensus_api_proto_init() {
+	if File_gowaves_node_grpc_consensus_api_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gowaves_node_grpc_consensus_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EffectiveBalanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_consensus_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EffectiveBalanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gowaves_node_grpc_consensus_api_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gowaves_node_grpc_consensus_api_proto_goTypes,
+		DependencyIndexes: file_gowaves_node_grpc_consensus_api_proto_depIdxs,
+		MessageInfos:      file_gowaves_node_grpc_consensus_api_proto_msgTypes,
+	}.Build()
+	File_gowaves_node_grpc_consensus_api_proto = out.File
+	file_gowaves_node_grpc_consensus_api_proto_rawDesc = nil
+	file_gowaves_node_grpc_consensus_api_proto_goTypes = nil
+	file_gowaves_node_grpc_consensus_api_proto_depIdxs = nil
+}
@@ -0,0 +1,107 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: gowaves/node/grpc/consensus_api.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ConsensusApiClient is the client API for ConsensusApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConsensusApiClient interface {
+	// Returns the minimal effective balance of the address in the range of heights, as it's used
+	// to check the generating balance of block's generator.
+	GetEffectiveBalance(ctx context.Context, in *EffectiveBalanceRequest, opts ...grpc.CallOption) (*EffectiveBalanceResponse, error)
+}
+
+type consensusApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConsensusApiClient(cc grpc.ClientConnInterface) ConsensusApiClient {
+	return &consensusApiClient{cc}
+}
+
+func (c *consensusApiClient) GetEffectiveBalance(ctx context.Context, in *EffectiveBalanceRequest, opts ...grpc.CallOption) (*EffectiveBalanceResponse, error) {
+	out := new(EffectiveBalanceResponse)
+	err := c.cc.Invoke(ctx, "/gowaves.node.grpc.ConsensusApi/GetEffectiveBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConsensusApiServer is the server API for ConsensusApi service.
+// All implementations should embed UnimplementedConsensusApiServer
+// for forward compatibility
+type ConsensusApiServer interface {
+	// Returns the minimal effective balance of the address in the range of heights, as it's used
+	// to check the generating balance of block's generator.
+	GetEffectiveBalance(context.Context, *EffectiveBalanceRequest) (*EffectiveBalanceResponse, error)
+}
+
+// UnimplementedConsensusApiServer should be embedded to have forward compatible implementations.
+type UnimplementedConsensusApiServer struct {
+}
+
+func (UnimplementedConsensusApiServer) GetEffectiveBalance(context.Context, *EffectiveBalanceRequest) (*EffectiveBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEffectiveBalance not implemented")
+}
+
+// UnsafeConsensusApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConsensusApiServer will
+// result in compilation errors.
+type UnsafeConsensusApiServer interface {
+	mustEmbedUnimplementedConsensusApiServer()
+}
+
+func RegisterConsensusApiServer(s grpc.ServiceRegistrar, srv ConsensusApiServer) {
+	s.RegisterService(&ConsensusApi_ServiceDesc, srv)
+}
+
+func _ConsensusApi_GetEffectiveBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EffectiveBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsensusApiServer).GetEffectiveBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gowaves.node.grpc.ConsensusApi/GetEffectiveBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsensusApiServer).GetEffectiveBalance(ctx, req.(*EffectiveBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConsensusApi_ServiceDesc is the grpc.ServiceDesc for ConsensusApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConsensusApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gowaves.node.grpc.ConsensusApi",
+	HandlerType: (*ConsensusApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEffectiveBalance",
+			Handler:    _ConsensusApi_GetEffectiveBalance_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gowaves/node/grpc/consensus_api.proto",
+}
@@ -0,0 +1,350 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: gowaves/node/grpc/peers_api.proto
+
+package grpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ConnectedPeersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ConnectedPeersRequest) Reset() {
+	*x = ConnectedPeersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_peers_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectedPeersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectedPeersRequest) ProtoMessage() {}
+
+func (x *ConnectedPeersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_peers_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectedPeersRequest.ProtoReflect.Descriptor instead.
+func (*ConnectedPeersRequest) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_peers_api_proto_rawDescGZIP(), []int{0}
+}
+
+type ConnectedPeersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Peers []*ConnectedPeer `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+}
+
+func (x *ConnectedPeersResponse) Reset() {
+	*x = ConnectedPeersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_peers_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectedPeersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectedPeersResponse) ProtoMessage() {}
+
+func (x *ConnectedPeersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_peers_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectedPeersResponse.ProtoReflect.Descriptor instead.
+func (*ConnectedPeersResponse) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_peers_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConnectedPeersResponse) GetPeers() []*ConnectedPeer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type ConnectedPeer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// Empty if the peer has no publicly available declared address.
+	DeclaredAddress    string `protobuf:"bytes,2,opt,name=declared_address,json=declaredAddress,proto3" json:"declared_address,omitempty"`
+	NodeName           string `protobuf:"bytes,3,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	NodeNonce          uint64 `protobuf:"varint,4,opt,name=node_nonce,json=nodeNonce,proto3" json:"node_nonce,omitempty"`
+	ApplicationName    string `protobuf:"bytes,5,opt,name=application_name,json=applicationName,proto3" json:"application_name,omitempty"`
+	ApplicationVersion string `protobuf:"bytes,6,opt,name=application_version,json=applicationVersion,proto3" json:"application_version,omitempty"`
+	// Big-endian bytes of the blockchain score of the peer.
+	Score []byte `protobuf:"bytes,7,opt,name=score,proto3" json:"score,omitempty"`
+	// Reputation of the peer, peers with higher reputation are preferred for synchronization.
+	Reputation int64 `protobuf:"varint,8,opt,name=reputation,proto3" json:"reputation,omitempty"`
+}
+
+func (x *ConnectedPeer) Reset() {
+	*x = ConnectedPeer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_peers_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectedPeer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectedPeer) ProtoMessage() {}
+
+func (x *ConnectedPeer) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_peers_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectedPeer.ProtoReflect.Descriptor instead.
+func (*ConnectedPeer) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_peers_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConnectedPeer) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ConnectedPeer) GetDeclaredAddress() string {
+	if x != nil {
+		return x.DeclaredAddress
+	}
+	return ""
+}
+
+func (x *ConnectedPeer) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *ConnectedPeer) GetNodeNonce() uint64 {
+	if x != nil {
+		return x.NodeNonce
+	}
+	return 0
+}
+
+func (x *ConnectedPeer) GetApplicationName() string {
+	if x != nil {
+		return x.ApplicationName
+	}
+	return ""
+}
+
+func (x *ConnectedPeer) GetApplicationVersion() string {
+	if x != nil {
+		return x.ApplicationVersion
+	}
+	return ""
+}
+
+func (x *ConnectedPeer) GetScore() []byte {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+func (x *ConnectedPeer) GetReputation() int64 {
+	if x != nil {
+		return x.Reputation
+	}
+	return 0
+}
+
+var File_gowaves_node_grpc_peers_api_proto protoreflect.FileDescriptor
+
+var file_gowaves_node_grpc_peers_api_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x11, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x22, 0x17, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x50, 0x0a, 0x16, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x05, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76,
+	0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72,
+	0x73, 0x22, 0xa2, 0x02, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50,
+	0x65, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65,
+	0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x4e,
+	0x6f, 0x6e, 0x63, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
+	0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x2f, 0x0a, 0x13, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x61, 0x70,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x75, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x75,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0x74, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x73, 0x41,
+	0x70, 0x69, 0x12, 0x68, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x12, 0x28, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65,
+	0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x29, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50,
+	0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x47, 0x5a, 0x45,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x76, 0x65, 0x73,
+	0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2f, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65,
+	0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gowaves_node_grpc_peers_api_proto_rawDescOnce sync.Once
+	file_gowaves_node_grpc_peers_api_proto_rawDescData = file_gowaves_node_grpc_peers_api_proto_rawDesc
+)
+
+func file_gowaves_node_grpc_peers_api_proto_rawDescGZIP() []byte {
+	file_gowaves_node_grpc_peers_api_proto_rawDescOnce.Do(func() {
+		file_gowaves_node_grpc_peers_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_gowaves_node_grpc_peers_api_proto_rawDescData)
+	})
+	return file_gowaves_node_grpc_peers_api_proto_rawDescData
+}
+
+var file_gowaves_node_grpc_peers_api_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_gowaves_node_grpc_peers_api_proto_goTypes = []interface{}{
+	(*ConnectedPeersRequest)(nil),  // 0: gowaves.node.grpc.ConnectedPeersRequest
+	(*ConnectedPeersResponse)(nil), // 1: gowaves.node.grpc.ConnectedPeersResponse
+	(*ConnectedPeer)(nil),          // 2: gowaves.node.grpc.ConnectedPeer
+}
+var file_gowaves_node_grpc_peers_api_proto_depIdxs = []int32{
+	2, // 0: gowaves.node.grpc.ConnectedPeersResponse.peers:type_name -> gowaves.node.grpc.ConnectedPeer
+	0, // 1: gowaves.node.grpc.PeersApi.GetConnectedPeers:input_type -> gowaves.node.grpc.ConnectedPeersRequest
+	1, // 2: gowaves.node.grpc.PeersApi.GetConnectedPeers:output_type -> gowaves.node.grpc.ConnectedPeersResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_gowaves_node_grpc_peers_api_proto_init() }
+func file_gowaves_node_grpc_peers_api_proto_init() {
+	if File_gowaves_node_grpc_peers_api_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gowaves_node_grpc_peers_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectedPeersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_peers_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectedPeersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_peers_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectedPeer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gowaves_node_grpc_peers_api_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gowaves_node_grpc_peers_api_proto_goTypes,
+		DependencyIndexes: file_gowaves_node_grpc_peers_api_proto_depIdxs,
+		MessageInfos:      file_gowaves_node_grpc_peers_api_proto_msgTypes,
+	}.Build()
+	File_gowaves_node_grpc_peers_api_proto = out.File
+	file_gowaves_node_grpc_peers_api_proto_rawDesc = nil
+	file_gowaves_node_grpc_peers_api_proto_goTypes = nil
+	file_gowaves_node_grpc_peers_api_proto_depIdxs = nil
+}
@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: gowaves/node/grpc/peers_api.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PeersApiClient is the client API for PeersApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PeersApiClient interface {
+	// Returns the connected peers with their scores and reputation.
+	GetConnectedPeers(ctx context.Context, in *ConnectedPeersRequest, opts ...grpc.CallOption) (*ConnectedPeersResponse, error)
+}
+
+type peersApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeersApiClient(cc grpc.ClientConnInterface) PeersApiClient {
+	return &peersApiClient{cc}
+}
+
+func (c *peersApiClient) GetConnectedPeers(ctx context.Context, in *ConnectedPeersRequest, opts ...grpc.CallOption) (*ConnectedPeersResponse, error) {
+	out := new(ConnectedPeersResponse)
+	err := c.cc.Invoke(ctx, "/gowaves.node.grpc.PeersApi/GetConnectedPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeersApiServer is the server API for PeersApi service.
+// All implementations should embed UnimplementedPeersApiServer
+// for forward compatibility
+type PeersApiServer interface {
+	// Returns the connected peers with their scores and reputation.
+	GetConnectedPeers(context.Context, *ConnectedPeersRequest) (*ConnectedPeersResponse, error)
+}
+
+// UnimplementedPeersApiServer should be embedded to have forward compatible implementations.
+type UnimplementedPeersApiServer struct {
+}
+
+func (UnimplementedPeersApiServer) GetConnectedPeers(context.Context, *ConnectedPeersRequest) (*ConnectedPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConnectedPeers not implemented")
+}
+
+// UnsafePeersApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeersApiServer will
+// result in compilation errors.
+type UnsafePeersApiServer interface {
+	mustEmbedUnimplementedPeersApiServer()
+}
+
+func RegisterPeersApiServer(s grpc.ServiceRegistrar, srv PeersApiServer) {
+	s.RegisterService(&PeersApi_ServiceDesc, srv)
+}
+
+func _PeersApi_GetConnectedPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectedPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersApiServer).GetConnectedPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gowaves.node.grpc.PeersApi/GetConnectedPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersApiServer).GetConnectedPeers(ctx, req.(*ConnectedPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeersApi_ServiceDesc is the grpc.ServiceDesc for PeersApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeersApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gowaves.node.grpc.PeersApi",
+	HandlerType: (*PeersApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetConnectedPeers",
+			Handler:    _PeersApi_GetConnectedPeers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gowaves/node/grpc/peers_api.proto",
+}
@@ -0,0 +1,254 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: gowaves/node/grpc/transactions_proof_api.proto
+
+package grpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MerkleProofsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionIds [][]byte `protobuf:"bytes,1,rep,name=transaction_ids,json=transactionIds,proto3" json:"transaction_ids,omitempty"`
+}
+
+func (x *MerkleProofsRequest) Reset() {
+	*x = MerkleProofsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MerkleProofsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MerkleProofsRequest) ProtoMessage() {}
+
+func (x *MerkleProofsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MerkleProofsRequest.ProtoReflect.Descriptor instead.
+func (*MerkleProofsRequest) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_transactions_proof_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MerkleProofsRequest) GetTransactionIds() [][]byte {
+	if x != nil {
+		return x.TransactionIds
+	}
+	return nil
+}
+
+type TransactionMerkleProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Height           int32  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	TransactionIndex int32  `protobuf:"varint,3,opt,name=transaction_index,json=transactionIndex,proto3" json:"transaction_index,omitempty"`
+	// Digests of sibling nodes ordered from the root level to the leaf level.
+	MerkleProof [][]byte `protobuf:"bytes,4,rep,name=merkle_proof,json=merkleProof,proto3" json:"merkle_proof,omitempty"`
+}
+
+func (x *TransactionMerkleProof) Reset() {
+	*x = TransactionMerkleProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionMerkleProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionMerkleProof) ProtoMessage() {}
+
+func (x *TransactionMerkleProof) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionMerkleProof.ProtoReflect.Descriptor instead.
+func (*TransactionMerkleProof) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_transactions_proof_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TransactionMerkleProof) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *TransactionMerkleProof) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *TransactionMerkleProof) GetTransactionIndex() int32 {
+	if x != nil {
+		return x.TransactionIndex
+	}
+	return 0
+}
+
+func (x *TransactionMerkleProof) GetMerkleProof() [][]byte {
+	if x != nil {
+		return x.MerkleProof
+	}
+	return nil
+}
+
+var File_gowaves_node_grpc_transactions_proof_api_proto protoreflect.FileDescriptor
+
+var file_gowaves_node_grpc_transactions_proof_api_proto_rawDesc = []byte{
+	0x0a, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x11, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x22, 0x3e, 0x0a, 0x13, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x73, 0x22, 0x90, 0x01, 0x0a, 0x16, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c,
+	0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x32, 0x7e, 0x0a, 0x14, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x70, 0x69, 0x12, 0x66,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x73, 0x12, 0x26, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x67, 0x6f, 0x77, 0x61,
+	0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x30, 0x01, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f,
+	0x72, 0x6d, 0x2f, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f,
+	0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gowaves_node_grpc_transactions_proof_api_proto_rawDescOnce sync.Once
+	file_gowaves_node_grpc_transactions_proof_api_proto_rawDescData = file_gowaves_node_grpc_transactions_proof_api_proto_rawDesc
+)
+
+func file_gowaves_node_grpc_transactions_proof_api_proto_rawDescGZIP() []byte {
+	file_gowaves_node_grpc_transactions_proof_api_proto_rawDescOnce.Do(func() {
+		file_gowaves_node_grpc_transactions_proof_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_gowaves_node_grpc_transactions_proof_api_proto_rawDescData)
+	})
+	return file_gowaves_node_grpc_transactions_proof_api_proto_rawDescData
+}
+
+var file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_gowaves_node_grpc_transactions_proof_api_proto_goTypes = []interface{}{
+	(*MerkleProofsRequest)(nil),    // 0: gowaves.node.grpc.MerkleProofsRequest
+	(*TransactionMerkleProof)(nil), // 1: gowaves.node.grpc.TransactionMerkleProof
+}
+var file_gowaves_node_grpc_transactions_proof_api_proto_depIdxs = []int32{
+	0, // 0: gowaves.node.grpc.TransactionsProofApi.GetMerkleProofs:input_type -> gowaves.node.grpc.MerkleProofsRequest
+	1, // 1: gowaves.node.grpc.TransactionsProofApi.GetMerkleProofs:output_type -> gowaves.node.grpc.TransactionMerkleProof
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_gowaves_node_grpc_transactions_proof_api_proto_init() }
+func file_gowaves_node_grpc_transactions_proof_api_proto_init() {
+	if File_gowaves_node_grpc_transactions_proof_api_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MerkleProofsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionMerkleProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gowaves_node_grpc_transactions_proof_api_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gowaves_node_grpc_transactions_proof_api_proto_goTypes,
+		DependencyIndexes: file_gowaves_node_grpc_transactions_proof_api_proto_depIdxs,
+		MessageInfos:      file_gowaves_node_grpc_transactions_proof_api_proto_msgTypes,
+	}.Build()
+	File_gowaves_node_grpc_transactions_proof_api_proto = out.File
+	file_gowaves_node_grpc_transactions_proof_api_proto_rawDesc = nil
+	file_gowaves_node_grpc_transactions_proof_api_proto_goTypes = nil
+	file_gowaves_node_grpc_transactions_proof_api_proto_depIdxs = nil
+}
@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: gowaves/node/grpc/transactions_proof_api.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TransactionsProofApiClient is the client API for TransactionsProofApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransactionsProofApiClient interface {
+	// Returns the merkle proofs of transactions against the transactions root of their blocks.
+	// Transactions of blocks prior to version 5 have no proofs.
+	GetMerkleProofs(ctx context.Context, in *MerkleProofsRequest, opts ...grpc.CallOption) (TransactionsProofApi_GetMerkleProofsClient, error)
+}
+
+type transactionsProofApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransactionsProofApiClient(cc grpc.ClientConnInterface) TransactionsProofApiClient {
+	return &transactionsProofApiClient{cc}
+}
+
+func (c *transactionsProofApiClient) GetMerkleProofs(ctx context.Context, in *MerkleProofsRequest, opts ...grpc.CallOption) (TransactionsProofApi_GetMerkleProofsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionsProofApi_ServiceDesc.Streams[0], "/gowaves.node.grpc.TransactionsProofApi/GetMerkleProofs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionsProofApiGetMerkleProofsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionsProofApi_GetMerkleProofsClient interface {
+	Recv() (*TransactionMerkleProof, error)
+	grpc.ClientStream
+}
+
+type transactionsProofApiGetMerkleProofsClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionsProofApiGetMerkleProofsClient) Recv() (*TransactionMerkleProof, error) {
+	m := new(TransactionMerkleProof)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TransactionsProofApiServer is the server API for TransactionsProofApi service.
+// All implementations should embed UnimplementedTransactionsProofApiServer
+// for forward compatibility
+type TransactionsProofApiServer interface {
+	// Returns the merkle proofs of transactions against the transactions root of their blocks.
+	// Transactions of blocks prior to version 5 have no proofs.
+	GetMerkleProofs(*MerkleProofsRequest, TransactionsProofApi_GetMerkleProofsServer) error
+}
+
+// UnimplementedTransactionsProofApiServer should be embedded to have forward compatible implementations.
+type UnimplementedTransactionsProofApiServer struct {
+}
+
+func (UnimplementedTransactionsProofApiServer) GetMerkleProofs(*MerkleProofsRequest, TransactionsProofApi_GetMerkleProofsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetMerkleProofs not implemented")
+}
+
+// UnsafeTransactionsProofApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransactionsProofApiServer will
+// result in compilation errors.
+type UnsafeTransactionsProofApiServer interface {
+	mustEmbedUnimplementedTransactionsProofApiServer()
+}
+
+func RegisterTransactionsProofApiServer(s grpc.ServiceRegistrar, srv TransactionsProofApiServer) {
+	s.RegisterService(&TransactionsProofApi_ServiceDesc, srv)
+}
+
+func _TransactionsProofApi_GetMerkleProofs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MerkleProofsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionsProofApiServer).GetMerkleProofs(m, &transactionsProofApiGetMerkleProofsServer{stream})
+}
+
+type TransactionsProofApi_GetMerkleProofsServer interface {
+	Send(*TransactionMerkleProof) error
+	grpc.ServerStream
+}
+
+type transactionsProofApiGetMerkleProofsServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionsProofApiGetMerkleProofsServer) Send(m *TransactionMerkleProof) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TransactionsProofApi_ServiceDesc is the grpc.ServiceDesc for TransactionsProofApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransactionsProofApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gowaves.node.grpc.TransactionsProofApi",
+	HandlerType: (*TransactionsProofApiServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetMerkleProofs",
+			Handler:       _TransactionsProofApi_GetMerkleProofs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gowaves/node/grpc/transactions_proof_api.proto",
+}
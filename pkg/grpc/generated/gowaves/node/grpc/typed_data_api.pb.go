@@ -0,0 +1,422 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: gowaves/node/grpc/typed_data_api.proto
+
+package grpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TypedDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Typed data in JSON format of eth_signTypedData_v4 request.
+	TypedData string `protobuf:"bytes,1,opt,name=typed_data,json=typedData,proto3" json:"typed_data,omitempty"`
+}
+
+func (x *TypedDataRequest) Reset() {
+	*x = TypedDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypedDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypedDataRequest) ProtoMessage() {}
+
+func (x *TypedDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypedDataRequest.ProtoReflect.Descriptor instead.
+func (*TypedDataRequest) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_typed_data_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TypedDataRequest) GetTypedData() string {
+	if x != nil {
+		return x.TypedData
+	}
+	return ""
+}
+
+type TypedDataHash struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (x *TypedDataHash) Reset() {
+	*x = TypedDataHash{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypedDataHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypedDataHash) ProtoMessage() {}
+
+func (x *TypedDataHash) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypedDataHash.ProtoReflect.Descriptor instead.
+func (*TypedDataHash) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_typed_data_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TypedDataHash) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+type TypedDataVerificationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Typed data in JSON format of eth_signTypedData_v4 request.
+	TypedData string `protobuf:"bytes,1,opt,name=typed_data,json=typedData,proto3" json:"typed_data,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Expected Ethereum address of the signer, optional.
+	Address []byte `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *TypedDataVerificationRequest) Reset() {
+	*x = TypedDataVerificationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypedDataVerificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypedDataVerificationRequest) ProtoMessage() {}
+
+func (x *TypedDataVerificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypedDataVerificationRequest.ProtoReflect.Descriptor instead.
+func (*TypedDataVerificationRequest) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_typed_data_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TypedDataVerificationRequest) GetTypedData() string {
+	if x != nil {
+		return x.TypedData
+	}
+	return ""
+}
+
+func (x *TypedDataVerificationRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *TypedDataVerificationRequest) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+type TypedDataVerification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Ethereum address of the signer.
+	Signer          []byte `protobuf:"bytes,2,opt,name=signer,proto3" json:"signer,omitempty"`
+	SignerPublicKey []byte `protobuf:"bytes,3,opt,name=signer_public_key,json=signerPublicKey,proto3" json:"signer_public_key,omitempty"`
+	// Waves address of the signer.
+	SignerAddress []byte `protobuf:"bytes,4,opt,name=signer_address,json=signerAddress,proto3" json:"signer_address,omitempty"`
+	// Set only if the expected address was given in the request.
+	Valid *bool `protobuf:"varint,5,opt,name=valid,proto3,oneof" json:"valid,omitempty"`
+}
+
+func (x *TypedDataVerification) Reset() {
+	*x = TypedDataVerification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypedDataVerification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypedDataVerification) ProtoMessage() {}
+
+func (x *TypedDataVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_typed_data_api_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypedDataVerification.ProtoReflect.Descriptor instead.
+func (*TypedDataVerification) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_typed_data_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TypedDataVerification) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *TypedDataVerification) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *TypedDataVerification) GetSignerPublicKey() []byte {
+	if x != nil {
+		return x.SignerPublicKey
+	}
+	return nil
+}
+
+func (x *TypedDataVerification) GetSignerAddress() []byte {
+	if x != nil {
+		return x.SignerAddress
+	}
+	return nil
+}
+
+func (x *TypedDataVerification) GetValid() bool {
+	if x != nil && x.Valid != nil {
+		return *x.Valid
+	}
+	return false
+}
+
+var File_gowaves_node_grpc_typed_data_api_proto protoreflect.FileDescriptor
+
+var file_gowaves_node_grpc_typed_data_api_proto_rawDesc = []byte{
+	0x0a, 0x26, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x61,
+	0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65,
+	0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x22, 0x31, 0x0a, 0x10, 0x54,
+	0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x22, 0x23,
+	0x0a, 0x0d, 0x54, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68,
+	0x61, 0x73, 0x68, 0x22, 0x75, 0x0a, 0x1c, 0x54, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xbb, 0x01, 0x0a, 0x15, 0x54,
+	0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72,
+	0x12, 0x2a, 0x0a, 0x11, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x0e,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x19, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x88, 0x01, 0x01, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x32, 0xd4, 0x01, 0x0a, 0x0c, 0x54, 0x79, 0x70,
+	0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x41, 0x70, 0x69, 0x12, 0x56, 0x0a, 0x0d, 0x48, 0x61, 0x73,
+	0x68, 0x54, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x2e, 0x67, 0x6f, 0x77,
+	0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54,
+	0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x20, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x6c, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x54, 0x79, 0x70, 0x65, 0x64,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x2f, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e,
+	0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x64, 0x44, 0x61,
+	0x74, 0x61, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e,
+	0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x64, 0x44,
+	0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61,
+	0x76, 0x65, 0x73, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2f, 0x67, 0x6f, 0x77, 0x61,
+	0x76, 0x65, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x67, 0x65, 0x6e,
+	0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e,
+	0x6f, 0x64, 0x65, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gowaves_node_grpc_typed_data_api_proto_rawDescOnce sync.Once
+	file_gowaves_node_grpc_typed_data_api_proto_rawDescData = file_gowaves_node_grpc_typed_data_api_proto_rawDesc
+)
+
+func file_gowaves_node_grpc_typed_data_api_proto_rawDescGZIP() []byte {
+	file_gowaves_node_grpc_typed_data_api_proto_rawDescOnce.Do(func() {
+		file_gowaves_node_grpc_typed_data_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_gowaves_node_grpc_typed_data_api_proto_rawDescData)
+	})
+	return file_gowaves_node_grpc_typed_data_api_proto_rawDescData
+}
+
+var file_gowaves_node_grpc_typed_data_api_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_gowaves_node_grpc_typed_data_api_proto_goTypes = []interface{}{
+	(*TypedDataRequest)(nil),             // 0: gowaves.node.grpc.TypedDataRequest
+	(*TypedDataHash)(nil),                // 1: gowaves.node.grpc.TypedDataHash
+	(*TypedDataVerificationRequest)(nil), // 2: gowaves.node.grpc.TypedDataVerificationRequest
+	(*TypedDataVerification)(nil),        // 3: gowaves.node.grpc.TypedDataVerification
+}
+var file_gowaves_node_grpc_typed_data_api_proto_depIdxs = []int32{
+	0, // 0: gowaves.node.grpc.TypedDataApi.HashTypedData:input_type -> gowaves.node.grpc.TypedDataRequest
+	2, // 1: gowaves.node.grpc.TypedDataApi.VerifyTypedData:input_type -> gowaves.node.grpc.TypedDataVerificationRequest
+	1, // 2: gowaves.node.grpc.TypedDataApi.HashTypedData:output_type -> gowaves.node.grpc.TypedDataHash
+	3, // 3: gowaves.node.grpc.TypedDataApi.VerifyTypedData:output_type -> gowaves.node.grpc.TypedDataVerification
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_gowaves_node_grpc_typed_data_api_proto_init() }
+func file_gowaves_node_grpc_typed_data_api_proto_init() {
+	if File_gowaves_node_grpc_typed_data_api_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gowaves_node_grpc_typed_data_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypedDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_typed_data_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypedDataHash); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_typed_data_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypedDataVerificationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_typed_data_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypedDataVerification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_gowaves_node_grpc_typed_data_api_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gowaves_node_grpc_typed_data_api_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gowaves_node_grpc_typed_data_api_proto_goTypes,
+		DependencyIndexes: file_gowaves_node_grpc_typed_data_api_proto_depIdxs,
+		MessageInfos:      file_gowaves_node_grpc_typed_data_api_proto_msgTypes,
+	}.Build()
+	File_gowaves_node_grpc_typed_data_api_proto = out.File
+	file_gowaves_node_grpc_typed_data_api_proto_rawDesc = nil
+	file_gowaves_node_grpc_typed_data_api_proto_goTypes = nil
+	file_gowaves_node_grpc_typed_data_api_proto_depIdxs = nil
+}
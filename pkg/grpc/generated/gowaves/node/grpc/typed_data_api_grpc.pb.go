@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: gowaves/node/grpc/typed_data_api.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TypedDataApiClient is the client API for TypedDataApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TypedDataApiClient interface {
+	HashTypedData(ctx context.Context, in *TypedDataRequest, opts ...grpc.CallOption) (*TypedDataHash, error)
+	// Recovers the signer of the typed data, if the address is given the signer is compared with it.
+	VerifyTypedData(ctx context.Context, in *TypedDataVerificationRequest, opts ...grpc.CallOption) (*TypedDataVerification, error)
+}
+
+type typedDataApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTypedDataApiClient(cc grpc.ClientConnInterface) TypedDataApiClient {
+	return &typedDataApiClient{cc}
+}
+
+func (c *typedDataApiClient) HashTypedData(ctx context.Context, in *TypedDataRequest, opts ...grpc.CallOption) (*TypedDataHash, error) {
+	out := new(TypedDataHash)
+	err := c.cc.Invoke(ctx, "/gowaves.node.grpc.TypedDataApi/HashTypedData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *typedDataApiClient) VerifyTypedData(ctx context.Context, in *TypedDataVerificationRequest, opts ...grpc.CallOption) (*TypedDataVerification, error) {
+	out := new(TypedDataVerification)
+	err := c.cc.Invoke(ctx, "/gowaves.node.grpc.TypedDataApi/VerifyTypedData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TypedDataApiServer is the server API for TypedDataApi service.
+// All implementations should embed UnimplementedTypedDataApiServer
+// for forward compatibility
+type TypedDataApiServer interface {
+	HashTypedData(context.Context, *TypedDataRequest) (*TypedDataHash, error)
+	// Recovers the signer of the typed data, if the address is given the signer is compared with it.
+	VerifyTypedData(context.Context, *TypedDataVerificationRequest) (*TypedDataVerification, error)
+}
+
+// UnimplementedTypedDataApiServer should be embedded to have forward compatible implementations.
+type UnimplementedTypedDataApiServer struct {
+}
+
+func (UnimplementedTypedDataApiServer) HashTypedData(context.Context, *TypedDataRequest) (*TypedDataHash, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashTypedData not implemented")
+}
+func (UnimplementedTypedDataApiServer) VerifyTypedData(context.Context, *TypedDataVerificationRequest) (*TypedDataVerification, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyTypedData not implemented")
+}
+
+// UnsafeTypedDataApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TypedDataApiServer will
+// result in compilation errors.
+type UnsafeTypedDataApiServer interface {
+	mustEmbedUnimplementedTypedDataApiServer()
+}
+
+func RegisterTypedDataApiServer(s grpc.ServiceRegistrar, srv TypedDataApiServer) {
+	s.RegisterService(&TypedDataApi_ServiceDesc, srv)
+}
+
+func _TypedDataApi_HashTypedData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TypedDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TypedDataApiServer).HashTypedData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gowaves.node.grpc.TypedDataApi/HashTypedData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TypedDataApiServer).HashTypedData(ctx, req.(*TypedDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TypedDataApi_VerifyTypedData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TypedDataVerificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TypedDataApiServer).VerifyTypedData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gowaves.node.grpc.TypedDataApi/VerifyTypedData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TypedDataApiServer).VerifyTypedData(ctx, req.(*TypedDataVerificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TypedDataApi_ServiceDesc is the grpc.ServiceDesc for TypedDataApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TypedDataApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gowaves.node.grpc.TypedDataApi",
+	HandlerType: (*TypedDataApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "HashTypedData",
+			Handler:    _TypedDataApi_HashTypedData_Handler,
+		},
+		{
+			MethodName: "VerifyTypedData",
+			Handler:    _TypedDataApi_VerifyTypedData_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gowaves/node/grpc/typed_data_api.proto",
+}
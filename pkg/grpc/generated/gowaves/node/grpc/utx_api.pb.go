@@ -0,0 +1,420 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: gowaves/node/grpc/utx_api.proto
+
+package grpc
+
+import (
+	waves "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UtxEvent_Type int32
+
+const (
+	UtxEvent_ADDED   UtxEvent_Type = 0
+	UtxEvent_REMOVED UtxEvent_Type = 1
+)
+
+// Enum value maps for UtxEvent_Type.
+var (
+	UtxEvent_Type_name = map[int32]string{
+		0: "ADDED",
+		1: "REMOVED",
+	}
+	UtxEvent_Type_value = map[string]int32{
+		"ADDED":   0,
+		"REMOVED": 1,
+	}
+)
+
+func (x UtxEvent_Type) Enum() *UtxEvent_Type {
+	p := new(UtxEvent_Type)
+	*p = x
+	return p
+}
+
+func (x UtxEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UtxEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_gowaves_node_grpc_utx_api_proto_enumTypes[0].Descriptor()
+}
+
+func (UtxEvent_Type) Type() protoreflect.EnumType {
+	return &file_gowaves_node_grpc_utx_api_proto_enumTypes[0]
+}
+
+func (x UtxEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UtxEvent_Type.Descriptor instead.
+func (UtxEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_utx_api_proto_rawDescGZIP(), []int{2, 0}
+}
+
+// UnconfirmedRequest is a filter of unconfirmed transactions, all set conditions must be met.
+type UnconfirmedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sender         []byte           `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Recipient      *waves.Recipient `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	TransactionIds [][]byte         `protobuf:"bytes,3,rep,name=transaction_ids,json=transactionIds,proto3" json:"transaction_ids,omitempty"`
+	Types          []int32          `protobuf:"varint,4,rep,packed,name=types,proto3" json:"types,omitempty"`
+	// Minimal fee in WAVES, fees in sponsored assets are converted by the sponsorship rate.
+	MinFee int64 `protobuf:"varint,5,opt,name=min_fee,json=minFee,proto3" json:"min_fee,omitempty"`
+}
+
+func (x *UnconfirmedRequest) Reset() {
+	*x = UnconfirmedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_utx_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnconfirmedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnconfirmedRequest) ProtoMessage() {}
+
+func (x *UnconfirmedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_utx_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnconfirmedRequest.ProtoReflect.Descriptor instead.
+func (*UnconfirmedRequest) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_utx_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UnconfirmedRequest) GetSender() []byte {
+	if x != nil {
+		return x.Sender
+	}
+	return nil
+}
+
+func (x *UnconfirmedRequest) GetRecipient() *waves.Recipient {
+	if x != nil {
+		return x.Recipient
+	}
+	return nil
+}
+
+func (x *UnconfirmedRequest) GetTransactionIds() [][]byte {
+	if x != nil {
+		return x.TransactionIds
+	}
+	return nil
+}
+
+func (x *UnconfirmedRequest) GetTypes() []int32 {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *UnconfirmedRequest) GetMinFee() int64 {
+	if x != nil {
+		return x.MinFee
+	}
+	return 0
+}
+
+type UnconfirmedTransaction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          []byte                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Transaction *waves.SignedTransaction `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+}
+
+func (x *UnconfirmedTransaction) Reset() {
+	*x = UnconfirmedTransaction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_utx_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnconfirmedTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnconfirmedTransaction) ProtoMessage() {}
+
+func (x *UnconfirmedTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_utx_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnconfirmedTransaction.ProtoReflect.Descriptor instead.
+func (*UnconfirmedTransaction) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_utx_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UnconfirmedTransaction) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *UnconfirmedTransaction) GetTransaction() *waves.SignedTransaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type UtxEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type        UtxEvent_Type           `protobuf:"varint,1,opt,name=type,proto3,enum=gowaves.node.grpc.UtxEvent_Type" json:"type,omitempty"`
+	Transaction *UnconfirmedTransaction `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+}
+
+func (x *UtxEvent) Reset() {
+	*x = UtxEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gowaves_node_grpc_utx_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UtxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UtxEvent) ProtoMessage() {}
+
+func (x *UtxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_gowaves_node_grpc_utx_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UtxEvent.ProtoReflect.Descriptor instead.
+func (*UtxEvent) Descriptor() ([]byte, []int) {
+	return file_gowaves_node_grpc_utx_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UtxEvent) GetType() UtxEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return UtxEvent_ADDED
+}
+
+func (x *UtxEvent) GetTransaction() *UnconfirmedTransaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+var File_gowaves_node_grpc_utx_api_proto protoreflect.FileDescriptor
+
+var file_gowaves_node_grpc_utx_api_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x75, 0x74, 0x78, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x11, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x1a, 0x15, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x77, 0x61, 0x76,
+	0x65, 0x73, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb4, 0x01, 0x0a, 0x12, 0x55, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x52,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x05, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x46, 0x65, 0x65, 0x22, 0x64, 0x0a, 0x16, 0x55,
+	0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3a, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x77, 0x61, 0x76,
+	0x65, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0xad, 0x01, 0x0a, 0x08, 0x55, 0x74, 0x78, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x34,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x67,
+	0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x55, 0x74, 0x78, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x4b, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x67, 0x6f, 0x77, 0x61,
+	0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x1e, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x44, 0x44,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10,
+	0x01, 0x32, 0xcd, 0x01, 0x0a, 0x06, 0x55, 0x74, 0x78, 0x41, 0x70, 0x69, 0x12, 0x65, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x55, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x12,
+	0x25, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73,
+	0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x30, 0x01, 0x12, 0x5c, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x55, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x12, 0x25, 0x2e, 0x67, 0x6f,
+	0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x55, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73, 0x2e, 0x6e, 0x6f, 0x64,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x74, 0x78, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30,
+	0x01, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x77, 0x61, 0x76, 0x65, 0x73, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2f, 0x67, 0x6f,
+	0x77, 0x61, 0x76, 0x65, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x67,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x77, 0x61, 0x76, 0x65, 0x73,
+	0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_gowaves_node_grpc_utx_api_proto_rawDescOnce sync.Once
+	file_gowaves_node_grpc_utx_api_proto_rawDescData = file_gowaves_node_grpc_utx_api_proto_rawDesc
+)
+
+func file_gowaves_node_grpc_utx_api_proto_rawDescGZIP() []byte {
+	file_gowaves_node_grpc_utx_api_proto_rawDescOnce.Do(func() {
+		file_gowaves_node_grpc_utx_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_gowaves_node_grpc_utx_api_proto_rawDescData)
+	})
+	return file_gowaves_node_grpc_utx_api_proto_rawDescData
+}
+
+var file_gowaves_node_grpc_utx_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_gowaves_node_grpc_utx_api_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_gowaves_node_grpc_utx_api_proto_goTypes = []interface{}{
+	(UtxEvent_Type)(0),              // 0: gowaves.node.grpc.UtxEvent.Type
+	(*UnconfirmedRequest)(nil),      // 1: gowaves.node.grpc.UnconfirmedRequest
+	(*UnconfirmedTransaction)(nil),  // 2: gowaves.node.grpc.UnconfirmedTransaction
+	(*UtxEvent)(nil),                // 3: gowaves.node.grpc.UtxEvent
+	(*waves.Recipient)(nil),         // 4: waves.Recipient
+	(*waves.SignedTransaction)(nil), // 5: waves.SignedTransaction
+}
+var file_gowaves_node_grpc_utx_api_proto_depIdxs = []int32{
+	4, // 0: gowaves.node.grpc.UnconfirmedRequest.recipient:type_name -> waves.Recipient
+	5, // 1: gowaves.node.grpc.UnconfirmedTransaction.transaction:type_name -> waves.SignedTransaction
+	0, // 2: gowaves.node.grpc.UtxEvent.type:type_name -> gowaves.node.grpc.UtxEvent.Type
+	2, // 3: gowaves.node.grpc.UtxEvent.transaction:type_name -> gowaves.node.grpc.UnconfirmedTransaction
+	1, // 4: gowaves.node.grpc.UtxApi.ListUnconfirmed:input_type -> gowaves.node.grpc.UnconfirmedRequest
+	1, // 5: gowaves.node.grpc.UtxApi.SubscribeUnconfirmed:input_type -> gowaves.node.grpc.UnconfirmedRequest
+	2, // 6: gowaves.node.grpc.UtxApi.ListUnconfirmed:output_type -> gowaves.node.grpc.UnconfirmedTransaction
+	3, // 7: gowaves.node.grpc.UtxApi.SubscribeUnconfirmed:output_type -> gowaves.node.grpc.UtxEvent
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_gowaves_node_grpc_utx_api_proto_init() }
+func file_gowaves_node_grpc_utx_api_proto_init() {
+	if File_gowaves_node_grpc_utx_api_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gowaves_node_grpc_utx_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnconfirmedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_utx_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnconfirmedTransaction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gowaves_node_grpc_utx_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UtxEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gowaves_node_grpc_utx_api_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gowaves_node_grpc_utx_api_proto_goTypes,
+		DependencyIndexes: file_gowaves_node_grpc_utx_api_proto_depIdxs,
+		EnumInfos:         file_gowaves_node_grpc_utx_api_proto_enumTypes,
+		MessageInfos:      file_gowaves_node_grpc_utx_api_proto_msgTypes,
+	}.Build()
+	File_gowaves_node_grpc_utx_api_proto = out.File
+	file_gowaves_node_grpc_utx_api_proto_rawDesc = nil
+	file_gowaves_node_grpc_utx_api_proto_goTypes = nil
+	file_gowaves_node_grpc_utx_api_proto_depIdxs = nil
+}
@@ -0,0 +1,199 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: gowaves/node/grpc/utx_api.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// UtxApiClient is the client API for UtxApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UtxApiClient interface {
+	// Returns unconfirmed transactions matching the filter, transactions with higher priority go first.
+	ListUnconfirmed(ctx context.Context, in *UnconfirmedRequest, opts ...grpc.CallOption) (UtxApi_ListUnconfirmedClient, error)
+	// Sends the current content of the pool as ADDED events and then streams additions and removals
+	// of transactions matching the filter.
+	SubscribeUnconfirmed(ctx context.Context, in *UnconfirmedRequest, opts ...grpc.CallOption) (UtxApi_SubscribeUnconfirmedClient, error)
+}
+
+type utxApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUtxApiClient(cc grpc.ClientConnInterface) UtxApiClient {
+	return &utxApiClient{cc}
+}
+
+func (c *utxApiClient) ListUnconfirmed(ctx context.Context, in *UnconfirmedRequest, opts ...grpc.CallOption) (UtxApi_ListUnconfirmedClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UtxApi_ServiceDesc.Streams[0], "/gowaves.node.grpc.UtxApi/ListUnconfirmed", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &utxApiListUnconfirmedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UtxApi_ListUnconfirmedClient interface {
+	Recv() (*UnconfirmedTransaction, error)
+	grpc.ClientStream
+}
+
+type utxApiListUnconfirmedClient struct {
+	grpc.ClientStream
+}
+
+func (x *utxApiListUnconfirmedClient) Recv() (*UnconfirmedTransaction, error) {
+	m := new(UnconfirmedTransaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *utxApiClient) SubscribeUnconfirmed(ctx context.Context, in *UnconfirmedRequest, opts ...grpc.CallOption) (UtxApi_SubscribeUnconfirmedClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UtxApi_ServiceDesc.Streams[1], "/gowaves.node.grpc.UtxApi/SubscribeUnconfirmed", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &utxApiSubscribeUnconfirmedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UtxApi_SubscribeUnconfirmedClient interface {
+	Recv() (*UtxEvent, error)
+	grpc.ClientStream
+}
+
+type utxApiSubscribeUnconfirmedClient struct {
+	grpc.ClientStream
+}
+
+func (x *utxApiSubscribeUnconfirmedClient) Recv() (*UtxEvent, error) {
+	m := new(UtxEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UtxApiServer is the server API for UtxApi service.
+// All implementations should embed UnimplementedUtxApiServer
+// for forward compatibility
+type UtxApiServer interface {
+	// Returns unconfirmed transactions matching the filter, transactions with higher priority go first.
+	ListUnconfirmed(*UnconfirmedRequest, UtxApi_ListUnconfirmedServer) error
+	// Sends the current content of the pool as ADDED events and then streams additions and removals
+	// of transactions matching the filter.
+	SubscribeUnconfirmed(*UnconfirmedRequest, UtxApi_SubscribeUnconfirmedServer) error
+}
+
+// UnimplementedUtxApiServer should be embedded to have forward compatible implementations.
+type UnimplementedUtxApiServer struct {
+}
+
+func (UnimplementedUtxApiServer) ListUnconfirmed(*UnconfirmedRequest, UtxApi_ListUnconfirmedServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListUnconfirmed not implemented")
+}
+func (UnimplementedUtxApiServer) SubscribeUnconfirmed(*UnconfirmedRequest, UtxApi_SubscribeUnconfirmedServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeUnconfirmed not implemented")
+}
+
+// UnsafeUtxApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UtxApiServer will
+// result in compilation errors.
+type UnsafeUtxApiServer interface {
+	mustEmbedUnimplementedUtxApiServer()
+}
+
+func RegisterUtxApiServer(s grpc.ServiceRegistrar, srv UtxApiServer) {
+	s.RegisterService(&UtxApi_ServiceDesc, srv)
+}
+
+func _UtxApi_ListUnconfirmed_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UnconfirmedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UtxApiServer).ListUnconfirmed(m, &utxApiListUnconfirmedServer{stream})
+}
+
+type UtxApi_ListUnconfirmedServer interface {
+	Send(*UnconfirmedTransaction) error
+	grpc.ServerStream
+}
+
+type utxApiListUnconfirmedServer struct {
+	grpc.ServerStream
+}
+
+func (x *utxApiListUnconfirmedServer) Send(m *UnconfirmedTransaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _UtxApi_SubscribeUnconfirmed_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UnconfirmedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UtxApiServer).SubscribeUnconfirmed(m, &utxApiSubscribeUnconfirmedServer{stream})
+}
+
+type UtxApi_SubscribeUnconfirmedServer interface {
+	Send(*UtxEvent) error
+	grpc.ServerStream
+}
+
+type utxApiSubscribeUnconfirmedServer struct {
+	grpc.ServerStream
+}
+
+func (x *utxApiSubscribeUnconfirmedServer) Send(m *UtxEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// UtxApi_ServiceDesc is the grpc.ServiceDesc for UtxApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UtxApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gowaves.node.grpc.UtxApi",
+	HandlerType: (*UtxApiServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListUnconfirmed",
+			Handler:       _UtxApi_ListUnconfirmed_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeUnconfirmed",
+			Handler:       _UtxApi_SubscribeUnconfirmed_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gowaves/node/grpc/utx_api.proto",
+}
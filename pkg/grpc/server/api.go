@@ -1,6 +1,10 @@
 package server
 
-import "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
+import (
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	eg "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
+)
 
 type GrpcHandlers interface {
 	grpc.AccountsApiServer
@@ -8,4 +12,10 @@ type GrpcHandlers interface {
 	grpc.BlockchainApiServer
 	grpc.BlocksApiServer
 	grpc.TransactionsApiServer
+	eg.BlockchainUpdatesApiServer
+	gg.UtxApiServer
+	gg.ConsensusApiServer
+	gg.TransactionsProofApiServer
+	gg.PeersApiServer
+	gg.TypedDataApiServer
 }
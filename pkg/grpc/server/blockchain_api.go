@@ -4,77 +4,36 @@ import (
 	"context"
 
 	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
-	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/services"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-// allFeatures combines blockchain features from state with features
-// which are defined in `settings`.
-func (s *Server) allFeatures() ([]int16, error) {
-	total := make(map[int16]bool)
-	blockchainFeatures, err := s.state.AllFeatures()
-	if err != nil {
-		return nil, err
-	}
-	for _, id := range blockchainFeatures {
-		total[id] = true
-	}
-	for id := range settings.FeaturesInfo {
-		total[int16(id)] = true
-	}
-	res := make([]int16, len(total))
-	i := 0
-	for id := range total {
-		res[i] = id
-		i++
-	}
-	return res, nil
-}
-
-func (s *Server) nodeStatusFromBool(implemented bool) g.FeatureActivationStatus_NodeFeatureStatus {
-	if implemented {
-		return g.FeatureActivationStatus_IMPLEMENTED
-	}
-	return g.FeatureActivationStatus_NOT_IMPLEMENTED
-}
-
-// featureActivationStatus retrieves all the info for given feature ID.
-func (s *Server) featureActivationStatus(id int16, height uint64) (*g.FeatureActivationStatus, error) {
-	res := &g.FeatureActivationStatus{Id: int32(id)}
-	res.NodeStatus = g.FeatureActivationStatus_NOT_IMPLEMENTED
-	info, ok := settings.FeaturesInfo[settings.Feature(id)]
-	if ok {
-		res.NodeStatus = s.nodeStatusFromBool(info.Implemented)
-		res.Description = info.Description
-	}
-	activated, err := s.state.IsActiveAtHeight(id, height)
-	if err != nil {
-		return nil, err
-	}
-	approved, err := s.state.IsApprovedAtHeight(id, height)
-	if err != nil {
-		return nil, err
-	}
-	if activated {
-		height, err := s.state.ActivationHeight(id)
-		if err != nil {
-			return nil, err
-		}
-		res.ActivationHeight = int32(height)
+func featureActivationStatusToProtobuf(f services.FeatureActivationStatus) *g.FeatureActivationStatus {
+	res := &g.FeatureActivationStatus{
+		Id:               int32(f.ID),
+		Description:      f.Description,
+		ActivationHeight: int32(f.ActivationHeight),
+		SupportingBlocks: int32(f.SupportingBlocks),
+	}
+	switch f.BlockchainStatus {
+	case services.FeatureActivated:
 		res.BlockchainStatus = g.FeatureActivationStatus_ACTIVATED
-	} else if approved {
+	case services.FeatureApproved:
 		res.BlockchainStatus = g.FeatureActivationStatus_APPROVED
-	} else {
+	default:
 		res.BlockchainStatus = g.FeatureActivationStatus_UNDEFINED
 	}
-	supportingBlocks, err := s.state.VotesNumAtHeight(id, height)
-	if err != nil {
-		return nil, err
+	switch f.NodeStatus {
+	case services.FeatureVoted:
+		res.NodeStatus = g.FeatureActivationStatus_VOTED
+	case services.FeatureImplemented:
+		res.NodeStatus = g.FeatureActivationStatus_IMPLEMENTED
+	default:
+		res.NodeStatus = g.FeatureActivationStatus_NOT_IMPLEMENTED
 	}
-	res.SupportingBlocks = int32(supportingBlocks)
-	return res, nil
+	return res
 }
 
 func (s *Server) GetActivationStatus(ctx context.Context, req *g.ActivationStatusRequest) (*g.ActivationStatusResponse, error) {
@@ -85,26 +44,19 @@ func (s *Server) GetActivationStatus(ctx context.Context, req *g.ActivationStatu
 	if req.Height > int32(height) {
 		return nil, status.Errorf(codes.FailedPrecondition, "requested height exceeds current height")
 	}
-	reqHeight := uint64(req.Height)
-	res := &g.ActivationStatusResponse{Height: req.Height}
-	sets, err := s.state.BlockchainSettings()
+	as, err := services.FeaturesActivationStatus(s.state, uint64(req.Height), s.services.VoteFeatures)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
-	res.VotingInterval = int32(sets.ActivationWindowSize(reqHeight))
-	res.VotingThreshold = int32(sets.VotesForFeatureElection(reqHeight))
-	prevCheck := reqHeight - (reqHeight % uint64(res.VotingInterval))
-	res.NextCheck = int32(prevCheck) + res.VotingInterval
-	features, err := s.allFeatures()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+	res := &g.ActivationStatusResponse{
+		Height:          req.Height,
+		VotingInterval:  int32(as.VotingInterval),
+		VotingThreshold: int32(as.VotingThreshold),
+		NextCheck:       int32(as.NextCheck),
+		Features:        make([]*g.FeatureActivationStatus, len(as.Features)),
 	}
-	res.Features = make([]*g.FeatureActivationStatus, len(features))
-	for i, id := range features {
-		res.Features[i], err = s.featureActivationStatus(id, reqHeight)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, err.Error())
-		}
+	for i, f := range as.Features {
+		res.Features[i] = featureActivationStatusToProtobuf(f)
 	}
 	return res, nil
 }
@@ -5,10 +5,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -63,3 +66,37 @@ func TestGetCumulativeScore(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, resultBytes, res.Score)
 }
+
+func TestGetActivationStatus(t *testing.T) {
+	params := defaultStateParams()
+	st := newTestState(t, true, params, settings.MainNetSettings)
+	ctx := withAutoCancel(t, context.Background())
+	sch := createTestNetWallet(t)
+	err := server.initServer(st, nil, sch)
+	assert.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+
+	cl := g.NewBlockchainApiClient(conn)
+
+	res, err := cl.GetActivationStatus(ctx, &g.ActivationStatusRequest{Height: 1})
+	require.NoError(t, err)
+	sets := settings.MainNetSettings
+	assert.Equal(t, int32(sets.FeaturesVotingPeriod), res.VotingInterval)
+	assert.Equal(t, int32(sets.VotesForFeatureActivation), res.VotingThreshold)
+	assert.Equal(t, int32(sets.FeaturesVotingPeriod), res.NextCheck)
+	require.Len(t, res.Features, len(settings.FeaturesInfo))
+	for i, f := range res.Features {
+		if i > 0 {
+			assert.Less(t, res.Features[i-1].Id, f.Id)
+		}
+		info := settings.FeaturesInfo[settings.Feature(f.Id)]
+		assert.Equal(t, info.Description, f.Description)
+		assert.Equal(t, g.FeatureActivationStatus_UNDEFINED, f.BlockchainStatus)
+		assert.Zero(t, f.ActivationHeight)
+		assert.Zero(t, f.SupportingBlocks)
+	}
+
+	_, err = cl.GetActivationStatus(ctx, &g.ActivationStatusRequest{Height: 2})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,1324 @@
+package server
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/errs"
+	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+type assetBalanceKey struct {
+	address proto.WavesAddress
+	asset   crypto.Digest
+}
+
+type dataEntryKey struct {
+	address proto.WavesAddress
+	key     string
+}
+
+// updatedKeys is a set of state entries that could have been changed by a block.
+// Addresses are used both for Waves balances and for leasing balances.
+type updatedKeys struct {
+	addresses     map[proto.WavesAddress]struct{}
+	assetBalances map[assetBalanceKey]struct{}
+	dataEntries   map[dataEntryKey]struct{}
+	assets        map[crypto.Digest]struct{}
+	leases        map[crypto.Digest]struct{}
+}
+
+func newUpdatedKeys() *updatedKeys {
+	return &updatedKeys{
+		addresses:     make(map[proto.WavesAddress]struct{}),
+		assetBalances: make(map[assetBalanceKey]struct{}),
+		dataEntries:   make(map[dataEntryKey]struct{}),
+		assets:        make(map[crypto.Digest]struct{}),
+		leases:        make(map[crypto.Digest]struct{}),
+	}
+}
+
+func (k *updatedKeys) sortedAddresses() []proto.WavesAddress {
+	res := make([]proto.WavesAddress, 0, len(k.addresses))
+	for a := range k.addresses {
+		res = append(res, a)
+	}
+	sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i][:], res[j][:]) < 0 })
+	return res
+}
+
+func (k *updatedKeys) sortedAssetBalances() []assetBalanceKey {
+	res := make([]assetBalanceKey, 0, len(k.assetBalances))
+	for ab := range k.assetBalances {
+		res = append(res, ab)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if c := bytes.Compare(res[i].address[:], res[j].address[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(res[i].asset[:], res[j].asset[:]) < 0
+	})
+	return res
+}
+
+func (k *updatedKeys) sortedDataEntries() []dataEntryKey {
+	res := make([]dataEntryKey, 0, len(k.dataEntries))
+	for de := range k.dataEntries {
+		res = append(res, de)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if c := bytes.Compare(res[i].address[:], res[j].address[:]); c != 0 {
+			return c < 0
+		}
+		return res[i].key < res[j].key
+	})
+	return res
+}
+
+func sortedDigests(m map[crypto.Digest]struct{}) []crypto.Digest {
+	res := make([]crypto.Digest, 0, len(m))
+	for d := range m {
+		res = append(res, d)
+	}
+	sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i][:], res[j][:]) < 0 })
+	return res
+}
+
+// updatesBuilder builds BlockchainUpdates events.
+// The set of changed state entries is determined by transactions of a block and by the results of their
+// invocations (the latter is only available if the state provides extended API).
+// Values of the entries are taken from the state histories, so updates can be built only for the heights that
+// are not older than the minimal rollback height of the state.
+type updatesBuilder struct {
+	st     state.StateInfo
+	scheme proto.Scheme
+	keys   *updatedKeys
+	height proto.Height // Height at which the collected transactions are stored in state.
+}
+
+func newUpdatesBuilder(st state.StateInfo, scheme proto.Scheme, height proto.Height) *updatesBuilder {
+	return &updatesBuilder{st: st, scheme: scheme, keys: newUpdatedKeys(), height: height}
+}
+
+func (b *updatesBuilder) addBalance(addr proto.WavesAddress, asset proto.OptionalAsset) {
+	if asset.Present {
+		b.keys.assetBalances[assetBalanceKey{address: addr, asset: asset.ID}] = struct{}{}
+		return
+	}
+	b.keys.addresses[addr] = struct{}{}
+}
+
+func (b *updatesBuilder) addPublicKeyBalance(pk crypto.PublicKey, asset proto.OptionalAsset) error {
+	addr, err := proto.NewAddressFromPublicKey(b.scheme, pk)
+	if err != nil {
+		return err
+	}
+	b.addBalance(addr, asset)
+	return nil
+}
+
+func (b *updatesBuilder) recipientToAddress(rcp proto.Recipient) (proto.WavesAddress, error) {
+	if addr := rcp.Address(); addr != nil {
+		return *addr, nil
+	}
+	if alias := rcp.Alias(); alias != nil {
+		return b.st.AddrByAlias(*alias)
+	}
+	return proto.WavesAddress{}, errors.New("empty recipient")
+}
+
+func (b *updatesBuilder) addRecipientBalance(rcp proto.Recipient, asset proto.OptionalAsset) error {
+	addr, err := b.recipientToAddress(rcp)
+	if err != nil {
+		return err
+	}
+	b.addBalance(addr, asset)
+	return nil
+}
+
+// addFee adds balances affected by the fee payment.
+// Sponsored fee also changes balances of the sponsor.
+func (b *updatesBuilder) addFee(sender proto.WavesAddress, feeAsset proto.OptionalAsset) error {
+	b.addBalance(sender, feeAsset)
+	if !feeAsset.Present {
+		return nil
+	}
+	info, err := b.st.AssetInfo(proto.AssetIDFromDigest(feeAsset.ID))
+	if err != nil {
+		return err
+	}
+	b.addBalance(info.Issuer, feeAsset)
+	b.addBalance(info.Issuer, proto.NewOptionalAssetWaves())
+	return nil
+}
+
+func (b *updatesBuilder) addLease(leaseID crypto.Digest) error {
+	b.keys.leases[leaseID] = struct{}{}
+	info, err := b.st.LeasingInfoAtHeight(leaseID, b.height)
+	if err != nil {
+		return err
+	}
+	b.keys.addresses[info.Sender] = struct{}{}
+	b.keys.addresses[info.Recipient] = struct{}{}
+	return nil
+}
+
+func (b *updatesBuilder) addBlock(block *proto.Block) error {
+	generator, err := proto.NewAddressFromPublicKey(b.scheme, block.GeneratorPublicKey)
+	if err != nil {
+		return err
+	}
+	b.keys.addresses[generator] = struct{}{}
+	sets, err := b.st.BlockchainSettings()
+	if err != nil {
+		return err
+	}
+	for _, a := range sets.RewardAddresses {
+		b.keys.addresses[a] = struct{}{}
+	}
+	for _, a := range sets.RewardAddressesAfter21 {
+		b.keys.addresses[a] = struct{}{}
+	}
+	for _, tx := range block.Transactions {
+		if err := b.addTransaction(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *updatesBuilder) addTransaction(tx proto.Transaction) error {
+	id, err := tx.GetID(b.scheme)
+	if err != nil {
+		return err
+	}
+	waves := proto.NewOptionalAssetWaves()
+	switch t := tx.(type) {
+	case *proto.Genesis:
+		b.addBalance(t.Recipient, waves)
+		return nil
+	case *proto.Payment:
+		if err := b.addPublicKeyBalance(t.SenderPK, waves); err != nil {
+			return err
+		}
+		b.addBalance(t.Recipient, waves)
+		return nil
+	case *proto.EthereumTransaction:
+		return b.addEthereumTransaction(t, id)
+	}
+	senderAddr, err := tx.GetSender(b.scheme)
+	if err != nil {
+		return err
+	}
+	sender, err := senderAddr.ToWavesAddress(b.scheme)
+	if err != nil {
+		return err
+	}
+	b.addBalance(sender, waves)
+	switch t := tx.(type) {
+	case *proto.IssueWithSig:
+		return b.addIssue(sender, id)
+	case *proto.IssueWithProofs:
+		return b.addIssue(sender, id)
+	case *proto.TransferWithSig:
+		return b.addTransfer(sender, &t.Transfer)
+	case *proto.TransferWithProofs:
+		return b.addTransfer(sender, &t.Transfer)
+	case *proto.ReissueWithSig:
+		b.addAssetChange(sender, t.AssetID)
+	case *proto.ReissueWithProofs:
+		b.addAssetChange(sender, t.AssetID)
+	case *proto.BurnWithSig:
+		b.addAssetChange(sender, t.AssetID)
+	case *proto.BurnWithProofs:
+		b.addAssetChange(sender, t.AssetID)
+	case proto.Exchange:
+		return b.addExchange(t)
+	case *proto.LeaseWithSig:
+		return b.addLeaseTransaction(id)
+	case *proto.LeaseWithProofs:
+		return b.addLeaseTransaction(id)
+	case *proto.LeaseCancelWithSig:
+		return b.addLease(t.LeaseID)
+	case *proto.LeaseCancelWithProofs:
+		return b.addLease(t.LeaseID)
+	case *proto.MassTransferWithProofs:
+		b.addBalance(sender, t.Asset)
+		for _, e := range t.Transfers {
+			if err := b.addRecipientBalance(e.Recipient, t.Asset); err != nil {
+				return err
+			}
+		}
+	case *proto.DataWithProofs:
+		for _, e := range t.Entries {
+			b.keys.dataEntries[dataEntryKey{address: sender, key: e.GetKey()}] = struct{}{}
+		}
+	case *proto.SponsorshipWithProofs:
+		b.keys.assets[t.AssetID] = struct{}{}
+	case *proto.SetAssetScriptWithProofs:
+		b.keys.assets[t.AssetID] = struct{}{}
+	case *proto.UpdateAssetInfoWithProofs:
+		b.keys.assets[t.AssetID] = struct{}{}
+		return b.addFee(sender, t.FeeAsset)
+	case *proto.InvokeScriptWithProofs:
+		if err := b.addFee(sender, t.FeeAsset); err != nil {
+			return err
+		}
+		dApp, err := b.recipientToAddress(t.ScriptRecipient)
+		if err != nil {
+			return err
+		}
+		b.addBalance(dApp, waves)
+		for _, p := range t.Payments {
+			b.addBalance(sender, p.Asset)
+			b.addBalance(dApp, p.Asset)
+		}
+		return b.addInvokeResult(dApp, id)
+	case *proto.InvokeExpressionTransactionWithProofs:
+		if err := b.addFee(sender, t.FeeAsset); err != nil {
+			return err
+		}
+		return b.addInvokeResult(sender, id)
+	}
+	return nil
+}
+
+func (b *updatesBuilder) addIssue(sender proto.WavesAddress, id []byte) error {
+	assetID, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return err
+	}
+	b.addAssetChange(sender, assetID)
+	return nil
+}
+
+func (b *updatesBuilder) addAssetChange(sender proto.WavesAddress, assetID crypto.Digest) {
+	b.keys.assets[assetID] = struct{}{}
+	b.addBalance(sender, *proto.NewOptionalAssetFromDigest(assetID))
+}
+
+func (b *updatesBuilder) addTransfer(sender proto.WavesAddress, tx *proto.Transfer) error {
+	b.addBalance(sender, tx.AmountAsset)
+	if err := b.addFee(sender, tx.FeeAsset); err != nil {
+		return err
+	}
+	return b.addRecipientBalance(tx.Recipient, tx.AmountAsset)
+}
+
+func (b *updatesBuilder) addLeaseTransaction(id []byte) error {
+	leaseID, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return err
+	}
+	return b.addLease(leaseID)
+}
+
+func (b *updatesBuilder) addExchange(tx proto.Exchange) error {
+	matcher, err := proto.NewAddressFromPublicKey(b.scheme, tx.GetSenderPK())
+	if err != nil {
+		return err
+	}
+	for _, o := range []proto.Order{tx.GetOrder1(), tx.GetOrder2()} {
+		senderAddr, err := o.GetSender(b.scheme)
+		if err != nil {
+			return err
+		}
+		sender, err := senderAddr.ToWavesAddress(b.scheme)
+		if err != nil {
+			return err
+		}
+		pair := o.GetAssetPair()
+		for _, a := range []proto.OptionalAsset{pair.AmountAsset, pair.PriceAsset, o.GetMatcherFeeAsset()} {
+			b.addBalance(sender, a)
+			b.addBalance(matcher, a)
+		}
+	}
+	return nil
+}
+
+func (b *updatesBuilder) addEthereumTransaction(tx *proto.EthereumTransaction, id []byte) error {
+	sender, err := tx.WavesAddressFrom(b.scheme)
+	if err != nil {
+		return err
+	}
+	waves := proto.NewOptionalAssetWaves()
+	b.addBalance(sender, waves)
+	switch kind := tx.TxKind.(type) {
+	case *proto.EthereumTransferWavesTxKind:
+		to, err := tx.WavesAddressTo(b.scheme)
+		if err != nil {
+			return err
+		}
+		b.addBalance(to, waves)
+	case *proto.EthereumTransferAssetsErc20TxKind:
+		to, err := proto.EthereumAddress(kind.Arguments.Recipient).ToWavesAddress(b.scheme)
+		if err != nil {
+			return err
+		}
+		b.addBalance(sender, kind.Asset)
+		b.addBalance(to, kind.Asset)
+	case *proto.EthereumInvokeScriptTxKind:
+		dApp, err := tx.WavesAddressTo(b.scheme)
+		if err != nil {
+			return err
+		}
+		b.addBalance(dApp, waves)
+		for _, p := range kind.DecodedData().Payments {
+			asset := proto.NewOptionalAsset(p.PresentAssetID, p.AssetID)
+			b.addBalance(sender, asset)
+			b.addBalance(dApp, asset)
+		}
+		return b.addInvokeResult(dApp, id)
+	}
+	return nil
+}
+
+func (b *updatesBuilder) actionSender(dApp proto.WavesAddress, pk *crypto.PublicKey) (proto.WavesAddress, error) {
+	if pk == nil {
+		return dApp, nil
+	}
+	return proto.NewAddressFromPublicKey(b.scheme, *pk)
+}
+
+// addInvokeResult adds entries changed by the actions of the invocation.
+// Invocation results are stored only if the state provides extended API, otherwise nothing is added.
+func (b *updatesBuilder) addInvokeResult(dApp proto.WavesAddress, id []byte) error {
+	ok, err := b.st.ProvidesExtendedApi()
+	if err != nil || !ok {
+		return err
+	}
+	invokeID, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return err
+	}
+	res, err := b.st.InvokeResultByID(invokeID)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return b.addScriptResult(dApp, res)
+}
+
+// addScriptResult adds entries changed by the actions of the dApp and by the dApps invoked by it.
+func (b *updatesBuilder) addScriptResult(dApp proto.WavesAddress, res *proto.ScriptResult) error {
+	for _, inv := range res.Invokes {
+		for _, p := range inv.Payments {
+			b.addBalance(dApp, p.Asset)
+			b.addBalance(inv.DApp, p.Asset)
+		}
+		if err := b.addScriptResult(inv.DApp, inv.Result); err != nil {
+			return err
+		}
+	}
+	for _, a := range res.DataEntries {
+		sender, err := b.actionSender(dApp, a.Sender)
+		if err != nil {
+			return err
+		}
+		b.keys.dataEntries[dataEntryKey{address: sender, key: a.Entry.GetKey()}] = struct{}{}
+	}
+	for _, a := range res.Transfers {
+		sender, err := b.actionSender(dApp, a.Sender)
+		if err != nil {
+			return err
+		}
+		b.addBalance(sender, a.Asset)
+		if err := b.addRecipientBalance(a.Recipient, a.Asset); err != nil {
+			return err
+		}
+	}
+	for _, a := range res.Issues {
+		sender, err := b.actionSender(dApp, a.Sender)
+		if err != nil {
+			return err
+		}
+		b.addAssetChange(sender, a.ID)
+	}
+	for _, a := range res.Reissues {
+		sender, err := b.actionSender(dApp, a.Sender)
+		if err != nil {
+			return err
+		}
+		b.addAssetChange(sender, a.AssetID)
+	}
+	for _, a := range res.Burns {
+		sender, err := b.actionSender(dApp, a.Sender)
+		if err != nil {
+			return err
+		}
+		b.addAssetChange(sender, a.AssetID)
+	}
+	for _, a := range res.Sponsorships {
+		b.keys.assets[a.AssetID] = struct{}{}
+	}
+	for _, a := range res.Leases {
+		if err := b.addLease(a.ID); err != nil {
+			return err
+		}
+	}
+	for _, a := range res.LeaseCancels {
+		if err := b.addLease(a.LeaseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stateUpdate compares values of the collected entries at heights `from` and `to` and returns the changed ones.
+// Zero height stands for the empty state before genesis block.
+func (b *updatesBuilder) stateUpdate(from, to proto.Height) (*events.StateUpdate, error) {
+	su := &events.StateUpdate{}
+	for _, addr := range b.keys.sortedAddresses() {
+		before, err := b.wavesProfile(addr, from)
+		if err != nil {
+			return nil, err
+		}
+		after, err := b.wavesProfile(addr, to)
+		if err != nil {
+			return nil, err
+		}
+		if before.Balance != after.Balance {
+			su.Balances = append(su.Balances, &events.StateUpdate_BalanceUpdate{
+				Address:      addr.Bytes(),
+				AmountAfter:  &g.Amount{Amount: int64(after.Balance)},
+				AmountBefore: int64(before.Balance),
+			})
+		}
+		if before.LeaseIn != after.LeaseIn || before.LeaseOut != after.LeaseOut {
+			su.LeasingForAddress = append(su.LeasingForAddress, &events.StateUpdate_LeasingUpdate{
+				Address:   addr.Bytes(),
+				InAfter:   after.LeaseIn,
+				OutAfter:  after.LeaseOut,
+				InBefore:  before.LeaseIn,
+				OutBefore: before.LeaseOut,
+			})
+		}
+	}
+	for _, k := range b.keys.sortedAssetBalances() {
+		before, err := b.assetBalance(k, from)
+		if err != nil {
+			return nil, err
+		}
+		after, err := b.assetBalance(k, to)
+		if err != nil {
+			return nil, err
+		}
+		if before != after {
+			su.Balances = append(su.Balances, &events.StateUpdate_BalanceUpdate{
+				Address:      k.address.Bytes(),
+				AmountAfter:  &g.Amount{AssetId: k.asset.Bytes(), Amount: int64(after)},
+				AmountBefore: int64(before),
+			})
+		}
+	}
+	for _, k := range b.keys.sortedDataEntries() {
+		before, err := b.dataEntry(k, from)
+		if err != nil {
+			return nil, err
+		}
+		after, err := b.dataEntry(k, to)
+		if err != nil {
+			return nil, err
+		}
+		if pb.Equal(before, after) {
+			continue
+		}
+		if after == nil {
+			after = &g.DataTransactionData_DataEntry{Key: k.key} // Entry without value means removal.
+		}
+		su.DataEntries = append(su.DataEntries, &events.StateUpdate_DataEntryUpdate{
+			Address:         k.address.Bytes(),
+			DataEntry:       after,
+			DataEntryBefore: before,
+		})
+	}
+	for _, id := range sortedDigests(b.keys.assets) {
+		before, err := b.assetDetails(id, from)
+		if err != nil {
+			return nil, err
+		}
+		after, err := b.assetDetails(id, to)
+		if err != nil {
+			return nil, err
+		}
+		if pb.Equal(before, after) {
+			continue
+		}
+		su.Assets = append(su.Assets, &events.StateUpdate_AssetStateUpdate{Before: before, After: after})
+	}
+	for _, id := range sortedDigests(b.keys.leases) {
+		before, err := b.leaseInfo(id, from)
+		if err != nil {
+			return nil, err
+		}
+		after, err := b.leaseInfo(id, to)
+		if err != nil {
+			return nil, err
+		}
+		if update := leaseUpdate(id, before, after); update != nil {
+			su.IndividualLeases = append(su.IndividualLeases, update)
+		}
+	}
+	return su, nil
+}
+
+func (b *updatesBuilder) wavesProfile(addr proto.WavesAddress, height proto.Height) (*types.WavesBalanceProfile, error) {
+	if height == 0 {
+		return &types.WavesBalanceProfile{}, nil
+	}
+	return b.st.WavesBalanceProfileAtHeight(proto.NewRecipientFromAddress(addr), height)
+}
+
+func (b *updatesBuilder) assetBalance(k assetBalanceKey, height proto.Height) (uint64, error) {
+	if height == 0 {
+		return 0, nil
+	}
+	return b.st.AssetBalanceAtHeight(proto.NewRecipientFromAddress(k.address), proto.AssetIDFromDigest(k.asset), height)
+}
+
+func (b *updatesBuilder) dataEntry(k dataEntryKey, height proto.Height) (*g.DataTransactionData_DataEntry, error) {
+	if height == 0 {
+		return nil, nil
+	}
+	entry, err := b.st.RetrieveEntryAtHeight(proto.NewRecipientFromAddress(k.address), k.key, height)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entry.ToProtobuf(), nil
+}
+
+func (b *updatesBuilder) fullAssetInfo(id crypto.Digest, height proto.Height) (*proto.FullAssetInfo, error) {
+	if height == 0 {
+		return nil, nil
+	}
+	info, err := b.st.FullAssetInfoAtHeight(proto.AssetIDFromDigest(id), height)
+	if err != nil {
+		if state.IsNotFound(err) || errors.Is(err, errs.UnknownAsset{}) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return info, nil
+}
+
+func (b *updatesBuilder) assetDetails(id crypto.Digest, height proto.Height) (*events.StateUpdate_AssetDetails, error) {
+	info, err := b.fullAssetInfo(id, height)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	d := &events.StateUpdate_AssetDetails{
+		AssetId:     info.ID.Bytes(),
+		Issuer:      info.IssuerPublicKey.Bytes(),
+		Decimals:    int32(info.Decimals),
+		Name:        info.Name,
+		Description: info.Description,
+		Reissuable:  info.Reissuable,
+		Volume:      int64(info.Quantity),
+		Sponsorship: int64(info.SponsorshipCost),
+		Nft:         info.Quantity == 1 && info.Decimals == 0 && !info.Reissuable,
+		SafeVolume:  new(big.Int).SetUint64(info.Quantity).Bytes(),
+	}
+	if info.Scripted {
+		d.ScriptInfo = &events.StateUpdate_AssetDetails_AssetScriptInfo{
+			Script:     info.ScriptInfo.Bytes,
+			Complexity: int64(info.ScriptInfo.Complexity),
+		}
+	}
+	return d, nil
+}
+
+func (b *updatesBuilder) leaseInfo(id crypto.Digest, height proto.Height) (*proto.LeaseInfo, error) {
+	if height == 0 {
+		return nil, nil
+	}
+	info, err := b.st.LeasingInfoAtHeight(id, height)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return info, nil
+}
+
+func leaseUpdate(id crypto.Digest, before, after *proto.LeaseInfo) *events.StateUpdate_LeaseUpdate {
+	info := after
+	if info == nil {
+		info = before
+	}
+	if info == nil || (before != nil && after != nil && before.IsActive == after.IsActive) {
+		return nil
+	}
+	status := events.StateUpdate_LeaseUpdate_INACTIVE
+	if after != nil && after.IsActive {
+		status = events.StateUpdate_LeaseUpdate_ACTIVE
+	}
+	origin := id
+	if info.OriginTransactionID != nil {
+		origin = *info.OriginTransactionID
+	}
+	return &events.StateUpdate_LeaseUpdate{
+		LeaseId:             id.Bytes(),
+		StatusAfter:         status,
+		Amount:              int64(info.LeaseAmount),
+		Sender:              info.Sender.Bytes(),
+		Recipient:           info.Recipient.Bytes(),
+		OriginTransactionId: origin.Bytes(),
+	}
+}
+
+// referencedAssets returns short information about assets which balances are present in the state update.
+func (b *updatesBuilder) referencedAssets(su *events.StateUpdate, height proto.Height) ([]*events.StateUpdate_AssetInfo, error) {
+	seen := make(map[crypto.Digest]struct{})
+	var res []*events.StateUpdate_AssetInfo
+	for _, bu := range su.Balances {
+		if len(bu.AmountAfter.AssetId) == 0 {
+			continue
+		}
+		id, err := crypto.NewDigestFromBytes(bu.AmountAfter.AssetId)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		info, err := b.fullAssetInfo(id, height)
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			continue
+		}
+		res = append(res, &events.StateUpdate_AssetInfo{Id: id.Bytes(), Decimals: int32(info.Decimals), Name: info.Name})
+	}
+	return res, nil
+}
+
+func (b *updatesBuilder) transactionsMetadata(txs []proto.Transaction) ([]*events.TransactionMetadata, error) {
+	res := make([]*events.TransactionMetadata, len(txs))
+	for i, tx := range txs {
+		md, err := b.transactionMetadata(tx)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = md
+	}
+	return res, nil
+}
+
+func (b *updatesBuilder) transactionMetadata(tx proto.Transaction) (*events.TransactionMetadata, error) {
+	md := &events.TransactionMetadata{}
+	if _, ok := tx.(*proto.Genesis); ok {
+		return md, nil
+	}
+	senderAddr, err := tx.GetSender(b.scheme)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := senderAddr.ToWavesAddress(b.scheme)
+	if err != nil {
+		return nil, err
+	}
+	md.SenderAddress = sender.Bytes()
+	switch t := tx.(type) {
+	case *proto.TransferWithSig:
+		return md, b.setTransferMetadata(md, t.Recipient)
+	case *proto.TransferWithProofs:
+		return md, b.setTransferMetadata(md, t.Recipient)
+	case *proto.LeaseWithSig:
+		return md, b.setLeaseMetadata(md, t.Recipient)
+	case *proto.LeaseWithProofs:
+		return md, b.setLeaseMetadata(md, t.Recipient)
+	case *proto.MassTransferWithProofs:
+		addresses := make([][]byte, len(t.Transfers))
+		for i, e := range t.Transfers {
+			addr, err := b.recipientToAddress(e.Recipient)
+			if err != nil {
+				return nil, err
+			}
+			addresses[i] = addr.Bytes()
+		}
+		md.Metadata = &events.TransactionMetadata_MassTransfer{
+			MassTransfer: &events.TransactionMetadata_MassTransferMetadata{RecipientsAddresses: addresses},
+		}
+	case proto.Exchange:
+		em := &events.TransactionMetadata_ExchangeMetadata{}
+		for _, o := range []proto.Order{t.GetOrder1(), t.GetOrder2()} {
+			id, err := o.GetID()
+			if err != nil {
+				return nil, err
+			}
+			addr, err := o.GetSender(b.scheme)
+			if err != nil {
+				return nil, err
+			}
+			em.OrderIds = append(em.OrderIds, id)
+			em.OrderSenderAddresses = append(em.OrderSenderAddresses, addr.Bytes())
+			em.OrderSenderPublicKeys = append(em.OrderSenderPublicKeys, o.GetSenderPKBytes())
+		}
+		md.Metadata = &events.TransactionMetadata_Exchange{Exchange: em}
+	case *proto.InvokeScriptWithProofs:
+		dApp, err := b.recipientToAddress(t.ScriptRecipient)
+		if err != nil {
+			return nil, err
+		}
+		payments := make([]*g.Amount, len(t.Payments))
+		for i, p := range t.Payments {
+			payments[i] = &g.Amount{AssetId: p.Asset.ToID(), Amount: int64(p.Amount)}
+		}
+		im, err := b.invokeMetadata(tx, dApp, t.FunctionCall.Name(), t.FunctionCall.Arguments(), payments)
+		if err != nil {
+			return nil, err
+		}
+		md.Metadata = &events.TransactionMetadata_InvokeScript{InvokeScript: im}
+	case *proto.EthereumTransaction:
+		em, err := b.ethereumMetadata(t)
+		if err != nil {
+			return nil, err
+		}
+		md.Metadata = &events.TransactionMetadata_Ethereum{Ethereum: em}
+	}
+	return md, nil
+}
+
+func (b *updatesBuilder) setTransferMetadata(md *events.TransactionMetadata, rcp proto.Recipient) error {
+	addr, err := b.recipientToAddress(rcp)
+	if err != nil {
+		return err
+	}
+	md.Metadata = &events.TransactionMetadata_Transfer{
+		Transfer: &events.TransactionMetadata_TransferMetadata{RecipientAddress: addr.Bytes()},
+	}
+	return nil
+}
+
+func (b *updatesBuilder) setLeaseMetadata(md *events.TransactionMetadata, rcp proto.Recipient) error {
+	addr, err := b.recipientToAddress(rcp)
+	if err != nil {
+		return err
+	}
+	md.Metadata = &events.TransactionMetadata_Lease{
+		Lease: &events.TransactionMetadata_LeaseMetadata{RecipientAddress: addr.Bytes()},
+	}
+	return nil
+}
+
+func (b *updatesBuilder) invokeMetadata(
+	tx proto.Transaction, dApp proto.WavesAddress, function string, args proto.Arguments, payments []*g.Amount,
+) (*events.TransactionMetadata_InvokeScriptMetadata, error) {
+	im := &events.TransactionMetadata_InvokeScriptMetadata{
+		DAppAddress:  dApp.Bytes(),
+		FunctionName: function,
+		Payments:     payments,
+	}
+	for _, arg := range args {
+		a, err := argumentToProtobuf(arg)
+		if err != nil {
+			return nil, err
+		}
+		im.Arguments = append(im.Arguments, a)
+	}
+	ok, err := b.st.ProvidesExtendedApi()
+	if err != nil || !ok {
+		return im, err
+	}
+	id, err := tx.GetID(b.scheme)
+	if err != nil {
+		return nil, err
+	}
+	invokeID, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return nil, err
+	}
+	res, err := b.st.InvokeResultByID(invokeID)
+	if err != nil {
+		if state.IsNotFound(err) {
+			return im, nil
+		}
+		return nil, err
+	}
+	if im.Result, err = res.ToProtobuf(); err != nil {
+		return nil, err
+	}
+	return im, nil
+}
+
+func (b *updatesBuilder) ethereumMetadata(tx *proto.EthereumTransaction) (*events.TransactionMetadata_EthereumMetadata, error) {
+	pk, err := tx.FromPK()
+	if err != nil {
+		return nil, err
+	}
+	em := &events.TransactionMetadata_EthereumMetadata{
+		Timestamp:       int64(tx.GetTimestamp()),
+		Fee:             int64(tx.GetFee()),
+		SenderPublicKey: pk.SerializeXYCoordinates(),
+	}
+	switch kind := tx.TxKind.(type) {
+	case *proto.EthereumTransferWavesTxKind:
+		to, err := tx.WavesAddressTo(b.scheme)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := proto.EthereumWeiToWavelet(tx.Value())
+		if err != nil {
+			return nil, err
+		}
+		em.Action = &events.TransactionMetadata_EthereumMetadata_Transfer{
+			Transfer: &events.TransactionMetadata_EthereumTransferMetadata{
+				RecipientAddress: to.Bytes(),
+				Amount:           &g.Amount{Amount: amount},
+			},
+		}
+	case *proto.EthereumTransferAssetsErc20TxKind:
+		to, err := proto.EthereumAddress(kind.Arguments.Recipient).ToWavesAddress(b.scheme)
+		if err != nil {
+			return nil, err
+		}
+		em.Action = &events.TransactionMetadata_EthereumMetadata_Transfer{
+			Transfer: &events.TransactionMetadata_EthereumTransferMetadata{
+				RecipientAddress: to.Bytes(),
+				Amount:           &g.Amount{AssetId: kind.Asset.ToID(), Amount: kind.Arguments.Amount},
+			},
+		}
+	case *proto.EthereumInvokeScriptTxKind:
+		dApp, err := tx.WavesAddressTo(b.scheme)
+		if err != nil {
+			return nil, err
+		}
+		decoded := kind.DecodedData()
+		args, err := proto.ConvertDecodedEthereumArgumentsToProtoArguments(decoded.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		payments := make([]*g.Amount, len(decoded.Payments))
+		for i, p := range decoded.Payments {
+			asset := proto.NewOptionalAsset(p.PresentAssetID, p.AssetID)
+			payments[i] = &g.Amount{AssetId: asset.ToID(), Amount: p.Amount}
+		}
+		im, err := b.invokeMetadata(tx, dApp, decoded.Name, args, payments)
+		if err != nil {
+			return nil, err
+		}
+		em.Action = &events.TransactionMetadata_EthereumMetadata_Invoke{Invoke: im}
+	}
+	return em, nil
+}
+
+func argumentToProtobuf(arg proto.Argument) (*g.InvokeScriptResult_Call_Argument, error) {
+	switch a := arg.(type) {
+	case *proto.IntegerArgument:
+		return &g.InvokeScriptResult_Call_Argument{
+			Value: &g.InvokeScriptResult_Call_Argument_IntegerValue{IntegerValue: a.Value},
+		}, nil
+	case *proto.BooleanArgument:
+		return &g.InvokeScriptResult_Call_Argument{
+			Value: &g.InvokeScriptResult_Call_Argument_BooleanValue{BooleanValue: a.Value},
+		}, nil
+	case *proto.BinaryArgument:
+		return &g.InvokeScriptResult_Call_Argument{
+			Value: &g.InvokeScriptResult_Call_Argument_BinaryValue{BinaryValue: a.Value},
+		}, nil
+	case *proto.StringArgument:
+		return &g.InvokeScriptResult_Call_Argument{
+			Value: &g.InvokeScriptResult_Call_Argument_StringValue{StringValue: a.Value},
+		}, nil
+	case *proto.ListArgument:
+		items := make([]*g.InvokeScriptResult_Call_Argument, len(a.Items))
+		for i, item := range a.Items {
+			pa, err := argumentToProtobuf(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = pa
+		}
+		return &g.InvokeScriptResult_Call_Argument{
+			Value: &g.InvokeScriptResult_Call_Argument_List_{List: &g.InvokeScriptResult_Call_Argument_List{Items: items}},
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported argument type %T", arg)
+	}
+}
+
+func transactionIDs(txs []proto.Transaction, scheme proto.Scheme) ([][]byte, error) {
+	ids := make([][]byte, len(txs))
+	for i, tx := range txs {
+		id, err := tx.GetID(scheme)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// transactionStateUpdates builds state updates of the transactions stored in state at the given height.
+// Intermediate values between the transactions of a block are not stored in state, so the entries changed by several
+// transactions of the block are reported with the values before and after the whole block.
+// If the transactions are the part of a liquid block, the values before are taken from the cumulative update `prev`
+// of the transactions that precede them.
+func transactionStateUpdates(
+	st state.StateInfo, scheme proto.Scheme, txs []proto.Transaction, height proto.Height, prev *events.StateUpdate,
+) ([]*events.StateUpdate, error) {
+	res := make([]*events.StateUpdate, len(txs))
+	for i, tx := range txs {
+		b := newUpdatesBuilder(st, scheme, height)
+		if err := b.addTransaction(tx); err != nil {
+			return nil, errors.Wrap(err, "failed to collect transaction changes")
+		}
+		su, err := b.stateUpdate(height-1, height)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build transaction state update")
+		}
+		if prev != nil {
+			su = stateUpdateDelta(prev, su)
+		}
+		res[i] = su
+	}
+	return res, nil
+}
+
+// blockAppendUpdate builds the event of appending the block stored in state at the given height.
+// The returned state update is also used to calculate updates for the following microblocks.
+func blockAppendUpdate(
+	st state.StateInfo, scheme proto.Scheme, block *proto.Block, height proto.Height,
+) (*events.BlockchainUpdated, *events.StateUpdate, error) {
+	b := newUpdatesBuilder(st, scheme, height)
+	if err := b.addBlock(block); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to collect block changes")
+	}
+	su, err := b.stateUpdate(height-1, height)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to build state update")
+	}
+	txsUpdates, err := transactionStateUpdates(st, scheme, block.Transactions, height, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	update, err := blockAppendEvent(st, scheme, block, height, su, txsUpdates)
+	if err != nil {
+		return nil, nil, err
+	}
+	return update, su, nil
+}
+
+// blockAppendUpdateWithoutState builds the event of appending the block which is older than the minimal rollback
+// height of the state. Values of the state entries at such heights are not kept, so the event contains the block
+// and its transactions only.
+func blockAppendUpdateWithoutState(
+	st state.StateInfo, scheme proto.Scheme, block *proto.Block, height proto.Height,
+) (*events.BlockchainUpdated, error) {
+	return blockAppendEvent(st, scheme, block, height, nil, nil)
+}
+
+func blockAppendEvent(
+	st state.StateInfo, scheme proto.Scheme, block *proto.Block, height proto.Height,
+	su *events.StateUpdate, txsUpdates []*events.StateUpdate,
+) (*events.BlockchainUpdated, error) {
+	b := newUpdatesBuilder(st, scheme, height)
+	pbBlock, err := block.ToProtobuf(scheme)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := transactionIDs(block.Transactions, scheme)
+	if err != nil {
+		return nil, err
+	}
+	md, err := b.transactionsMetadata(block.Transactions)
+	if err != nil {
+		return nil, err
+	}
+	var refs []*events.StateUpdate_AssetInfo
+	if su != nil {
+		if refs, err = b.referencedAssets(su, height); err != nil {
+			return nil, err
+		}
+	}
+	return &events.BlockchainUpdated{
+		Id:     block.BlockID().Bytes(),
+		Height: int32(height),
+		Update: &events.BlockchainUpdated_Append_{Append: &events.BlockchainUpdated_Append{
+			Body: &events.BlockchainUpdated_Append_Block{
+				Block: &events.BlockchainUpdated_Append_BlockAppend{Block: pbBlock},
+			},
+			TransactionIds:          ids,
+			TransactionsMetadata:    md,
+			StateUpdate:             su,
+			TransactionStateUpdates: txsUpdates,
+		}},
+		ReferencedAssets: refs,
+	}, nil
+}
+
+// blockStateUpdate returns cumulative state update of the block stored in state at the given height.
+func blockStateUpdate(st state.StateInfo, scheme proto.Scheme, block *proto.Block, height proto.Height) (*events.StateUpdate, error) {
+	b := newUpdatesBuilder(st, scheme, height)
+	if err := b.addBlock(block); err != nil {
+		return nil, errors.Wrap(err, "failed to collect block changes")
+	}
+	return b.stateUpdate(height-1, height)
+}
+
+// microBlockAppendUpdate builds the event of appending the microblock. Microblock is represented by the new
+// liquid block, which replaced the previous liquid block with the cumulative state update `prev`.
+// The microblock signature is not known at this point, so it's left empty.
+func microBlockAppendUpdate(
+	st state.StateInfo, scheme proto.Scheme, prevID proto.BlockID, prevTxCount int, prev *events.StateUpdate,
+	block *proto.Block, height proto.Height,
+) (*events.BlockchainUpdated, *events.StateUpdate, error) {
+	if prevTxCount > len(block.Transactions) {
+		return nil, nil, errors.Errorf("invalid number of transactions in block '%s'", block.BlockID().String())
+	}
+	cumulative, err := blockStateUpdate(st, scheme, block, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	txs := block.Transactions[prevTxCount:]
+	micro := &proto.MicroBlock{
+		VersionField:          byte(block.Version),
+		Reference:             prevID,
+		TotalResBlockSigField: block.BlockSignature,
+		TotalBlockID:          block.BlockID(),
+		TransactionCount:      uint32(len(txs)),
+		Transactions:          txs,
+		SenderPK:              block.GeneratorPublicKey,
+	}
+	pbMicro, err := micro.ToProtobuf(scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids, err := transactionIDs(txs, scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := newUpdatesBuilder(st, scheme, height)
+	md, err := b.transactionsMetadata(txs)
+	if err != nil {
+		return nil, nil, err
+	}
+	su := stateUpdateDelta(prev, cumulative)
+	refs, err := b.referencedAssets(su, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	txsUpdates, err := transactionStateUpdates(st, scheme, txs, height, prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &events.BlockchainUpdated{
+		Id:     block.BlockID().Bytes(),
+		Height: int32(height),
+		Update: &events.BlockchainUpdated_Append_{Append: &events.BlockchainUpdated_Append{
+			Body: &events.BlockchainUpdated_Append_MicroBlock{
+				MicroBlock: &events.BlockchainUpdated_Append_MicroBlockAppend{
+					MicroBlock:              pbMicro,
+					UpdatedTransactionsRoot: block.TransactionsRoot,
+				},
+			},
+			TransactionIds:          ids,
+			TransactionsMetadata:    md,
+			StateUpdate:             su,
+			TransactionStateUpdates: txsUpdates,
+		}},
+		ReferencedAssets: refs,
+	}, cumulative, nil
+}
+
+// rollbackUpdate builds the event of rolling back from the current state height to the given height.
+// It must be called before the rollback happens, while removed blocks are still in state.
+func rollbackUpdate(st state.StateInfo, scheme proto.Scheme, to proto.Height) (*events.BlockchainUpdated, error) {
+	current, err := st.Height()
+	if err != nil {
+		return nil, err
+	}
+	target, err := st.HeightToBlockID(to)
+	if err != nil {
+		return nil, err
+	}
+	b := newUpdatesBuilder(st, scheme, current)
+	rb := &events.BlockchainUpdated_Rollback{Type: events.BlockchainUpdated_Rollback_BLOCK}
+	for h := to + 1; h <= current; h++ {
+		block, err := st.BlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.addBlock(block); err != nil {
+			return nil, errors.Wrap(err, "failed to collect block changes")
+		}
+		ids, err := transactionIDs(block.Transactions, scheme)
+		if err != nil {
+			return nil, err
+		}
+		rb.RemovedTransactionIds = append(rb.RemovedTransactionIds, ids...)
+		pbBlock, err := block.ToProtobuf(scheme)
+		if err != nil {
+			return nil, err
+		}
+		rb.RemovedBlocks = append(rb.RemovedBlocks, pbBlock)
+	}
+	if rb.RollbackStateUpdate, err = b.stateUpdate(current, to); err != nil {
+		return nil, errors.Wrap(err, "failed to build state update")
+	}
+	return &events.BlockchainUpdated{
+		Id:     target.Bytes(),
+		Height: int32(to),
+		Update: &events.BlockchainUpdated_Rollback_{Rollback: rb},
+	}, nil
+}
+
+// stateUpdateDelta returns entries of the cumulative update `cur` that differ from the cumulative update `prev`.
+// Values before the change are taken from `prev` if the entry was changed there.
+func stateUpdateDelta(prev, cur *events.StateUpdate) *events.StateUpdate {
+	res := &events.StateUpdate{}
+	balances := make(map[string]*events.StateUpdate_BalanceUpdate, len(prev.Balances))
+	for _, u := range prev.Balances {
+		balances[string(u.Address)+string(u.AmountAfter.AssetId)] = u
+	}
+	for _, u := range cur.Balances {
+		p, ok := balances[string(u.Address)+string(u.AmountAfter.AssetId)]
+		switch {
+		case !ok:
+			res.Balances = append(res.Balances, u)
+		case p.AmountAfter.Amount != u.AmountAfter.Amount:
+			res.Balances = append(res.Balances, &events.StateUpdate_BalanceUpdate{
+				Address:      u.Address,
+				AmountAfter:  u.AmountAfter,
+				AmountBefore: p.AmountAfter.Amount,
+			})
+		}
+	}
+	leasing := make(map[string]*events.StateUpdate_LeasingUpdate, len(prev.LeasingForAddress))
+	for _, u := range prev.LeasingForAddress {
+		leasing[string(u.Address)] = u
+	}
+	for _, u := range cur.LeasingForAddress {
+		p, ok := leasing[string(u.Address)]
+		switch {
+		case !ok:
+			res.LeasingForAddress = append(res.LeasingForAddress, u)
+		case p.InAfter != u.InAfter || p.OutAfter != u.OutAfter:
+			res.LeasingForAddress = append(res.LeasingForAddress, &events.StateUpdate_LeasingUpdate{
+				Address:   u.Address,
+				InAfter:   u.InAfter,
+				OutAfter:  u.OutAfter,
+				InBefore:  p.InAfter,
+				OutBefore: p.OutAfter,
+			})
+		}
+	}
+	data := make(map[string]*events.StateUpdate_DataEntryUpdate, len(prev.DataEntries))
+	for _, u := range prev.DataEntries {
+		data[string(u.Address)+u.DataEntry.Key] = u
+	}
+	for _, u := range cur.DataEntries {
+		p, ok := data[string(u.Address)+u.DataEntry.Key]
+		switch {
+		case !ok:
+			res.DataEntries = append(res.DataEntries, u)
+		case !pb.Equal(p.DataEntry, u.DataEntry):
+			res.DataEntries = append(res.DataEntries, &events.StateUpdate_DataEntryUpdate{
+				Address:         u.Address,
+				DataEntry:       u.DataEntry,
+				DataEntryBefore: p.DataEntry,
+			})
+		}
+	}
+	assets := make(map[string]*events.StateUpdate_AssetStateUpdate, len(prev.Assets))
+	for _, u := range prev.Assets {
+		assets[assetUpdateKey(u)] = u
+	}
+	for _, u := range cur.Assets {
+		p, ok := assets[assetUpdateKey(u)]
+		switch {
+		case !ok:
+			res.Assets = append(res.Assets, u)
+		case !pb.Equal(p.After, u.After):
+			res.Assets = append(res.Assets, &events.StateUpdate_AssetStateUpdate{Before: p.After, After: u.After})
+		}
+	}
+	leases := make(map[string]*events.StateUpdate_LeaseUpdate, len(prev.IndividualLeases))
+	for _, u := range prev.IndividualLeases {
+		leases[string(u.LeaseId)] = u
+	}
+	for _, u := range cur.IndividualLeases {
+		if p, ok := leases[string(u.LeaseId)]; !ok || p.StatusAfter != u.StatusAfter {
+			res.IndividualLeases = append(res.IndividualLeases, u)
+		}
+	}
+	return res
+}
+
+func assetUpdateKey(u *events.StateUpdate_AssetStateUpdate) string {
+	if u.After != nil {
+		return string(u.After.AssetId)
+	}
+	return string(u.Before.AssetId)
+}
+
+// invertStateUpdate swaps values before and after the change.
+func invertStateUpdate(su *events.StateUpdate) *events.StateUpdate {
+	res := &events.StateUpdate{}
+	for _, u := range su.Balances {
+		res.Balances = append(res.Balances, &events.StateUpdate_BalanceUpdate{
+			Address:      u.Address,
+			AmountAfter:  &g.Amount{AssetId: u.AmountAfter.AssetId, Amount: u.AmountBefore},
+			AmountBefore: u.AmountAfter.Amount,
+		})
+	}
+	for _, u := range su.LeasingForAddress {
+		res.LeasingForAddress = append(res.LeasingForAddress, &events.StateUpdate_LeasingUpdate{
+			Address:   u.Address,
+			InAfter:   u.InBefore,
+			OutAfter:  u.OutBefore,
+			InBefore:  u.InAfter,
+			OutBefore: u.OutAfter,
+		})
+	}
+	for _, u := range su.DataEntries {
+		after := u.DataEntryBefore
+		if after == nil {
+			after = &g.DataTransactionData_DataEntry{Key: u.DataEntry.Key}
+		}
+		var before *g.DataTransactionData_DataEntry
+		if u.DataEntry.Value != nil {
+			before = u.DataEntry
+		}
+		res.DataEntries = append(res.DataEntries, &events.StateUpdate_DataEntryUpdate{
+			Address:         u.Address,
+			DataEntry:       after,
+			DataEntryBefore: before,
+		})
+	}
+	for _, u := range su.Assets {
+		res.Assets = append(res.Assets, &events.StateUpdate_AssetStateUpdate{Before: u.After, After: u.Before})
+	}
+	for _, u := range su.IndividualLeases {
+		status := events.StateUpdate_LeaseUpdate_ACTIVE
+		if u.StatusAfter == events.StateUpdate_LeaseUpdate_ACTIVE {
+			status = events.StateUpdate_LeaseUpdate_INACTIVE
+		}
+		res.IndividualLeases = append(res.IndividualLeases, &events.StateUpdate_LeaseUpdate{
+			LeaseId:             u.LeaseId,
+			StatusAfter:         status,
+			Amount:              u.Amount,
+			Sender:              u.Sender,
+			Recipient:           u.Recipient,
+			OriginTransactionId: u.OriginTransactionId,
+		})
+	}
+	return res
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events"
+	eg "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+const maxBlockUpdatesRange = 1000
+
+var errHeightNotReached = errors.New("height is not reached yet")
+
+// blockUpdate builds the event of appending the block at the given height. State changes are included only if
+// the previous height is within the rollback window of the state (or it's the empty state before genesis).
+func (s *Server) blockUpdate(height proto.Height) (*events.BlockchainUpdated, error) {
+	res, err := s.state.MapR(func(st state.StateInfo) (interface{}, error) {
+		current, err := st.Height()
+		if err != nil {
+			return nil, err
+		}
+		if height > current {
+			return nil, errHeightNotReached
+		}
+		block, err := st.BlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		minHeight, err := st.MinRollbackHeight()
+		if err != nil {
+			return nil, err
+		}
+		if prev := height - 1; prev != 0 && prev < minHeight {
+			return blockAppendUpdateWithoutState(st, s.scheme, block, height)
+		}
+		update, _, err := blockAppendUpdate(st, s.scheme, block, height)
+		return update, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*events.BlockchainUpdated), nil
+}
+
+func blockUpdateError(err error) error {
+	switch {
+	case errors.Is(err, errHeightNotReached):
+		return status.Errorf(codes.NotFound, err.Error())
+	case state.IsInvalidInput(err):
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, err.Error())
+	}
+}
+
+func (s *Server) GetBlockUpdate(ctx context.Context, req *eg.GetBlockUpdateRequest) (*eg.GetBlockUpdateResponse, error) {
+	if req.Height < 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid height %d", req.Height)
+	}
+	update, err := s.blockUpdate(proto.Height(req.Height))
+	if err != nil {
+		return nil, blockUpdateError(err)
+	}
+	return &eg.GetBlockUpdateResponse{Update: update}, nil
+}
+
+func (s *Server) GetBlockUpdatesRange(ctx context.Context, req *eg.GetBlockUpdatesRangeRequest) (*eg.GetBlockUpdatesRangeResponse, error) {
+	if req.FromHeight < 1 || req.ToHeight < req.FromHeight {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid heights range [%d, %d]", req.FromHeight, req.ToHeight)
+	}
+	if req.ToHeight-req.FromHeight >= maxBlockUpdatesRange {
+		return nil, status.Errorf(codes.InvalidArgument, "heights range is too big, max %d", maxBlockUpdatesRange)
+	}
+	updates := make([]*events.BlockchainUpdated, 0, req.ToHeight-req.FromHeight+1)
+	for h := req.FromHeight; h <= req.ToHeight; h++ {
+		update, err := s.blockUpdate(proto.Height(h))
+		if err != nil {
+			return nil, blockUpdateError(err)
+		}
+		updates = append(updates, update)
+	}
+	return &eg.GetBlockUpdatesRangeResponse{Updates: updates}, nil
+}
+
+// Subscribe sends historical updates starting from the requested height and then switches to the live updates.
+// Subscription to live updates is made before sending the history, so no event is missed. Events that are
+// already covered by the history or that are below the requested height are skipped. Rollbacks below the requested
+// height are sent only if they remove the blocks that were already sent.
+func (s *Server) Subscribe(req *eg.SubscribeRequest, srv eg.BlockchainUpdatesApi_SubscribeServer) error {
+	if req.FromHeight < 1 || (req.ToHeight != 0 && req.ToHeight < req.FromHeight) {
+		return status.Errorf(codes.InvalidArgument, "invalid heights range [%d, %d]", req.FromHeight, req.ToHeight)
+	}
+	var sub *updatesSubscription
+	if s.updates != nil {
+		sub = s.updates.subscribe()
+		defer s.updates.unsubscribe(sub)
+	}
+	var (
+		lastHeight = req.FromHeight - 1
+		lastID     []byte
+	)
+	for h := req.FromHeight; req.ToHeight == 0 || h <= req.ToHeight; h++ {
+		update, err := s.blockUpdate(proto.Height(h))
+		if errors.Is(err, errHeightNotReached) {
+			break
+		}
+		if err != nil {
+			return blockUpdateError(err)
+		}
+		if err := srv.Send(&eg.SubscribeEvent{Update: update}); err != nil {
+			return err
+		}
+		lastHeight, lastID = update.Height, update.Id
+	}
+	if req.ToHeight != 0 && lastHeight >= req.ToHeight {
+		return nil
+	}
+	if sub == nil {
+		return status.Errorf(codes.Unavailable, "live blockchain updates are not available")
+	}
+	for {
+		select {
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		case <-sub.overflow:
+			return status.Errorf(codes.ResourceExhausted, "subscriber is too slow")
+		case update := <-sub.events:
+			if !isNextUpdate(update, lastHeight, lastID) {
+				continue
+			}
+			if update.Height < req.FromHeight {
+				if _, ok := update.Update.(*events.BlockchainUpdated_Rollback_); !ok || lastHeight < req.FromHeight {
+					continue
+				}
+			}
+			if req.ToHeight != 0 && update.Height > req.ToHeight {
+				return nil
+			}
+			if err := srv.Send(&eg.SubscribeEvent{Update: update}); err != nil {
+				return err
+			}
+			lastHeight, lastID = update.Height, update.Id
+		}
+	}
+}
+
+// isNextUpdate checks that the live update follows the last sent one.
+func isNextUpdate(update *events.BlockchainUpdated, lastHeight int32, lastID []byte) bool {
+	switch u := update.Update.(type) {
+	case *events.BlockchainUpdated_Append_:
+		if mb, ok := u.Append.Body.(*events.BlockchainUpdated_Append_MicroBlock); ok {
+			return update.Height == lastHeight && bytes.Equal(mb.MicroBlock.MicroBlock.GetMicroBlock().GetReference(), lastID)
+		}
+		return update.Height > lastHeight
+	case *events.BlockchainUpdated_Rollback_:
+		return update.Height < lastHeight || !bytes.Equal(update.Id, lastID)
+	default:
+		return false
+	}
+}
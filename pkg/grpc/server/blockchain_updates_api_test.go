@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events"
+	eg "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+func TestGetBlockUpdate(t *testing.T) {
+	params := defaultStateParams()
+	st := newTestState(t, true, params, settings.MainNetSettings)
+	ctx := withAutoCancel(t, context.Background())
+	err := server.initServer(st, nil, nil)
+	require.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := eg.NewBlockchainUpdatesApiClient(conn)
+
+	blocks, err := state.ReadMainnetBlocksToHeight(proto.Height(10))
+	require.NoError(t, err)
+	_, err = st.AddDeserializedBlocks(blocks)
+	require.NoError(t, err)
+
+	res, err := cl.GetBlockUpdate(ctx, &eg.GetBlockUpdateRequest{Height: 1})
+	require.NoError(t, err)
+	genesis, err := st.BlockByHeight(1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), res.Update.Height)
+	assert.Equal(t, genesis.BlockID().Bytes(), res.Update.Id)
+	appendUpdate, ok := res.Update.Update.(*events.BlockchainUpdated_Append_)
+	require.True(t, ok)
+	assert.Len(t, appendUpdate.Append.TransactionIds, len(genesis.Transactions))
+	require.NotEmpty(t, appendUpdate.Append.StateUpdate.Balances)
+	for _, tx := range genesis.Transactions {
+		gen := tx.(*proto.Genesis)
+		var found bool
+		for _, u := range appendUpdate.Append.StateUpdate.Balances {
+			if string(u.Address) == string(gen.Recipient.Bytes()) {
+				found = true
+				assert.Equal(t, int64(0), u.AmountBefore)
+				assert.Equal(t, int64(gen.Amount), u.AmountAfter.Amount)
+			}
+		}
+		assert.True(t, found)
+	}
+
+	_, err = cl.GetBlockUpdate(ctx, &eg.GetBlockUpdateRequest{Height: 11})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = cl.GetBlockUpdate(ctx, &eg.GetBlockUpdateRequest{Height: 0})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetBlockUpdatesRange(t *testing.T) {
+	params := defaultStateParams()
+	st := newTestState(t, true, params, settings.MainNetSettings)
+	ctx := withAutoCancel(t, context.Background())
+	err := server.initServer(st, nil, nil)
+	require.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := eg.NewBlockchainUpdatesApiClient(conn)
+
+	blocks, err := state.ReadMainnetBlocksToHeight(proto.Height(100))
+	require.NoError(t, err)
+	_, err = st.AddDeserializedBlocks(blocks)
+	require.NoError(t, err)
+
+	res, err := cl.GetBlockUpdatesRange(ctx, &eg.GetBlockUpdatesRangeRequest{FromHeight: 2, ToHeight: 100})
+	require.NoError(t, err)
+	require.Len(t, res.Updates, 99)
+	for i, u := range res.Updates {
+		h := proto.Height(i + 2)
+		id, err := st.HeightToBlockID(h)
+		require.NoError(t, err)
+		assert.Equal(t, int32(h), u.Height)
+		assert.Equal(t, id.Bytes(), u.Id)
+		block, err := st.BlockByHeight(h)
+		require.NoError(t, err)
+		appendUpdate := u.Update.(*events.BlockchainUpdated_Append_).Append
+		assert.Len(t, appendUpdate.TransactionIds, len(block.Transactions))
+		if len(block.Transactions) > 0 {
+			// Transactions always change the balance of sender at least.
+			assert.NotEmpty(t, appendUpdate.StateUpdate.Balances)
+		}
+		require.Len(t, appendUpdate.TransactionStateUpdates, len(block.Transactions))
+		for _, su := range appendUpdate.TransactionStateUpdates {
+			assert.NotEmpty(t, su.Balances)
+		}
+	}
+
+	_, err = cl.GetBlockUpdatesRange(ctx, &eg.GetBlockUpdatesRangeRequest{FromHeight: 5, ToHeight: 4})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = cl.GetBlockUpdatesRange(ctx, &eg.GetBlockUpdatesRangeRequest{FromHeight: 99, ToHeight: 101})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInvertStateUpdate(t *testing.T) {
+	su := &events.StateUpdate{
+		Balances: []*events.StateUpdate_BalanceUpdate{
+			{Address: []byte{1}, AmountAfter: &waves.Amount{Amount: 10}, AmountBefore: 5},
+		},
+		LeasingForAddress: []*events.StateUpdate_LeasingUpdate{
+			{Address: []byte{2}, InAfter: 1, OutAfter: 2, InBefore: 3, OutBefore: 4},
+		},
+	}
+	inv := invertStateUpdate(su)
+	assert.Equal(t, int64(5), inv.Balances[0].AmountAfter.Amount)
+	assert.Equal(t, int64(10), inv.Balances[0].AmountBefore)
+	assert.Equal(t, int64(3), inv.LeasingForAddress[0].InAfter)
+	assert.Equal(t, int64(4), inv.LeasingForAddress[0].OutAfter)
+	assert.Equal(t, int64(1), inv.LeasingForAddress[0].InBefore)
+	assert.Equal(t, int64(2), inv.LeasingForAddress[0].OutBefore)
+}
+
+func TestBlockUpdateBelowRollbackWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blocks, err := state.ReadMainnetBlocksToHeight(proto.Height(3))
+	require.NoError(t, err)
+	block := blocks[1]
+	st := mock.NewMockStateInfo(ctrl)
+	st.EXPECT().MapR(gomock.Any()).DoAndReturn(func(fn func(state.StateInfo) (interface{}, error)) (interface{}, error) {
+		return fn(st)
+	})
+	st.EXPECT().Height().Return(proto.Height(3000), nil)
+	st.EXPECT().BlockByHeight(proto.Height(2)).Return(block, nil)
+	st.EXPECT().MinRollbackHeight().Return(proto.Height(1000), nil)
+
+	s := &Server{state: st, scheme: proto.MainNetScheme}
+	update, err := s.blockUpdate(2)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), update.Height)
+	assert.Equal(t, block.BlockID().Bytes(), update.Id)
+	appendUpdate := update.Update.(*events.BlockchainUpdated_Append_).Append
+	assert.Len(t, appendUpdate.TransactionIds, len(block.Transactions))
+	assert.Nil(t, appendUpdate.StateUpdate)
+	assert.Empty(t, appendUpdate.TransactionStateUpdates)
+}
+
+func blockEvent(height int32, id byte) *events.BlockchainUpdated {
+	return &events.BlockchainUpdated{Id: []byte{id}, Height: height, Update: &events.BlockchainUpdated_Append_{
+		Append: &events.BlockchainUpdated_Append{Body: &events.BlockchainUpdated_Append_Block{
+			Block: &events.BlockchainUpdated_Append_BlockAppend{},
+		}},
+	}}
+}
+
+func microBlockEvent(height int32, id, reference byte) *events.BlockchainUpdated {
+	return &events.BlockchainUpdated{Id: []byte{id}, Height: height, Update: &events.BlockchainUpdated_Append_{
+		Append: &events.BlockchainUpdated_Append{Body: &events.BlockchainUpdated_Append_MicroBlock{
+			MicroBlock: &events.BlockchainUpdated_Append_MicroBlockAppend{
+				MicroBlock: &waves.SignedMicroBlock{MicroBlock: &waves.MicroBlock{Reference: []byte{reference}}},
+			},
+		}},
+	}}
+}
+
+func rollbackEvent(height int32, id byte) *events.BlockchainUpdated {
+	return &events.BlockchainUpdated{Id: []byte{id}, Height: height, Update: &events.BlockchainUpdated_Rollback_{
+		Rollback: &events.BlockchainUpdated_Rollback{Type: events.BlockchainUpdated_Rollback_BLOCK},
+	}}
+}
+
+func requireNextEvent(t *testing.T, stream eg.BlockchainUpdatesApi_SubscribeClient, height int32, id []byte) *events.BlockchainUpdated {
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, height, ev.Update.Height)
+	require.Equal(t, id, ev.Update.Id)
+	return ev.Update
+}
+
+func TestSubscribe(t *testing.T) {
+	params := defaultStateParams()
+	st := newTestState(t, true, params, settings.MainNetSettings)
+	ctx := withAutoCancel(t, context.Background())
+	err := server.initServer(st, nil, nil)
+	require.NoError(t, err)
+	server.updates = newBlockchainUpdatesBroker()
+	defer func() { server.updates = nil }()
+
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := eg.NewBlockchainUpdatesApiClient(conn)
+
+	blocks, err := state.ReadMainnetBlocksToHeight(proto.Height(10))
+	require.NoError(t, err)
+	_, err = st.AddDeserializedBlocks(blocks)
+	require.NoError(t, err)
+
+	t.Run("history and live updates", func(t *testing.T) {
+		stream, err := cl.Subscribe(ctx, &eg.SubscribeRequest{FromHeight: 9})
+		require.NoError(t, err)
+		for h := proto.Height(9); h <= 10; h++ {
+			block, err := st.BlockByHeight(h)
+			require.NoError(t, err)
+			u := requireNextEvent(t, stream, int32(h), block.BlockID().Bytes())
+			appendUpdate := u.Update.(*events.BlockchainUpdated_Append_).Append
+			assert.Len(t, appendUpdate.TransactionStateUpdates, len(block.Transactions))
+		}
+		last, err := st.BlockByHeight(10)
+		require.NoError(t, err)
+		lastID := last.BlockID().Bytes()
+
+		server.updates.publish(blockEvent(10, 1))         // Already sent in history
+		server.updates.publish(microBlockEvent(10, 2, 3)) // Doesn't reference the last block
+		server.updates.publish(&events.BlockchainUpdated{Id: []byte{2}, Height: 10, Update: &events.BlockchainUpdated_Append_{
+			Append: &events.BlockchainUpdated_Append{Body: &events.BlockchainUpdated_Append_MicroBlock{
+				MicroBlock: &events.BlockchainUpdated_Append_MicroBlockAppend{
+					MicroBlock: &waves.SignedMicroBlock{MicroBlock: &waves.MicroBlock{Reference: lastID}},
+				},
+			}},
+		}})
+		server.updates.publish(microBlockEvent(10, 4, 2)) // References the previous microblock
+		server.updates.publish(blockEvent(11, 5))
+		server.updates.publish(rollbackEvent(10, 4))
+		server.updates.publish(blockEvent(11, 6))
+
+		requireNextEvent(t, stream, 10, []byte{2})
+		requireNextEvent(t, stream, 10, []byte{4})
+		requireNextEvent(t, stream, 11, []byte{5})
+		u := requireNextEvent(t, stream, 10, []byte{4})
+		assert.IsType(t, &events.BlockchainUpdated_Rollback_{}, u.Update)
+		requireNextEvent(t, stream, 11, []byte{6})
+	})
+
+	t.Run("from height above the current one", func(t *testing.T) {
+		stream, err := cl.Subscribe(ctx, &eg.SubscribeRequest{FromHeight: 12, ToHeight: 13})
+		require.NoError(t, err)
+		require.Eventually(t, server.updates.hasSubscribers, time.Second, 10*time.Millisecond)
+
+		server.updates.publish(blockEvent(11, 1))         // Below the requested height
+		server.updates.publish(rollbackEvent(10, 2))      // Nothing was sent yet
+		server.updates.publish(microBlockEvent(11, 3, 1)) // Below the requested height
+		server.updates.publish(blockEvent(12, 4))
+		server.updates.publish(rollbackEvent(11, 5)) // Removes the sent block
+		server.updates.publish(blockEvent(11, 6))    // Below the requested height
+		server.updates.publish(blockEvent(12, 7))
+		server.updates.publish(microBlockEvent(12, 8, 7))
+		server.updates.publish(blockEvent(13, 9))
+		server.updates.publish(blockEvent(14, 10)) // Above the requested range
+
+		requireNextEvent(t, stream, 12, []byte{4})
+		u := requireNextEvent(t, stream, 11, []byte{5})
+		assert.IsType(t, &events.BlockchainUpdated_Rollback_{}, u.Update)
+		requireNextEvent(t, stream, 12, []byte{7})
+		requireNextEvent(t, stream, 12, []byte{8})
+		requireNextEvent(t, stream, 13, []byte{9})
+		_, err = stream.Recv()
+		assert.Equal(t, io.EOF, err)
+	})
+}
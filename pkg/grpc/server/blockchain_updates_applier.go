@@ -0,0 +1,235 @@
+package server
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/services"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+const subscriptionBufferSize = 1024
+
+// updatesSubscription receives published events until it's closed.
+// Subscription is closed by the broker if the subscriber can't keep up with the events.
+type updatesSubscription struct {
+	events     chan *events.BlockchainUpdated
+	overflow   chan struct{}
+	overflowed bool
+}
+
+// blockchainUpdatesBroker delivers BlockchainUpdates events to subscribers.
+type blockchainUpdatesBroker struct {
+	mu   sync.Mutex
+	subs map[*updatesSubscription]struct{}
+}
+
+func newBlockchainUpdatesBroker() *blockchainUpdatesBroker {
+	return &blockchainUpdatesBroker{subs: make(map[*updatesSubscription]struct{})}
+}
+
+func (b *blockchainUpdatesBroker) subscribe() *updatesSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := &updatesSubscription{
+		events:   make(chan *events.BlockchainUpdated, subscriptionBufferSize),
+		overflow: make(chan struct{}),
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+func (b *blockchainUpdatesBroker) unsubscribe(sub *updatesSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+}
+
+func (b *blockchainUpdatesBroker) hasSubscribers() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs) > 0
+}
+
+func (b *blockchainUpdatesBroker) publish(update *events.BlockchainUpdated) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub.events <- update:
+		default:
+			if !sub.overflowed {
+				sub.overflowed = true
+				close(sub.overflow)
+			}
+			delete(b.subs, sub)
+		}
+	}
+}
+
+// appliedBlock describes the last block which events were published by BlockchainUpdatesApplier.
+type appliedBlock struct {
+	height proto.Height
+	block  *proto.Block
+	update *events.StateUpdate // Cumulative state update of the block.
+}
+
+// BlockchainUpdatesApplier is a services.BlocksApplier that publishes BlockchainUpdates events
+// for applied blocks, microblocks and rollbacks. Events are built only if there are subscribers.
+// Failures of events building are logged and don't affect the blocks application.
+type BlockchainUpdatesApplier struct {
+	applier services.BlocksApplier
+	scheme  proto.Scheme
+	broker  *blockchainUpdatesBroker
+
+	mu   sync.Mutex
+	last *appliedBlock
+}
+
+func NewBlockchainUpdatesApplier(applier services.BlocksApplier, scheme proto.Scheme) *BlockchainUpdatesApplier {
+	return &BlockchainUpdatesApplier{applier: applier, scheme: scheme, broker: newBlockchainUpdatesBroker()}
+}
+
+func (a *BlockchainUpdatesApplier) BlockExists(st state.State, block *proto.Block) (bool, error) {
+	return a.applier.BlockExists(st, block)
+}
+
+func (a *BlockchainUpdatesApplier) Apply(st state.State, blocks []*proto.Block) (proto.Height, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(blocks) == 0 || !a.broker.hasSubscribers() {
+		a.last = nil
+		return a.applier.Apply(st, blocks)
+	}
+	rollbacks := a.rollbackEvents(st, blocks[0].Parent)
+	height, err := a.applier.Apply(st, blocks)
+	if err != nil {
+		return height, err
+	}
+	for _, ev := range rollbacks {
+		a.broker.publish(ev)
+	}
+	a.last = nil
+	first := height - proto.Height(len(blocks)) + 1
+	for i, block := range blocks {
+		h := first + proto.Height(i)
+		ev, su, err := blockAppendUpdate(st, a.scheme, block, h)
+		if err != nil {
+			zap.S().Errorf("Failed to build BlockchainUpdates event for block '%s': %v", block.BlockID().String(), err)
+			return height, nil
+		}
+		a.broker.publish(ev)
+		a.last = &appliedBlock{height: h, block: block, update: su}
+	}
+	return height, nil
+}
+
+// rollbackEvents builds events of rollbacks that happened since the last published event
+// and of the rollback that will be performed to apply blocks on top of the given parent.
+func (a *BlockchainUpdatesApplier) rollbackEvents(st state.State, parent proto.BlockID) []*events.BlockchainUpdated {
+	var res []*events.BlockchainUpdated
+	if ev, ok := a.externalRollbackEvent(st); ok {
+		res = append(res, ev)
+	}
+	current, err := st.Height()
+	if err != nil {
+		zap.S().Errorf("Failed to build BlockchainUpdates rollback event: %v", err)
+		return res
+	}
+	parentHeight, err := st.BlockIDToHeight(parent)
+	if err != nil || parentHeight >= current {
+		return res // Blocks will be appended on top or won't be applied at all.
+	}
+	ev, err := rollbackUpdate(st, a.scheme, parentHeight)
+	if err != nil {
+		zap.S().Errorf("Failed to build BlockchainUpdates rollback event: %v", err)
+		return res
+	}
+	return append(res, ev)
+}
+
+// externalRollbackEvent returns the event of rollback that was performed directly on state after the last
+// published event. Such rollback happens, for example, when the node switches back to the cached block.
+func (a *BlockchainUpdatesApplier) externalRollbackEvent(st state.State) (*events.BlockchainUpdated, bool) {
+	if a.last == nil {
+		return nil, false
+	}
+	top := st.TopBlock()
+	if top.BlockID() == a.last.block.BlockID() {
+		return nil, false
+	}
+	height, err := st.Height()
+	if err != nil {
+		zap.S().Errorf("Failed to build BlockchainUpdates rollback event: %v", err)
+		return nil, false
+	}
+	rb := &events.BlockchainUpdated_Rollback{Type: events.BlockchainUpdated_Rollback_BLOCK}
+	if height == a.last.height-1 {
+		ids, err := transactionIDs(a.last.block.Transactions, a.scheme)
+		if err != nil {
+			zap.S().Errorf("Failed to build BlockchainUpdates rollback event: %v", err)
+			return nil, false
+		}
+		pbBlock, err := a.last.block.ToProtobuf(a.scheme)
+		if err != nil {
+			zap.S().Errorf("Failed to build BlockchainUpdates rollback event: %v", err)
+			return nil, false
+		}
+		rb.RemovedTransactionIds = ids
+		rb.RemovedBlocks = append(rb.RemovedBlocks, pbBlock)
+		rb.RollbackStateUpdate = invertStateUpdate(a.last.update)
+	}
+	return &events.BlockchainUpdated{
+		Id:     top.BlockID().Bytes(),
+		Height: int32(height),
+		Update: &events.BlockchainUpdated_Rollback_{Rollback: rb},
+	}, true
+}
+
+func (a *BlockchainUpdatesApplier) ApplyMicro(st state.State, block *proto.Block) (proto.Height, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.broker.hasSubscribers() {
+		a.last = nil
+		return a.applier.ApplyMicro(st, block)
+	}
+	rollback, rolledBack := a.externalRollbackEvent(st)
+	prev := a.last
+	top := st.TopBlock()
+	if prev == nil || prev.block.BlockID() != top.BlockID() {
+		prev = nil
+		height, err := st.Height()
+		if err == nil {
+			var su *events.StateUpdate
+			if su, err = blockStateUpdate(st, a.scheme, top, height); err == nil {
+				prev = &appliedBlock{height: height, block: top, update: su}
+			}
+		}
+		if err != nil {
+			zap.S().Errorf("Failed to build BlockchainUpdates event for block '%s': %v", top.BlockID().String(), err)
+		}
+	}
+	height, err := a.applier.ApplyMicro(st, block)
+	if err != nil {
+		return height, err
+	}
+	a.last = nil
+	if rolledBack {
+		a.broker.publish(rollback)
+	}
+	if prev == nil {
+		return height, nil
+	}
+	ev, su, err := microBlockAppendUpdate(st, a.scheme, prev.block.BlockID(), len(prev.block.Transactions),
+		prev.update, block, height)
+	if err != nil {
+		zap.S().Errorf("Failed to build BlockchainUpdates event for microblock '%s': %v", block.BlockID().String(), err)
+		return height, nil
+	}
+	a.broker.publish(ev)
+	a.last = &appliedBlock{height: height, block: block, update: su}
+	return height, nil
+}
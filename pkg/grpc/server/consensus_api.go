@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func (s *Server) GetEffectiveBalance(_ context.Context, req *gg.EffectiveBalanceRequest) (*gg.EffectiveBalanceResponse, error) {
+	c := proto.ProtobufConverter{FallbackChainID: s.scheme}
+	addr, err := c.Address(s.scheme, req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if req.FromHeight < 1 || req.ToHeight < req.FromHeight {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid heights range [%d, %d]", req.FromHeight, req.ToHeight)
+	}
+	height, err := s.state.Height()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if uint64(req.ToHeight) > height {
+		return nil, status.Errorf(codes.FailedPrecondition, "requested height exceeds current height")
+	}
+	balance, err := s.state.EffectiveBalance(proto.NewRecipientFromAddress(addr), uint64(req.FromHeight), uint64(req.ToHeight))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &gg.EffectiveBalanceResponse{Balance: int64(balance)}, nil
+}
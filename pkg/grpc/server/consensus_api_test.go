@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetEffectiveBalance(t *testing.T) {
+	params := defaultStateParams()
+	st := newTestState(t, true, params, settings.MainNetSettings)
+	ctx := withAutoCancel(t, context.Background())
+	sch := createTestNetWallet(t)
+	err := server.initServer(st, nil, sch)
+	require.NoError(t, err)
+
+	blocks, err := state.ReadMainnetBlocksToHeight(proto.Height(3))
+	require.NoError(t, err)
+	_, err = st.AddDeserializedBlocks(blocks)
+	require.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := gg.NewConsensusApiClient(conn)
+
+	addr, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, blocks[1].GeneratorPublicKey)
+	require.NoError(t, err)
+	expected, err := st.EffectiveBalance(proto.NewRecipientFromAddress(addr), 1, 3)
+	require.NoError(t, err)
+	res, err := cl.GetEffectiveBalance(ctx, &gg.EffectiveBalanceRequest{Address: addr.Bytes(), FromHeight: 1, ToHeight: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(expected), res.Balance)
+	assert.NotZero(t, res.Balance)
+
+	_, err = cl.GetEffectiveBalance(ctx, &gg.EffectiveBalanceRequest{Address: addr.Bytes(), FromHeight: 3, ToHeight: 2})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = cl.GetEffectiveBalance(ctx, &gg.EffectiveBalanceRequest{Address: addr.Bytes(), FromHeight: 1, ToHeight: 4})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = cl.GetEffectiveBalance(ctx, &gg.EffectiveBalanceRequest{Address: []byte{1, 2, 3}, FromHeight: 1, ToHeight: 3})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	eg "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
 	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/services"
@@ -28,6 +30,7 @@ type Server struct {
 	utx        types.UtxPool
 	wallet     types.EmbeddedWallet
 	services   services.Services
+	updates    *blockchainUpdatesBroker
 	grpcServer *grpc.Server
 }
 
@@ -45,6 +48,9 @@ func NewServer(services services.Services) (*Server, error) {
 	s := &Server{}
 	s.grpcServer = createGRPCServerWithHandlers(s)
 	s.services = services
+	if a, ok := services.BlocksApplier.(*BlockchainUpdatesApplier); ok {
+		s.updates = a.broker
+	}
 	if err := s.initServer(services.State, services.UtxPool, services.Wallet); err != nil {
 		return nil, err
 	}
@@ -63,6 +69,12 @@ func createGRPCServerWithHandlers(handlers GrpcHandlers) *grpc.Server {
 	g.RegisterBlockchainApiServer(grpcServer, handlers)
 	g.RegisterBlocksApiServer(grpcServer, handlers)
 	g.RegisterTransactionsApiServer(grpcServer, handlers)
+	eg.RegisterBlockchainUpdatesApiServer(grpcServer, handlers)
+	gg.RegisterUtxApiServer(grpcServer, handlers)
+	gg.RegisterConsensusApiServer(grpcServer, handlers)
+	gg.RegisterTransactionsProofApiServer(grpcServer, handlers)
+	gg.RegisterPeersApiServer(grpcServer, handlers)
+	gg.RegisterTypedDataApiServer(grpcServer, handlers)
 	reflection.Register(grpcServer) // Register reflection service on gRPC server.
 	return grpcServer
 }
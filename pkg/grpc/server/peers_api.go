@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func (s *Server) GetConnectedPeers(_ context.Context, _ *gg.ConnectedPeersRequest) (*gg.ConnectedPeersResponse, error) {
+	if s.services.Peers == nil {
+		return nil, status.Errorf(codes.Unavailable, "peers are not available")
+	}
+	type connectedPeer struct {
+		peer  peer.Peer
+		score []byte
+	}
+	var connected []connectedPeer
+	// Reputation is not requested inside the callback to keep the lock of peer manager as short as possible
+	s.services.Peers.EachConnected(func(p peer.Peer, score *proto.Score) {
+		cp := connectedPeer{peer: p}
+		if score != nil {
+			cp.score = score.Bytes()
+		}
+		connected = append(connected, cp)
+	})
+	res := &gg.ConnectedPeersResponse{Peers: make([]*gg.ConnectedPeer, 0, len(connected))}
+	for _, cp := range connected {
+		p := cp.peer
+		handshake := p.Handshake()
+		var declared string
+		if !handshake.DeclaredAddr.Empty() {
+			declared = handshake.DeclaredAddr.String()
+		}
+		res.Peers = append(res.Peers, &gg.ConnectedPeer{
+			Address:            p.RemoteAddr().String(),
+			DeclaredAddress:    declared,
+			NodeName:           handshake.NodeName,
+			NodeNonce:          handshake.NodeNonce,
+			ApplicationName:    handshake.AppName,
+			ApplicationVersion: handshake.Version.String(),
+			Score:              cp.score,
+			Reputation:         s.services.Peers.Reputation(p),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func TestGetConnectedPeers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := mock.NewMockPeer(ctrl)
+	p.EXPECT().RemoteAddr().Return(proto.NewTCPAddrFromString("13.3.4.1:6868"))
+	p.EXPECT().Handshake().Return(proto.Handshake{
+		AppName:   "wavesW",
+		Version:   proto.NewVersion(1, 4, 0),
+		NodeName:  "node",
+		NodeNonce: 10,
+	})
+	peers := mock.NewMockPeerManager(ctrl)
+	peers.EXPECT().EachConnected(gomock.Any()).Do(func(f func(peer.Peer, *proto.Score)) {
+		f(p, big.NewInt(1000))
+	})
+	peers.EXPECT().Reputation(p).Return(int64(42))
+
+	server.services.Peers = peers
+	defer func() {
+		server.services.Peers = nil
+	}()
+	ctx := withAutoCancel(t, context.Background())
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := gg.NewPeersApiClient(conn)
+
+	res, err := cl.GetConnectedPeers(ctx, &gg.ConnectedPeersRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Peers, 1)
+	cp := res.Peers[0]
+	assert.Equal(t, "13.3.4.1:6868", cp.Address)
+	assert.Empty(t, cp.DeclaredAddress)
+	assert.Equal(t, "node", cp.NodeName)
+	assert.Equal(t, uint64(10), cp.NodeNonce)
+	assert.Equal(t, "wavesW", cp.ApplicationName)
+	assert.Equal(t, "1.4.0", cp.ApplicationVersion)
+	assert.Equal(t, big.NewInt(1000).Bytes(), cp.Score)
+	assert.Equal(t, int64(42), cp.Reputation)
+}
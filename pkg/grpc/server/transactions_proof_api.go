@@ -0,0 +1,60 @@
+package server
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+func (s *Server) GetMerkleProofs(req *gg.MerkleProofsRequest, srv gg.TransactionsProofApi_GetMerkleProofsServer) error {
+	ids := make([]crypto.Digest, len(req.TransactionIds))
+	for i, b := range req.TransactionIds {
+		id, err := crypto.NewDigestFromBytes(b)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid transaction ID: %v", err)
+		}
+		ids[i] = id
+	}
+	blocks := make(map[proto.Height]*proto.Block)
+	for _, id := range ids {
+		height, err := s.state.TransactionHeightByID(id.Bytes())
+		if err != nil {
+			if state.IsNotFound(err) {
+				return status.Errorf(codes.NotFound, "transaction %s does not exist", id.String())
+			}
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		block, ok := blocks[height]
+		if !ok {
+			block, err = s.state.BlockByHeight(height)
+			if err != nil {
+				return status.Errorf(codes.Internal, err.Error())
+			}
+			blocks[height] = block
+		}
+		if block.Version < proto.ProtobufBlockVersion {
+			return status.Errorf(codes.FailedPrecondition, "transaction %s is in block of version %d without transactions root", id.String(), block.Version)
+		}
+		index, proofs, err := block.TransactionMerkleProof(s.scheme, id.Bytes())
+		if err != nil {
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		res := &gg.TransactionMerkleProof{
+			Id:               id.Bytes(),
+			Height:           int32(height),
+			TransactionIndex: int32(index),
+			MerkleProof:      make([][]byte, len(proofs)),
+		}
+		for i := range proofs {
+			res.MerkleProof[i] = proofs[i].Bytes()
+		}
+		if err := srv.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
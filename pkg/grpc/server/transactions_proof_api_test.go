@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetMerkleProofs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := withAutoCancel(t, context.Background())
+
+	sk, pk, err := crypto.GenerateKeyPair([]byte("whatever"))
+	require.NoError(t, err)
+	waves := proto.NewOptionalAssetWaves()
+	rcp := proto.NewRecipientFromAddress(proto.MustAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ"))
+	txs := make([]proto.Transaction, 3)
+	for i := range txs {
+		tx := proto.NewUnsignedTransferWithProofs(3, pk, waves, waves, uint64(i), 1, 100000, rcp, nil)
+		require.NoError(t, tx.Sign(proto.MainNetScheme, sk))
+		txs[i] = tx
+	}
+	block := &proto.Block{BlockHeader: proto.BlockHeader{Version: proto.ProtobufBlockVersion}, Transactions: txs[:2]}
+	require.NoError(t, block.SetTransactionsRoot(proto.MainNetScheme))
+	legacy := &proto.Block{BlockHeader: proto.BlockHeader{Version: proto.NgBlockVersion}, Transactions: txs[2:]}
+
+	st := mock.NewMockState(ctrl)
+	st.EXPECT().TransactionHeightByID(gomock.Any()).DoAndReturn(func(id []byte) (uint64, error) {
+		if id1, _ := txs[2].GetID(proto.MainNetScheme); string(id) == string(id1) {
+			return 3, nil
+		}
+		return 5, nil
+	}).AnyTimes()
+	st.EXPECT().BlockByHeight(proto.Height(5)).Return(block, nil).AnyTimes()
+	st.EXPECT().BlockByHeight(proto.Height(3)).Return(legacy, nil).AnyTimes()
+	err = server.initServer(st, nil, nil)
+	require.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := gg.NewTransactionsProofApiClient(conn)
+
+	id1, err := txs[1].GetID(proto.MainNetScheme)
+	require.NoError(t, err)
+	stream, err := cl.GetMerkleProofs(ctx, &gg.MerkleProofsRequest{TransactionIds: [][]byte{id1}})
+	require.NoError(t, err)
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, id1, res.Id)
+	assert.EqualValues(t, 5, res.Height)
+	assert.EqualValues(t, 1, res.TransactionIndex)
+	require.Len(t, res.MerkleProof, 1)
+	mb, err := txs[0].MerkleBytes(proto.MainNetScheme)
+	require.NoError(t, err)
+	sibling, err := crypto.FastHash(mb)
+	require.NoError(t, err)
+	assert.Equal(t, sibling.Bytes(), res.MerkleProof[0])
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	id2, err := txs[2].GetID(proto.MainNetScheme)
+	require.NoError(t, err)
+	stream, err = cl.GetMerkleProofs(ctx, &gg.MerkleProofsRequest{TransactionIds: [][]byte{id2}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	stream, err = cl.GetMerkleProofs(ctx, &gg.MerkleProofsRequest{TransactionIds: [][]byte{{1, 2, 3}}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
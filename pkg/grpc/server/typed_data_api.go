@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func (s *Server) HashTypedData(_ context.Context, req *gg.TypedDataRequest) (*gg.TypedDataHash, error) {
+	typedData, err := proto.NewEthereumTypedDataFromJSON([]byte(req.TypedData))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid typed data: %v", err)
+	}
+	hash, err := typedData.Hash()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to hash typed data: %v", err)
+	}
+	return &gg.TypedDataHash{Hash: hash.Bytes()}, nil
+}
+
+func (s *Server) VerifyTypedData(_ context.Context, req *gg.TypedDataVerificationRequest) (*gg.TypedDataVerification, error) {
+	typedData, err := proto.NewEthereumTypedDataFromJSON([]byte(req.TypedData))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid typed data: %v", err)
+	}
+	sig, err := proto.NewEthereumSignatureFromBytes(req.Signature)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature: %v", err)
+	}
+	hash, err := typedData.Hash()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to hash typed data: %v", err)
+	}
+	pk, err := typedData.RecoverSigner(sig)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to recover signer: %v", err)
+	}
+	signer := pk.EthereumAddress()
+	addr, err := signer.ToWavesAddress(s.scheme)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	res := &gg.TypedDataVerification{
+		Hash:            hash.Bytes(),
+		Signer:          signer.Bytes(),
+		SignerPublicKey: pk.SerializeXYCoordinates(),
+		SignerAddress:   addr.Bytes(),
+	}
+	if len(req.Address) != 0 {
+		expected, err := proto.NewEthereumAddressFromBytes(req.Address)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid address: %v", err)
+		}
+		valid := expected == signer
+		res.Valid = &valid
+	}
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const testMailTypedData = `{"types":{"EIP712Domain":[{"name":"name","type":"string"},{"name":"version","type":"string"},
+{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"}],
+"Person":[{"name":"name","type":"string"},{"name":"wallet","type":"address"}],
+"Mail":[{"name":"from","type":"Person"},{"name":"to","type":"Person"},{"name":"contents","type":"string"}]},
+"primaryType":"Mail","domain":{"name":"Ether Mail","version":"1","chainId":1,
+"verifyingContract":"0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+"message":{"from":{"name":"Cow","wallet":"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+"to":{"name":"Bob","wallet":"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},"contents":"Hello, Bob!"}}`
+
+const testMailSignature = "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+	"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+
+func TestTypedDataApi(t *testing.T) {
+	ctx := withAutoCancel(t, context.Background())
+	err := server.initServer(nil, nil, nil)
+	require.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+	cl := gg.NewTypedDataApiClient(conn)
+
+	h, err := cl.HashTypedData(ctx, &gg.TypedDataRequest{TypedData: testMailTypedData})
+	require.NoError(t, err)
+	assert.Equal(t, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToString(h.Hash))
+
+	_, err = cl.HashTypedData(ctx, &gg.TypedDataRequest{TypedData: `{"types":{}}`})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	sig, err := hex.DecodeString(testMailSignature)
+	require.NoError(t, err)
+	signer, err := proto.NewEthereumAddressFromHexString("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	require.NoError(t, err)
+	signerAddr, err := signer.ToWavesAddress(server.scheme)
+	require.NoError(t, err)
+	other, err := proto.NewEthereumAddressFromHexString("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")
+	require.NoError(t, err)
+	valid, invalid := true, false
+	for _, tc := range []struct {
+		address []byte
+		valid   *bool
+	}{
+		{nil, nil},
+		{signer.Bytes(), &valid},
+		{other.Bytes(), &invalid},
+	} {
+		res, err := cl.VerifyTypedData(ctx, &gg.TypedDataVerificationRequest{
+			TypedData: testMailTypedData,
+			Signature: sig,
+			Address:   tc.address,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, h.Hash, res.Hash)
+		assert.Equal(t, signer.Bytes(), res.Signer)
+		assert.Len(t, res.SignerPublicKey, 64)
+		assert.Equal(t, signerAddr.Bytes(), res.SignerAddress)
+		assert.Equal(t, tc.valid, res.Valid)
+	}
+
+	_, err = cl.VerifyTypedData(ctx, &gg.TypedDataVerificationRequest{TypedData: testMailTypedData, Signature: []byte{0}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = cl.VerifyTypedData(ctx, &gg.TypedDataVerificationRequest{
+		TypedData: strings.Replace(testMailTypedData, `"Mail"`, `"Unknown"`, 1),
+		Signature: sig,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
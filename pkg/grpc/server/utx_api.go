@@ -0,0 +1,127 @@
+package server
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+func newUtxFilter(scheme proto.Scheme, req *gg.UnconfirmedRequest) (*types.UtxFilter, error) {
+	filter := &types.UtxFilter{}
+	c := proto.ProtobufConverter{FallbackChainID: scheme}
+	if req.Sender != nil {
+		addr, err := c.Address(scheme, req.Sender)
+		if err != nil {
+			return nil, err
+		}
+		filter.Sender = &addr
+	}
+	if req.Recipient != nil {
+		rcp, err := c.Recipient(scheme, req.Recipient)
+		if err != nil {
+			return nil, err
+		}
+		filter.Recipient = &rcp
+	}
+	for _, id := range req.TransactionIds {
+		d, err := crypto.NewDigestFromBytes(id)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid transaction ID")
+		}
+		filter.IDs = append(filter.IDs, d)
+	}
+	for _, t := range req.Types {
+		if t < 0 || t > math.MaxUint8 {
+			return nil, errors.Errorf("invalid transaction type %d", t)
+		}
+		filter.Types = append(filter.Types, proto.TransactionType(t))
+	}
+	if req.MinFee < 0 {
+		return nil, errors.Errorf("invalid minimal fee %d", req.MinFee)
+	}
+	filter.MinFee = uint64(req.MinFee)
+	return filter, nil
+}
+
+func (s *Server) unconfirmedTransaction(tx *types.TransactionWithBytes) (*gg.UnconfirmedTransaction, error) {
+	id, err := tx.T.GetID(s.scheme)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.T.ToProtobufSigned(s.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &gg.UnconfirmedTransaction{Id: id, Transaction: signed}, nil
+}
+
+func (s *Server) utxEvent(e types.UtxEvent) (*gg.UtxEvent, error) {
+	tx, err := s.unconfirmedTransaction(e.Tx)
+	if err != nil {
+		return nil, err
+	}
+	res := &gg.UtxEvent{Type: gg.UtxEvent_ADDED, Transaction: tx}
+	if e.Type == types.UtxTransactionRemoved {
+		res.Type = gg.UtxEvent_REMOVED
+	}
+	return res, nil
+}
+
+func (s *Server) ListUnconfirmed(req *gg.UnconfirmedRequest, srv gg.UtxApi_ListUnconfirmedServer) error {
+	filter, err := newUtxFilter(s.scheme, req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	for _, tx := range s.utx.Transactions(filter) {
+		res, err := s.unconfirmedTransaction(tx)
+		if err != nil {
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		if err := srv.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubscribeUnconfirmed sends the matching transactions of the pool as ADDED events and then streams the changes
+// of the pool. Snapshot is taken atomically with the subscription, so no change is missed or repeated.
+func (s *Server) SubscribeUnconfirmed(req *gg.UnconfirmedRequest, srv gg.UtxApi_SubscribeUnconfirmedServer) error {
+	filter, err := newUtxFilter(s.scheme, req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	sub, snapshot := s.utx.Subscribe(filter)
+	defer s.utx.Unsubscribe(sub)
+	send := func(e types.UtxEvent) error {
+		res, err := s.utxEvent(e)
+		if err != nil {
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		return srv.Send(res)
+	}
+	for _, e := range snapshot {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		case <-sub.Overflow():
+			return status.Errorf(codes.ResourceExhausted, "subscriber is too slow")
+		case e := <-sub.Events():
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
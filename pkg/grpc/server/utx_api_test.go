@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	pb "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	"github.com/wavesplatform/gowaves/pkg/miner/utxpool"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUtxApi(t *testing.T) {
+	params := defaultStateParams()
+	st := newTestState(t, true, params, settings.MainNetSettings)
+	ctx := withAutoCancel(t, context.Background())
+	sch := createTestNetWallet(t)
+	utx := utxpool.New(utxSize, utxpool.NoOpValidator{}, settings.MainNetSettings)
+	err := server.initServer(st, utx, sch)
+	require.NoError(t, err)
+
+	conn := connectAutoClose(t, grpcTestAddr)
+
+	addr, err := proto.NewAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	require.NoError(t, err)
+	sk, pk, err := crypto.GenerateKeyPair([]byte("whatever"))
+	require.NoError(t, err)
+	waves := proto.NewOptionalAssetWaves()
+	transfer := func(amount, fee uint64) *proto.TransferWithSig {
+		tx := proto.NewUnsignedTransferWithSig(pk, waves, waves, 100, amount, fee, proto.NewRecipientFromAddress(addr), nil)
+		require.NoError(t, tx.Sign(server.scheme, sk))
+		txBytes, err := tx.MarshalBinary(server.scheme)
+		require.NoError(t, err)
+		require.NoError(t, utx.AddWithBytes(tx, txBytes))
+		return tx
+	}
+	cheap := transfer(1, 100)
+	expensive := transfer(2, 100000)
+
+	cl := gg.NewUtxApiClient(conn)
+	ids := func(stream gg.UtxApi_ListUnconfirmedClient) [][]byte {
+		var r [][]byte
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				return r
+			}
+			require.NoError(t, err)
+			r = append(r, res.Id)
+		}
+	}
+	stream, err := cl.ListUnconfirmed(ctx, &gg.UnconfirmedRequest{
+		Recipient: &pb.Recipient{Recipient: &pb.Recipient_PublicKeyHash{PublicKeyHash: addr.Body()}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{expensive.ID.Bytes(), cheap.ID.Bytes()}, ids(stream))
+
+	stream, err = cl.ListUnconfirmed(ctx, &gg.UnconfirmedRequest{MinFee: 1000, Types: []int32{int32(proto.TransferTransaction)}})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{expensive.ID.Bytes()}, ids(stream))
+
+	stream, err = cl.ListUnconfirmed(ctx, &gg.UnconfirmedRequest{MinFee: -1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	sub, err := cl.SubscribeUnconfirmed(ctx, &gg.UnconfirmedRequest{MinFee: 1000})
+	require.NoError(t, err)
+	e, err := sub.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, gg.UtxEvent_ADDED, e.Type)
+	assert.Equal(t, expensive.ID.Bytes(), e.Transaction.Id)
+
+	added := transfer(3, 200000)
+	e, err = sub.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, gg.UtxEvent_ADDED, e.Type)
+	assert.Equal(t, added.ID.Bytes(), e.Transaction.Id)
+	signed, err := added.ToProtobufSigned(server.scheme)
+	require.NoError(t, err)
+	assert.Equal(t, signed.String(), e.Transaction.Transaction.String())
+
+	require.NotNil(t, utx.Pop())
+	utx.ReleasePopped()
+	e, err = sub.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, gg.UtxEvent_REMOVED, e.Type)
+	assert.Equal(t, added.ID.Bytes(), e.Transaction.Id)
+}
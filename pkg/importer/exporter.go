@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const (
+	exportProgressInterval = 10000
+	firstBlockHeight       = 2 // The block following the genesis block
+)
+
+// Format is the encoding of blocks in the blockchain file.
+type Format byte
+
+const (
+	// BinaryFormat encodes blocks of versions before 5 in legacy binary format and newer blocks in protobuf.
+	// This is the format read by ApplyFromFile.
+	BinaryFormat Format = iota
+	// ProtobufFormat encodes all blocks in protobuf.
+	ProtobufFormat
+)
+
+func NewFormatFromString(s string) (Format, error) {
+	switch s {
+	case "binary":
+		return BinaryFormat, nil
+	case "protobuf":
+		return ProtobufFormat, nil
+	default:
+		return 0, errors.Errorf("unknown blocks format '%s'", s)
+	}
+}
+
+func (f Format) String() string {
+	switch f {
+	case BinaryFormat:
+		return "binary"
+	case ProtobufFormat:
+		return "protobuf"
+	default:
+		return "unknown"
+	}
+}
+
+type BlocksSource interface {
+	Height() (uint64, error)
+	BlockByHeight(height uint64) (*proto.Block, error)
+}
+
+// WriteToFile writes blocks from startHeight to endHeight inclusively to w in the blockchain file format:
+// each block is prefixed with its size as 4 bytes big-endian integer.
+// Zero endHeight means the current height of the source. The genesis block is never written because it's
+// generated from the blockchain settings, so the file to be imported by ApplyFromFile must start at height 2.
+func WriteToFile(w io.Writer, src BlocksSource, scheme proto.Scheme, format Format, startHeight, endHeight uint64) (uint64, error) {
+	height, err := src.Height()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get height")
+	}
+	if endHeight == 0 {
+		endHeight = height
+	}
+	if startHeight < firstBlockHeight || startHeight > endHeight || endHeight > height {
+		return 0, errors.Errorf("invalid heights range [%d, %d], valid range is [%d, %d]",
+			startHeight, endHeight, firstBlockHeight, height)
+	}
+	bw := bufio.NewWriter(w)
+	sb := make([]byte, 4)
+	for h := startHeight; h <= endHeight; h++ {
+		block, err := src.BlockByHeight(h)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get block at height %d", h)
+		}
+		var b []byte
+		switch format {
+		case BinaryFormat:
+			b, err = block.Marshal(scheme)
+		case ProtobufFormat:
+			b, err = block.MarshalToProtobuf(scheme)
+		default:
+			return 0, errors.Errorf("unsupported blocks format %d", format)
+		}
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to marshal block at height %d", h)
+		}
+		if len(b) > MaxBlockSize {
+			return 0, errors.Errorf("block at height %d is too big: %d bytes", h, len(b))
+		}
+		binary.BigEndian.PutUint32(sb, uint32(len(b)))
+		if _, err := bw.Write(sb); err != nil {
+			return 0, err
+		}
+		if _, err := bw.Write(b); err != nil {
+			return 0, err
+		}
+		if (h-startHeight+1)%exportProgressInterval == 0 {
+			zap.S().Infof("Exported %d blocks, height %d", h-startHeight+1, h)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return endHeight - startHeight + 1, nil
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const testBlocksNumber = 100
+
+// testBlocksSource holds blocks starting from height 2, like the blockchain file.
+type testBlocksSource []*proto.Block
+
+func (s testBlocksSource) Height() (uint64, error) {
+	return uint64(len(s)) + 1, nil
+}
+
+func (s testBlocksSource) BlockByHeight(height uint64) (*proto.Block, error) {
+	if height < 2 || height > uint64(len(s))+1 {
+		return nil, errors.New("not found")
+	}
+	return s[height-2], nil
+}
+
+// readTestBlocks reads blocks from the blockchain file used by state tests, it returns blocks and the file prefix.
+func readTestBlocks(t *testing.T, n int) (testBlocksSource, []byte) {
+	data, err := os.ReadFile(filepath.Join("..", "state", "testdata", "blocks-10000"))
+	require.NoError(t, err)
+	blocks := make(testBlocksSource, n)
+	pos := 0
+	for i := 0; i < n; i++ {
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		pos += 4
+		block := new(proto.Block)
+		require.NoError(t, block.UnmarshalBinary(data[pos:pos+size], proto.MainNetScheme))
+		blocks[i] = block
+		pos += size
+	}
+	return blocks, data[:pos]
+}
+
+func TestWriteToFile(t *testing.T) {
+	blocks, expected := readTestBlocks(t, testBlocksNumber)
+
+	buf := new(bytes.Buffer)
+	n, err := WriteToFile(buf, blocks, proto.MainNetScheme, BinaryFormat, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(testBlocksNumber), n)
+	assert.Equal(t, expected, buf.Bytes())
+
+	buf.Reset()
+	n, err = WriteToFile(buf, blocks, proto.MainNetScheme, ProtobufFormat, 10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), n)
+	data := buf.Bytes()
+	for h := 10; h <= 20; h++ {
+		size := binary.BigEndian.Uint32(data)
+		block := new(proto.Block)
+		require.NoError(t, block.UnmarshalFromProtobuf(data[4:4+size]))
+		assert.Equal(t, blocks[h-2].BlockID(), block.BlockID())
+		data = data[4+size:]
+	}
+	assert.Empty(t, data)
+
+	for _, r := range [][2]uint64{{1, 10}, {20, 10}, {2, testBlocksNumber + 2}} {
+		_, err = WriteToFile(new(bytes.Buffer), blocks, proto.MainNetScheme, BinaryFormat, r[0], r[1])
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,151 @@
+package light
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+)
+
+type headerResponse struct {
+	*proto.BlockHeader
+	Height proto.Height `json:"height"`
+}
+
+type verifyRequest struct {
+	Transaction      json.RawMessage  `json:"transaction"`
+	Height           proto.Height     `json:"height"`
+	TransactionIndex uint64           `json:"transactionIndex"`
+	MerkleProof      []proto.B58Bytes `json:"merkleProof"`
+}
+
+type verifyResponse struct {
+	ID    string `json:"id"`
+	Valid bool   `json:"valid"`
+}
+
+// API is the HTTP API of the light node.
+type API struct {
+	chain  *Chain
+	scheme proto.Scheme
+}
+
+func NewAPI(chain *Chain, scheme proto.Scheme) *API {
+	return &API{chain: chain, scheme: scheme}
+}
+
+func (a *API) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/blocks/height", a.height)
+	r.Get("/blocks/headers/last", a.lastHeader)
+	r.Get("/blocks/headers/at/{height}", a.headerAt)
+	r.Post("/transactions/verify", a.verify)
+	return r
+}
+
+func sendJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		zap.S().Debugf("Failed to send light node API response: %v", err)
+	}
+}
+
+func sendHeader(w http.ResponseWriter, chain *Chain, height proto.Height) {
+	header, err := chain.HeaderByHeight(height)
+	if IsNotFound(err) {
+		http.Error(w, "block does not exist", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, headerResponse{BlockHeader: header, Height: height})
+}
+
+func (a *API) height(w http.ResponseWriter, _ *http.Request) {
+	height, err := a.chain.Height()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, struct {
+		Height proto.Height `json:"height"`
+	}{height})
+}
+
+func (a *API) lastHeader(w http.ResponseWriter, _ *http.Request) {
+	height, err := a.chain.Height()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendHeader(w, a.chain, height)
+}
+
+func (a *API) headerAt(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.ParseUint(chi.URLParam(r, "height"), 10, 64)
+	if err != nil || height == 0 {
+		http.Error(w, "invalid height", http.StatusBadRequest)
+		return
+	}
+	sendHeader(w, a.chain, height)
+}
+
+func (a *API) unmarshalTransaction(b []byte) (proto.Transaction, error) {
+	tt := proto.TransactionTypeVersion{}
+	if err := json.Unmarshal(b, &tt); err != nil {
+		return nil, err
+	}
+	tx, err := proto.GuessTransactionType(&tt)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.UnmarshalTransactionFromJSON(b, a.scheme, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// verify checks the inclusion of transaction into the block with the merkle proof.
+func (a *API) verify(w http.ResponseWriter, r *http.Request) {
+	req := verifyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, errors.Wrap(err, "invalid request").Error(), http.StatusBadRequest)
+		return
+	}
+	tx, err := a.unmarshalTransaction(req.Transaction)
+	if err != nil {
+		http.Error(w, errors.Wrap(err, "invalid transaction").Error(), http.StatusBadRequest)
+		return
+	}
+	proofs := make([]crypto.Digest, len(req.MerkleProof))
+	for i, p := range req.MerkleProof {
+		d, err := crypto.NewDigestFromBytes(p)
+		if err != nil {
+			http.Error(w, errors.Wrap(err, "invalid merkle proof").Error(), http.StatusBadRequest)
+			return
+		}
+		proofs[i] = d
+	}
+	id, err := tx.GetID(a.scheme)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	valid, err := a.chain.VerifyTransaction(tx, req.Height, req.TransactionIndex, proofs)
+	if IsNotFound(err) {
+		http.Error(w, "block does not exist", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sendJSON(w, verifyResponse{ID: proto.B58Bytes(id).String(), Valid: valid})
+}
@@ -0,0 +1,192 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/consensus"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+const balanceRequestTimeout = 30 * time.Second
+
+// Chain is the chain of validated block headers.
+//
+// Every appended header is checked to reference the previous one, to be signed by its generator,
+// to carry the valid generation signature (VRF after BlockV5 activation) and to satisfy the PoS rules:
+// block delay, base target, timestamp and version. Generating balances of the miners and feature activation
+// heights can't be derived from the headers, they are requested from the Source and trusted,
+// see the package documentation for the details.
+type Chain struct {
+	mu          sync.Mutex
+	storage     *Storage
+	source      Source
+	settings    *settings.BlockchainSettings
+	cv          *consensus.Validator
+	activations map[int16]proto.Height
+}
+
+// NewChain creates the chain over the storage, genesis block from the settings is stored if the storage is empty.
+func NewChain(storage *Storage, source Source, bs *settings.BlockchainSettings, tm types.Time) (*Chain, error) {
+	c := &Chain{
+		storage:     storage,
+		source:      source,
+		settings:    bs,
+		activations: make(map[int16]proto.Height),
+	}
+	c.cv = consensus.NewValidator(c, bs, tm)
+	for _, f := range bs.PreactivatedFeatures {
+		c.activations[f] = 1
+	}
+	height, err := storage.Height()
+	if err != nil {
+		return nil, err
+	}
+	genesis := bs.Genesis
+	if err := genesis.GenerateBlockID(bs.AddressSchemeCharacter); err != nil {
+		return nil, errors.Wrap(err, "failed to generate genesis block ID")
+	}
+	if height == 0 {
+		if _, err := storage.append(&genesis.BlockHeader, genesis.GenSignature); err != nil {
+			return nil, errors.Wrap(err, "failed to store genesis block")
+		}
+		return c, nil
+	}
+	stored, err := storage.HeaderByHeight(1)
+	if err != nil {
+		return nil, err
+	}
+	if stored.BlockID() != genesis.BlockID() {
+		return nil, errors.New("genesis blocks from storage and config mismatch")
+	}
+	return c, nil
+}
+
+// UpdateActivations requests the activated features from the source.
+func (c *Chain) UpdateActivations(ctx context.Context) error {
+	activations, err := c.source.ActivationHeights(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get activated features")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for f, h := range activations {
+		if _, ok := c.activations[f]; !ok {
+			c.activations[f] = h
+		}
+	}
+	return nil
+}
+
+func (c *Chain) Height() (proto.Height, error) {
+	return c.storage.Height()
+}
+
+func (c *Chain) HeaderByHeight(height uint64) (*proto.BlockHeader, error) {
+	return c.storage.HeaderByHeight(height)
+}
+
+func (c *Chain) TopHeader() (*proto.BlockHeader, error) {
+	height, err := c.storage.Height()
+	if err != nil {
+		return nil, err
+	}
+	return c.storage.HeaderByHeight(height)
+}
+
+func (c *Chain) NewestHitSourceAtHeight(height uint64) ([]byte, error) {
+	return c.storage.HitSourceAtHeight(height)
+}
+
+func (c *Chain) NewestEffectiveBalance(addr proto.Recipient, startHeight, endHeight uint64) (uint64, error) {
+	if addr.Address() == nil {
+		return 0, errors.New("effective balance of alias is not supported")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), balanceRequestTimeout)
+	defer cancel()
+	return c.source.EffectiveBalance(ctx, *addr.Address(), startHeight, endHeight)
+}
+
+func (c *Chain) NewestIsActiveAtHeight(featureID int16, height proto.Height) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.activations[featureID]
+	return ok && height >= h, nil
+}
+
+// NewestAccountHasScript always reports that the account has no script, so the miners are not checked for scripts.
+// Sources provide the current scripts only, which don't match the scripts at the heights of historical blocks.
+func (c *Chain) NewestAccountHasScript(_ proto.WavesAddress) (bool, error) {
+	return false, nil
+}
+
+// Append validates the block and stores its header on top of the chain.
+// Transactions are required only for blocks of versions prior to 5 to check the signature.
+func (c *Chain) Append(block *proto.Block) (proto.Height, error) {
+	top, err := c.TopHeader()
+	if err != nil {
+		return 0, err
+	}
+	height, err := c.storage.Height()
+	if err != nil {
+		return 0, err
+	}
+	if block.Parent != top.BlockID() {
+		return 0, errors.Errorf("block '%s' doesn't reference the last block '%s'", block.BlockID().String(), top.BlockID().String())
+	}
+	ok, err := block.VerifySignature(c.settings.AddressSchemeCharacter)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to verify signature of block '%s'", block.BlockID().String())
+	}
+	if !ok {
+		return 0, errors.Errorf("invalid signature of block '%s'", block.BlockID().String())
+	}
+	hs, err := c.cv.GenerateHitSource(height, block.BlockHeader)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.cv.ValidateHeadersBatch([]proto.BlockHeader{block.BlockHeader}, height); err != nil {
+		return 0, err
+	}
+	return c.storage.append(&block.BlockHeader, hs)
+}
+
+// Rollback removes the headers above the given height, the genesis block can't be removed.
+func (c *Chain) Rollback(height proto.Height) error {
+	if height < 1 {
+		return errors.New("genesis block can't be removed")
+	}
+	return c.storage.rollback(height)
+}
+
+// VerifyTransaction checks that the transaction is included in the block at the given height
+// using the merkle proof of transaction and the transactions root of the stored header.
+func (c *Chain) VerifyTransaction(tx proto.Transaction, height proto.Height, index uint64, proofs []crypto.Digest) (bool, error) {
+	header, err := c.storage.HeaderByHeight(height)
+	if err != nil {
+		return false, err
+	}
+	if header.Version < proto.ProtobufBlockVersion {
+		return false, errors.Errorf("no transactions root in block of version %d", header.Version)
+	}
+	b, err := tx.MerkleBytes(c.settings.AddressSchemeCharacter)
+	if err != nil {
+		return false, err
+	}
+	leaf, err := crypto.FastHash(b)
+	if err != nil {
+		return false, err
+	}
+	tree, err := crypto.NewMerkleTree()
+	if err != nil {
+		return false, err
+	}
+	root := tree.RebuildRoot(leaf, proofs, index)
+	return bytes.Equal(root.Bytes(), header.TransactionsRoot), nil
+}
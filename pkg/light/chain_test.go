@@ -0,0 +1,72 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+type testTime struct{}
+
+func (testTime) Now() time.Time { return time.Now() }
+
+func TestChain_VerifyTransaction(t *testing.T) {
+	s, err := newMemStorage(proto.TestNetScheme)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+	c, err := NewChain(s, nil, settings.TestNetSettings, testTime{})
+	require.NoError(t, err)
+
+	sk, pk, err := crypto.GenerateKeyPair([]byte("sender"))
+	require.NoError(t, err)
+	rcp := proto.NewRecipientFromAddress(proto.MustAddressFromString("3MsX9C2MzzxE4ySF5aYcJoaiPfkyxZMg4cW"))
+	waves := proto.NewOptionalAssetWaves()
+	txs := make([]proto.Transaction, 3)
+	tree, err := crypto.NewMerkleTree()
+	require.NoError(t, err)
+	leaves := make([]crypto.Digest, len(txs))
+	for i := range txs {
+		tx := proto.NewUnsignedTransferWithSig(pk, waves, waves, uint64(i), 1, 100000, rcp, nil)
+		require.NoError(t, tx.Sign(proto.TestNetScheme, sk))
+		b, err := tx.MerkleBytes(proto.TestNetScheme)
+		require.NoError(t, err)
+		tree.Push(b)
+		leaves[i], err = crypto.FastHash(b)
+		require.NoError(t, err)
+		txs[i] = tx
+	}
+	root := tree.Root()
+	genesis, err := c.TopHeader()
+	require.NoError(t, err)
+	_, err = s.append(testHeader(t, genesis.BlockID(), 1000, root.Bytes()), []byte{1})
+	require.NoError(t, err)
+
+	// Tree of three transactions is padded with zero digest
+	proof := func(i int) []crypto.Digest {
+		d, err := crypto.FastHash(append(leaves[2].Bytes(), crypto.ZeroDigest.Bytes()...))
+		require.NoError(t, err)
+		return []crypto.Digest{d, leaves[1-i]}
+	}
+	for i := 0; i < 2; i++ {
+		ok, err := c.VerifyTransaction(txs[i], 2, uint64(i), proof(i))
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := c.VerifyTransaction(txs[0], 2, 1, proof(0))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = c.VerifyTransaction(txs[2], 2, 0, proof(0))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Legacy blocks have no transactions root
+	_, err = c.VerifyTransaction(txs[0], 1, 0, proof(0))
+	assert.Error(t, err)
+	_, err = c.VerifyTransaction(txs[0], 3, 0, proof(0))
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,23 @@
+/*
+Package light implements the light node, which follows the chain by validating block headers and keeps no state.
+
+# Trust model
+
+Blocks are requested from the source nodes but not trusted: every header is checked to reference the previous one,
+to be signed by its generator and to satisfy the PoS rules, so a source can't forge or reorder the blocks.
+
+Some data needed for the validation can't be derived from the headers and is trusted instead:
+
+  - generating balances of the miners, which are used to check the generation signature and the block delay;
+  - activation heights of the features, which change the consensus rules.
+
+A single source could make the light node accept a chain of blocks generated by accounts with insufficient balances
+or validated by the wrong rules. To reduce the trust, several sources can be set with CrossCheckedSource, then
+the trusted data is accepted only if all sources agree on it. Forging the data then requires all sources to collude,
+so they should be operated independently.
+
+Miner accounts are not checked for scripts. Mining with scripted accounts is forbidden before RideV6 activation
+only, but the sources can tell the current scripts of accounts, not the scripts at the heights of historical blocks,
+so the check would reject valid blocks of the accounts which set scripts later.
+*/
+package light
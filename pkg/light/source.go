@@ -0,0 +1,199 @@
+package light
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	gg "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
+	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// Source provides the blocks to follow and the data which can't be derived from the headers:
+// generating balances of the miners and activation heights of the features.
+// Blocks are not trusted and checked by the light node, the rest of the data is trusted.
+type Source interface {
+	Height(ctx context.Context) (proto.Height, error)
+	// Headers returns blocks in the range of heights without transactions.
+	Headers(ctx context.Context, from, to proto.Height) ([]*proto.Block, error)
+	// Block returns the block at the height with transactions.
+	Block(ctx context.Context, height proto.Height) (*proto.Block, error)
+	ActivationHeights(ctx context.Context) (map[int16]proto.Height, error)
+	EffectiveBalance(ctx context.Context, addr proto.WavesAddress, from, to proto.Height) (uint64, error)
+}
+
+// GRPCSource is the Source backed by gRPC API of the full node.
+type GRPCSource struct {
+	scheme     proto.Scheme
+	blocks     g.BlocksApiClient
+	blockchain g.BlockchainApiClient
+	consensus  gg.ConsensusApiClient
+}
+
+func NewGRPCSource(conn grpc.ClientConnInterface, scheme proto.Scheme) *GRPCSource {
+	return &GRPCSource{
+		scheme:     scheme,
+		blocks:     g.NewBlocksApiClient(conn),
+		blockchain: g.NewBlockchainApiClient(conn),
+		consensus:  gg.NewConsensusApiClient(conn),
+	}
+}
+
+func (s *GRPCSource) Height(ctx context.Context) (proto.Height, error) {
+	res, err := s.blocks.GetCurrentHeight(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return proto.Height(res.Value), nil
+}
+
+func (s *GRPCSource) Headers(ctx context.Context, from, to proto.Height) ([]*proto.Block, error) {
+	stream, err := s.blocks.GetBlockRange(ctx, &g.BlockRangeRequest{FromHeight: uint32(from), ToHeight: uint32(to)})
+	if err != nil {
+		return nil, err
+	}
+	c := proto.ProtobufConverter{FallbackChainID: s.scheme}
+	res := make([]*proto.Block, 0, to-from+1)
+	for {
+		b, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		header, err := c.BlockHeader(b.Block)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid header at height %d", b.Height)
+		}
+		res = append(res, &proto.Block{BlockHeader: header})
+	}
+}
+
+func (s *GRPCSource) Block(ctx context.Context, height proto.Height) (*proto.Block, error) {
+	b, err := s.blocks.GetBlock(ctx, &g.BlockRequest{
+		Request:             &g.BlockRequest_Height{Height: int32(height)},
+		IncludeTransactions: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := proto.ProtobufConverter{FallbackChainID: s.scheme}
+	block, err := c.Block(b.Block)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid block at height %d", height)
+	}
+	return &block, nil
+}
+
+func (s *GRPCSource) ActivationHeights(ctx context.Context) (map[int16]proto.Height, error) {
+	height, err := s.Height(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.blockchain.GetActivationStatus(ctx, &g.ActivationStatusRequest{Height: int32(height)})
+	if err != nil {
+		return nil, err
+	}
+	activations := make(map[int16]proto.Height)
+	for _, f := range res.Features {
+		if f.BlockchainStatus == g.FeatureActivationStatus_ACTIVATED {
+			activations[int16(f.Id)] = proto.Height(f.ActivationHeight)
+		}
+	}
+	return activations, nil
+}
+
+func (s *GRPCSource) EffectiveBalance(ctx context.Context, addr proto.WavesAddress, from, to proto.Height) (uint64, error) {
+	res, err := s.consensus.GetEffectiveBalance(ctx, &gg.EffectiveBalanceRequest{
+		Address:    addr.Bytes(),
+		FromHeight: int32(from),
+		ToHeight:   int32(to),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return uint64(res.Balance), nil
+}
+
+// CrossCheckedSource reduces the trust in a single source by asking several of them. Blocks are taken from the first
+// source because they are validated anyway, the trusted data is accepted only if all sources agree on it.
+type CrossCheckedSource struct {
+	sources []Source
+}
+
+func NewCrossCheckedSource(sources ...Source) *CrossCheckedSource {
+	return &CrossCheckedSource{sources: sources}
+}
+
+// Height returns the lowest height of the sources, so the data up to it can be requested from all of them.
+func (s *CrossCheckedSource) Height(ctx context.Context) (proto.Height, error) {
+	var res proto.Height
+	for i, src := range s.sources {
+		h, err := src.Height(ctx)
+		if err != nil {
+			return 0, errors.Wrapf(err, "source %d", i)
+		}
+		if i == 0 || h < res {
+			res = h
+		}
+	}
+	return res, nil
+}
+
+func (s *CrossCheckedSource) Headers(ctx context.Context, from, to proto.Height) ([]*proto.Block, error) {
+	return s.sources[0].Headers(ctx, from, to)
+}
+
+func (s *CrossCheckedSource) Block(ctx context.Context, height proto.Height) (*proto.Block, error) {
+	return s.sources[0].Block(ctx, height)
+}
+
+// ActivationHeights returns the features activated according to all sources. Feature activated by some sources
+// only is omitted until the rest of the sources reach its activation, error is returned if activation heights differ.
+func (s *CrossCheckedSource) ActivationHeights(ctx context.Context) (map[int16]proto.Height, error) {
+	var res map[int16]proto.Height
+	for i, src := range s.sources {
+		activations, err := src.ActivationHeights(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "source %d", i)
+		}
+		if i == 0 {
+			res = make(map[int16]proto.Height, len(activations))
+			for f, h := range activations {
+				res[f] = h
+			}
+			continue
+		}
+		for f, h := range res {
+			other, ok := activations[f]
+			if !ok {
+				delete(res, f)
+				continue
+			}
+			if other != h {
+				return nil, errors.Errorf("sources disagree on activation height of feature %d: %d and %d", f, h, other)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *CrossCheckedSource) EffectiveBalance(ctx context.Context, addr proto.WavesAddress, from, to proto.Height) (uint64, error) {
+	var res uint64
+	for i, src := range s.sources {
+		b, err := src.EffectiveBalance(ctx, addr, from, to)
+		if err != nil {
+			return 0, errors.Wrapf(err, "source %d", i)
+		}
+		if i > 0 && b != res {
+			return 0, errors.Errorf("sources disagree on effective balance of '%s': %d and %d", addr.String(), res, b)
+		}
+		res = b
+	}
+	return res, nil
+}
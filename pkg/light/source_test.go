@@ -0,0 +1,67 @@
+package light
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// fixedSource reports the same data regardless of the request.
+type fixedSource struct {
+	height      proto.Height
+	activations map[int16]proto.Height
+	balance     uint64
+}
+
+func (s *fixedSource) Height(_ context.Context) (proto.Height, error) {
+	return s.height, nil
+}
+
+func (s *fixedSource) Headers(_ context.Context, _, _ proto.Height) ([]*proto.Block, error) {
+	return nil, nil
+}
+
+func (s *fixedSource) Block(_ context.Context, _ proto.Height) (*proto.Block, error) {
+	return nil, nil
+}
+
+func (s *fixedSource) ActivationHeights(_ context.Context) (map[int16]proto.Height, error) {
+	return s.activations, nil
+}
+
+func (s *fixedSource) EffectiveBalance(_ context.Context, _ proto.WavesAddress, _, _ proto.Height) (uint64, error) {
+	return s.balance, nil
+}
+
+func TestCrossCheckedSource(t *testing.T) {
+	ctx := context.Background()
+	addr := proto.MustAddressFromString("3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3")
+	first := &fixedSource{height: 120, activations: map[int16]proto.Height{1: 10, 2: 100}, balance: 1000}
+	second := &fixedSource{height: 110, activations: map[int16]proto.Height{1: 10}, balance: 1000}
+	s := NewCrossCheckedSource(first, second)
+
+	h, err := s.Height(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 110, h)
+
+	// Feature activated by the first source only is not accepted yet
+	activations, err := s.ActivationHeights(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int16]proto.Height{1: 10}, activations)
+	assert.Len(t, first.activations, 2)
+
+	b, err := s.EffectiveBalance(ctx, addr, 1, 100)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, b)
+
+	// Sources disagree
+	second.activations[1] = 11
+	_, err = s.ActivationHeights(ctx)
+	assert.Error(t, err)
+	second.balance = 1001
+	_, err = s.EffectiveBalance(ctx, addr, 1, 100)
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package light
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// StorageDir is the name of the directory inside the state directory where the headers are stored.
+const StorageDir = "light"
+
+const (
+	headerKeyPrefix byte = iota
+	heightKeyPrefix
+	hitSourceKeyPrefix
+	topKey
+)
+
+var errNotFound = errors.New("not found")
+
+// IsNotFound checks that the error is returned for the missing header.
+func IsNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+// Storage keeps the validated headers of the chain along with the hit sources of the blocks.
+// Headers are stored in protobuf format by heights, heights are indexed by block IDs.
+type Storage struct {
+	db     *leveldb.DB
+	scheme proto.Scheme
+}
+
+func NewStorage(path string, scheme proto.Scheme) (*Storage, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open headers storage %q", path)
+	}
+	return &Storage{db: db, scheme: scheme}, nil
+}
+
+func newMemStorage(scheme proto.Scheme) (*Storage, error) {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Storage{db: db, scheme: scheme}, nil
+}
+
+func heightBytes(height proto.Height) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
+
+func headerKey(height proto.Height) []byte {
+	return append([]byte{headerKeyPrefix}, heightBytes(height)...)
+}
+
+func heightKey(id proto.BlockID) []byte {
+	return append([]byte{heightKeyPrefix}, id.Bytes()...)
+}
+
+func hitSourceKey(height proto.Height) []byte {
+	return append([]byte{hitSourceKeyPrefix}, heightBytes(height)...)
+}
+
+func (s *Storage) get(key []byte) ([]byte, error) {
+	v, err := s.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, errNotFound
+	}
+	return v, err
+}
+
+// Height returns the height of the last stored header, zero means that the storage is empty.
+func (s *Storage) Height() (proto.Height, error) {
+	v, err := s.get([]byte{topKey})
+	if IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (s *Storage) HeaderByHeight(height proto.Height) (*proto.BlockHeader, error) {
+	v, err := s.get(headerKey(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get header at height %d", height)
+	}
+	if len(v) < 4 {
+		return nil, errors.Errorf("invalid header record at height %d", height)
+	}
+	var pb g.Block
+	if err := protobuf.Unmarshal(v[4:], &pb); err != nil {
+		return nil, err
+	}
+	c := proto.ProtobufConverter{FallbackChainID: s.scheme}
+	header, err := c.BlockHeader(&pb)
+	if err != nil {
+		return nil, err
+	}
+	header.TransactionCount = int(binary.BigEndian.Uint32(v[:4]))
+	return &header, nil
+}
+
+func (s *Storage) HeightByID(id proto.BlockID) (proto.Height, error) {
+	v, err := s.get(heightKey(id))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get height of block %s", id.String())
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (s *Storage) HitSourceAtHeight(height proto.Height) ([]byte, error) {
+	v, err := s.get(hitSourceKey(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get hit source at height %d", height)
+	}
+	return v, nil
+}
+
+// append stores the header and its hit source at the next height.
+func (s *Storage) append(header *proto.BlockHeader, hitSource []byte) (proto.Height, error) {
+	top, err := s.Height()
+	if err != nil {
+		return 0, err
+	}
+	height := top + 1
+	pb, err := header.HeaderToProtobuf(s.scheme)
+	if err != nil {
+		return 0, err
+	}
+	b, err := protobuf.Marshal(pb)
+	if err != nil {
+		return 0, err
+	}
+	record := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(record[:4], uint32(header.TransactionCount))
+	copy(record[4:], b)
+	batch := new(leveldb.Batch)
+	batch.Put(headerKey(height), record)
+	batch.Put(heightKey(header.BlockID()), heightBytes(height))
+	batch.Put(hitSourceKey(height), hitSource)
+	batch.Put([]byte{topKey}, heightBytes(height))
+	if err := s.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// rollback removes the headers above the given height.
+func (s *Storage) rollback(height proto.Height) error {
+	top, err := s.Height()
+	if err != nil {
+		return err
+	}
+	if height >= top {
+		return nil
+	}
+	batch := new(leveldb.Batch)
+	for h := top; h > height; h-- {
+		header, err := s.HeaderByHeight(h)
+		if err != nil {
+			return err
+		}
+		batch.Delete(heightKey(header.BlockID()))
+		batch.Delete(headerKey(h))
+		batch.Delete(hitSourceKey(h))
+	}
+	batch.Put([]byte{topKey}, heightBytes(height))
+	return s.db.Write(batch, nil)
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,66 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func testHeader(t *testing.T, parent proto.BlockID, timestamp uint64, root []byte) *proto.BlockHeader {
+	_, pk, err := crypto.GenerateKeyPair([]byte("generator"))
+	require.NoError(t, err)
+	h := &proto.BlockHeader{
+		Version:            proto.ProtobufBlockVersion,
+		Timestamp:          timestamp,
+		Parent:             parent,
+		NxtConsensus:       proto.NxtConsensus{BaseTarget: 100, GenSignature: make([]byte, crypto.DigestSize)},
+		GeneratorPublicKey: pk,
+		TransactionsRoot:   root,
+	}
+	require.NoError(t, h.GenerateBlockID(proto.TestNetScheme))
+	return h
+}
+
+func TestStorage(t *testing.T) {
+	s, err := newMemStorage(proto.TestNetScheme)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+
+	height, err := s.Height()
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, height)
+
+	first := testHeader(t, proto.BlockID{}, 1000, nil)
+	second := testHeader(t, first.BlockID(), 2000, nil)
+	for i, h := range []*proto.BlockHeader{first, second} {
+		height, err := s.append(h, []byte{byte(i)})
+		require.NoError(t, err)
+		assert.EqualValues(t, i+1, height)
+	}
+
+	header, err := s.HeaderByHeight(2)
+	require.NoError(t, err)
+	assert.Equal(t, second.BlockID(), header.BlockID())
+	assert.Equal(t, second.Timestamp, header.Timestamp)
+	height, err = s.HeightByID(second.BlockID())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, height)
+	hs, err := s.HitSourceAtHeight(2)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, hs)
+
+	require.NoError(t, s.rollback(1))
+	height, err = s.Height()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, height)
+	_, err = s.HeaderByHeight(2)
+	assert.True(t, IsNotFound(err))
+	_, err = s.HeightByID(second.BlockID())
+	assert.True(t, IsNotFound(err))
+	header, err = s.HeaderByHeight(1)
+	require.NoError(t, err)
+	assert.Equal(t, first.BlockID(), header.BlockID())
+}
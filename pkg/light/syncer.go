@@ -0,0 +1,104 @@
+package light
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBatchSize = 100
+	// Maximum number of headers removed during the single synchronization in search of common block.
+	maxRollbackDepth = 100
+)
+
+// Syncer periodically appends new blocks from the source to the chain.
+// On fork the headers of the chain are removed one by one until the block from the source fits.
+type Syncer struct {
+	chain    *Chain
+	source   Source
+	interval time.Duration
+	batch    uint64
+}
+
+func NewSyncer(chain *Chain, source Source, interval time.Duration) *Syncer {
+	return &Syncer{chain: chain, source: source, interval: interval, batch: defaultBatchSize}
+}
+
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			zap.S().Warnf("Light node synchronization failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync appends blocks from the source until the height of the chain reaches the height of the source.
+func (s *Syncer) Sync(ctx context.Context) error {
+	if err := s.chain.UpdateActivations(ctx); err != nil {
+		return err
+	}
+	remote, err := s.source.Height(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get source height")
+	}
+	rollbacks := 0
+	for {
+		height, err := s.chain.Height()
+		if err != nil {
+			return err
+		}
+		if height >= remote {
+			return nil
+		}
+		to := height + s.batch
+		if to > remote {
+			to = remote
+		}
+		blocks, err := s.source.Headers(ctx, height+1, to)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get headers from %d to %d", height+1, to)
+		}
+		if len(blocks) == 0 {
+			return errors.Errorf("no headers from %d to %d", height+1, to)
+		}
+		top, err := s.chain.TopHeader()
+		if err != nil {
+			return err
+		}
+		if blocks[0].Parent != top.BlockID() {
+			if height <= 1 || rollbacks >= maxRollbackDepth {
+				return errors.Errorf("failed to find common block with source at height %d", height)
+			}
+			rollbacks++
+			if err := s.chain.Rollback(height - 1); err != nil {
+				return err
+			}
+			zap.S().Debugf("Light node rolled back to height %d", height-1)
+			continue
+		}
+		for i, b := range blocks {
+			if b.Version < proto.ProtobufBlockVersion {
+				// Signatures of legacy blocks are calculated over transactions too
+				b, err = s.source.Block(ctx, height+uint64(i)+1)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get block at height %d", height+uint64(i)+1)
+				}
+			}
+			if _, err := s.chain.Append(b); err != nil {
+				return errors.Wrapf(err, "invalid block at height %d", height+uint64(i)+1)
+			}
+		}
+		zap.S().Debugf("Light node synchronized to height %d", height+uint64(len(blocks)))
+	}
+}
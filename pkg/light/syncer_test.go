@@ -0,0 +1,92 @@
+package light
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+// stateSource serves blocks from the list and takes generating balances from the full state.
+type stateSource struct {
+	st     state.State
+	blocks []*proto.Block // Blocks starting from height 2
+}
+
+func (s *stateSource) Height(_ context.Context) (proto.Height, error) {
+	return proto.Height(len(s.blocks) + 1), nil
+}
+
+func (s *stateSource) Headers(_ context.Context, from, to proto.Height) ([]*proto.Block, error) {
+	var res []*proto.Block
+	for h := from; h <= to; h++ {
+		res = append(res, &proto.Block{BlockHeader: s.blocks[h-2].BlockHeader})
+	}
+	return res, nil
+}
+
+func (s *stateSource) Block(_ context.Context, height proto.Height) (*proto.Block, error) {
+	if height < 2 || height-2 >= uint64(len(s.blocks)) {
+		return nil, errors.New("no block")
+	}
+	return s.blocks[height-2], nil
+}
+
+func (s *stateSource) ActivationHeights(_ context.Context) (map[int16]proto.Height, error) {
+	return nil, nil
+}
+
+func (s *stateSource) EffectiveBalance(_ context.Context, addr proto.WavesAddress, from, to proto.Height) (uint64, error) {
+	return s.st.EffectiveBalance(proto.NewRecipientFromAddress(addr), from, to)
+}
+
+func TestSyncer_MainNet(t *testing.T) {
+	const height = 50
+	blocks, err := state.ReadMainnetBlocksToHeight(height)
+	require.NoError(t, err)
+	st, err := state.NewState(t.TempDir(), true, state.DefaultTestingStateParams(), settings.MainNetSettings)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, st.Close()) }()
+	_, err = st.AddDeserializedBlocks(blocks)
+	require.NoError(t, err)
+
+	s, err := newMemStorage(proto.MainNetScheme)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+	source := &stateSource{st: st, blocks: blocks}
+	c, err := NewChain(s, source, settings.MainNetSettings, testTime{})
+	require.NoError(t, err)
+
+	syncer := NewSyncer(c, source, 0)
+	syncer.batch = 7
+	require.NoError(t, syncer.Sync(context.Background()))
+	h, err := c.Height()
+	require.NoError(t, err)
+	assert.EqualValues(t, height, h)
+	for i := uint64(1); i <= height; i++ {
+		expected, err := st.HeaderByHeight(i)
+		require.NoError(t, err)
+		actual, err := c.HeaderByHeight(i)
+		require.NoError(t, err)
+		assert.Equal(t, expected.BlockID(), actual.BlockID())
+		expectedHS, err := st.HitSourceAtHeight(i)
+		require.NoError(t, err)
+		actualHS, err := c.NewestHitSourceAtHeight(i)
+		require.NoError(t, err)
+		assert.Equal(t, expectedHS, actualHS)
+	}
+
+	// Forged block is rejected
+	forged := *blocks[len(blocks)-1]
+	forged.Timestamp++
+	require.NoError(t, c.Rollback(height-1))
+	_, err = c.Append(&forged)
+	assert.Error(t, err)
+	_, err = c.Append(blocks[len(blocks)-1])
+	assert.NoError(t, err)
+}
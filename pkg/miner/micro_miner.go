@@ -112,6 +112,7 @@ func (a *MicroMiner) Micro(minedBlock *proto.Block, rest proto.MiningLimits, key
 	for _, tx := range inapplicable {
 		_ = a.utx.AddWithBytes(tx.T, tx.B)
 	}
+	a.utx.ReleasePopped()
 
 	// no transactions applied, skip
 	if txCount == 0 {
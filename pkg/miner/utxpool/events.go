@@ -0,0 +1,77 @@
+package utxpool
+
+import (
+	"sync"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+const subscriptionBufferSize = 1024
+
+// Subscription receives events of the pool which match the filter until it's closed.
+// Subscription is closed by the pool if the subscriber can't keep up with the events.
+type Subscription struct {
+	scheme     proto.Scheme
+	filter     *types.UtxFilter
+	events     chan types.UtxEvent
+	overflow   chan struct{}
+	overflowed bool
+}
+
+// Events returns the channel of pool events.
+func (s *Subscription) Events() <-chan types.UtxEvent {
+	return s.events
+}
+
+// Overflow returns the channel which is closed when the subscription is dropped because of the full buffer.
+func (s *Subscription) Overflow() <-chan struct{} {
+	return s.overflow
+}
+
+type eventsBroker struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func newEventsBroker() *eventsBroker {
+	return &eventsBroker{subs: make(map[*Subscription]struct{})}
+}
+
+func (b *eventsBroker) subscribe(scheme proto.Scheme, filter *types.UtxFilter) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := &Subscription{
+		scheme:   scheme,
+		filter:   filter,
+		events:   make(chan types.UtxEvent, subscriptionBufferSize),
+		overflow: make(chan struct{}),
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+func (b *eventsBroker) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+}
+
+func (b *eventsBroker) publish(e types.UtxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if !sub.filter.Match(sub.scheme, e.ID, e.Tx.T, e.Fee) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			if !sub.overflowed {
+				sub.overflowed = true
+				close(sub.overflow)
+			}
+			delete(b.subs, sub)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package utxpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+func nextEvent(t *testing.T, sub types.UtxSubscription) types.UtxEvent {
+	select {
+	case e := <-sub.Events():
+		return e
+	default:
+		require.FailNow(t, "no event")
+		return types.UtxEvent{}
+	}
+}
+
+func noEvents(t *testing.T, sub types.UtxSubscription) {
+	select {
+	case e := <-sub.Events():
+		require.FailNow(t, "unexpected event", "%+v", e)
+	default:
+	}
+}
+
+func TestUtxPool_Events(t *testing.T) {
+	a := New(3, NoOpValidator{}, settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(id([]byte{1}, 5), []byte{1}))
+
+	sub, snapshot := a.Subscribe(&types.UtxFilter{MinFee: 3})
+	defer a.Unsubscribe(sub)
+	require.Len(t, snapshot, 1)
+	assert.Equal(t, types.UtxTransactionAdded, snapshot[0].Type)
+	assert.Equal(t, makeDigest([]byte{1}, nil), snapshot[0].ID)
+
+	require.NoError(t, a.AddWithBytes(id([]byte{2}, 1), []byte{1})) // Filtered out by fee
+	require.NoError(t, a.AddWithBytes(id([]byte{3}, 7), []byte{1}))
+	e := nextEvent(t, sub)
+	assert.Equal(t, types.UtxTransactionAdded, e.Type)
+	assert.Equal(t, makeDigest([]byte{3}, nil), e.ID)
+	assert.EqualValues(t, 7, e.Fee)
+	noEvents(t, sub)
+
+	// Popped and returned transactions are not reported
+	popped := a.Pop()
+	require.NoError(t, a.AddWithBytes(popped.T, popped.B))
+	a.ReleasePopped()
+	noEvents(t, sub)
+	require.Equal(t, 3, a.Len())
+
+	// Popped transactions which are not returned are removed on release
+	popped = a.Pop()
+	noEvents(t, sub)
+	a.ReleasePopped()
+	e = nextEvent(t, sub)
+	assert.Equal(t, types.UtxTransactionRemoved, e.Type)
+	assert.Equal(t, makeDigest([]byte{3}, nil), e.ID)
+
+	// Evicted transactions are removed
+	require.NoError(t, a.AddWithBytes(id([]byte{4}, 30), []byte{1, 2, 3}))
+	e = nextEvent(t, sub)
+	assert.Equal(t, types.UtxTransactionRemoved, e.Type)
+	assert.Equal(t, makeDigest([]byte{1}, nil), e.ID)
+	e = nextEvent(t, sub)
+	assert.Equal(t, types.UtxTransactionAdded, e.Type)
+	assert.Equal(t, makeDigest([]byte{4}, nil), e.ID)
+	noEvents(t, sub)
+}
+
+func TestUtxPool_Transactions(t *testing.T) {
+	alice := proto.MustAddressFromString("3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3")
+	bob := proto.MustAddressFromString("3P76TmRjfjhdN9KEmwSnzQHpLrMRuf1qV29")
+	a := New(10000, NoOpValidator{}, settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{1}, 5, alice), []byte{1}))
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{2}, 3, bob), []byte{1}))
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{3}, 7, alice), []byte{1}))
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{4}, 1, alice), []byte{1}))
+
+	fees := func(filter *types.UtxFilter) []uint64 {
+		var r []uint64
+		for _, tx := range a.Transactions(filter) {
+			r = append(r, tx.T.GetFee())
+		}
+		return r
+	}
+	assert.Equal(t, []uint64{7, 5, 3, 1}, fees(nil))
+	assert.Equal(t, []uint64{7, 5, 1}, fees(&types.UtxFilter{Sender: &alice}))
+	assert.Equal(t, []uint64{7, 5}, fees(&types.UtxFilter{Sender: &alice, MinFee: 2}))
+	assert.Equal(t, []uint64{3}, fees(&types.UtxFilter{IDs: []crypto.Digest{makeDigest([]byte{2}, nil)}}))
+
+	tx, ok := a.TransactionByID(makeDigest([]byte{3}, nil))
+	require.True(t, ok)
+	assert.EqualValues(t, 7, tx.T.GetFee())
+	_, ok = a.TransactionByID(makeDigest([]byte{5}, nil))
+	assert.False(t, ok)
+}
@@ -3,70 +3,217 @@ package utxpool
 import (
 	"container/heap"
 	"fmt"
+	"math/bits"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
 	"github.com/wavesplatform/gowaves/pkg/types"
+	"go.uber.org/zap"
 )
 
-type transactionsHeap []*types.TransactionWithBytes
+// AssetsState provides the sponsorship costs of assets to rank the transactions with fees in sponsored assets.
+type AssetsState interface {
+	FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error)
+}
+
+// Params are the limits of the pool.
+type Params struct {
+	// MaxSize is the total size of transactions in bytes.
+	MaxSize uint64
+	// MaxPerSender is the number of transactions of one sender, zero means no limit.
+	MaxPerSender int
+	// Time is used to drop expired transactions, if not set transactions never expire.
+	Time types.Time
+	// Assets is used to convert fees in sponsored assets to WAVES, if not set fees are ranked as is.
+	Assets AssetsState
+	// Storage persists the transactions, if not set the pool is kept in memory only.
+	Storage *Storage
+}
+
+// expiredSweepInterval limits how often the full pool is scanned for expired transactions.
+const expiredSweepInterval = time.Second
+
+const (
+	highestFirst = iota
+	lowestFirst
+)
+
+type poolItem struct {
+	tx     *types.TransactionWithBytes
+	id     crypto.Digest
+	sender proto.AddressID
+	// fee is the fee of transaction in WAVES.
+	fee     uint64
+	indexes [2]int
+}
+
+// higherPriority compares transactions by fee per byte, then older transaction goes first.
+func (a *poolItem) higherPriority(b *poolItem) bool {
+	// a.fee/len(a) > b.fee/len(b) <=> a.fee*len(b) > b.fee*len(a), 128-bit products can't overflow
+	ah, al := bits.Mul64(a.fee, uint64(len(b.tx.B)))
+	bh, bl := bits.Mul64(b.fee, uint64(len(a.tx.B)))
+	if ah != bh {
+		return ah > bh
+	}
+	if al != bl {
+		return al > bl
+	}
+	return a.tx.T.GetTimestamp() < b.tx.T.GetTimestamp()
+}
+
+func (a *poolItem) event(t types.UtxEventType) types.UtxEvent {
+	return types.UtxEvent{Type: t, ID: a.id, Tx: a.tx, Fee: a.fee}
+}
+
+// transactionsHeap is ordered either by the highest or by the lowest priority,
+// items keep their positions in both heaps to be removed from the other one.
+type transactionsHeap struct {
+	items []*poolItem
+	order int
+}
 
-func (a transactionsHeap) Len() int { return len(a) }
+func (a transactionsHeap) Len() int { return len(a.items) }
 
 func (a transactionsHeap) Less(i, j int) bool {
-	// skip division by zero, check it when we add transaction
-	return a[i].T.GetFee()/uint64(len(a[i].B)) > a[j].T.GetFee()/uint64(len(a[j].B))
+	if a.order == highestFirst {
+		return a.items[i].higherPriority(a.items[j])
+	}
+	return a.items[j].higherPriority(a.items[i])
 }
 
 func (a transactionsHeap) Swap(i, j int) {
-	a[i], a[j] = a[j], a[i]
+	a.items[i], a.items[j] = a.items[j], a.items[i]
+	a.items[i].indexes[a.order] = i
+	a.items[j].indexes[a.order] = j
 }
 
 func (a *transactionsHeap) Push(x interface{}) {
-	item := x.(*types.TransactionWithBytes)
-	*a = append(*a, item)
+	item := x.(*poolItem)
+	item.indexes[a.order] = len(a.items)
+	a.items = append(a.items, item)
 }
 
 func (a *transactionsHeap) Pop() interface{} {
-	old := *a
+	old := a.items
 	n := len(old)
 	item := old[n-1]
-	*a = old[0 : n-1]
+	old[n-1] = nil
+	a.items = old[0 : n-1]
 	return item
 }
 
+func (a *transactionsHeap) top() *poolItem {
+	if len(a.items) == 0 {
+		return nil
+	}
+	return a.items[0]
+}
+
+// UtxImpl keeps unconfirmed transactions ordered by fee per byte. When the pool is full the transactions
+// with the lowest priority are evicted in favor of better paid ones, the same happens to the transactions
+// of the sender who reached the limit.
+// Popped transactions are expected to be returned back or released with ReleasePopped, so only the transactions
+// which actually leave the pool are reported to subscribers and removed from the storage.
 type UtxImpl struct {
-	mu             sync.Mutex
-	transactions   transactionsHeap
-	transactionIds map[crypto.Digest]struct{}
-	sizeLimit      uint64 // max transaction size in bytes
-	curSize        uint64
-	validator      Validator
-	settings       *settings.BlockchainSettings
+	mu           sync.Mutex
+	highest      transactionsHeap
+	lowest       transactionsHeap
+	transactions map[crypto.Digest]*poolItem
+	senders      map[proto.AddressID][]*poolItem
+	popped       map[crypto.Digest]*poolItem
+	params       Params
+	curSize      uint64
+	validator    Validator
+	settings     *settings.BlockchainSettings
+	events       *eventsBroker
+	lastSweep    time.Time
 }
 
 func New(sizeLimit uint64, validator Validator, settings *settings.BlockchainSettings) *UtxImpl {
+	return NewWithParams(Params{MaxSize: sizeLimit}, validator, settings)
+}
+
+func NewWithParams(params Params, validator Validator, settings *settings.BlockchainSettings) *UtxImpl {
 	return &UtxImpl{
-		transactionIds: make(map[crypto.Digest]struct{}),
-		sizeLimit:      sizeLimit,
-		validator:      validator,
-		settings:       settings,
+		highest:      transactionsHeap{order: highestFirst},
+		lowest:       transactionsHeap{order: lowestFirst},
+		transactions: make(map[crypto.Digest]*poolItem),
+		senders:      make(map[proto.AddressID][]*poolItem),
+		popped:       make(map[crypto.Digest]*poolItem),
+		params:       params,
+		validator:    validator,
+		settings:     settings,
+		events:       newEventsBroker(),
 	}
 }
 
+// AllTransactions returns the transactions of the pool ordered by priority, the first one has the highest priority.
 func (a *UtxImpl) AllTransactions() []*types.TransactionWithBytes {
+	return a.Transactions(nil)
+}
+
+// Transactions returns the transactions of the pool which match the filter ordered by priority.
+func (a *UtxImpl) Transactions(filter *types.UtxFilter) []*types.TransactionWithBytes {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	res := make([]*types.TransactionWithBytes, len(a.transactions))
-	copy(res, a.transactions)
+	items := a.matching(filter)
+	res := make([]*types.TransactionWithBytes, len(items))
+	for i, item := range items {
+		res[i] = item.tx
+	}
 	return res
 }
 
+func (a *UtxImpl) matching(filter *types.UtxFilter) []*poolItem {
+	items := make([]*poolItem, 0, len(a.highest.items))
+	for _, item := range a.highest.items {
+		if filter.Match(a.settings.AddressSchemeCharacter, item.id, item.tx.T, item.fee) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].higherPriority(items[j]) })
+	return items
+}
+
+// TransactionByID returns the transaction of the pool with the given ID.
+func (a *UtxImpl) TransactionByID(id crypto.Digest) (*types.TransactionWithBytes, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	item, ok := a.transactions[id]
+	if !ok {
+		return nil, false
+	}
+	return item.tx, true
+}
+
+// Subscribe returns the subscription to the events of the pool. Events which match the filter are published to it
+// along with the snapshot of matching transactions taken at the moment of subscription, so no change is missed.
+func (a *UtxImpl) Subscribe(filter *types.UtxFilter) (types.UtxSubscription, []types.UtxEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sub := a.events.subscribe(a.settings.AddressSchemeCharacter, filter)
+	items := a.matching(filter)
+	snapshot := make([]types.UtxEvent, len(items))
+	for i, item := range items {
+		snapshot[i] = item.event(types.UtxTransactionAdded)
+	}
+	return sub, snapshot
+}
+
+func (a *UtxImpl) Unsubscribe(sub types.UtxSubscription) {
+	if s, ok := sub.(*Subscription); ok {
+		a.events.unsubscribe(s)
+	}
+}
+
 func (a *UtxImpl) Add(t proto.Transaction) error {
 	bts, err := proto.MarshalTx(a.settings.AddressSchemeCharacter, t)
 	if err != nil {
@@ -97,13 +244,48 @@ func (a *UtxImpl) AddWithBytes(t proto.Transaction, b []byte) error {
 	return a.addWithBytes(t, b)
 }
 
+// Restore loads the persisted transactions into the pool. Transactions are not validated here,
+// the pool should be revalidated with the Cleaner afterwards.
+func (a *UtxImpl) Restore() (int, error) {
+	if a.params.Storage == nil {
+		return 0, nil
+	}
+	txs, err := a.params.Storage.load()
+	if err != nil {
+		return 0, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	restored := 0
+	for _, t := range txs {
+		b, err := proto.MarshalTx(a.settings.AddressSchemeCharacter, t)
+		if err == nil {
+			err = a.add(t, b, true)
+		}
+		if err != nil {
+			id := makeDigest(t.GetID(a.settings.AddressSchemeCharacter))
+			zap.S().Debugf("Persisted transaction %s is not restored to UTX pool: %v", id.String(), err)
+			if err := a.params.Storage.delete(id); err != nil {
+				return restored, err
+			}
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
 func (a *UtxImpl) addWithBytes(t proto.Transaction, b []byte) error {
+	return a.add(t, b, false)
+}
+
+// add puts the transaction into the pool. Restored transactions are already persisted and are not validated.
+func (a *UtxImpl) add(t proto.Transaction, b []byte, restored bool) error {
 	if len(b) == 0 {
 		return errors.New("transaction with empty bytes")
 	}
-	// exceed limit
-	if a.curSize+uint64(len(b)) > a.sizeLimit {
-		return errors.Errorf("size overflow, curSize: %d, limit: %d", a.curSize, a.sizeLimit)
+	if uint64(len(b)) > a.params.MaxSize {
+		return errors.Errorf("size overflow, transaction size: %d, limit: %d", len(b), a.params.MaxSize)
 	}
 	if err := t.GenerateID(a.settings.AddressSchemeCharacter); err != nil {
 		return errors.Errorf("failed to generate ID: %v", err)
@@ -115,21 +297,244 @@ func (a *UtxImpl) addWithBytes(t proto.Transaction, b []byte) error {
 	if a.exists(t) {
 		return proto.NewInfoMsg(errors.Errorf("transaction with id %s exists", base58.Encode(tID)))
 	}
-	err = a.validator.Validate(t)
+	if a.expired(t) {
+		return errors.Errorf("transaction with id %s is expired", base58.Encode(tID))
+	}
+	item := &poolItem{tx: &types.TransactionWithBytes{T: t, B: b}, id: makeDigest(tID, nil)}
+	_, returned := a.popped[item.id]
+	if item.fee, err = a.wavesFee(t); err != nil {
+		return errors.Wrap(err, "failed to convert fee to WAVES")
+	}
+	if a.params.MaxPerSender > 0 {
+		sender, err := t.GetSender(a.settings.AddressSchemeCharacter)
+		if err != nil {
+			return errors.Wrap(err, "failed to get sender")
+		}
+		item.sender = sender.ID()
+	}
+	if a.full(item) {
+		a.removeExpired()
+	}
+	// Check that the transaction can take a place before the validation, which is expensive
+	replaced, err := a.senderReplacement(item)
+	if err != nil {
+		return err
+	}
+	evicted, err := a.evictionCandidates(item, replaced)
 	if err != nil {
 		return err
 	}
-	tb := &types.TransactionWithBytes{
-		T: t,
-		B: b,
+	if !restored {
+		if err := a.validator.Validate(t); err != nil {
+			return err
+		}
+		if a.params.Storage != nil && !returned {
+			if err := a.params.Storage.put(item.id, t); err != nil {
+				return errors.Wrap(err, "failed to persist transaction")
+			}
+		}
+	}
+	if replaced != nil {
+		a.remove(replaced)
+	}
+	for _, e := range evicted {
+		a.remove(e)
 	}
-	heap.Push(&a.transactions, tb)
-	id := makeDigest(t.GetID(a.settings.AddressSchemeCharacter))
-	a.transactionIds[id] = struct{}{}
-	a.curSize += uint64(len(b))
+	a.push(item)
+	if returned {
+		delete(a.popped, item.id) // Popped transaction is back, nothing has changed for the subscribers
+		return nil
+	}
+	a.events.publish(item.event(types.UtxTransactionAdded))
 	return nil
 }
 
+// wavesFee returns the fee of transaction in WAVES, fees in sponsored assets are converted by the sponsorship rate.
+func (a *UtxImpl) wavesFee(t proto.Transaction) (uint64, error) {
+	asset := feeAsset(t)
+	if !asset.Present || a.params.Assets == nil {
+		return t.GetFee(), nil
+	}
+	info, err := a.params.Assets.FullAssetInfo(proto.AssetIDFromDigest(asset.ID))
+	if err != nil {
+		return 0, err
+	}
+	if info.SponsorshipCost == 0 {
+		return 0, nil // Not sponsored, such transaction fails the validation anyway
+	}
+	hi, lo := bits.Mul64(t.GetFee(), state.FeeUnit)
+	if hi >= info.SponsorshipCost {
+		return 0, errors.New("fee in WAVES overflows uint64")
+	}
+	fee, _ := bits.Div64(hi, lo, info.SponsorshipCost)
+	return fee, nil
+}
+
+func feeAsset(t proto.Transaction) proto.OptionalAsset {
+	switch tx := t.(type) {
+	case *proto.TransferWithSig:
+		return tx.FeeAsset
+	case *proto.TransferWithProofs:
+		return tx.FeeAsset
+	case *proto.InvokeScriptWithProofs:
+		return tx.FeeAsset
+	case *proto.InvokeExpressionTransactionWithProofs:
+		return tx.FeeAsset
+	case *proto.UpdateAssetInfoWithProofs:
+		return tx.FeeAsset
+	default:
+		return proto.NewOptionalAssetWaves()
+	}
+}
+
+// senderReplacement returns the transaction of the sender which is replaced by the new one if the sender
+// reached the limit. Error is returned if all transactions of the sender have higher priority.
+func (a *UtxImpl) senderReplacement(item *poolItem) (*poolItem, error) {
+	if a.params.MaxPerSender <= 0 {
+		return nil, nil
+	}
+	items := a.senders[item.sender]
+	if len(items) < a.params.MaxPerSender {
+		return nil, nil
+	}
+	lowest := items[0]
+	for _, it := range items[1:] {
+		if lowest.higherPriority(it) {
+			lowest = it
+		}
+	}
+	if !item.higherPriority(lowest) {
+		return nil, errors.Errorf("sender has reached the limit of %d transactions", a.params.MaxPerSender)
+	}
+	return lowest, nil
+}
+
+// evictionCandidates returns the transactions with the lowest priority which should be removed to free the space
+// for the new transaction. Error is returned if there is not enough transactions with lower priority.
+func (a *UtxImpl) evictionCandidates(item, replaced *poolItem) ([]*poolItem, error) {
+	size := a.curSize
+	if replaced != nil {
+		size -= uint64(len(replaced.tx.B))
+	}
+	need := uint64(len(item.tx.B))
+	if size+need <= a.params.MaxSize {
+		return nil, nil
+	}
+	var (
+		candidates []*poolItem
+		popped     []*poolItem
+	)
+	defer func() { // Restore the heap after looking through it
+		for _, p := range popped {
+			heap.Push(&a.lowest, p)
+		}
+	}()
+	for size+need > a.params.MaxSize {
+		lowest := a.lowest.top()
+		if lowest == nil || !item.higherPriority(lowest) {
+			return nil, errors.Errorf("size overflow, curSize: %d, limit: %d", a.curSize, a.params.MaxSize)
+		}
+		popped = append(popped, heap.Pop(&a.lowest).(*poolItem))
+		if lowest == replaced {
+			continue
+		}
+		candidates = append(candidates, lowest)
+		size -= uint64(len(lowest.tx.B))
+	}
+	return candidates, nil
+}
+
+func (a *UtxImpl) push(item *poolItem) {
+	heap.Push(&a.highest, item)
+	heap.Push(&a.lowest, item)
+	a.transactions[item.id] = item
+	if a.params.MaxPerSender > 0 {
+		a.senders[item.sender] = append(a.senders[item.sender], item)
+	}
+	a.curSize += uint64(len(item.tx.B))
+}
+
+// remove takes the transaction out of the pool for good.
+func (a *UtxImpl) remove(item *poolItem) {
+	a.detach(item)
+	a.drop(item)
+}
+
+// drop deletes the detached transaction from the storage and notifies the subscribers.
+func (a *UtxImpl) drop(item *poolItem) {
+	if a.params.Storage != nil {
+		if err := a.params.Storage.delete(item.id); err != nil {
+			zap.S().Errorf("Failed to remove transaction %s from UTX pool storage: %v", item.id.String(), err)
+		}
+	}
+	a.events.publish(item.event(types.UtxTransactionRemoved))
+}
+
+// detach takes the transaction out of the pool structures.
+func (a *UtxImpl) detach(item *poolItem) {
+	heap.Remove(&a.highest, item.indexes[highestFirst])
+	heap.Remove(&a.lowest, item.indexes[lowestFirst])
+	delete(a.transactions, item.id)
+	if a.params.MaxPerSender > 0 {
+		items := a.senders[item.sender]
+		for i, it := range items {
+			if it == item {
+				items = append(items[:i], items[i+1:]...)
+				break
+			}
+		}
+		if len(items) == 0 {
+			delete(a.senders, item.sender)
+		} else {
+			a.senders[item.sender] = items
+		}
+	}
+	if uint64(len(item.tx.B)) > a.curSize {
+		panic(fmt.Sprintf("UtxImpl: size of transaction %d > than current size %d", len(item.tx.B), a.curSize))
+	}
+	a.curSize -= uint64(len(item.tx.B))
+}
+
+// full checks that the transaction doesn't fit into the pool or the sender has reached the limit.
+func (a *UtxImpl) full(item *poolItem) bool {
+	if a.curSize+uint64(len(item.tx.B)) > a.params.MaxSize {
+		return true
+	}
+	return a.params.MaxPerSender > 0 && len(a.senders[item.sender]) >= a.params.MaxPerSender
+}
+
+// removeExpired drops all expired transactions to give place to the new ones. Otherwise, expired transactions
+// stay in the pool until they are popped on the next block. The sweep is done at most once per expiredSweepInterval.
+func (a *UtxImpl) removeExpired() {
+	if a.params.Time == nil {
+		return
+	}
+	now := a.params.Time.Now()
+	if now.Sub(a.lastSweep) < expiredSweepInterval {
+		return
+	}
+	a.lastSweep = now
+	var expired []*poolItem
+	for _, item := range a.transactions {
+		if a.expired(item.tx.T) {
+			expired = append(expired, item)
+		}
+	}
+	for _, item := range expired {
+		zap.S().Debugf("Transaction %s is expired and removed from UTX pool", item.id.String())
+		a.remove(item)
+	}
+}
+
+// expired checks that transaction's timestamp is too old to put it into a block.
+func (a *UtxImpl) expired(t proto.Transaction) bool {
+	if a.params.Time == nil {
+		return false
+	}
+	now := proto.NewTimestampFromTime(a.params.Time.Now())
+	return t.GetTimestamp()+a.settings.MaxTxTimeBackOffset < now
+}
+
 func (a *UtxImpl) Count() int {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -143,7 +548,7 @@ func makeDigest(b []byte, _ error) crypto.Digest {
 }
 
 func (a *UtxImpl) exists(t proto.Transaction) bool {
-	_, ok := a.transactionIds[makeDigest(t.GetID(a.settings.AddressSchemeCharacter))]
+	_, ok := a.transactions[makeDigest(t.GetID(a.settings.AddressSchemeCharacter))]
 	return ok
 }
 
@@ -160,23 +565,39 @@ func (a *UtxImpl) ExistsByID(id []byte) bool {
 	if err != nil {
 		return false
 	}
-	_, ok := a.transactionIds[digest]
+	_, ok := a.transactions[digest]
 	return ok
 }
 
+// Pop removes and returns the transaction with the highest priority, expired transactions are dropped.
+// Popped transaction is considered to be in the pool until ReleasePopped is called.
 func (a *UtxImpl) Pop() *types.TransactionWithBytes {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	if a.transactions.Len() > 0 {
-		tb := heap.Pop(&a.transactions).(*types.TransactionWithBytes)
-		delete(a.transactionIds, makeDigest(tb.T.GetID(a.settings.AddressSchemeCharacter)))
-		if uint64(len(tb.B)) > a.curSize {
-			panic(fmt.Sprintf("UtxImpl Pop: size of transaction %d > than current size %d", len(tb.B), a.curSize))
+	for {
+		item := a.highest.top()
+		if item == nil {
+			return nil
+		}
+		if a.expired(item.tx.T) {
+			zap.S().Debugf("Transaction %s is expired and removed from UTX pool", item.id.String())
+			a.remove(item)
+			continue
 		}
-		a.curSize -= uint64(len(tb.B))
-		return tb
+		a.detach(item)
+		a.popped[item.id] = item
+		return item.tx
+	}
+}
+
+// ReleasePopped removes for good the popped transactions which were not returned back to the pool.
+func (a *UtxImpl) ReleasePopped() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id, item := range a.popped {
+		a.drop(item)
+		delete(a.popped, id)
 	}
-	return nil
 }
 
 func (a *UtxImpl) CurSize() uint64 {
@@ -188,5 +609,5 @@ func (a *UtxImpl) CurSize() uint64 {
 func (a *UtxImpl) Len() int {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	return a.transactions.Len()
+	return a.highest.Len()
 }
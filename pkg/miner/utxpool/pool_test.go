@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
@@ -15,8 +16,10 @@ import (
 )
 
 type transaction struct {
-	fee uint64
-	id  []byte
+	fee       uint64
+	id        []byte
+	sender    proto.WavesAddress
+	timestamp uint64
 }
 
 func (a transaction) BinarySize() int {
@@ -71,8 +74,8 @@ func (transaction) UnmarshalBinary([]byte, proto.Scheme) error {
 	panic("not implemented")
 }
 
-func (transaction) GetTimestamp() uint64 {
-	return 0
+func (a transaction) GetTimestamp() uint64 {
+	return a.timestamp
 }
 
 func (transaction) GenerateID(_ proto.Scheme) error {
@@ -99,8 +102,8 @@ func (a transaction) GetSenderPK() crypto.PublicKey {
 	panic("not implemented")
 }
 
-func (a transaction) GetSender(scheme proto.Scheme) (proto.Address, error) {
-	panic("not implemented")
+func (a transaction) GetSender(_ proto.Scheme) (proto.Address, error) {
+	return a.sender, nil
 }
 
 func tr(fee uint64) *transaction {
@@ -196,3 +199,132 @@ func TestUtxImpl_TransactionExists(t *testing.T) {
 	require.True(t, a.ExistsByID(byte_helpers.BurnWithSig.Transaction.ID.Bytes()))
 	require.False(t, a.ExistsByID(byte_helpers.TransferWithSig.Transaction.ID.Bytes()))
 }
+
+func fromSender(b []byte, fee uint64, sender proto.WavesAddress) *transaction {
+	return &transaction{fee: fee, id: b, sender: sender}
+}
+
+func TestUtxPool_Eviction(t *testing.T) {
+	a := New(3, NoOpValidator{}, settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(id([]byte{1}, 5), []byte{1}))
+	require.NoError(t, a.AddWithBytes(id([]byte{2}, 3), []byte{1}))
+	require.NoError(t, a.AddWithBytes(id([]byte{3}, 7), []byte{1}))
+
+	// cheaper than everything in the pool
+	require.Error(t, a.AddWithBytes(id([]byte{4}, 2), []byte{1}))
+	// evicts two cheapest transactions
+	require.NoError(t, a.AddWithBytes(id([]byte{5}, 20), []byte{1, 2}))
+	require.Equal(t, 2, a.Len())
+	require.EqualValues(t, 3, a.CurSize())
+	require.False(t, a.ExistsByID(makeDigest([]byte{2}, nil).Bytes()))
+	require.False(t, a.ExistsByID(makeDigest([]byte{1}, nil).Bytes()))
+
+	// fee per byte matters: 20/2 > 7/1
+	require.EqualValues(t, 20, a.Pop().T.GetFee())
+	require.EqualValues(t, 7, a.Pop().T.GetFee())
+	require.Nil(t, a.Pop())
+}
+
+func TestUtxPool_EvictionRejectedByValidator(t *testing.T) {
+	a := New(1, validatorFunc(func(t proto.Transaction) error {
+		if t.GetFee() > 10 {
+			return errors.New("invalid")
+		}
+		return nil
+	}), settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(id([]byte{1}, 5), []byte{1}))
+	require.Error(t, a.AddWithBytes(id([]byte{2}, 20), []byte{1}))
+	require.True(t, a.ExistsByID(makeDigest([]byte{1}, nil).Bytes()))
+	require.EqualValues(t, 1, a.CurSize())
+}
+
+func TestUtxPool_SenderLimit(t *testing.T) {
+	alice := proto.MustAddressFromString("3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3")
+	bob := proto.MustAddressFromString("3P76TmRjfjhdN9KEmwSnzQHpLrMRuf1qV29")
+	a := NewWithParams(Params{MaxSize: 10000, MaxPerSender: 2}, NoOpValidator{}, settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{1}, 5, alice), []byte{1}))
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{2}, 3, alice), []byte{1}))
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{3}, 1, bob), []byte{1}))
+
+	require.Error(t, a.AddWithBytes(fromSender([]byte{4}, 2, alice), []byte{1}))
+	// replaces the cheapest transaction of the sender
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{5}, 4, alice), []byte{1}))
+	require.Equal(t, 3, a.Len())
+	require.False(t, a.ExistsByID(makeDigest([]byte{2}, nil).Bytes()))
+	require.True(t, a.ExistsByID(makeDigest([]byte{3}, nil).Bytes()))
+
+	// popped transactions free the quota
+	require.EqualValues(t, 5, a.Pop().T.GetFee())
+	require.NoError(t, a.AddWithBytes(fromSender([]byte{6}, 1, alice), []byte{1}))
+}
+
+type clockStub struct {
+	now time.Time
+}
+
+func (c *clockStub) Now() time.Time {
+	return c.now
+}
+
+func TestUtxPool_Expiration(t *testing.T) {
+	clock := &clockStub{now: time.Now()}
+	now := proto.NewTimestampFromTime(clock.now)
+	a := NewWithParams(Params{MaxSize: 10000, Time: clock}, NoOpValidator{}, settings.MainNetSettings)
+	old := &transaction{fee: 10, id: []byte{1}, timestamp: now - settings.MainNetSettings.MaxTxTimeBackOffset - 1000}
+	require.Error(t, a.AddWithBytes(old, []byte{1}))
+
+	soon := &transaction{fee: 10, id: []byte{2}, timestamp: now - settings.MainNetSettings.MaxTxTimeBackOffset + 300}
+	require.NoError(t, a.AddWithBytes(soon, []byte{1}))
+	require.NoError(t, a.AddWithBytes(&transaction{fee: 1, id: []byte{3}, timestamp: now}, []byte{1}))
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	// expired transaction is dropped
+	require.EqualValues(t, 1, a.Pop().T.GetFee())
+	require.Nil(t, a.Pop())
+}
+
+func TestUtxPool_ExpiredRemovedWhenFull(t *testing.T) {
+	clock := &clockStub{now: time.Now()}
+	now := proto.NewTimestampFromTime(clock.now)
+	a := NewWithParams(Params{MaxSize: 2, Time: clock}, NoOpValidator{}, settings.MainNetSettings)
+	soon := now - settings.MainNetSettings.MaxTxTimeBackOffset + 300
+	require.NoError(t, a.AddWithBytes(&transaction{fee: 10, id: []byte{1}, timestamp: soon}, []byte{1}))
+	require.NoError(t, a.AddWithBytes(&transaction{fee: 10, id: []byte{2}, timestamp: soon}, []byte{1}))
+	// the pool is full of better paid transactions
+	require.Error(t, a.AddWithBytes(&transaction{fee: 1, id: []byte{3}, timestamp: now}, []byte{1}))
+
+	clock.now = clock.now.Add(expiredSweepInterval)
+	require.NoError(t, a.AddWithBytes(&transaction{fee: 1, id: []byte{3}, timestamp: now}, []byte{1}))
+	require.Equal(t, 1, a.Count())
+}
+
+type assetsStub map[proto.AssetID]uint64
+
+func (s assetsStub) FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error) {
+	return &proto.FullAssetInfo{SponsorshipCost: s[assetID]}, nil
+}
+
+func TestUtxPool_SponsoredFee(t *testing.T) {
+	asset := crypto.MustFastHash([]byte("asset"))
+	pk, err := crypto.NewPublicKeyFromBase58("8TLsCqkkroQWKwjuYEPNE5xmozcePMmhkfJ8swrDvBiq")
+	require.NoError(t, err)
+	rcp := proto.NewRecipientFromAddress(proto.MustAddressFromString("3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3"))
+	transfer := func(fee uint64, feeAsset proto.OptionalAsset, ts uint64) *proto.TransferWithProofs {
+		return proto.NewUnsignedTransferWithProofs(2, pk, proto.NewOptionalAssetWaves(), feeAsset, ts, 1, fee, rcp, nil)
+	}
+	// 1000 units of asset cost 100000 WAVES units
+	a := NewWithParams(Params{MaxSize: 10000, Assets: assetsStub{proto.AssetIDFromDigest(asset): 1000}},
+		NoOpValidator{}, settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(transfer(300000, proto.NewOptionalAssetWaves(), 1), []byte{1}))
+	require.NoError(t, a.AddWithBytes(transfer(4000, *proto.NewOptionalAssetFromDigest(asset), 2), []byte{1}))
+	require.NoError(t, a.AddWithBytes(transfer(2000, *proto.NewOptionalAssetFromDigest(asset), 3), []byte{1}))
+
+	require.EqualValues(t, 4000, a.Pop().T.GetFee())
+	require.EqualValues(t, 300000, a.Pop().T.GetFee())
+	require.EqualValues(t, 2000, a.Pop().T.GetFee())
+}
+
+type validatorFunc func(t proto.Transaction) error
+
+func (f validatorFunc) Validate(t proto.Transaction) error {
+	return f(t)
+}
@@ -0,0 +1,78 @@
+package utxpool
+
+import (
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// StorageDir is the name of the directory inside the state directory where the pool is persisted.
+const StorageDir = "utx_pool"
+
+// Storage keeps the transactions of the pool on disk, so they survive restarts of the node.
+// Transactions are stored by their IDs in signed protobuf format.
+type Storage struct {
+	db     *leveldb.DB
+	scheme proto.Scheme
+}
+
+func NewStorage(path string, scheme proto.Scheme) (*Storage, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open UTX pool storage %q", path)
+	}
+	return &Storage{db: db, scheme: scheme}, nil
+}
+
+func newMemStorage(scheme proto.Scheme) (*Storage, error) {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Storage{db: db, scheme: scheme}, nil
+}
+
+func (s *Storage) put(id crypto.Digest, t proto.Transaction) error {
+	b, err := t.MarshalSignedToProtobuf(s.scheme)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal transaction")
+	}
+	return s.db.Put(id.Bytes(), b, nil)
+}
+
+func (s *Storage) delete(id crypto.Digest) error {
+	return s.db.Delete(id.Bytes(), nil)
+}
+
+// load returns all stored transactions. Records which can't be read are removed.
+func (s *Storage) load() ([]proto.Transaction, error) {
+	var (
+		txs    []proto.Transaction
+		broken [][]byte
+	)
+	it := s.db.NewIterator(nil, nil)
+	for it.Next() {
+		t, err := proto.SignedTxFromProtobuf(it.Value())
+		if err != nil {
+			broken = append(broken, append([]byte(nil), it.Key()...))
+			continue
+		}
+		txs = append(txs, t)
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to read UTX pool storage")
+	}
+	for _, k := range broken {
+		if err := s.db.Delete(k, nil); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
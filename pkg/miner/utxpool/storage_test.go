@@ -0,0 +1,47 @@
+package utxpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/util/byte_helpers"
+)
+
+func TestUtxPool_Persistence(t *testing.T) {
+	storage, err := newMemStorage(settings.MainNetSettings.AddressSchemeCharacter)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, storage.Close()) }()
+
+	burn := byte_helpers.BurnWithSig
+	transfer := byte_helpers.TransferWithSig
+	a := NewWithParams(Params{MaxSize: 10000, Storage: storage}, NoOpValidator{}, settings.MainNetSettings)
+	require.NoError(t, a.AddWithBytes(burn.Transaction, burn.TransactionBytes))
+	require.NoError(t, a.AddWithBytes(transfer.Transaction, transfer.TransactionBytes))
+	popped := a.Pop()
+	require.NotNil(t, popped)
+	// Popped transaction is kept in the storage until it is released
+	stored, err := storage.load()
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+	a.ReleasePopped()
+
+	restoredPool := NewWithParams(Params{MaxSize: 10000, Storage: storage}, NoOpValidator{}, settings.MainNetSettings)
+	n, err := restoredPool.Restore()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, restoredPool.Exists(popped.T))
+	left := a.AllTransactions()
+	require.Len(t, left, 1)
+	assert.True(t, restoredPool.Exists(left[0].T))
+
+	// Transactions which don't fit the pool are removed from the storage
+	tooSmall := NewWithParams(Params{MaxSize: 1, Storage: storage}, NoOpValidator{}, settings.MainNetSettings)
+	n, err = tooSmall.Restore()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	txs, err := storage.load()
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+}
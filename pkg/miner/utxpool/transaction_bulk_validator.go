@@ -34,6 +34,7 @@ func (a bulkValidator) Validate() {
 	for _, t := range transactions {
 		_ = a.utx.AddWithBytes(t.T, t.B)
 	}
+	a.utx.ReleasePopped()
 }
 
 func (a bulkValidator) validate() ([]*types.TransactionWithBytes, error) {
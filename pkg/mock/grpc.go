@@ -9,8 +9,10 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	grpc "github.com/wavesplatform/gowaves/pkg/grpc/generated/gowaves/node/grpc"
 	waves "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
-	grpc "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
+	grpc0 "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
+	grpc1 "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 )
@@ -54,10 +56,10 @@ func (mr *MockGrpcHandlersMockRecorder) Broadcast(arg0, arg1 interface{}) *gomoc
 }
 
 // GetActivationStatus mocks base method.
-func (m *MockGrpcHandlers) GetActivationStatus(arg0 context.Context, arg1 *grpc.ActivationStatusRequest) (*grpc.ActivationStatusResponse, error) {
+func (m *MockGrpcHandlers) GetActivationStatus(arg0 context.Context, arg1 *grpc1.ActivationStatusRequest) (*grpc1.ActivationStatusResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetActivationStatus", arg0, arg1)
-	ret0, _ := ret[0].(*grpc.ActivationStatusResponse)
+	ret0, _ := ret[0].(*grpc1.ActivationStatusResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -69,7 +71,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetActivationStatus(arg0, arg1 interface
 }
 
 // GetActiveLeases mocks base method.
-func (m *MockGrpcHandlers) GetActiveLeases(arg0 *grpc.AccountRequest, arg1 grpc.AccountsApi_GetActiveLeasesServer) error {
+func (m *MockGrpcHandlers) GetActiveLeases(arg0 *grpc1.AccountRequest, arg1 grpc1.AccountsApi_GetActiveLeasesServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetActiveLeases", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -83,7 +85,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetActiveLeases(arg0, arg1 interface{})
 }
 
 // GetBalances mocks base method.
-func (m *MockGrpcHandlers) GetBalances(arg0 *grpc.BalancesRequest, arg1 grpc.AccountsApi_GetBalancesServer) error {
+func (m *MockGrpcHandlers) GetBalances(arg0 *grpc1.BalancesRequest, arg1 grpc1.AccountsApi_GetBalancesServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBalances", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -97,10 +99,10 @@ func (mr *MockGrpcHandlersMockRecorder) GetBalances(arg0, arg1 interface{}) *gom
 }
 
 // GetBaseTarget mocks base method.
-func (m *MockGrpcHandlers) GetBaseTarget(arg0 context.Context, arg1 *emptypb.Empty) (*grpc.BaseTargetResponse, error) {
+func (m *MockGrpcHandlers) GetBaseTarget(arg0 context.Context, arg1 *emptypb.Empty) (*grpc1.BaseTargetResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBaseTarget", arg0, arg1)
-	ret0, _ := ret[0].(*grpc.BaseTargetResponse)
+	ret0, _ := ret[0].(*grpc1.BaseTargetResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -112,10 +114,10 @@ func (mr *MockGrpcHandlersMockRecorder) GetBaseTarget(arg0, arg1 interface{}) *g
 }
 
 // GetBlock mocks base method.
-func (m *MockGrpcHandlers) GetBlock(arg0 context.Context, arg1 *grpc.BlockRequest) (*grpc.BlockWithHeight, error) {
+func (m *MockGrpcHandlers) GetBlock(arg0 context.Context, arg1 *grpc1.BlockRequest) (*grpc1.BlockWithHeight, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBlock", arg0, arg1)
-	ret0, _ := ret[0].(*grpc.BlockWithHeight)
+	ret0, _ := ret[0].(*grpc1.BlockWithHeight)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -127,7 +129,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetBlock(arg0, arg1 interface{}) *gomock
 }
 
 // GetBlockRange mocks base method.
-func (m *MockGrpcHandlers) GetBlockRange(arg0 *grpc.BlockRangeRequest, arg1 grpc.BlocksApi_GetBlockRangeServer) error {
+func (m *MockGrpcHandlers) GetBlockRange(arg0 *grpc1.BlockRangeRequest, arg1 grpc1.BlocksApi_GetBlockRangeServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBlockRange", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -140,11 +142,56 @@ func (mr *MockGrpcHandlersMockRecorder) GetBlockRange(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockRange", reflect.TypeOf((*MockGrpcHandlers)(nil).GetBlockRange), arg0, arg1)
 }
 
+// GetBlockUpdate mocks base method.
+func (m *MockGrpcHandlers) GetBlockUpdate(arg0 context.Context, arg1 *grpc0.GetBlockUpdateRequest) (*grpc0.GetBlockUpdateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockUpdate", arg0, arg1)
+	ret0, _ := ret[0].(*grpc0.GetBlockUpdateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockUpdate indicates an expected call of GetBlockUpdate.
+func (mr *MockGrpcHandlersMockRecorder) GetBlockUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockUpdate", reflect.TypeOf((*MockGrpcHandlers)(nil).GetBlockUpdate), arg0, arg1)
+}
+
+// GetBlockUpdatesRange mocks base method.
+func (m *MockGrpcHandlers) GetBlockUpdatesRange(arg0 context.Context, arg1 *grpc0.GetBlockUpdatesRangeRequest) (*grpc0.GetBlockUpdatesRangeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockUpdatesRange", arg0, arg1)
+	ret0, _ := ret[0].(*grpc0.GetBlockUpdatesRangeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockUpdatesRange indicates an expected call of GetBlockUpdatesRange.
+func (mr *MockGrpcHandlersMockRecorder) GetBlockUpdatesRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockUpdatesRange", reflect.TypeOf((*MockGrpcHandlers)(nil).GetBlockUpdatesRange), arg0, arg1)
+}
+
+// GetConnectedPeers mocks base method.
+func (m *MockGrpcHandlers) GetConnectedPeers(arg0 context.Context, arg1 *grpc.ConnectedPeersRequest) (*grpc.ConnectedPeersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnectedPeers", arg0, arg1)
+	ret0, _ := ret[0].(*grpc.ConnectedPeersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConnectedPeers indicates an expected call of GetConnectedPeers.
+func (mr *MockGrpcHandlersMockRecorder) GetConnectedPeers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnectedPeers", reflect.TypeOf((*MockGrpcHandlers)(nil).GetConnectedPeers), arg0, arg1)
+}
+
 // GetCumulativeScore mocks base method.
-func (m *MockGrpcHandlers) GetCumulativeScore(arg0 context.Context, arg1 *emptypb.Empty) (*grpc.ScoreResponse, error) {
+func (m *MockGrpcHandlers) GetCumulativeScore(arg0 context.Context, arg1 *emptypb.Empty) (*grpc1.ScoreResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCumulativeScore", arg0, arg1)
-	ret0, _ := ret[0].(*grpc.ScoreResponse)
+	ret0, _ := ret[0].(*grpc1.ScoreResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -171,7 +218,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetCurrentHeight(arg0, arg1 interface{})
 }
 
 // GetDataEntries mocks base method.
-func (m *MockGrpcHandlers) GetDataEntries(arg0 *grpc.DataRequest, arg1 grpc.AccountsApi_GetDataEntriesServer) error {
+func (m *MockGrpcHandlers) GetDataEntries(arg0 *grpc1.DataRequest, arg1 grpc1.AccountsApi_GetDataEntriesServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDataEntries", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -184,11 +231,26 @@ func (mr *MockGrpcHandlersMockRecorder) GetDataEntries(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataEntries", reflect.TypeOf((*MockGrpcHandlers)(nil).GetDataEntries), arg0, arg1)
 }
 
+// GetEffectiveBalance mocks base method.
+func (m *MockGrpcHandlers) GetEffectiveBalance(arg0 context.Context, arg1 *grpc.EffectiveBalanceRequest) (*grpc.EffectiveBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectiveBalance", arg0, arg1)
+	ret0, _ := ret[0].(*grpc.EffectiveBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectiveBalance indicates an expected call of GetEffectiveBalance.
+func (mr *MockGrpcHandlersMockRecorder) GetEffectiveBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveBalance", reflect.TypeOf((*MockGrpcHandlers)(nil).GetEffectiveBalance), arg0, arg1)
+}
+
 // GetInfo mocks base method.
-func (m *MockGrpcHandlers) GetInfo(arg0 context.Context, arg1 *grpc.AssetRequest) (*grpc.AssetInfoResponse, error) {
+func (m *MockGrpcHandlers) GetInfo(arg0 context.Context, arg1 *grpc1.AssetRequest) (*grpc1.AssetInfoResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetInfo", arg0, arg1)
-	ret0, _ := ret[0].(*grpc.AssetInfoResponse)
+	ret0, _ := ret[0].(*grpc1.AssetInfoResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -199,8 +261,22 @@ func (mr *MockGrpcHandlersMockRecorder) GetInfo(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockGrpcHandlers)(nil).GetInfo), arg0, arg1)
 }
 
+// GetMerkleProofs mocks base method.
+func (m *MockGrpcHandlers) GetMerkleProofs(arg0 *grpc.MerkleProofsRequest, arg1 grpc.TransactionsProofApi_GetMerkleProofsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerkleProofs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetMerkleProofs indicates an expected call of GetMerkleProofs.
+func (mr *MockGrpcHandlersMockRecorder) GetMerkleProofs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerkleProofs", reflect.TypeOf((*MockGrpcHandlers)(nil).GetMerkleProofs), arg0, arg1)
+}
+
 // GetNFTList mocks base method.
-func (m *MockGrpcHandlers) GetNFTList(arg0 *grpc.NFTRequest, arg1 grpc.AssetsApi_GetNFTListServer) error {
+func (m *MockGrpcHandlers) GetNFTList(arg0 *grpc1.NFTRequest, arg1 grpc1.AssetsApi_GetNFTListServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetNFTList", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -214,10 +290,10 @@ func (mr *MockGrpcHandlersMockRecorder) GetNFTList(arg0, arg1 interface{}) *gomo
 }
 
 // GetScript mocks base method.
-func (m *MockGrpcHandlers) GetScript(arg0 context.Context, arg1 *grpc.AccountRequest) (*grpc.ScriptData, error) {
+func (m *MockGrpcHandlers) GetScript(arg0 context.Context, arg1 *grpc1.AccountRequest) (*grpc1.ScriptData, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetScript", arg0, arg1)
-	ret0, _ := ret[0].(*grpc.ScriptData)
+	ret0, _ := ret[0].(*grpc1.ScriptData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -229,7 +305,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetScript(arg0, arg1 interface{}) *gomoc
 }
 
 // GetStateChanges mocks base method.
-func (m *MockGrpcHandlers) GetStateChanges(arg0 *grpc.TransactionsRequest, arg1 grpc.TransactionsApi_GetStateChangesServer) error {
+func (m *MockGrpcHandlers) GetStateChanges(arg0 *grpc1.TransactionsRequest, arg1 grpc1.TransactionsApi_GetStateChangesServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStateChanges", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -243,7 +319,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetStateChanges(arg0, arg1 interface{})
 }
 
 // GetStatuses mocks base method.
-func (m *MockGrpcHandlers) GetStatuses(arg0 *grpc.TransactionsByIdRequest, arg1 grpc.TransactionsApi_GetStatusesServer) error {
+func (m *MockGrpcHandlers) GetStatuses(arg0 *grpc1.TransactionsByIdRequest, arg1 grpc1.TransactionsApi_GetStatusesServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStatuses", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -257,7 +333,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetStatuses(arg0, arg1 interface{}) *gom
 }
 
 // GetTransactions mocks base method.
-func (m *MockGrpcHandlers) GetTransactions(arg0 *grpc.TransactionsRequest, arg1 grpc.TransactionsApi_GetTransactionsServer) error {
+func (m *MockGrpcHandlers) GetTransactions(arg0 *grpc1.TransactionsRequest, arg1 grpc1.TransactionsApi_GetTransactionsServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTransactions", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -271,7 +347,7 @@ func (mr *MockGrpcHandlersMockRecorder) GetTransactions(arg0, arg1 interface{})
 }
 
 // GetUnconfirmed mocks base method.
-func (m *MockGrpcHandlers) GetUnconfirmed(arg0 *grpc.TransactionsRequest, arg1 grpc.TransactionsApi_GetUnconfirmedServer) error {
+func (m *MockGrpcHandlers) GetUnconfirmed(arg0 *grpc1.TransactionsRequest, arg1 grpc1.TransactionsApi_GetUnconfirmedServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUnconfirmed", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -284,6 +360,35 @@ func (mr *MockGrpcHandlersMockRecorder) GetUnconfirmed(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnconfirmed", reflect.TypeOf((*MockGrpcHandlers)(nil).GetUnconfirmed), arg0, arg1)
 }
 
+// HashTypedData mocks base method.
+func (m *MockGrpcHandlers) HashTypedData(arg0 context.Context, arg1 *grpc.TypedDataRequest) (*grpc.TypedDataHash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashTypedData", arg0, arg1)
+	ret0, _ := ret[0].(*grpc.TypedDataHash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HashTypedData indicates an expected call of HashTypedData.
+func (mr *MockGrpcHandlersMockRecorder) HashTypedData(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashTypedData", reflect.TypeOf((*MockGrpcHandlers)(nil).HashTypedData), arg0, arg1)
+}
+
+// ListUnconfirmed mocks base method.
+func (m *MockGrpcHandlers) ListUnconfirmed(arg0 *grpc.UnconfirmedRequest, arg1 grpc.UtxApi_ListUnconfirmedServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnconfirmed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListUnconfirmed indicates an expected call of ListUnconfirmed.
+func (mr *MockGrpcHandlersMockRecorder) ListUnconfirmed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnconfirmed", reflect.TypeOf((*MockGrpcHandlers)(nil).ListUnconfirmed), arg0, arg1)
+}
+
 // ResolveAlias mocks base method.
 func (m *MockGrpcHandlers) ResolveAlias(arg0 context.Context, arg1 *wrapperspb.StringValue) (*wrapperspb.BytesValue, error) {
 	m.ctrl.T.Helper()
@@ -300,7 +405,7 @@ func (mr *MockGrpcHandlersMockRecorder) ResolveAlias(arg0, arg1 interface{}) *go
 }
 
 // Sign mocks base method.
-func (m *MockGrpcHandlers) Sign(arg0 context.Context, arg1 *grpc.SignRequest) (*waves.SignedTransaction, error) {
+func (m *MockGrpcHandlers) Sign(arg0 context.Context, arg1 *grpc1.SignRequest) (*waves.SignedTransaction, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Sign", arg0, arg1)
 	ret0, _ := ret[0].(*waves.SignedTransaction)
@@ -313,3 +418,46 @@ func (mr *MockGrpcHandlersMockRecorder) Sign(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockGrpcHandlers)(nil).Sign), arg0, arg1)
 }
+
+// Subscribe mocks base method.
+func (m *MockGrpcHandlers) Subscribe(arg0 *grpc0.SubscribeRequest, arg1 grpc0.BlockchainUpdatesApi_SubscribeServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockGrpcHandlersMockRecorder) Subscribe(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockGrpcHandlers)(nil).Subscribe), arg0, arg1)
+}
+
+// SubscribeUnconfirmed mocks base method.
+func (m *MockGrpcHandlers) SubscribeUnconfirmed(arg0 *grpc.UnconfirmedRequest, arg1 grpc.UtxApi_SubscribeUnconfirmedServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeUnconfirmed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeUnconfirmed indicates an expected call of SubscribeUnconfirmed.
+func (mr *MockGrpcHandlersMockRecorder) SubscribeUnconfirmed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeUnconfirmed", reflect.TypeOf((*MockGrpcHandlers)(nil).SubscribeUnconfirmed), arg0, arg1)
+}
+
+// VerifyTypedData mocks base method.
+func (m *MockGrpcHandlers) VerifyTypedData(arg0 context.Context, arg1 *grpc.TypedDataVerificationRequest) (*grpc.TypedDataVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTypedData", arg0, arg1)
+	ret0, _ := ret[0].(*grpc.TypedDataVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTypedData indicates an expected call of VerifyTypedData.
+func (mr *MockGrpcHandlersMockRecorder) VerifyTypedData(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTypedData", reflect.TypeOf((*MockGrpcHandlers)(nil).VerifyTypedData), arg0, arg1)
+}
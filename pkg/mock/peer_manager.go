@@ -198,6 +198,20 @@ func (mr *MockPeerManagerMockRecorder) NewConnection(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewConnection", reflect.TypeOf((*MockPeerManager)(nil).NewConnection), arg0)
 }
 
+// Reputation mocks base method.
+func (m *MockPeerManager) Reputation(p peer.Peer) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reputation", p)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Reputation indicates an expected call of Reputation.
+func (mr *MockPeerManagerMockRecorder) Reputation(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reputation", reflect.TypeOf((*MockPeerManager)(nil).Reputation), p)
+}
+
 // Score mocks base method.
 func (m *MockPeerManager) Score(p peer.Peer) (*proto.Score, error) {
 	m.ctrl.T.Helper()
@@ -293,6 +307,18 @@ func (mr *MockPeerManagerMockRecorder) UpdateKnownPeers(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKnownPeers", reflect.TypeOf((*MockPeerManager)(nil).UpdateKnownPeers), arg0)
 }
 
+// UpdateReputation mocks base method.
+func (m *MockPeerManager) UpdateReputation(p peer.Peer, event storage.ReputationEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateReputation", p, event)
+}
+
+// UpdateReputation indicates an expected call of UpdateReputation.
+func (mr *MockPeerManagerMockRecorder) UpdateReputation(p, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReputation", reflect.TypeOf((*MockPeerManager)(nil).UpdateReputation), p, event)
+}
+
 // UpdateScore mocks base method.
 func (m *MockPeerManager) UpdateScore(p peer.Peer, score *proto.Score) error {
 	m.ctrl.T.Helper()
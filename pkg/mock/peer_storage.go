@@ -161,6 +161,20 @@ func (mr *MockPeerStorageMockRecorder) DropKnown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropKnown", reflect.TypeOf((*MockPeerStorage)(nil).DropKnown))
 }
 
+// DropReputation mocks base method.
+func (m *MockPeerStorage) DropReputation() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropReputation")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropReputation indicates an expected call of DropReputation.
+func (mr *MockPeerStorageMockRecorder) DropReputation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropReputation", reflect.TypeOf((*MockPeerStorage)(nil).DropReputation))
+}
+
 // DropStorage mocks base method.
 func (m *MockPeerStorage) DropStorage() error {
 	m.ctrl.T.Helper()
@@ -287,6 +301,34 @@ func (mr *MockPeerStorageMockRecorder) RefreshSuspended(now interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSuspended", reflect.TypeOf((*MockPeerStorage)(nil).RefreshSuspended), now)
 }
 
+// ReplaceReputation mocks base method.
+func (m *MockPeerStorage) ReplaceReputation(reputations []storage.PeerReputation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceReputation", reputations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceReputation indicates an expected call of ReplaceReputation.
+func (mr *MockPeerStorageMockRecorder) ReplaceReputation(reputations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceReputation", reflect.TypeOf((*MockPeerStorage)(nil).ReplaceReputation), reputations)
+}
+
+// Reputations mocks base method.
+func (m *MockPeerStorage) Reputations() []storage.PeerReputation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reputations")
+	ret0, _ := ret[0].([]storage.PeerReputation)
+	return ret0
+}
+
+// Reputations indicates an expected call of Reputations.
+func (mr *MockPeerStorageMockRecorder) Reputations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reputations", reflect.TypeOf((*MockPeerStorage)(nil).Reputations))
+}
+
 // Suspended mocks base method.
 func (m *MockPeerStorage) Suspended(now time.Time) []storage.SuspendedPeer {
 	m.ctrl.T.Helper()
@@ -11,9 +11,11 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	crypto "github.com/wavesplatform/gowaves/pkg/crypto"
 	proto "github.com/wavesplatform/gowaves/pkg/proto"
+	ride "github.com/wavesplatform/gowaves/pkg/ride"
 	ast "github.com/wavesplatform/gowaves/pkg/ride/ast"
 	settings "github.com/wavesplatform/gowaves/pkg/settings"
 	state "github.com/wavesplatform/gowaves/pkg/state"
+	types "github.com/wavesplatform/gowaves/pkg/types"
 )
 
 // MockTransactionIterator is a mock of TransactionIterator interface.
@@ -208,6 +210,21 @@ func (mr *MockStateInfoMockRecorder) AssetBalance(account, assetID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetBalance", reflect.TypeOf((*MockStateInfo)(nil).AssetBalance), account, assetID)
 }
 
+// AssetBalanceAtHeight mocks base method.
+func (m *MockStateInfo) AssetBalanceAtHeight(account proto.Recipient, assetID proto.AssetID, height proto.Height) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetBalanceAtHeight", account, assetID, height)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssetBalanceAtHeight indicates an expected call of AssetBalanceAtHeight.
+func (mr *MockStateInfoMockRecorder) AssetBalanceAtHeight(account, assetID, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetBalanceAtHeight", reflect.TypeOf((*MockStateInfo)(nil).AssetBalanceAtHeight), account, assetID, height)
+}
+
 // AssetInfo mocks base method.
 func (m *MockStateInfo) AssetInfo(assetID proto.AssetID) (*proto.AssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -373,6 +390,21 @@ func (mr *MockStateInfoMockRecorder) FullAssetInfo(assetID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullAssetInfo", reflect.TypeOf((*MockStateInfo)(nil).FullAssetInfo), assetID)
 }
 
+// FullAssetInfoAtHeight mocks base method.
+func (m *MockStateInfo) FullAssetInfoAtHeight(assetID proto.AssetID, height proto.Height) (*proto.FullAssetInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FullAssetInfoAtHeight", assetID, height)
+	ret0, _ := ret[0].(*proto.FullAssetInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FullAssetInfoAtHeight indicates an expected call of FullAssetInfoAtHeight.
+func (mr *MockStateInfoMockRecorder) FullAssetInfoAtHeight(assetID, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullAssetInfoAtHeight", reflect.TypeOf((*MockStateInfo)(nil).FullAssetInfoAtHeight), assetID, height)
+}
+
 // FullWavesBalance mocks base method.
 func (m *MockStateInfo) FullWavesBalance(account proto.Recipient) (*proto.FullWavesBalance, error) {
 	m.ctrl.T.Helper()
@@ -568,6 +600,36 @@ func (mr *MockStateInfoMockRecorder) IsAssetExist(assetID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAssetExist", reflect.TypeOf((*MockStateInfo)(nil).IsAssetExist), assetID)
 }
 
+// LeasingInfo mocks base method.
+func (m *MockStateInfo) LeasingInfo(leaseID crypto.Digest) (*proto.LeaseInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeasingInfo", leaseID)
+	ret0, _ := ret[0].(*proto.LeaseInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeasingInfo indicates an expected call of LeasingInfo.
+func (mr *MockStateInfoMockRecorder) LeasingInfo(leaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeasingInfo", reflect.TypeOf((*MockStateInfo)(nil).LeasingInfo), leaseID)
+}
+
+// LeasingInfoAtHeight mocks base method.
+func (m *MockStateInfo) LeasingInfoAtHeight(leaseID crypto.Digest, height proto.Height) (*proto.LeaseInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeasingInfoAtHeight", leaseID, height)
+	ret0, _ := ret[0].(*proto.LeaseInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeasingInfoAtHeight indicates an expected call of LeasingInfoAtHeight.
+func (mr *MockStateInfoMockRecorder) LeasingInfoAtHeight(leaseID, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeasingInfoAtHeight", reflect.TypeOf((*MockStateInfo)(nil).LeasingInfoAtHeight), leaseID, height)
+}
+
 // MapR mocks base method.
 func (m *MockStateInfo) MapR(arg0 func(state.StateInfo) (interface{}, error)) (interface{}, error) {
 	m.ctrl.T.Helper()
@@ -583,6 +645,21 @@ func (mr *MockStateInfoMockRecorder) MapR(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MapR", reflect.TypeOf((*MockStateInfo)(nil).MapR), arg0)
 }
 
+// MinRollbackHeight mocks base method.
+func (m *MockStateInfo) MinRollbackHeight() (proto.Height, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MinRollbackHeight")
+	ret0, _ := ret[0].(proto.Height)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MinRollbackHeight indicates an expected call of MinRollbackHeight.
+func (mr *MockStateInfoMockRecorder) MinRollbackHeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MinRollbackHeight", reflect.TypeOf((*MockStateInfo)(nil).MinRollbackHeight))
+}
+
 // NFTList mocks base method.
 func (m *MockStateInfo) NFTList(account proto.Recipient, limit uint64, afterAssetID *proto.AssetID) ([]*proto.FullAssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -733,6 +810,21 @@ func (mr *MockStateInfoMockRecorder) RetrieveEntry(account, key interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveEntry", reflect.TypeOf((*MockStateInfo)(nil).RetrieveEntry), account, key)
 }
 
+// RetrieveEntryAtHeight mocks base method.
+func (m *MockStateInfo) RetrieveEntryAtHeight(account proto.Recipient, key string, height proto.Height) (proto.DataEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveEntryAtHeight", account, key, height)
+	ret0, _ := ret[0].(proto.DataEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveEntryAtHeight indicates an expected call of RetrieveEntryAtHeight.
+func (mr *MockStateInfoMockRecorder) RetrieveEntryAtHeight(account, key, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveEntryAtHeight", reflect.TypeOf((*MockStateInfo)(nil).RetrieveEntryAtHeight), account, key, height)
+}
+
 // RetrieveIntegerEntry mocks base method.
 func (m *MockStateInfo) RetrieveIntegerEntry(account proto.Recipient, key string) (*proto.IntegerDataEntry, error) {
 	m.ctrl.T.Helper()
@@ -763,6 +855,21 @@ func (mr *MockStateInfoMockRecorder) RetrieveStringEntry(account, key interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveStringEntry", reflect.TypeOf((*MockStateInfo)(nil).RetrieveStringEntry), account, key)
 }
 
+// RewardsInfoAtHeight mocks base method.
+func (m *MockStateInfo) RewardsInfoAtHeight(height proto.Height) (*proto.RewardsInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewardsInfoAtHeight", height)
+	ret0, _ := ret[0].(*proto.RewardsInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewardsInfoAtHeight indicates an expected call of RewardsInfoAtHeight.
+func (mr *MockStateInfoMockRecorder) RewardsInfoAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewardsInfoAtHeight", reflect.TypeOf((*MockStateInfo)(nil).RewardsInfoAtHeight), height)
+}
+
 // ScoreAtHeight mocks base method.
 func (m *MockStateInfo) ScoreAtHeight(height proto.Height) (*big.Int, error) {
 	m.ctrl.T.Helper()
@@ -913,6 +1020,21 @@ func (mr *MockStateInfoMockRecorder) TransactionHeightByID(id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionHeightByID", reflect.TypeOf((*MockStateInfo)(nil).TransactionHeightByID), id)
 }
 
+// TransactionsFailed mocks base method.
+func (m *MockStateInfo) TransactionsFailed(ids [][]byte) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionsFailed", ids)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionsFailed indicates an expected call of TransactionsFailed.
+func (mr *MockStateInfoMockRecorder) TransactionsFailed(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionsFailed", reflect.TypeOf((*MockStateInfo)(nil).TransactionsFailed), ids)
+}
+
 // VotesNum mocks base method.
 func (m *MockStateInfo) VotesNum(featureID int16) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -973,6 +1095,21 @@ func (mr *MockStateInfoMockRecorder) WavesBalance(account interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WavesBalance", reflect.TypeOf((*MockStateInfo)(nil).WavesBalance), account)
 }
 
+// WavesBalanceProfileAtHeight mocks base method.
+func (m *MockStateInfo) WavesBalanceProfileAtHeight(account proto.Recipient, height proto.Height) (*types.WavesBalanceProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WavesBalanceProfileAtHeight", account, height)
+	ret0, _ := ret[0].(*types.WavesBalanceProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WavesBalanceProfileAtHeight indicates an expected call of WavesBalanceProfileAtHeight.
+func (mr *MockStateInfoMockRecorder) WavesBalanceProfileAtHeight(account, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WavesBalanceProfileAtHeight", reflect.TypeOf((*MockStateInfo)(nil).WavesBalanceProfileAtHeight), account, height)
+}
+
 // MockStateModifier is a mock of StateModifier interface.
 type MockStateModifier struct {
 	ctrl     *gomock.Controller
@@ -1069,6 +1206,21 @@ func (mr *MockStateModifierMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStateModifier)(nil).Close))
 }
 
+// EvaluateScript mocks base method.
+func (m *MockStateModifier) EvaluateScript(ev *state.ScriptEvaluation, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (ride.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateScript", ev, currentTimestamp, parentTimestamp, blockVersion)
+	ret0, _ := ret[0].(ride.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateScript indicates an expected call of EvaluateScript.
+func (mr *MockStateModifierMockRecorder) EvaluateScript(ev, currentTimestamp, parentTimestamp, blockVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateScript", reflect.TypeOf((*MockStateModifier)(nil).EvaluateScript), ev, currentTimestamp, parentTimestamp, blockVersion)
+}
+
 // Map mocks base method.
 func (m *MockStateModifier) Map(arg0 func(state.NonThreadSafeState) error) error {
 	m.ctrl.T.Helper()
@@ -1151,6 +1303,21 @@ func (mr *MockStateModifierMockRecorder) StartProvidingExtendedApi() *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartProvidingExtendedApi", reflect.TypeOf((*MockStateModifier)(nil).StartProvidingExtendedApi))
 }
 
+// TraceNextTx mocks base method.
+func (m *MockStateModifier) TraceNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (*ride.Trace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceNextTx", tx, currentTimestamp, parentTimestamp, blockVersion)
+	ret0, _ := ret[0].(*ride.Trace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceNextTx indicates an expected call of TraceNextTx.
+func (mr *MockStateModifierMockRecorder) TraceNextTx(tx, currentTimestamp, parentTimestamp, blockVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceNextTx", reflect.TypeOf((*MockStateModifier)(nil).TraceNextTx), tx, currentTimestamp, parentTimestamp, blockVersion)
+}
+
 // TxValidation mocks base method.
 func (m *MockStateModifier) TxValidation(arg0 func(state.TxValidation) error) error {
 	m.ctrl.T.Helper()
@@ -1202,6 +1369,36 @@ func (m *MockTxValidation) EXPECT() *MockTxValidationMockRecorder {
 	return m.recorder
 }
 
+// EvaluateScript mocks base method.
+func (m *MockTxValidation) EvaluateScript(ev *state.ScriptEvaluation, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (ride.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateScript", ev, currentTimestamp, parentTimestamp, blockVersion)
+	ret0, _ := ret[0].(ride.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateScript indicates an expected call of EvaluateScript.
+func (mr *MockTxValidationMockRecorder) EvaluateScript(ev, currentTimestamp, parentTimestamp, blockVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateScript", reflect.TypeOf((*MockTxValidation)(nil).EvaluateScript), ev, currentTimestamp, parentTimestamp, blockVersion)
+}
+
+// TraceNextTx mocks base method.
+func (m *MockTxValidation) TraceNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (*ride.Trace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceNextTx", tx, currentTimestamp, parentTimestamp, blockVersion)
+	ret0, _ := ret[0].(*ride.Trace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceNextTx indicates an expected call of TraceNextTx.
+func (mr *MockTxValidationMockRecorder) TraceNextTx(tx, currentTimestamp, parentTimestamp, blockVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceNextTx", reflect.TypeOf((*MockTxValidation)(nil).TraceNextTx), tx, currentTimestamp, parentTimestamp, blockVersion)
+}
+
 // ValidateNextTx mocks base method.
 func (m *MockTxValidation) ValidateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion, acceptFailed bool) error {
 	m.ctrl.T.Helper()
@@ -1388,6 +1585,21 @@ func (mr *MockStateMockRecorder) AssetBalance(account, assetID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetBalance", reflect.TypeOf((*MockState)(nil).AssetBalance), account, assetID)
 }
 
+// AssetBalanceAtHeight mocks base method.
+func (m *MockState) AssetBalanceAtHeight(account proto.Recipient, assetID proto.AssetID, height proto.Height) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetBalanceAtHeight", account, assetID, height)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssetBalanceAtHeight indicates an expected call of AssetBalanceAtHeight.
+func (mr *MockStateMockRecorder) AssetBalanceAtHeight(account, assetID, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetBalanceAtHeight", reflect.TypeOf((*MockState)(nil).AssetBalanceAtHeight), account, assetID, height)
+}
+
 // AssetInfo mocks base method.
 func (m *MockState) AssetInfo(assetID proto.AssetID) (*proto.AssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -1552,6 +1764,21 @@ func (mr *MockStateMockRecorder) EstimatorVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimatorVersion", reflect.TypeOf((*MockState)(nil).EstimatorVersion))
 }
 
+// EvaluateScript mocks base method.
+func (m *MockState) EvaluateScript(ev *state.ScriptEvaluation, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (ride.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateScript", ev, currentTimestamp, parentTimestamp, blockVersion)
+	ret0, _ := ret[0].(ride.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateScript indicates an expected call of EvaluateScript.
+func (mr *MockStateMockRecorder) EvaluateScript(ev, currentTimestamp, parentTimestamp, blockVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateScript", reflect.TypeOf((*MockState)(nil).EvaluateScript), ev, currentTimestamp, parentTimestamp, blockVersion)
+}
+
 // FullAssetInfo mocks base method.
 func (m *MockState) FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -1567,6 +1794,21 @@ func (mr *MockStateMockRecorder) FullAssetInfo(assetID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullAssetInfo", reflect.TypeOf((*MockState)(nil).FullAssetInfo), assetID)
 }
 
+// FullAssetInfoAtHeight mocks base method.
+func (m *MockState) FullAssetInfoAtHeight(assetID proto.AssetID, height proto.Height) (*proto.FullAssetInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FullAssetInfoAtHeight", assetID, height)
+	ret0, _ := ret[0].(*proto.FullAssetInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FullAssetInfoAtHeight indicates an expected call of FullAssetInfoAtHeight.
+func (mr *MockStateMockRecorder) FullAssetInfoAtHeight(assetID, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullAssetInfoAtHeight", reflect.TypeOf((*MockState)(nil).FullAssetInfoAtHeight), assetID, height)
+}
+
 // FullWavesBalance mocks base method.
 func (m *MockState) FullWavesBalance(account proto.Recipient) (*proto.FullWavesBalance, error) {
 	m.ctrl.T.Helper()
@@ -1762,6 +2004,36 @@ func (mr *MockStateMockRecorder) IsAssetExist(assetID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAssetExist", reflect.TypeOf((*MockState)(nil).IsAssetExist), assetID)
 }
 
+// LeasingInfo mocks base method.
+func (m *MockState) LeasingInfo(leaseID crypto.Digest) (*proto.LeaseInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeasingInfo", leaseID)
+	ret0, _ := ret[0].(*proto.LeaseInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeasingInfo indicates an expected call of LeasingInfo.
+func (mr *MockStateMockRecorder) LeasingInfo(leaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeasingInfo", reflect.TypeOf((*MockState)(nil).LeasingInfo), leaseID)
+}
+
+// LeasingInfoAtHeight mocks base method.
+func (m *MockState) LeasingInfoAtHeight(leaseID crypto.Digest, height proto.Height) (*proto.LeaseInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeasingInfoAtHeight", leaseID, height)
+	ret0, _ := ret[0].(*proto.LeaseInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeasingInfoAtHeight indicates an expected call of LeasingInfoAtHeight.
+func (mr *MockStateMockRecorder) LeasingInfoAtHeight(leaseID, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeasingInfoAtHeight", reflect.TypeOf((*MockState)(nil).LeasingInfoAtHeight), leaseID, height)
+}
+
 // Map mocks base method.
 func (m *MockState) Map(arg0 func(state.NonThreadSafeState) error) error {
 	m.ctrl.T.Helper()
@@ -1791,6 +2063,21 @@ func (mr *MockStateMockRecorder) MapR(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MapR", reflect.TypeOf((*MockState)(nil).MapR), arg0)
 }
 
+// MinRollbackHeight mocks base method.
+func (m *MockState) MinRollbackHeight() (proto.Height, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MinRollbackHeight")
+	ret0, _ := ret[0].(proto.Height)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MinRollbackHeight indicates an expected call of MinRollbackHeight.
+func (mr *MockStateMockRecorder) MinRollbackHeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MinRollbackHeight", reflect.TypeOf((*MockState)(nil).MinRollbackHeight))
+}
+
 // NFTList mocks base method.
 func (m *MockState) NFTList(account proto.Recipient, limit uint64, afterAssetID *proto.AssetID) ([]*proto.FullAssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -1967,6 +2254,21 @@ func (mr *MockStateMockRecorder) RetrieveEntry(account, key interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveEntry", reflect.TypeOf((*MockState)(nil).RetrieveEntry), account, key)
 }
 
+// RetrieveEntryAtHeight mocks base method.
+func (m *MockState) RetrieveEntryAtHeight(account proto.Recipient, key string, height proto.Height) (proto.DataEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveEntryAtHeight", account, key, height)
+	ret0, _ := ret[0].(proto.DataEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveEntryAtHeight indicates an expected call of RetrieveEntryAtHeight.
+func (mr *MockStateMockRecorder) RetrieveEntryAtHeight(account, key, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveEntryAtHeight", reflect.TypeOf((*MockState)(nil).RetrieveEntryAtHeight), account, key, height)
+}
+
 // RetrieveIntegerEntry mocks base method.
 func (m *MockState) RetrieveIntegerEntry(account proto.Recipient, key string) (*proto.IntegerDataEntry, error) {
 	m.ctrl.T.Helper()
@@ -1997,6 +2299,21 @@ func (mr *MockStateMockRecorder) RetrieveStringEntry(account, key interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveStringEntry", reflect.TypeOf((*MockState)(nil).RetrieveStringEntry), account, key)
 }
 
+// RewardsInfoAtHeight mocks base method.
+func (m *MockState) RewardsInfoAtHeight(height proto.Height) (*proto.RewardsInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewardsInfoAtHeight", height)
+	ret0, _ := ret[0].(*proto.RewardsInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewardsInfoAtHeight indicates an expected call of RewardsInfoAtHeight.
+func (mr *MockStateMockRecorder) RewardsInfoAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewardsInfoAtHeight", reflect.TypeOf((*MockState)(nil).RewardsInfoAtHeight), height)
+}
+
 // RollbackTo mocks base method.
 func (m *MockState) RollbackTo(removalEdge proto.BlockID) error {
 	m.ctrl.T.Helper()
@@ -2143,6 +2460,21 @@ func (mr *MockStateMockRecorder) TopBlock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopBlock", reflect.TypeOf((*MockState)(nil).TopBlock))
 }
 
+// TraceNextTx mocks base method.
+func (m *MockState) TraceNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (*ride.Trace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceNextTx", tx, currentTimestamp, parentTimestamp, blockVersion)
+	ret0, _ := ret[0].(*ride.Trace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceNextTx indicates an expected call of TraceNextTx.
+func (mr *MockStateMockRecorder) TraceNextTx(tx, currentTimestamp, parentTimestamp, blockVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceNextTx", reflect.TypeOf((*MockState)(nil).TraceNextTx), tx, currentTimestamp, parentTimestamp, blockVersion)
+}
+
 // TransactionByID mocks base method.
 func (m *MockState) TransactionByID(id []byte) (proto.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -2189,6 +2521,21 @@ func (mr *MockStateMockRecorder) TransactionHeightByID(id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionHeightByID", reflect.TypeOf((*MockState)(nil).TransactionHeightByID), id)
 }
 
+// TransactionsFailed mocks base method.
+func (m *MockState) TransactionsFailed(ids [][]byte) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionsFailed", ids)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionsFailed indicates an expected call of TransactionsFailed.
+func (mr *MockStateMockRecorder) TransactionsFailed(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionsFailed", reflect.TypeOf((*MockState)(nil).TransactionsFailed), ids)
+}
+
 // TxValidation mocks base method.
 func (m *MockState) TxValidation(arg0 func(state.TxValidation) error) error {
 	m.ctrl.T.Helper()
@@ -2276,3 +2623,18 @@ func (mr *MockStateMockRecorder) WavesBalance(account interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WavesBalance", reflect.TypeOf((*MockState)(nil).WavesBalance), account)
 }
+
+// WavesBalanceProfileAtHeight mocks base method.
+func (m *MockState) WavesBalanceProfileAtHeight(account proto.Recipient, height proto.Height) (*types.WavesBalanceProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WavesBalanceProfileAtHeight", account, height)
+	ret0, _ := ret[0].(*types.WavesBalanceProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WavesBalanceProfileAtHeight indicates an expected call of WavesBalanceProfileAtHeight.
+func (mr *MockStateMockRecorder) WavesBalanceProfileAtHeight(account, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WavesBalanceProfileAtHeight", reflect.TypeOf((*MockState)(nil).WavesBalanceProfileAtHeight), account, height)
+}
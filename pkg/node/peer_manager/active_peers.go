@@ -78,6 +78,23 @@ func (ap *activePeers) getPeerWithMaxScore() (peerInfo, bool) {
 	return ap.m[ap.sortedByScore[0]], true
 }
 
+// peersWithMaxScore returns all peers that have the maximal score.
+func (ap *activePeers) peersWithMaxScore() []peerInfo {
+	if len(ap.m) == 0 {
+		return nil
+	}
+	top := ap.m[ap.sortedByScore[0]]
+	res := []peerInfo{top}
+	for _, id := range ap.sortedByScore[1:] {
+		info := ap.m[id]
+		if info.score.Cmp(top.score) != 0 {
+			break
+		}
+		res = append(res, info)
+	}
+	return res
+}
+
 func (ap *activePeers) size() int {
 	return len(ap.m)
 }
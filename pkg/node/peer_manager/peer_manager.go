@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 const (
 	suspendDuration              = 5 * time.Minute
 	clearRestrictedPeersInterval = 1 * time.Minute
+	flushReputationInterval      = 5 * time.Minute
 )
 
 type peerInfo struct {
@@ -58,6 +60,11 @@ type PeerManager interface {
 	GetPeerWithMaxScore() (peer.Peer, error)
 
 	Disconnect(peer.Peer)
+
+	// UpdateReputation changes the reputation of the peer according to the event.
+	UpdateReputation(p peer.Peer, event storage.ReputationEvent)
+	// Reputation returns the current reputation of the peer.
+	Reputation(p peer.Peer) int64
 }
 
 type PeerManagerImpl struct {
@@ -72,6 +79,9 @@ type PeerManagerImpl struct {
 	newConnectionsLimit       int
 	version                   proto.Version
 	networkName               string
+	reputationMu              sync.Mutex // Guards reputation
+	reputation                map[storage.IP]storage.PeerReputation
+	reputationChanged         bool // Reputation has changes that are not flushed to the storage
 }
 
 func NewPeerManager(spawner PeerSpawner, storage PeerStorage, limitConnections int, version proto.Version,
@@ -89,7 +99,17 @@ func NewPeerManager(spawner PeerSpawner, storage PeerStorage, limitConnections i
 		newConnectionsLimit:       newConnectionsLimit,
 		version:                   version,
 		networkName:               networkName,
+		reputation:                loadReputation(storage),
+	}
+}
+
+func loadReputation(peerStorage PeerStorage) map[storage.IP]storage.PeerReputation {
+	stored := peerStorage.Reputations()
+	res := make(map[storage.IP]storage.PeerReputation, len(stored))
+	for _, r := range stored {
+		res[r.IP] = r
 	}
+	return res
 }
 
 func (a *PeerManagerImpl) NewConnection(p peer.Peer) (err error) {
@@ -243,6 +263,8 @@ func (a *PeerManagerImpl) UpdateKnownPeers(known []storage.KnownPeer) error {
 }
 
 func (a *PeerManagerImpl) Close() {
+	a.flushReputation(time.Now())
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -277,7 +299,7 @@ func (a *PeerManagerImpl) SpawnOutgoingConnections(ctx context.Context) {
 		return
 	}
 
-	known := a.KnownPeers()
+	known := a.preferredKnownPeers(time.Now())
 
 	active := map[proto.IpPort]struct{}{}
 	a.active.forEach(func(_ peer.ID, info peerInfo) {
@@ -403,12 +425,16 @@ func (a *PeerManagerImpl) Disconnect(p peer.Peer) {
 func (a *PeerManagerImpl) Run(ctx context.Context) {
 	ticker := time.NewTicker(clearRestrictedPeersInterval)
 	defer ticker.Stop()
+	flushTicker := time.NewTicker(flushReputationInterval)
+	defer flushTicker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			a.clearRestrictedPeers(time.Now())
+		case <-flushTicker.C:
+			a.flushReputation(time.Now())
 		}
 	}
 }
@@ -430,11 +456,65 @@ func (a *PeerManagerImpl) AddAddress(ctx context.Context, addr proto.TCPAddr) er
 	return nil
 }
 
+// GetPeerWithMaxScore returns the peer with the maximal score, among the peers with equal scores the one with
+// the best reputation is chosen.
 func (a *PeerManagerImpl) GetPeerWithMaxScore() (peer.Peer, error) {
-	if info, ok := a.active.getPeerWithMaxScore(); ok {
-		return info.peer, nil
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	candidates := a.active.peersWithMaxScore()
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("no active peers")
+	}
+	now := time.Now()
+	best, bestReputation := candidates[0].peer, a.peerReputation(candidates[0].peer, now)
+	for _, info := range candidates[1:] {
+		if r := a.peerReputation(info.peer, now); r > bestReputation {
+			best, bestReputation = info.peer, r
+		}
 	}
-	return nil, errors.Errorf("no active peers")
+	return best, nil
+}
+
+// UpdateReputation changes the reputation in memory, changes are saved to the storage periodically and on Close.
+func (a *PeerManagerImpl) UpdateReputation(p peer.Peer, event storage.ReputationEvent) {
+	a.reputationMu.Lock()
+	defer a.reputationMu.Unlock()
+	now := time.Now()
+	ip := storage.IpFromIpPort(p.RemoteAddr().ToIpPort())
+	score := applyReputationEvent(a.unsafeIPReputation(ip, now), event)
+	if a.reputation == nil {
+		a.reputation = make(map[storage.IP]storage.PeerReputation)
+	}
+	a.reputation[ip] = storage.NewPeerReputation(ip, score, now.UnixMilli())
+	a.reputationChanged = true
+	zap.S().Debugf("[%s] Peer reputation changed to %d on %s", p.ID(), score, event)
+}
+
+func (a *PeerManagerImpl) Reputation(p peer.Peer) int64 {
+	return a.peerReputation(p, time.Now())
+}
+
+// flushReputation saves the reputation to the storage, the entries that decayed to zero are dropped.
+func (a *PeerManagerImpl) flushReputation(now time.Time) {
+	a.reputationMu.Lock()
+	defer a.reputationMu.Unlock()
+	reputations := make([]storage.PeerReputation, 0, len(a.reputation))
+	for ip, r := range a.reputation {
+		if decayedReputation(r, now) == 0 {
+			delete(a.reputation, ip)
+			a.reputationChanged = true
+			continue
+		}
+		reputations = append(reputations, r)
+	}
+	if !a.reputationChanged {
+		return
+	}
+	if err := a.peerStorage.ReplaceReputation(reputations); err != nil {
+		zap.S().Errorf("Failed to save peers reputation: %v", err)
+		return
+	}
+	a.reputationChanged = false
 }
 
 func (a *PeerManagerImpl) connected(p peer.Peer) (peer.Peer, bool) {
@@ -444,6 +524,44 @@ func (a *PeerManagerImpl) connected(p peer.Peer) (peer.Peer, bool) {
 	return info.peer, ok
 }
 
+func (a *PeerManagerImpl) peerReputation(p peer.Peer, now time.Time) int64 {
+	return a.ipReputation(storage.IpFromIpPort(p.RemoteAddr().ToIpPort()), now)
+}
+
+func (a *PeerManagerImpl) ipReputation(ip storage.IP, now time.Time) int64 {
+	a.reputationMu.Lock()
+	defer a.reputationMu.Unlock()
+	return a.unsafeIPReputation(ip, now)
+}
+
+// non thread safe
+func (a *PeerManagerImpl) unsafeIPReputation(ip storage.IP, now time.Time) int64 {
+	r, ok := a.reputation[ip]
+	if !ok {
+		return 0
+	}
+	return decayedReputation(r, now)
+}
+
+// preferredKnownPeers returns known peers except the peers with bad reputation, peers with better reputation go first.
+func (a *PeerManagerImpl) preferredKnownPeers(now time.Time) []storage.KnownPeer {
+	known := a.KnownPeers()
+	preferred := make([]storage.KnownPeer, 0, len(known))
+	reputations := make(map[storage.KnownPeer]int64, len(known))
+	for _, k := range known {
+		r := a.ipReputation(k.IP(), now)
+		if r < badReputationThreshold {
+			continue
+		}
+		preferred = append(preferred, k)
+		reputations[k] = r
+	}
+	sort.SliceStable(preferred, func(i, j int) bool {
+		return reputations[preferred[i]] > reputations[preferred[j]]
+	})
+	return preferred
+}
+
 // non thread safe
 func (a *PeerManagerImpl) unsafeConnectedCount() int {
 	return a.active.size()
@@ -28,5 +28,9 @@ type PeerStorage interface {
 	RefreshBlackList(now time.Time) error
 	DropBlackList() error
 
+	Reputations() []storage.PeerReputation
+	ReplaceReputation(reputations []storage.PeerReputation) error
+	DropReputation() error
+
 	DropStorage() error
 }
@@ -0,0 +1,56 @@
+package peer_manager
+
+import (
+	"math"
+	"time"
+
+	"github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
+)
+
+const (
+	maxReputation = 1000
+	minReputation = -1000
+	// Outgoing connections are not established to peers with reputation below the threshold.
+	badReputationThreshold = -500
+	// Reputation tends to zero with time, it halves every reputationHalfLife since the last update.
+	reputationHalfLife = time.Hour
+)
+
+func reputationEventWeight(e storage.ReputationEvent) int64 {
+	switch e {
+	case storage.BlockApplied:
+		return 1
+	case storage.InvalidBlock:
+		return -300
+	case storage.SlowResponse:
+		return -20
+	case storage.DuplicateMicroBlock:
+		return -10
+	case storage.Timeout:
+		return -100
+	default:
+		return 0
+	}
+}
+
+// decayedReputation returns the reputation score reduced according to the time passed since its last update.
+func decayedReputation(r storage.PeerReputation, now time.Time) int64 {
+	elapsed := now.Sub(r.UpdateTime())
+	if r.Score == 0 || elapsed <= 0 {
+		return r.Score
+	}
+	return int64(math.Round(float64(r.Score) * math.Exp2(-float64(elapsed)/float64(reputationHalfLife))))
+}
+
+// applyReputationEvent adds the weight of the event to the reputation score keeping it within the limits.
+func applyReputationEvent(score int64, event storage.ReputationEvent) int64 {
+	score += reputationEventWeight(event)
+	switch {
+	case score > maxReputation:
+		return maxReputation
+	case score < minReputation:
+		return minReputation
+	default:
+		return score
+	}
+}
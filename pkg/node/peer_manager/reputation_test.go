@@ -0,0 +1,152 @@
+package peer_manager
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
+	p2pMock "github.com/wavesplatform/gowaves/pkg/p2p/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func TestDecayedReputation(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		score   int64
+		elapsed time.Duration
+		decayed int64
+	}{
+		{0, time.Hour, 0},
+		{100, 0, 100},
+		{100, -time.Minute, 100},
+		{100, reputationHalfLife, 50},
+		{-400, 2 * reputationHalfLife, -100},
+		{-1000, 20 * reputationHalfLife, 0},
+	} {
+		r := storage.NewPeerReputation(storage.IPFromString("13.3.4.1"), test.score, now.Add(-test.elapsed).UnixMilli())
+		assert.Equal(t, test.decayed, decayedReputation(r, now), "score %d, elapsed %s", test.score, test.elapsed)
+	}
+}
+
+func TestApplyReputationEvent(t *testing.T) {
+	assert.Equal(t, int64(1), applyReputationEvent(0, storage.BlockApplied))
+	assert.Equal(t, int64(-300), applyReputationEvent(0, storage.InvalidBlock))
+	assert.Equal(t, int64(-20), applyReputationEvent(0, storage.SlowResponse))
+	assert.Equal(t, int64(-10), applyReputationEvent(0, storage.DuplicateMicroBlock))
+	assert.Equal(t, int64(-100), applyReputationEvent(0, storage.Timeout))
+	assert.Equal(t, int64(maxReputation), applyReputationEvent(maxReputation, storage.BlockApplied))
+	assert.Equal(t, int64(minReputation), applyReputationEvent(minReputation+100, storage.InvalidBlock))
+}
+
+func TestPeerManagerImpl_UpdateReputation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tcpAddr := proto.NewTCPAddrFromString("32.34.46.1:4535")
+	ip := storage.IpFromIpPort(tcpAddr.ToIpPort())
+	decayed := storage.IPFromString("13.3.4.1")
+
+	p := mock.NewMockPeer(ctrl)
+	p.EXPECT().RemoteAddr().Return(tcpAddr).AnyTimes()
+	p.EXPECT().ID().AnyTimes()
+
+	peerStorage := mock.NewMockPeerStorage(ctrl)
+	peerStorage.EXPECT().Reputations().Return([]storage.PeerReputation{
+		storage.NewPeerReputation(decayed, 10, time.Now().Add(-20*reputationHalfLife).UnixMilli()),
+	})
+	manager := NewPeerManager(nil, peerStorage, 0, proto.ProtocolVersion, "", false, 0, 0)
+
+	// Updates are kept in memory
+	manager.UpdateReputation(p, storage.Timeout)
+	assert.Equal(t, int64(-100), manager.Reputation(p))
+	manager.UpdateReputation(p, storage.InvalidBlock)
+	assert.Equal(t, int64(-400), manager.Reputation(p))
+
+	// Decayed entries are dropped on flush
+	peerStorage.EXPECT().ReplaceReputation(gomock.Any()).DoAndReturn(func(rs []storage.PeerReputation) error {
+		require.Len(t, rs, 1)
+		assert.Equal(t, ip, rs[0].IP)
+		assert.Equal(t, int64(-400), rs[0].Score)
+		return nil
+	})
+	manager.flushReputation(time.Now())
+	_, ok := manager.reputation[decayed]
+	assert.False(t, ok)
+
+	// Nothing is written without changes
+	manager.flushReputation(time.Now())
+}
+
+func TestPeerManagerImpl_GetPeerWithMaxScore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addrs := []proto.TCPAddr{
+		proto.NewTCPAddrFromString("32.34.46.1:4535"),
+		proto.NewTCPAddrFromString("32.34.46.2:4535"),
+		proto.NewTCPAddrFromString("32.34.46.3:4535"),
+	}
+	reputations := map[storage.IP]int64{
+		storage.IpFromIpPort(addrs[0].ToIpPort()): -50,
+		storage.IpFromIpPort(addrs[1].ToIpPort()): 20,
+		storage.IpFromIpPort(addrs[2].ToIpPort()): 100,
+	}
+	manager := PeerManagerImpl{
+		active:     newActivePeers(),
+		reputation: make(map[storage.IP]storage.PeerReputation),
+	}
+	for ip, r := range reputations {
+		manager.reputation[ip] = storage.NewPeerReputation(ip, r, time.Now().UnixMilli())
+	}
+	_, err := manager.GetPeerWithMaxScore()
+	require.Error(t, err)
+
+	peers := make([]*p2pMock.Peer, len(addrs))
+	for i, addr := range addrs {
+		peers[i] = &p2pMock.Peer{Addr: addr.String(), RemoteAddress: addr}
+		manager.active.add(peers[i])
+	}
+	// The third peer has the best reputation but the lowest score
+	require.NoError(t, manager.active.updateScore(peers[0].ID(), big.NewInt(10)))
+	require.NoError(t, manager.active.updateScore(peers[1].ID(), big.NewInt(10)))
+	require.NoError(t, manager.active.updateScore(peers[2].ID(), big.NewInt(5)))
+
+	p, err := manager.GetPeerWithMaxScore()
+	require.NoError(t, err)
+	assert.Equal(t, peers[1], p)
+}
+
+func TestPeerManagerImpl_PreferredKnownPeers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	known := []storage.KnownPeer{
+		storage.KnownPeer(proto.NewTCPAddrFromString("13.3.4.1:2345").ToIpPort()),
+		storage.KnownPeer(proto.NewTCPAddrFromString("3.54.1.9:1454").ToIpPort()),
+		storage.KnownPeer(proto.NewTCPAddrFromString("23.43.7.43:4234").ToIpPort()),
+		storage.KnownPeer(proto.NewTCPAddrFromString("42.54.1.6:54356").ToIpPort()),
+	}
+	reputations := map[storage.IP]int64{
+		known[1].IP(): badReputationThreshold - 1,
+		known[2].IP(): 30,
+		known[3].IP(): -10,
+	}
+	peerStorage := mock.NewMockPeerStorage(ctrl)
+	peerStorage.EXPECT().Known(10).Return(known)
+
+	manager := PeerManagerImpl{
+		peerStorage:         peerStorage,
+		newConnectionsLimit: 10,
+		reputation:          make(map[storage.IP]storage.PeerReputation),
+	}
+	for ip, r := range reputations {
+		manager.reputation[ip] = storage.NewPeerReputation(ip, r, time.Now().UnixMilli())
+	}
+	preferred := manager.preferredKnownPeers(time.Now())
+	assert.Equal(t, []storage.KnownPeer{known[2], known[0], known[3]}, preferred)
+}
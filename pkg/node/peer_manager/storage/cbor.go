@@ -21,14 +21,16 @@ const (
 )
 
 type CBORStorage struct {
-	rwMutex           sync.RWMutex
-	storageDir        string
-	suspended         restrictedPeers
-	blackList         restrictedPeers
-	suspendedFilePath string
-	blackListFilePath string
-	known             knownPeers // Map of all ever known peers with a publicly available declared address and the last connection attempt timestamp.
-	knownFilePath     string
+	rwMutex            sync.RWMutex
+	storageDir         string
+	suspended          restrictedPeers
+	blackList          restrictedPeers
+	suspendedFilePath  string
+	blackListFilePath  string
+	known              knownPeers // Map of all ever known peers with a publicly available declared address and the last connection attempt timestamp.
+	knownFilePath      string
+	reputation         peersReputation
+	reputationFilePath string
 }
 
 type restrictedPeersID byte
@@ -82,15 +84,21 @@ func newCBORStorageInDir(storageDir string, now time.Time, currVersion int) (*CB
 	if err := createFileIfNotExist(blackListFile); err != nil {
 		return nil, errors.Wrapf(err, "failed to create black list peers storage file")
 	}
+	reputationFile := reputationFilePath(storageDir)
+	if err := createFileIfNotExist(reputationFile); err != nil {
+		return nil, errors.Wrap(err, "failed to create peers reputation storage file")
+	}
 
 	storage := &CBORStorage{
-		storageDir:        storageDir,
-		suspended:         suspendedPeers{},
-		blackList:         blackListedPeers{},
-		suspendedFilePath: suspendedFile,
-		blackListFilePath: blackListFile,
-		known:             knownPeers{},
-		knownFilePath:     knownFile,
+		storageDir:         storageDir,
+		suspended:          suspendedPeers{},
+		blackList:          blackListedPeers{},
+		suspendedFilePath:  suspendedFile,
+		blackListFilePath:  blackListFile,
+		known:              knownPeers{},
+		knownFilePath:      knownFile,
+		reputation:         peersReputation{},
+		reputationFilePath: reputationFile,
 	}
 
 	versionFile := storageVersionFilePath(storageDir)
@@ -126,6 +134,9 @@ func newCBORStorageInDir(storageDir string, now time.Time, currVersion int) (*CB
 	if err := unmarshalCborFromFile(blackListFile, &storage.blackList); err != nil && err != io.EOF {
 		return nil, errors.Wrapf(err, "failed to load black list peers from file %q", blackListFile)
 	}
+	if err := unmarshalCborFromFile(reputationFile, &storage.reputation); err != nil && err != io.EOF {
+		return nil, errors.Wrapf(err, "failed to load peers reputation from file %q", reputationFile)
+	}
 
 	if len(storage.suspended) != 0 {
 		// Remove expired peers
@@ -375,6 +386,43 @@ func (bs *CBORStorage) DropBlackList() error {
 	return bs.dropRestricted(blackListedPeersID)
 }
 
+// Reputations returns all stored reputations of peers.
+func (bs *CBORStorage) Reputations() []PeerReputation {
+	bs.rwMutex.RLock()
+	defer bs.rwMutex.RUnlock()
+	res := make([]PeerReputation, 0, len(bs.reputation))
+	for _, r := range bs.reputation {
+		res = append(res, r)
+	}
+	return res
+}
+
+// ReplaceReputation replaces all reputations of peers in peers storage with strong error guarantees.
+func (bs *CBORStorage) ReplaceReputation(reputations []PeerReputation) error {
+	bs.rwMutex.Lock()
+	defer bs.rwMutex.Unlock()
+
+	backup := bs.reputation
+	bs.reputation = make(peersReputation, len(reputations))
+	for _, r := range reputations {
+		bs.reputation[r.IP] = r
+	}
+	if err := marshalToCborAndSyncToFile(bs.reputationFilePath, bs.reputation); err != nil {
+		// In case of failure restore initial state from backup
+		bs.reputation = backup
+		return errors.Wrap(err, "failed to marshal peers reputation and sync storage")
+	}
+	return nil
+}
+
+// DropReputation clear reputation in memory cache and truncates peers reputation storage file with strong error
+// guarantee.
+func (bs *CBORStorage) DropReputation() error {
+	bs.rwMutex.Lock()
+	defer bs.rwMutex.Unlock()
+	return bs.unsafeDropReputation()
+}
+
 // DropStorage clear storage memory cache and truncates storage files.
 // In case of error we can lose suspended peers storage file, but honestly it's almost impossible case.
 func (bs *CBORStorage) DropStorage() error {
@@ -390,9 +438,15 @@ func (bs *CBORStorage) DropStorage() error {
 		return errors.Wrap(err, "failed to drop black list peers storage")
 	}
 
+	reputationBackup := bs.reputation
+	if err := bs.unsafeDropReputation(); err != nil {
+		return errors.Wrap(err, "failed to drop peers reputation storage")
+	}
+
 	if err := bs.unsafeDropKnown(); err != nil {
 		bs.suspended = suspendedBackup
 		bs.blackList = blackListBackup
+		bs.reputation = reputationBackup
 		// It's almost impossible case, but if it happens we have inconsistency in suspended peers,
 		// but honestly it's not fatal error
 		if syncErr := marshalToCborAndSyncToFile(bs.suspendedFilePath, bs.suspended); syncErr != nil {
@@ -401,6 +455,9 @@ func (bs *CBORStorage) DropStorage() error {
 		if syncErr := marshalToCborAndSyncToFile(bs.blackListFilePath, bs.blackList); syncErr != nil {
 			return errors.Wrapf(err, "failed to sync black list peers storage from backup: %v", syncErr)
 		}
+		if syncErr := marshalToCborAndSyncToFile(bs.reputationFilePath, bs.reputation); syncErr != nil {
+			return errors.Wrapf(err, "failed to sync peers reputation storage from backup: %v", syncErr)
+		}
 		return errors.Wrap(err, "failed to drop known peers storage")
 	}
 	return nil
@@ -448,6 +505,16 @@ func (bs *CBORStorage) unsafeDropKnown() error {
 	return nil
 }
 
+func (bs *CBORStorage) unsafeDropReputation() error {
+	// Truncate reputationStorageFile to zero size
+	if err := os.Truncate(bs.reputationFilePath, 0); err != nil {
+		return errors.Wrapf(err, "failed to drop peers reputation storage file %q", bs.reputationFilePath)
+	}
+	// Clear map
+	bs.reputation = peersReputation{}
+	return nil
+}
+
 func (bs *CBORStorage) restrictedFilePathByID(restrictedID restrictedPeersID) string {
 	switch restrictedID {
 	case suspendedPeersID:
@@ -567,6 +634,10 @@ func blackListFilePath(storageDir string) string {
 	return filepath.Join(storageDir, "peers_black_list.cbor")
 }
 
+func reputationFilePath(storageDir string) string {
+	return filepath.Join(storageDir, "peers_reputation.cbor")
+}
+
 func storageVersionFilePath(storageDir string) string {
 	return filepath.Join(storageDir, "peers_storage_version.txt")
 }
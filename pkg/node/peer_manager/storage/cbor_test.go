@@ -499,6 +499,58 @@ func (s *binaryStorageCborSuite) TestCBORStorageBlackList() {
 	})
 }
 
+func (s *binaryStorageCborSuite) TestCBORStorageReputation() {
+	now := s.now.Truncate(time.Millisecond)
+	reputations := []PeerReputation{
+		NewPeerReputation(IPFromString("13.3.4.1"), 10, now.UnixMilli()),
+		NewPeerReputation(IPFromString("3.54.1.9"), -100, now.UnixMilli()),
+		NewPeerReputation(IPFromString("23.43.7.43"), 0, now.UnixMilli()),
+	}
+
+	check := func(expected []PeerReputation) {
+		var unmarshalled peersReputation
+		require.NoError(s.T(), unmarshalCborFromFile(s.storage.reputationFilePath, &unmarshalled))
+		assert.Equal(s.T(), len(expected), len(unmarshalled))
+		for _, r := range expected {
+			// check that data saved in file
+			stored, in := unmarshalled[r.IP]
+			require.True(s.T(), in)
+			assert.Equal(s.T(), r, stored)
+		}
+		// check that data saved in cache
+		assert.ElementsMatch(s.T(), expected, s.storage.Reputations())
+	}
+
+	s.Run("replace and get peers reputation", func() {
+		require.NoError(s.T(), s.storage.ReplaceReputation(reputations))
+		check(reputations)
+
+		updated := NewPeerReputation(reputations[0].IP, -20, now.Add(time.Minute).UnixMilli())
+		require.NoError(s.T(), s.storage.ReplaceReputation([]PeerReputation{updated, reputations[1]}))
+		check([]PeerReputation{updated, reputations[1]})
+
+		require.NoError(s.T(), s.storage.ReplaceReputation(nil))
+		check(nil)
+
+		// clean peers reputation storage to eliminate unexpected side effects
+		require.NoError(s.T(), s.storage.DropReputation())
+	})
+
+	s.Run("reputation is loaded from storage file", func() {
+		require.NoError(s.T(), s.storage.ReplaceReputation(reputations))
+
+		storage, err := newCBORStorageInDir(s.storage.storageDir, s.now, peersStorageCurrentVersion)
+		require.NoError(s.T(), err)
+		s.storage = storage
+		check(reputations)
+
+		require.NoError(s.T(), s.storage.DropStorage())
+		var unmarshalled peersReputation
+		require.Equal(s.T(), io.EOF, unmarshalCborFromFile(s.storage.reputationFilePath, &unmarshalled))
+		assert.Empty(s.T(), s.storage.Reputations())
+	})
+}
+
 func (s *binaryStorageCborSuite) TestCBORStorageDropsAndVersioning() {
 	suspendDuration := time.Minute * 5
 	now := s.now.Truncate(time.Millisecond)
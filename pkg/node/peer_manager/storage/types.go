@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"fmt"
 	"net"
 	"sort"
 	"time"
@@ -123,3 +124,52 @@ func (a knownPeers) OldestFirst(limit int) []KnownPeer {
 	}
 	return r
 }
+
+// ReputationEvent is an observed behaviour of a peer which changes its reputation.
+type ReputationEvent byte
+
+const (
+	BlockApplied        ReputationEvent = iota + 1 // Blocks received from the peer were applied
+	InvalidBlock                                   // Peer sent a block that failed validation
+	SlowResponse                                   // Peer responded to GetBlockIds request too slowly
+	DuplicateMicroBlock                            // Peer sent a microblock that was already received
+	Timeout                                        // Peer didn't respond during synchronization
+)
+
+func (e ReputationEvent) String() string {
+	switch e {
+	case BlockApplied:
+		return "BlockApplied"
+	case InvalidBlock:
+		return "InvalidBlock"
+	case SlowResponse:
+		return "SlowResponse"
+	case DuplicateMicroBlock:
+		return "DuplicateMicroBlock"
+	case Timeout:
+		return "Timeout"
+	default:
+		return fmt.Sprintf("ReputationEvent(%d)", byte(e))
+	}
+}
+
+// PeerReputation is an accumulated reputation score of the peer's IP and the time of its last update.
+type PeerReputation struct {
+	IP                    IP    `cbor:"0,keyasint,omitempty"`
+	Score                 int64 `cbor:"1,keyasint,omitempty"`
+	UpdateTimestampMillis int64 `cbor:"2,keyasint,omitempty"`
+}
+
+func NewPeerReputation(ip IP, score int64, updateTimestampMillis int64) PeerReputation {
+	return PeerReputation{
+		IP:                    ip,
+		Score:                 score,
+		UpdateTimestampMillis: updateTimestampMillis,
+	}
+}
+
+func (pr *PeerReputation) UpdateTime() time.Time {
+	return time.UnixMilli(pr.UpdateTimestampMillis)
+}
+
+type peersReputation map[IP]PeerReputation
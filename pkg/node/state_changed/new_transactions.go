@@ -0,0 +1,66 @@
+package state_changed
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+// transactionsQueueSize is the number of transactions waiting to be handled by a handler,
+// new transactions are dropped for the handler that can't keep up.
+const transactionsQueueSize = 1024
+
+// transactionWorker passes the queued transactions to the handler in order in its own goroutine.
+type transactionWorker struct {
+	handler types.TransactionHandler
+	queue   chan proto.Transaction
+}
+
+func newTransactionWorker(h types.TransactionHandler) *transactionWorker {
+	w := &transactionWorker{handler: h, queue: make(chan proto.Transaction, transactionsQueueSize)}
+	go w.run()
+	return w
+}
+
+func (w *transactionWorker) run() {
+	for tx := range w.queue {
+		w.handler.HandleTransaction(tx)
+	}
+}
+
+type NewTransactions struct {
+	mu      sync.Mutex
+	workers []*transactionWorker
+}
+
+func NewNewTransactions() *NewTransactions {
+	return &NewTransactions{}
+}
+
+func (a *NewTransactions) AddHandler(h types.TransactionHandler) {
+	a.mu.Lock()
+	a.workers = append(a.workers, newTransactionWorker(h))
+	a.mu.Unlock()
+}
+
+// HandleTransaction queues the transaction to all handlers without blocking.
+func (a *NewTransactions) HandleTransaction(tx proto.Transaction) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, w := range a.workers {
+		select {
+		case w.queue <- tx:
+		default:
+			zap.S().Debugf("Transactions handler %T is too slow, transaction dropped", w.handler)
+		}
+	}
+}
+
+func (a *NewTransactions) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.workers)
+}
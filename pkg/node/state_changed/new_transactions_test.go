@@ -0,0 +1,70 @@
+package state_changed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type txNotify struct {
+	received chan proto.Transaction
+}
+
+func (a *txNotify) HandleTransaction(tx proto.Transaction) {
+	a.received <- tx
+}
+
+func TestNewTransactions_HandleTransaction(t *testing.T) {
+	ch := make(chan proto.Transaction, 1)
+	s := NewNewTransactions()
+	require.Equal(t, 0, s.Len())
+	s.AddHandler(&txNotify{received: ch})
+	require.Equal(t, 1, s.Len())
+	tx := &proto.Genesis{}
+	s.HandleTransaction(tx)
+	require.Equal(t, tx, <-ch)
+}
+
+type blockingTxNotify struct {
+	release  chan struct{}
+	received chan proto.Transaction
+}
+
+func (a *blockingTxNotify) HandleTransaction(tx proto.Transaction) {
+	<-a.release
+	a.received <- tx
+}
+
+func TestNewTransactions_SlowHandler(t *testing.T) {
+	slow := &blockingTxNotify{release: make(chan struct{}), received: make(chan proto.Transaction, transactionsQueueSize+1)}
+	fast := make(chan proto.Transaction, transactionsQueueSize+2)
+	s := NewNewTransactions()
+	s.AddHandler(slow)
+	s.AddHandler(&txNotify{received: fast})
+	// The slow handler takes the first transaction and blocks, the following ones fill its queue
+	txs := make([]proto.Transaction, transactionsQueueSize+2)
+	for i := range txs {
+		txs[i] = &proto.Genesis{Timestamp: uint64(i)}
+		s.HandleTransaction(txs[i])
+		if i == 0 {
+			require.Equal(t, txs[0], <-fast)
+			require.Eventually(t, func() bool { return len(s.workers[0].queue) == 0 }, time.Second, time.Millisecond)
+		}
+	}
+	// The fast handler receives all transactions in order
+	for _, tx := range txs[1:] {
+		require.Equal(t, tx, <-fast)
+	}
+	// The last transaction is dropped for the slow handler
+	close(slow.release)
+	for _, tx := range txs[:transactionsQueueSize+1] {
+		require.Equal(t, tx, <-slow.received)
+	}
+	select {
+	case tx := <-slow.received:
+		require.Failf(t, "unexpected transaction", "%v", tx)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
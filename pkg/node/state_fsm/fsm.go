@@ -9,6 +9,7 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/miner/utxpool"
 	"github.com/wavesplatform/gowaves/pkg/node/messages"
 	"github.com/wavesplatform/gowaves/pkg/node/peer_manager"
+	"github.com/wavesplatform/gowaves/pkg/node/state_changed"
 	"github.com/wavesplatform/gowaves/pkg/node/state_fsm/ng"
 	. "github.com/wavesplatform/gowaves/pkg/node/state_fsm/tasks"
 	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
@@ -59,6 +60,9 @@ type BaseInfo struct {
 	minPeersMining int
 
 	skipMessageList *messages.SkipMessageList
+
+	stateChanged    *state_changed.StateChanged
+	newTransactions *state_changed.NewTransactions
 }
 
 func (a *BaseInfo) BroadcastTransaction(t proto.Transaction, receivedFrom peer.Peer) {
@@ -73,6 +77,20 @@ func (a *BaseInfo) CleanUtx() {
 	utxpool.NewCleaner(a.storage, a.utx, a.tm).Clean()
 }
 
+// NotifyStateChanged notifies subscribers that blocks or microblocks were applied to the state.
+func (a *BaseInfo) NotifyStateChanged() {
+	if a.stateChanged != nil {
+		a.stateChanged.Handle()
+	}
+}
+
+// NotifyNewTransaction notifies subscribers that the transaction was added to the UTX pool.
+func (a *BaseInfo) NotifyNewTransaction(t proto.Transaction) {
+	if a.newTransactions != nil {
+		a.newTransactions.HandleTransaction(t)
+	}
+}
+
 type FromBaseInfo interface {
 	FromBaseInfo(b BaseInfo) FSM
 }
@@ -132,6 +150,9 @@ func NewFsm(services services.Services, microblockInterval time.Duration) (FSM,
 		minPeersMining: services.MinPeersMining,
 
 		skipMessageList: services.SkipMessageList,
+
+		stateChanged:    services.StateChanged,
+		newTransactions: services.NewTransactions,
 	}
 
 	b.Scheduler.Reschedule()
@@ -60,6 +60,7 @@ func tryBroadcastTransaction(fsm FSM, baseInfo BaseInfo, p peer.Peer, t proto.Tr
 		err = errors.Wrap(err, "Failed to add transaction to utx")
 		return fsm, nil, err
 	}
+	baseInfo.NotifyNewTransaction(t)
 	baseInfo.BroadcastTransaction(t, p)
 	return fsm, nil, nil
 }
@@ -86,8 +87,10 @@ func syncWithNewPeer(fsm FSM, baseInfo BaseInfo, p peer.Peer) (FSM, Async, error
 	}
 	internal := sync_internal.InternalFromLastSignatures(extension.NewPeerExtension(p, baseInfo.scheme), lastSignatures)
 	c := conf{
-		peerSyncWith: p,
-		timeout:      30 * time.Second,
+		peerSyncWith:    p,
+		blockIDsAskedAt: baseInfo.tm.Now(),
+		timeout:         30 * time.Second,
+		downloader:      sync_internal.NewDownloader(baseInfo.tm, baseInfo.scheme, blockRequestTimeout, p),
 	}
 	zap.S().Debugf("[%s] Starting synchronization with peer '%s'", fsm.String(), p.ID())
 	return NewSyncFsm(baseInfo, c.Now(baseInfo.tm), internal)
@@ -2,8 +2,10 @@ package state_fsm
 
 import (
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/errs"
 	"github.com/wavesplatform/gowaves/pkg/metrics"
 	"github.com/wavesplatform/gowaves/pkg/miner"
+	"github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
 	. "github.com/wavesplatform/gowaves/pkg/node/state_fsm/tasks"
 	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
 	"github.com/wavesplatform/gowaves/pkg/proto"
@@ -121,15 +123,20 @@ func (a *NGFsm) Block(peer peer.Peer, block *proto.Block) (FSM, Async, error) {
 	_, err = a.baseInfo.blocksApplier.Apply(a.baseInfo.storage, []*proto.Block{block})
 	if err != nil {
 		metrics.FSMKeyBlockDeclined("ng", block, err)
+		if errs.IsValidationError(err) || errs.IsValidationError(errors.Cause(err)) {
+			a.baseInfo.peers.UpdateReputation(peer, storage.InvalidBlock)
+		}
 		return a, nil, a.Errorf(errors.Wrapf(err, "peer '%s'", peer.ID()))
 	}
 	metrics.FSMKeyBlockApplied("ng", block)
+	a.baseInfo.peers.UpdateReputation(peer, storage.BlockApplied)
 	zap.S().Debugf("[%s] Handle received key block message: block '%s' applied to state", a, block.BlockID())
 
 	a.blocksCache.Clear()
 	a.blocksCache.AddBlockState(block)
 
 	a.baseInfo.Scheduler.Reschedule()
+	a.baseInfo.NotifyStateChanged()
 	a.baseInfo.actions.SendScore(a.baseInfo.storage)
 	a.baseInfo.CleanUtx()
 
@@ -155,6 +162,7 @@ func (a *NGFsm) MinedBlock(block *proto.Block, limits proto.MiningLimits, keyPai
 	a.blocksCache.AddBlockState(block)
 
 	a.baseInfo.Reschedule()
+	a.baseInfo.NotifyStateChanged()
 	a.baseInfo.actions.SendBlock(block)
 	a.baseInfo.actions.SendScore(a.baseInfo.storage)
 	a.baseInfo.CleanUtx()
@@ -170,6 +178,10 @@ func (a *NGFsm) BlockIDs(_ peer.Peer, _ []proto.BlockID) (FSM, Async, error) {
 // MicroBlock handles new microblock message.
 func (a *NGFsm) MicroBlock(p peer.Peer, micro *proto.MicroBlock) (FSM, Async, error) {
 	metrics.FSMMicroBlockReceived("ng", micro, p.Handshake().NodeName)
+	if _, ok := a.baseInfo.MicroBlockCache.Get(micro.TotalBlockID); ok {
+		a.baseInfo.peers.UpdateReputation(p, storage.DuplicateMicroBlock)
+		return a, nil, a.Errorf(proto.NewInfoMsg(errors.Errorf("microblock '%s' was already received", micro.TotalBlockID.String())))
+	}
 	block, err := a.checkAndAppendMicroblock(micro) // the TopBlock() is used here
 	if err != nil {
 		metrics.FSMMicroBlockDeclined("ng", micro, err)
@@ -181,6 +193,7 @@ func (a *NGFsm) MicroBlock(p peer.Peer, micro *proto.MicroBlock) (FSM, Async, er
 	a.baseInfo.MicroBlockCache.Add(block.BlockID(), micro)
 	a.blocksCache.AddBlockState(block)
 	a.baseInfo.Reschedule()
+	a.baseInfo.NotifyStateChanged()
 
 	// Notify all connected peers about new microblock, send them microblock inv network message
 	if inv, ok := a.baseInfo.MicroBlockInvCache.Get(block.BlockID()); ok {
@@ -217,6 +230,7 @@ func (a *NGFsm) mineMicro(minedBlock *proto.Block, rest proto.MiningLimits, keyP
 	)
 	a.blocksCache.AddBlockState(block)
 	a.baseInfo.Reschedule()
+	a.baseInfo.NotifyStateChanged()
 	metrics.FSMMicroBlockApplied("ng", micro)
 	inv := proto.NewUnsignedMicroblockInv(
 		micro.SenderPK,
@@ -1,11 +1,13 @@
 package state_fsm
 
 import (
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/errs"
 	"github.com/wavesplatform/gowaves/pkg/metrics"
+	"github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
 	"github.com/wavesplatform/gowaves/pkg/node/state_fsm/sync_internal"
 	"github.com/wavesplatform/gowaves/pkg/node/state_fsm/tasks"
 	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
@@ -16,19 +18,34 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// Response to GetBlockIds request that takes longer than slowResponseDuration worsens the reputation of the peer.
+	slowResponseDuration = 10 * time.Second
+	// Block that is not received during blockRequestTimeout is requested from another peer.
+	blockRequestTimeout = 10 * time.Second
+	// Maximal number of peers to download blocks from simultaneously, including the peer synchronization goes with.
+	maxDownloadPeers = 5
+)
+
 type conf struct {
 	peerSyncWith peer.Peer
 	// if nothing happens more than N duration, means we stalled, so go to idle and again
 	lastReceiveTime time.Time
+	// the time block IDs were asked from peerSyncWith, the response time of the peer is measured from it
+	blockIDsAskedAt time.Time
 
 	timeout time.Duration
+	// blocks are downloaded from several peers with the same score
+	downloader *sync_internal.Downloader
 }
 
 func (c conf) Now(tm types.Time) conf {
 	return conf{
 		peerSyncWith:    c.peerSyncWith,
 		lastReceiveTime: tm.Now(),
+		blockIDsAskedAt: c.blockIDsAskedAt,
 		timeout:         c.timeout,
+		downloader:      c.downloader,
 	}
 }
 
@@ -84,8 +101,14 @@ func (a *SyncFsm) Task(task tasks.AsyncTask) (FSM, Async, error) {
 		timeout := a.conf.lastReceiveTime.Add(a.conf.timeout).Before(a.baseInfo.tm.Now())
 		if timeout {
 			zap.S().Debugf("[Sync] Timeout (%s) while syncronisation with peer '%s'", a.conf.timeout.String(), a.conf.peerSyncWith.ID())
+			a.baseInfo.peers.UpdateReputation(a.conf.peerSyncWith, storage.Timeout)
 			return NewIdleFsm(a.baseInfo), nil, a.Errorf(TimeoutErr)
 		}
+		for _, p := range a.conf.downloader.RetryExpired() {
+			zap.S().Debugf("[Sync][%s] Block requests timed out, asking other peers", p.ID())
+			a.baseInfo.peers.UpdateReputation(p, storage.SlowResponse)
+		}
+		a.replaceStalledPeerSyncWith()
 		return a, nil, nil
 	case tasks.MineMicro:
 		return a, nil, nil
@@ -96,15 +119,21 @@ func (a *SyncFsm) Task(task tasks.AsyncTask) (FSM, Async, error) {
 
 func (a *SyncFsm) PeerError(p peer.Peer, _ error) (FSM, Async, error) {
 	a.baseInfo.peers.Disconnect(p)
-	if a.conf.peerSyncWith == p {
-		_, blocks, _, _ := a.internal.Blocks(noopWrapper{}, nil)
-		if len(blocks) > 0 {
-			err := a.baseInfo.storage.Map(func(s state.NonThreadSafeState) error {
-				_, err := a.baseInfo.blocksApplier.Apply(s, blocks)
-				return err
-			})
-			return NewIdleFsm(a.baseInfo), nil, a.Errorf(err)
+	if a.conf.peerSyncWith != p {
+		// Blocks requested from the peer are requested from the others
+		a.conf.downloader.RemovePeer(p)
+		return a, nil, nil
+	}
+	_, blocks, _, _ := a.internal.Blocks(noopWrapper{}, nil)
+	if len(blocks) > 0 {
+		err := a.baseInfo.storage.Map(func(s state.NonThreadSafeState) error {
+			_, err := a.baseInfo.blocksApplier.Apply(s, blocks)
+			return err
+		})
+		if err == nil {
+			a.baseInfo.NotifyStateChanged()
 		}
+		return NewIdleFsm(a.baseInfo), nil, a.Errorf(err)
 	}
 	return a, nil, nil
 }
@@ -113,7 +142,12 @@ func (a *SyncFsm) BlockIDs(peer peer.Peer, signatures []proto.BlockID) (FSM, Asy
 	if a.conf.peerSyncWith != peer {
 		return a, nil, nil
 	}
-	internal, err := a.internal.BlockIDs(extension.NewPeerExtension(peer, a.baseInfo.scheme), signatures)
+	if elapsed := a.baseInfo.tm.Now().Sub(a.conf.blockIDsAskedAt); elapsed > slowResponseDuration {
+		zap.S().Debugf("[Sync][%s] Slow response to block IDs request: %s", peer.ID(), elapsed.String())
+		a.baseInfo.peers.UpdateReputation(peer, storage.SlowResponse)
+	}
+	a.conf.downloader.SetPeers(a.downloadPeers())
+	internal, err := a.internal.BlockIDs(a.conf.downloader, signatures)
 	if err != nil {
 		return newSyncFsm(a.baseInfo, a.conf, internal), nil, a.Errorf(err)
 	}
@@ -142,7 +176,9 @@ func (a *SyncFsm) Score(p peer.Peer, score *proto.Score) (FSM, Async, error) {
 }
 
 func (a *SyncFsm) Block(p peer.Peer, block *proto.Block) (FSM, Async, error) {
-	if p != a.conf.peerSyncWith {
+	// Requested blocks are accepted from any peer, a block that comes again after a retry is ignored
+	requested := a.conf.downloader.Received(block.BlockID(), p)
+	if !requested && p != a.conf.peerSyncWith {
 		return a, nil, nil
 	}
 	metrics.FSMKeyBlockReceived("sync", block, p.Handshake().NodeName)
@@ -163,6 +199,7 @@ func (a *SyncFsm) MinedBlock(block *proto.Block, limits proto.MiningLimits, keyP
 	}
 	metrics.FSMKeyBlockApplied("sync", block)
 	a.baseInfo.Reschedule()
+	a.baseInfo.NotifyStateChanged()
 
 	// first we should send block
 	a.baseInfo.actions.SendBlock(block)
@@ -196,6 +233,71 @@ func (a *SyncFsm) getPeerWithMaxScore() (peer.Peer, error) {
 	return maxScorePeer, nil
 }
 
+// downloadPeers returns the peer synchronization goes with and the connected peers with the same score,
+// peers with better reputation go first.
+func (a *SyncFsm) downloadPeers() []peer.Peer {
+	syncWith := a.conf.peerSyncWith
+	syncWithScore, err := a.baseInfo.peers.Score(syncWith)
+	if err != nil {
+		return []peer.Peer{syncWith}
+	}
+	var others []peer.Peer
+	a.baseInfo.peers.EachConnected(func(p peer.Peer, score *proto.Score) {
+		if p != syncWith && score.Cmp(syncWithScore) == 0 {
+			others = append(others, p)
+		}
+	})
+	reputations := make([]int64, len(others))
+	for i, p := range others {
+		reputations[i] = a.baseInfo.peers.Reputation(p)
+	}
+	sort.Stable(byReputation{peers: others, reputations: reputations})
+	if len(others) > maxDownloadPeers-1 {
+		others = others[:maxDownloadPeers-1]
+	}
+	return append([]peer.Peer{syncWith}, others...)
+}
+
+type byReputation struct {
+	peers       []peer.Peer
+	reputations []int64
+}
+
+func (a byReputation) Len() int { return len(a.peers) }
+
+func (a byReputation) Less(i, j int) bool { return a.reputations[i] > a.reputations[j] }
+
+func (a byReputation) Swap(i, j int) {
+	a.peers[i], a.peers[j] = a.peers[j], a.peers[i]
+	a.reputations[i], a.reputations[j] = a.reputations[j], a.reputations[i]
+}
+
+// replaceStalledPeerSyncWith continues synchronization with one of the download peers if the peer synchronization
+// goes with was excluded from downloading for being slow. Otherwise, the next block IDs would be asked from
+// the stalled peer and the sync would be stuck with it while other peers deliver the blocks.
+func (a *SyncFsm) replaceStalledPeerSyncWith() {
+	peers := a.conf.downloader.Peers()
+	if containsPeer(peers, a.conf.peerSyncWith) {
+		return
+	}
+	stalled := a.conf.peerSyncWith
+	a.conf.peerSyncWith = peers[0]
+	zap.S().Debugf("[Sync][%s] Peer stalled, continue synchronization with peer '%s'", stalled.ID(), a.conf.peerSyncWith.ID())
+	if a.internal.WaitingForSignatures() {
+		a.internal.AskBlockIDs(extension.NewPeerExtension(a.conf.peerSyncWith, a.baseInfo.scheme))
+		a.conf.blockIDsAskedAt = a.baseInfo.tm.Now()
+	}
+}
+
+func containsPeer(peers []peer.Peer, p peer.Peer) bool {
+	for _, e := range peers {
+		if e == p {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *SyncFsm) changePeerSyncWith() (FSM, Async, error) {
 	peer, err := a.getPeerWithMaxScore()
 	if err != nil {
@@ -226,6 +328,7 @@ func (a *SyncFsm) applyBlocks(baseInfo BaseInfo, conf conf, internal sync_intern
 	})
 	if err != nil {
 		if errs.IsValidationError(err) || errs.IsValidationError(errors.Cause(err)) {
+			a.baseInfo.peers.UpdateReputation(conf.peerSyncWith, storage.InvalidBlock)
 			a.baseInfo.peers.Suspend(conf.peerSyncWith, time.Now(), err.Error())
 		}
 		for _, b := range blocks {
@@ -233,10 +336,29 @@ func (a *SyncFsm) applyBlocks(baseInfo BaseInfo, conf conf, internal sync_intern
 		}
 		return NewIdleFsm(a.baseInfo), nil, a.Errorf(err)
 	}
+	// Every peer that delivered blocks of the batch is credited once per batch
+	var delivered []peer.Peer
 	for _, b := range blocks {
 		metrics.FSMKeyBlockApplied("sync", b)
+		// Blocks that were not requested from the downloader are accepted from the peer synchronization goes with
+		p := conf.downloader.Delivered(b.BlockID())
+		if p == nil {
+			p = conf.peerSyncWith
+		}
+		if !containsPeer(delivered, p) {
+			delivered = append(delivered, p)
+		}
+	}
+	for _, p := range delivered {
+		a.baseInfo.peers.UpdateReputation(p, storage.BlockApplied)
+	}
+	if internal.WaitingForSignatures() {
+		// Block IDs were asked before applying the blocks, but the response can't be handled until the blocks
+		// are applied, so the time of applying doesn't count in the response time of the peer
+		conf.blockIDsAskedAt = a.baseInfo.tm.Now()
 	}
 	a.baseInfo.Reschedule()
+	a.baseInfo.NotifyStateChanged()
 	a.baseInfo.actions.SendScore(a.baseInfo.storage)
 	should, err := a.baseInfo.storage.ShouldPersistAddressTransactions()
 	if err != nil {
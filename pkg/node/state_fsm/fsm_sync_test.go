@@ -1,19 +1,27 @@
 package state_fsm
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/libs/ordered_blocks"
 	"github.com/wavesplatform/gowaves/pkg/libs/signatures"
 	"github.com/wavesplatform/gowaves/pkg/mock"
 	"github.com/wavesplatform/gowaves/pkg/node/messages"
+	"github.com/wavesplatform/gowaves/pkg/node/peer_manager/storage"
 	"github.com/wavesplatform/gowaves/pkg/node/state_fsm/sync_internal"
 	"github.com/wavesplatform/gowaves/pkg/node/state_fsm/tasks"
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
 	"github.com/wavesplatform/gowaves/pkg/p2p/peer/extension"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
 )
 
 //go:generate moq -pkg state_fsm -out time_moq.go ../../types Time:MockTime
@@ -60,9 +68,11 @@ func TestSyncFsm_SignaturesTimeout(t *testing.T) {
 	defer ctrl.Finish()
 	p := mock.NewMockPeer(ctrl)
 	p.EXPECT().ID()
+	peers := mock.NewMockPeerManager(ctrl)
+	peers.EXPECT().UpdateReputation(p, storage.Timeout)
 
 	conf := conf{peerSyncWith: p}
-	fsm, async, err := NewSyncFsm(BaseInfo{tm: ntptime.Stub{}, skipMessageList: &messages.SkipMessageList{}}, conf, sync_internal.Internal{})
+	fsm, async, err := NewSyncFsm(BaseInfo{peers: peers, tm: ntptime.Stub{}, skipMessageList: &messages.SkipMessageList{}}, conf, sync_internal.Internal{})
 	require.NoError(t, err)
 	require.Len(t, async, 0)
 	require.NotNil(t, fsm)
@@ -73,3 +83,196 @@ func TestSyncFsm_SignaturesTimeout(t *testing.T) {
 
 	require.IsType(t, &IdleFsm{}, fsm)
 }
+
+func TestSyncFsm_DownloadPeers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	syncWith := mock.NewMockPeer(ctrl)
+	p1 := mock.NewMockPeer(ctrl)
+	p2 := mock.NewMockPeer(ctrl)
+	p3 := mock.NewMockPeer(ctrl)
+	peers := mock.NewMockPeerManager(ctrl)
+	peers.EXPECT().Score(syncWith).Return(big.NewInt(10), nil)
+	peers.EXPECT().EachConnected(gomock.Any()).Do(func(f func(peer.Peer, *proto.Score)) {
+		f(syncWith, big.NewInt(10))
+		f(p1, big.NewInt(10))
+		f(p2, big.NewInt(5))
+		f(p3, big.NewInt(10))
+	})
+	peers.EXPECT().Reputation(p1).Return(int64(-5))
+	peers.EXPECT().Reputation(p3).Return(int64(7))
+
+	fsm := &SyncFsm{baseInfo: BaseInfo{peers: peers}, conf: conf{peerSyncWith: syncWith}}
+	assert.Equal(t, []peer.Peer{syncWith, p3, p1}, fsm.downloadPeers())
+}
+
+func TestSyncFsm_BlockFromDownloadPeer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newBlock := func(sig string) *proto.Block {
+		s := crypto.MustSignatureFromBase58(sig)
+		return &proto.Block{BlockHeader: proto.BlockHeader{BlockSignature: s, ID: proto.NewBlockIDFromSignature(s)}}
+	}
+	requested := newBlock("5syuWANDSgk8KyPxq2yQs2CYV23QfnrBoZMSv2LaciycxDYfBw6cLA2SqVnonnh1nFiFumzTgy2cPETnE7ZaZg5P")
+	pending := newBlock("3kvbjSovZWLg1zdMyW5vGsCj1DR1jkHY3ALtu5VxoqscrXQq3nH2vS2V5dhVo6ff9bxtbFAkUkVQQqCFUAHmwnpX")
+	unexpected := newBlock("31jt6L3pDU2mkow3kDK7kUZjQbqJsMnE5gC6As7Cz27xjqAaZpiNqopf6NJWbtwrV9VcjShKFfhgLmjpr8Ybuv41")
+
+	syncWith := mock.NewMockPeer(ctrl)
+	syncWith.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: pending.BlockID()})
+	p := mock.NewMockPeer(ctrl)
+	p.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: requested.BlockID()})
+	p.EXPECT().Handshake().Return(proto.Handshake{})
+	p.EXPECT().ID()
+
+	tm := ntptime.Stub{}
+	downloader := sync_internal.NewDownloader(tm, proto.MainNetScheme, time.Minute, p, syncWith)
+	internal, err := sync_internal.NewInternal(ordered_blocks.NewOrderedBlocks(), signatures.NewSignatures(), true).
+		BlockIDs(downloader, []proto.BlockID{requested.BlockID(), pending.BlockID()})
+	require.NoError(t, err)
+	baseInfo := BaseInfo{tm: tm, skipMessageList: &messages.SkipMessageList{}}
+	fsm := newSyncFsm(baseInfo, conf{peerSyncWith: syncWith, downloader: downloader}, internal)
+
+	// Block that wasn't requested is ignored if it's not from the peer synchronization goes with
+	_, _, err = fsm.Block(p, unexpected)
+	require.NoError(t, err)
+	assert.Equal(t, 0, internal.AvailableCount())
+
+	// Requested block is accepted from the download peer, synchronization waits for the rest of blocks
+	_, _, err = fsm.Block(p, requested)
+	require.NoError(t, err)
+	assert.Equal(t, 1, internal.AvailableCount())
+	assert.Equal(t, 1, downloader.PendingCount())
+}
+
+func TestSyncFsm_SlowBlockIDsResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	p := mock.NewMockPeer(ctrl)
+	p.EXPECT().ID().AnyTimes()
+	peers := mock.NewMockPeerManager(ctrl)
+	peers.EXPECT().Score(p).Return(nil, errors.New("no score")).AnyTimes()
+	st := mock.NewMockState(ctrl)
+	st.EXPECT().StartProvidingExtendedApi().Return(nil).AnyTimes()
+	baseInfo := BaseInfo{
+		peers:           peers,
+		storage:         st,
+		tm:              &MockTime{NowFunc: func() time.Time { return now }},
+		skipMessageList: &messages.SkipMessageList{},
+	}
+	newFsm := func(askedAt time.Time) FSM {
+		internal := sync_internal.NewInternal(ordered_blocks.NewOrderedBlocks(), signatures.NewSignatures(), true)
+		c := conf{
+			peerSyncWith: p,
+			// Blocks were received long ago, but applying them doesn't count in the response time
+			lastReceiveTime: now.Add(-time.Minute),
+			blockIDsAskedAt: askedAt,
+			downloader:      sync_internal.NewDownloader(baseInfo.tm, proto.MainNetScheme, time.Minute, p),
+		}
+		return newSyncFsm(baseInfo, c, internal)
+	}
+
+	_, _, err := newFsm(now.Add(-time.Second)).BlockIDs(p, nil)
+	require.NoError(t, err)
+
+	peers.EXPECT().UpdateReputation(p, storage.SlowResponse)
+	_, _, err = newFsm(now.Add(-slowResponseDuration-time.Second)).BlockIDs(p, nil)
+	require.NoError(t, err)
+}
+
+type testBlocksApplier struct {
+	applied []*proto.Block
+}
+
+func (a *testBlocksApplier) BlockExists(_ state.State, _ *proto.Block) (bool, error) {
+	return false, nil
+}
+
+func (a *testBlocksApplier) Apply(_ state.State, blocks []*proto.Block) (proto.Height, error) {
+	a.applied = append(a.applied, blocks...)
+	return proto.Height(len(a.applied)), nil
+}
+
+func (a *testBlocksApplier) ApplyMicro(_ state.State, _ *proto.Block) (proto.Height, error) {
+	return 0, nil
+}
+
+type noopActions struct {
+}
+
+func (noopActions) SendScore(currentScorer) {
+}
+
+func (noopActions) SendBlock(*proto.Block) {
+}
+
+func TestSyncFsm_StalledPeerSyncWith(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newBlock := func(sig string) *proto.Block {
+		s := crypto.MustSignatureFromBase58(sig)
+		return &proto.Block{BlockHeader: proto.BlockHeader{BlockSignature: s, ID: proto.NewBlockIDFromSignature(s)}}
+	}
+	b1 := newBlock("5syuWANDSgk8KyPxq2yQs2CYV23QfnrBoZMSv2LaciycxDYfBw6cLA2SqVnonnh1nFiFumzTgy2cPETnE7ZaZg5P")
+	b2 := newBlock("3kvbjSovZWLg1zdMyW5vGsCj1DR1jkHY3ALtu5VxoqscrXQq3nH2vS2V5dhVo6ff9bxtbFAkUkVQQqCFUAHmwnpX")
+
+	syncWith := mock.NewMockPeer(ctrl)
+	syncWith.EXPECT().ID().AnyTimes()
+	syncWith.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: b1.BlockID()})
+	helper := mock.NewMockPeer(ctrl)
+	helper.EXPECT().ID().AnyTimes()
+	helper.EXPECT().Handshake().Return(proto.Handshake{}).AnyTimes()
+	// The block requested from the stalled peer is requested from the helper
+	gomock.InOrder(
+		helper.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: b2.BlockID()}),
+		helper.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: b1.BlockID()}),
+	)
+	peers := mock.NewMockPeerManager(ctrl)
+	st := mock.NewMockState(ctrl)
+	st.EXPECT().Map(gomock.Any()).DoAndReturn(func(f func(state.NonThreadSafeState) error) error {
+		return f(nil)
+	})
+	st.EXPECT().ShouldPersistAddressTransactions().Return(false, nil)
+	st.EXPECT().StartProvidingExtendedApi().Return(nil)
+
+	now := time.Now()
+	applier := &testBlocksApplier{}
+	baseInfo := BaseInfo{
+		peers:           peers,
+		storage:         st,
+		tm:              &MockTime{NowFunc: func() time.Time { return now }},
+		blocksApplier:   applier,
+		Scheduler:       noopReschedule{},
+		actions:         noopActions{},
+		skipMessageList: &messages.SkipMessageList{},
+	}
+	downloader := sync_internal.NewDownloader(baseInfo.tm, proto.MainNetScheme, blockRequestTimeout, syncWith, helper)
+	internal, err := sync_internal.NewInternal(ordered_blocks.NewOrderedBlocks(), signatures.NewSignatures(), true).
+		BlockIDs(downloader, []proto.BlockID{b1.BlockID(), b2.BlockID()})
+	require.NoError(t, err)
+	c := conf{peerSyncWith: syncWith, timeout: 30 * time.Second, downloader: downloader}
+	var fsm FSM = newSyncFsm(baseInfo, c.Now(baseInfo.tm), internal)
+
+	fsm, _, err = fsm.Block(helper, b2)
+	require.NoError(t, err)
+
+	// The peer synchronization goes with doesn't answer, synchronization continues with the helper
+	now = now.Add(blockRequestTimeout + time.Second)
+	peers.EXPECT().UpdateReputation(syncWith, storage.SlowResponse)
+	fsm, _, err = fsm.Task(tasks.AsyncTask{TaskType: tasks.Ping})
+	require.NoError(t, err)
+	require.IsType(t, &SyncFsm{}, fsm)
+	assert.Equal(t, helper, fsm.(*SyncFsm).conf.peerSyncWith)
+	assert.Equal(t, []peer.Peer{helper}, downloader.Peers())
+
+	// Blocks are applied and credited to the peer that delivered them
+	peers.EXPECT().UpdateReputation(helper, storage.BlockApplied)
+	fsm, _, err = fsm.Block(helper, b1)
+	require.NoError(t, err)
+	assert.IsType(t, &NGFsm{}, fsm)
+	assert.Equal(t, []*proto.Block{b1, b2}, applier.applied)
+}
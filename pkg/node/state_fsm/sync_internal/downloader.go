@@ -0,0 +1,146 @@
+package sync_internal
+
+import (
+	"time"
+
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer/extension"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+type blockRequest struct {
+	peer    peer.Peer
+	askedAt time.Time
+}
+
+// Downloader distributes requests of blocks among several peers in turn and keeps track of the pending requests.
+// Requests that are not answered in time are sent again to other peers.
+type Downloader struct {
+	tm      types.Time
+	scheme  proto.Scheme
+	timeout time.Duration
+	peers   []peer.Peer
+	next    int
+	pending map[proto.BlockID]blockRequest
+	// peers that delivered the received blocks, kept until the blocks are applied
+	delivered map[proto.BlockID]peer.Peer
+}
+
+func NewDownloader(tm types.Time, scheme proto.Scheme, timeout time.Duration, peers ...peer.Peer) *Downloader {
+	return &Downloader{
+		tm:        tm,
+		scheme:    scheme,
+		timeout:   timeout,
+		peers:     peers,
+		pending:   make(map[proto.BlockID]blockRequest),
+		delivered: make(map[proto.BlockID]peer.Peer),
+	}
+}
+
+// SetPeers replaces the peers to download blocks from, pending requests are kept untouched.
+func (d *Downloader) SetPeers(peers []peer.Peer) {
+	if len(peers) == 0 {
+		return
+	}
+	d.peers = peers
+	d.next = 0
+}
+
+func (d *Downloader) Peers() []peer.Peer {
+	return d.peers
+}
+
+// AskBlock requests the block from the next peer in turn.
+func (d *Downloader) AskBlock(id proto.BlockID) {
+	p := d.peers[d.next%len(d.peers)]
+	d.next++
+	extension.NewPeerExtension(p, d.scheme).AskBlock(id)
+	d.pending[id] = blockRequest{peer: p, askedAt: d.tm.Now()}
+}
+
+// Received removes the pending request of the block and remembers the peer that delivered it,
+// false is returned if the block was not requested.
+func (d *Downloader) Received(id proto.BlockID, p peer.Peer) bool {
+	if _, ok := d.pending[id]; !ok {
+		return false
+	}
+	delete(d.pending, id)
+	d.delivered[id] = p
+	return true
+}
+
+// Delivered returns the peer that delivered the block and forgets it, nil is returned for unknown blocks.
+func (d *Downloader) Delivered(id proto.BlockID) peer.Peer {
+	p, ok := d.delivered[id]
+	if !ok {
+		return nil
+	}
+	delete(d.delivered, id)
+	return p
+}
+
+func (d *Downloader) PendingCount() int {
+	return len(d.pending)
+}
+
+// RetryExpired sends again the requests that were not answered in time and returns the peers that failed to answer.
+// Such peers are excluded from downloading unless there are no other peers left.
+func (d *Downloader) RetryExpired() []peer.Peer {
+	now := d.tm.Now()
+	var (
+		expired []proto.BlockID
+		slow    []peer.Peer
+	)
+	for id, r := range d.pending {
+		if now.Sub(r.askedAt) <= d.timeout {
+			continue
+		}
+		expired = append(expired, id)
+		if !containsPeer(slow, r.peer) {
+			slow = append(slow, r.peer)
+		}
+	}
+	for _, p := range slow {
+		d.excludePeer(p)
+	}
+	for _, id := range expired {
+		d.AskBlock(id)
+	}
+	return slow
+}
+
+// RemovePeer excludes the peer from downloading and sends its pending requests to other peers.
+func (d *Downloader) RemovePeer(p peer.Peer) {
+	if !containsPeer(d.peers, p) {
+		return
+	}
+	d.excludePeer(p)
+	for id, r := range d.pending {
+		if r.peer == p {
+			d.AskBlock(id)
+		}
+	}
+}
+
+func (d *Downloader) excludePeer(p peer.Peer) {
+	if len(d.peers) < 2 {
+		return
+	}
+	peers := make([]peer.Peer, 0, len(d.peers))
+	for _, dp := range d.peers {
+		if dp != p {
+			peers = append(peers, dp)
+		}
+	}
+	d.peers = peers
+}
+
+func containsPeer(peers []peer.Peer, p peer.Peer) bool {
+	for _, e := range peers {
+		if e == p {
+			return true
+		}
+	}
+	return false
+}
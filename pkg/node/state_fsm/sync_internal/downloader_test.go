@@ -0,0 +1,106 @@
+package sync_internal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	. "github.com/wavesplatform/gowaves/pkg/node/state_fsm/sync_internal"
+	"github.com/wavesplatform/gowaves/pkg/p2p/peer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type testTime struct {
+	now time.Time
+}
+
+func (t *testTime) Now() time.Time {
+	return t.now
+}
+
+var sig3 = crypto.MustSignatureFromBase58("31jt6L3pDU2mkow3kDK7kUZjQbqJsMnE5gC6As7Cz27xjqAaZpiNqopf6NJWbtwrV9VcjShKFfhgLmjpr8Ybuv41")
+
+func expectBlockRequests(p *mock.MockPeer, ids ...proto.BlockID) {
+	for _, id := range ids {
+		p.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: id})
+	}
+}
+
+func TestDownloader_AskBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ids := blocksFromSigs(sig1, sig2, sig3)
+	p1 := mock.NewMockPeer(ctrl)
+	p2 := mock.NewMockPeer(ctrl)
+	expectBlockRequests(p1, ids[0], ids[2])
+	expectBlockRequests(p2, ids[1])
+
+	d := NewDownloader(&testTime{now: time.Now()}, proto.MainNetScheme, time.Second, p1)
+	d.SetPeers([]peer.Peer{p1, p2})
+	for _, id := range ids {
+		d.AskBlock(id)
+	}
+	require.Equal(t, 3, d.PendingCount())
+
+	assert.True(t, d.Received(ids[1], p1))
+	assert.False(t, d.Received(ids[1], p2))
+	assert.Equal(t, 2, d.PendingCount())
+
+	// The block is credited to the peer that delivered it first, not to the one it was asked from
+	assert.Equal(t, p1, d.Delivered(ids[1]))
+	assert.Nil(t, d.Delivered(ids[1]))
+	assert.Nil(t, d.Delivered(ids[0]))
+}
+
+func TestDownloader_RetryExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ids := blocksFromSigs(sig1, sig2)
+	p1 := mock.NewMockPeer(ctrl)
+	p2 := mock.NewMockPeer(ctrl)
+	// Block requested from the second peer is requested again from the first one
+	expectBlockRequests(p1, ids[0], ids[1])
+	expectBlockRequests(p2, ids[1])
+
+	tm := &testTime{now: time.Now()}
+	d := NewDownloader(tm, proto.MainNetScheme, time.Second, p1, p2)
+	d.AskBlock(ids[0])
+	d.AskBlock(ids[1])
+	assert.Empty(t, d.RetryExpired())
+
+	tm.now = tm.now.Add(2 * time.Second)
+	require.True(t, d.Received(ids[0], p1))
+	assert.Equal(t, []peer.Peer{p2}, d.RetryExpired())
+	assert.Equal(t, []peer.Peer{p1}, d.Peers())
+	assert.Equal(t, 1, d.PendingCount())
+
+	// The last peer is never excluded
+	tm.now = tm.now.Add(2 * time.Second)
+	p1.EXPECT().SendMessage(&proto.GetBlockMessage{BlockID: ids[1]})
+	assert.Equal(t, []peer.Peer{p1}, d.RetryExpired())
+	assert.Equal(t, []peer.Peer{p1}, d.Peers())
+}
+
+func TestDownloader_RemovePeer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ids := blocksFromSigs(sig1, sig2)
+	p1 := mock.NewMockPeer(ctrl)
+	p2 := mock.NewMockPeer(ctrl)
+	expectBlockRequests(p1, ids[0], ids[1])
+	expectBlockRequests(p2, ids[1])
+
+	d := NewDownloader(&testTime{now: time.Now()}, proto.MainNetScheme, time.Second, p1, p2)
+	d.AskBlock(ids[0])
+	d.AskBlock(ids[1])
+	d.RemovePeer(p2)
+	assert.Equal(t, []peer.Peer{p1}, d.Peers())
+	assert.Equal(t, 2, d.PendingCount())
+}
@@ -41,7 +41,11 @@ func NewInternal(orderedBlocks *ordered_blocks.OrderedBlocks, respondedSignature
 	}
 }
 
-func (a Internal) BlockIDs(p PeerExtension, ids []proto.BlockID) (Internal, error) {
+type blockAsker interface {
+	AskBlock(id proto.BlockID)
+}
+
+func (a Internal) BlockIDs(p blockAsker, ids []proto.BlockID) (Internal, error) {
 	if !a.waitingForSignatures {
 		return a, NoSignaturesExpectedErr
 	}
@@ -93,6 +97,13 @@ func (a Internal) Blocks(p peerExtension, needToChangePeerSyncWithFunc func() bo
 	return NewInternal(a.orderedBlocks, a.respondedSignatures, true), a.orderedBlocks.PopAll(), false, false
 }
 
+// AskBlockIDs repeats the pending request of block IDs, it's used when the request is moved to another peer.
+func (a Internal) AskBlockIDs(p peerExtension) {
+	if a.waitingForSignatures {
+		p.AskBlocksIDs(a.respondedSignatures.BlockIDS())
+	}
+}
+
 func (a Internal) AvailableCount() int {
 	return a.orderedBlocks.ReceivedCount()
 }
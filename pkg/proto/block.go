@@ -734,6 +734,38 @@ func (b *Block) transactionsRoot(scheme Scheme) ([]byte, error) {
 	return tree.Root().Bytes(), nil
 }
 
+// TransactionMerkleProof returns the index of the transaction with the given ID in the block and the merkle proof
+// of its inclusion into the transactions root of the block.
+func (b *Block) TransactionMerkleProof(scheme Scheme, id []byte) (int, []crypto.Digest, error) {
+	if b.Version < ProtobufBlockVersion {
+		return 0, nil, errors.Errorf("no transactions root prior block version %d, current version %d", ProtobufBlockVersion, b.Version)
+	}
+	index := -1
+	data := make([][]byte, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		txID, err := tx.GetID(scheme)
+		if err != nil {
+			return 0, nil, err
+		}
+		if bytes.Equal(txID, id) {
+			index = i
+		}
+		mb, err := tx.MerkleBytes(scheme)
+		if err != nil {
+			return 0, nil, err
+		}
+		data[i] = mb
+	}
+	if index < 0 {
+		return 0, nil, errors.Errorf("no transaction '%s' in block '%s'", base58.Encode(id), b.BlockID().String())
+	}
+	proofs, err := crypto.MerkleProofs(data, uint64(index))
+	if err != nil {
+		return 0, nil, err
+	}
+	return index, proofs, nil
+}
+
 func CreateBlock(transactions Transactions, timestamp Timestamp, parentID BlockID, publicKey crypto.PublicKey, nxtConsensus NxtConsensus, version BlockVersion, features []int16, rewardVote int64, scheme Scheme) (*Block, error) {
 	consensusLength := nxtConsensus.BinarySize()
 	b := &Block{
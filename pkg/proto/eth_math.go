@@ -63,6 +63,14 @@ func (i *hexOrDecimal256) UnmarshalText(input []byte) error {
 	return nil
 }
 
+// UnmarshalJSON implements json.Unmarshaler. It accepts JSON numbers as well as quoted hex or decimal strings.
+func (i *hexOrDecimal256) UnmarshalJSON(input []byte) error {
+	if len(input) > 1 && input[0] == '"' {
+		input = input[1 : len(input)-1]
+	}
+	return i.UnmarshalText(input)
+}
+
 // MarshalText implements encoding.TextMarshaler.
 func (i *hexOrDecimal256) MarshalText() ([]byte, error) {
 	if i == nil {
@@ -2,6 +2,7 @@ package proto
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"regexp"
@@ -14,25 +15,26 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 )
 
-type ethereumTypedDataType struct {
+// EthereumTypedDataType is a member of the EIP-712 struct type.
+type EthereumTypedDataType struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
 }
 
-func (t *ethereumTypedDataType) isArray() bool {
+func (t *EthereumTypedDataType) isArray() bool {
 	return strings.HasSuffix(t.Type, "[]")
 }
 
 // typeName returns the canonical name of the type. If the type is 'Person[]', then
 // this method returns 'Person'
-func (t *ethereumTypedDataType) typeName() string {
+func (t *EthereumTypedDataType) typeName() string {
 	if t.isArray() {
 		return strings.TrimSuffix(t.Type, "[]")
 	}
 	return t.Type
 }
 
-func (t *ethereumTypedDataType) isReferenceType() bool {
+func (t *EthereumTypedDataType) isReferenceType() bool {
 	if len(t.Type) == 0 {
 		return false
 	}
@@ -40,24 +42,67 @@ func (t *ethereumTypedDataType) isReferenceType() bool {
 	return unicode.IsUpper([]rune(t.Type)[0])
 }
 
-type ethereumTypedDataTypes map[string][]ethereumTypedDataType
+// EthereumTypedDataTypes are the EIP-712 struct types by their names.
+type EthereumTypedDataTypes map[string][]EthereumTypedDataType
 
-type ethereumTypedDataDomain struct {
-	Name    string           `json:"name,omitempty"`
-	Version string           `json:"version,omitempty"`
-	ChainId *hexOrDecimal256 `json:"chainId,omitempty"`
+// EthereumTypedDataDomain is the EIP-712 domain separator.
+type EthereumTypedDataDomain struct {
+	Name              string           `json:"name,omitempty"`
+	Version           string           `json:"version,omitempty"`
+	ChainId           *hexOrDecimal256 `json:"chainId,omitempty"`
+	VerifyingContract string           `json:"verifyingContract,omitempty"`
+	Salt              string           `json:"salt,omitempty"`
 }
 
-type ethereumTypedDataMessage map[string]interface{}
+// EthereumTypedDataMessage is the message of the primary type.
+type EthereumTypedDataMessage map[string]interface{}
 
-type ethereumTypedData struct {
-	Types       ethereumTypedDataTypes   `json:"types"`
+// EthereumTypedData is the EIP-712 typed structured data in the format of eth_signTypedData_v4 request.
+type EthereumTypedData struct {
+	Types       EthereumTypedDataTypes   `json:"types"`
 	PrimaryType string                   `json:"primaryType"`
-	Domain      ethereumTypedDataDomain  `json:"domain"`
-	Message     ethereumTypedDataMessage `json:"message"`
+	Domain      EthereumTypedDataDomain  `json:"domain"`
+	Message     EthereumTypedDataMessage `json:"message"`
 }
 
-func (typedData *ethereumTypedData) RawData() ([]byte, error) {
+// NewEthereumTypedDataFromJSON parses and validates the typed data. Numbers are parsed without loss of precision.
+func NewEthereumTypedDataFromJSON(data []byte) (*EthereumTypedData, error) {
+	typedData := new(EthereumTypedData)
+	if err := typedData.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return typedData, nil
+}
+
+// UnmarshalJSON accepts the typed data as JSON object or as string with JSON object inside,
+// the latter is how wallets pass it to eth_signTypedData_v4.
+func (typedData *EthereumTypedData) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return errors.Wrap(err, "failed to unmarshal typed data string")
+		}
+		data = []byte(s)
+	}
+	type shadowed EthereumTypedData
+	var td shadowed
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&td); err != nil {
+		return errors.Wrap(err, "failed to unmarshal typed data")
+	}
+	*typedData = EthereumTypedData(td)
+	if err := typedData.validate(); err != nil {
+		return errors.Wrap(err, "invalid typed data")
+	}
+	if _, ok := typedData.Types[typedData.PrimaryType]; !ok {
+		return errors.Errorf("primary type %q is undefined", typedData.PrimaryType)
+	}
+	return nil
+}
+
+func (typedData *EthereumTypedData) RawData() ([]byte, error) {
 	domainSeparator, err := typedData.HashStructMap("EIP712Domain", typedData.Domain.Map())
 	if err != nil {
 		return nil, err
@@ -70,16 +115,35 @@ func (typedData *ethereumTypedData) RawData() ([]byte, error) {
 	return []byte(rawData), nil
 }
 
-func (typedData *ethereumTypedData) Hash() (EthereumHash, error) {
+// Hash returns the EIP-712 hash of the typed data, this is the digest signed by eth_signTypedData_v4.
+func (typedData *EthereumTypedData) Hash() (EthereumHash, error) {
 	rawData, err := typedData.RawData()
 	if err != nil {
-		return EthereumHash{}, nil
+		return EthereumHash{}, err
 	}
 	return Keccak256EthereumHash(rawData), nil
 }
 
+// RecoverSigner recovers the public key of the signer from the signature of the typed data.
+func (typedData *EthereumTypedData) RecoverSigner(sig EthereumSignature) (*EthereumPublicKey, error) {
+	hash, err := typedData.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate typed data hash")
+	}
+	return sig.RecoverEthereumPublicKey(hash[:])
+}
+
+// VerifySignature checks that the typed data was signed by the owner of the address.
+func (typedData *EthereumTypedData) VerifySignature(addr EthereumAddress, sig EthereumSignature) (bool, error) {
+	pk, err := typedData.RecoverSigner(sig)
+	if err != nil {
+		return false, err
+	}
+	return pk.EthereumAddress() == addr, nil
+}
+
 // HashStructMap generates a keccak256 hash of the encoding of the provided data
-func (typedData *ethereumTypedData) HashStructMap(primaryType string,
+func (typedData *EthereumTypedData) HashStructMap(primaryType string,
 	data map[string]interface{}) (EthereumHash, error) {
 
 	encodedData, err := typedData.EncodeData(primaryType, data, 1)
@@ -90,7 +154,7 @@ func (typedData *ethereumTypedData) HashStructMap(primaryType string,
 }
 
 // Dependencies returns an array of custom types ordered by their hierarchical reference tree
-func (typedData *ethereumTypedData) Dependencies(primaryType string, found []string) []string {
+func (typedData *EthereumTypedData) Dependencies(primaryType string, found []string) []string {
 	includes := func(arr []string, str string) bool {
 		for _, obj := range arr {
 			if obj == str {
@@ -121,7 +185,7 @@ func (typedData *ethereumTypedData) Dependencies(primaryType string, found []str
 // `name ‖ "(" ‖ member₁ ‖ "," ‖ member₂ ‖ "," ‖ … ‖ memberₙ ")"`
 //
 // each member is written as `type ‖ " " ‖ name` encodings cascade down and are sorted by name
-func (typedData *ethereumTypedData) EncodeType(primaryType string) []byte {
+func (typedData *EthereumTypedData) EncodeType(primaryType string) []byte {
 	// Get dependencies primary first, then alphabetical
 	deps := typedData.Dependencies(primaryType, []string{})
 	if len(deps) > 0 {
@@ -148,7 +212,7 @@ func (typedData *ethereumTypedData) EncodeType(primaryType string) []byte {
 }
 
 // TypeHash creates the keccak256 hash  of the data
-func (typedData *ethereumTypedData) TypeHash(primaryType string) []byte {
+func (typedData *EthereumTypedData) TypeHash(primaryType string) []byte {
 	return crypto.MustKeccak256(typedData.EncodeType(primaryType)).Bytes()
 }
 
@@ -156,7 +220,7 @@ func (typedData *ethereumTypedData) TypeHash(primaryType string) []byte {
 // `enc(value₁) ‖ enc(value₂) ‖ … ‖ enc(valueₙ)`
 //
 // each encoded member is 32-byte long
-func (typedData *ethereumTypedData) EncodeData(primaryType string, data map[string]interface{}, depth int) ([]byte, error) {
+func (typedData *EthereumTypedData) EncodeData(primaryType string, data map[string]interface{}, depth int) ([]byte, error) {
 	if err := typedData.validate(); err != nil {
 		return nil, err
 	}
@@ -280,6 +344,12 @@ func parseInteger(encType string, encValue interface{}) (*big.Int, error) {
 			return nil, err
 		}
 		b = (*big.Int)(&hexIntValue)
+	case json.Number:
+		var hexIntValue hexOrDecimal256
+		if err := hexIntValue.UnmarshalText([]byte(v)); err != nil {
+			return nil, err
+		}
+		b = (*big.Int)(&hexIntValue)
 
 	case float64:
 		// standard JSON unmarshal parses non-strings as float64. Fail if we cannot
@@ -337,7 +407,7 @@ func parseInteger(encType string, encValue interface{}) (*big.Int, error) {
 
 // EncodePrimitiveValue deals with the primitive values found
 // while searching through the typed data
-func (typedData *ethereumTypedData) EncodePrimitiveValue(encType string, encValue interface{}, depth int) ([]byte, error) {
+func (typedData *EthereumTypedData) EncodePrimitiveValue(encType string, encValue interface{}, depth int) ([]byte, error) {
 	switch encType {
 	case "address":
 		stringValue, ok := encValue.(string)
@@ -412,7 +482,7 @@ func dataMismatchError(encType string, encValue interface{}) error {
 }
 
 // validate makes sure the types are sound
-func (typedData *ethereumTypedData) validate() error {
+func (typedData *EthereumTypedData) validate() error {
 	if err := typedData.Types.validate(); err != nil {
 		return err
 	}
@@ -423,7 +493,7 @@ func (typedData *ethereumTypedData) validate() error {
 }
 
 // Map generates a map version of the typed data
-func (typedData *ethereumTypedData) Map() map[string]interface{} {
+func (typedData *EthereumTypedData) Map() map[string]interface{} {
 	dataMap := map[string]interface{}{
 		"types":       typedData.Types,
 		"domain":      typedData.Domain.Map(),
@@ -550,7 +620,7 @@ func isPrimitiveTypeValid(primitiveType string) bool {
 }
 
 // validate checks if the types object is conformant to the specs
-func (t ethereumTypedDataTypes) validate() error {
+func (t EthereumTypedDataTypes) validate() error {
 	for typeKey, typeArr := range t {
 		if len(typeKey) == 0 {
 			return errors.Errorf("empty type key")
@@ -582,16 +652,17 @@ func (t ethereumTypedDataTypes) validate() error {
 
 // validate checks if the given domain is valid, i.e. contains at least
 // the minimum viable keys and values
-func (domain *ethereumTypedDataDomain) validate() error {
-	if domain.ChainId == nil && len(domain.Name) == 0 && len(domain.Version) == 0 {
+func (domain *EthereumTypedDataDomain) validate() error {
+	if domain.ChainId == nil && len(domain.Name) == 0 && len(domain.Version) == 0 &&
+		len(domain.VerifyingContract) == 0 && len(domain.Salt) == 0 {
 		return errors.New("domain is undefined")
 	}
 	return nil
 }
 
 // Map is a helper function to generate a map version of the domain
-func (domain *ethereumTypedDataDomain) Map() map[string]interface{} {
-	dataMap := make(map[string]interface{}, 3)
+func (domain *EthereumTypedDataDomain) Map() map[string]interface{} {
+	dataMap := make(map[string]interface{}, 5)
 
 	if domain.ChainId != nil {
 		dataMap["chainId"] = domain.ChainId
@@ -605,5 +676,13 @@ func (domain *ethereumTypedDataDomain) Map() map[string]interface{} {
 		dataMap["version"] = domain.Version
 	}
 
+	if len(domain.VerifyingContract) > 0 {
+		dataMap["verifyingContract"] = domain.VerifyingContract
+	}
+
+	if len(domain.Salt) > 0 {
+		dataMap["salt"] = domain.Salt
+	}
+
 	return dataMap
 }
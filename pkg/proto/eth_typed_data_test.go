@@ -0,0 +1,96 @@
+package proto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailTypedData is the example from EIP-712 specification.
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+const mailSignature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+	"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+
+func TestEthereumTypedDataHash(t *testing.T) {
+	td, err := NewEthereumTypedDataFromJSON([]byte(mailTypedData))
+	require.NoError(t, err)
+	hash, err := td.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hash.String())
+
+	// Wallets pass typed data to eth_signTypedData_v4 as a JSON string
+	quoted, err := json.Marshal(mailTypedData)
+	require.NoError(t, err)
+	var fromString EthereumTypedData
+	require.NoError(t, json.Unmarshal(quoted, &fromString))
+	hash2, err := fromString.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, hash, hash2)
+}
+
+func TestEthereumTypedDataInvalid(t *testing.T) {
+	for _, js := range []string{
+		`{"types":{"Mail":[{"name":"a","type":"string"}]},"primaryType":"Mail","domain":{},"message":{}}`,
+		`{"types":{"EIP712Domain":[{"name":"name","type":"string"}]},"primaryType":"Mail","domain":{"name":"x"},"message":{}}`,
+		`{"types":{"Mail":[{"name":"a","type":"Unknown"}]},"primaryType":"Mail","domain":{"name":"x"},"message":{}}`,
+		`[]`,
+	} {
+		_, err := NewEthereumTypedDataFromJSON([]byte(js))
+		assert.Error(t, err, js)
+	}
+}
+
+func TestEthereumTypedDataVerifySignature(t *testing.T) {
+	td, err := NewEthereumTypedDataFromJSON([]byte(mailTypedData))
+	require.NoError(t, err)
+	sig, err := NewEthereumSignatureFromHexString(mailSignature)
+	require.NoError(t, err)
+	signer, err := NewEthereumAddressFromHexString("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	require.NoError(t, err)
+
+	pk, err := td.RecoverSigner(sig)
+	require.NoError(t, err)
+	assert.Equal(t, signer, pk.EthereumAddress())
+
+	ok, err := td.VerifySignature(signer, sig)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	td.Message["contents"] = "Hello, Alice!"
+	ok, err = td.VerifySignature(signer, sig)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
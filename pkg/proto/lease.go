@@ -1,8 +1,14 @@
 package proto
 
+import "github.com/wavesplatform/gowaves/pkg/crypto"
+
 type LeaseInfo struct {
-	IsActive    bool
-	LeaseAmount uint64
-	Recipient   WavesAddress
-	Sender      WavesAddress
+	IsActive            bool
+	LeaseAmount         uint64
+	Recipient           WavesAddress
+	Sender              WavesAddress
+	OriginTransactionID *crypto.Digest
+	Height              uint64
+	CancelHeight        uint64
+	CancelTransactionID *crypto.Digest
 }
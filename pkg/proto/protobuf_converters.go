@@ -727,6 +727,69 @@ func (c *ProtobufConverter) ErrorMessage(msg *g.InvokeScriptResult_ErrorMessage)
 	}, nil
 }
 
+func (c *ProtobufConverter) ScriptInvocations(scheme byte, invokes []*g.InvokeScriptResult_Invocation) ([]*ScriptInvocation, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if len(invokes) == 0 {
+		return nil, nil
+	}
+	res := make([]*ScriptInvocation, len(invokes))
+	for i, x := range invokes {
+		if x.Call == nil || x.StateChanges == nil {
+			return nil, errors.New("empty invocation call or state changes")
+		}
+		dApp, err := c.Address(scheme, x.DApp)
+		if err != nil {
+			return nil, err
+		}
+		args, err := c.callArguments(x.Call.Args)
+		if err != nil {
+			return nil, err
+		}
+		payments := c.payments(x.Payments)
+		if c.err != nil {
+			return nil, c.err
+		}
+		var sr ScriptResult
+		if err := sr.FromProtobuf(scheme, x.StateChanges); err != nil {
+			return nil, err
+		}
+		res[i] = &ScriptInvocation{
+			DApp:     dApp,
+			Call:     NewFunctionCall(x.Call.Function, args),
+			Payments: payments,
+			Result:   &sr,
+		}
+	}
+	return res, nil
+}
+
+func (c *ProtobufConverter) callArguments(args []*g.InvokeScriptResult_Call_Argument) (Arguments, error) {
+	res := make(Arguments, len(args))
+	for i, arg := range args {
+		switch v := arg.GetValue().(type) {
+		case *g.InvokeScriptResult_Call_Argument_IntegerValue:
+			res[i] = &IntegerArgument{Value: v.IntegerValue}
+		case *g.InvokeScriptResult_Call_Argument_BooleanValue:
+			res[i] = &BooleanArgument{Value: v.BooleanValue}
+		case *g.InvokeScriptResult_Call_Argument_BinaryValue:
+			res[i] = &BinaryArgument{Value: v.BinaryValue}
+		case *g.InvokeScriptResult_Call_Argument_StringValue:
+			res[i] = &StringArgument{Value: v.StringValue}
+		case *g.InvokeScriptResult_Call_Argument_List_:
+			items, err := c.callArguments(v.List.GetItems())
+			if err != nil {
+				return nil, err
+			}
+			res[i] = &ListArgument{Items: items}
+		default:
+			return nil, errors.Errorf("unsupported argument type '%T'", v)
+		}
+	}
+	return res, nil
+}
+
 func (c *ProtobufConverter) reset() {
 	c.err = nil
 }
@@ -38,3 +38,18 @@ func (r rewardsByAddress) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
 func (r rewardsByAddress) Less(i, j int) bool {
 	return bytes.Compare(r[i].address.Bytes(), r[j].address.Bytes()) < 0
 }
+
+// RewardsInfo describes the state of monetary policy at some height.
+type RewardsInfo struct {
+	Height              Height
+	TotalWavesAmount    uint64 // Waves issued in genesis block and as block rewards up to the height
+	CurrentReward       uint64
+	MinIncrement        uint64
+	Term                uint64
+	NextCheck           Height // The last block of the term, reward is changed after it
+	VotingIntervalStart Height
+	VotingInterval      uint64
+	VotingThreshold     uint64
+	IncreaseVotes       uint32
+	DecreaseVotes       uint32
+}
@@ -321,6 +321,65 @@ func (msg *ScriptErrorMessage) ToProtobuf() *g.InvokeScriptResult_ErrorMessage {
 	}
 }
 
+// ScriptInvocation is the invocation of dApp made by another dApp. The result of invocation contains the actions
+// of the invoked function and the invocations made by it.
+type ScriptInvocation struct {
+	DApp     WavesAddress
+	Call     FunctionCall
+	Payments ScriptPayments
+	Result   *ScriptResult
+}
+
+func (i *ScriptInvocation) ToProtobuf() (*g.InvokeScriptResult_Invocation, error) {
+	args, err := argumentsToProtobuf(i.Call.Arguments())
+	if err != nil {
+		return nil, err
+	}
+	payments := make([]*g.Amount, len(i.Payments))
+	for j, p := range i.Payments {
+		payments[j] = &g.Amount{AssetId: p.Asset.ToID(), Amount: int64(p.Amount)}
+	}
+	res, err := i.Result.ToProtobuf()
+	if err != nil {
+		return nil, err
+	}
+	return &g.InvokeScriptResult_Invocation{
+		DApp:         i.DApp.Bytes(),
+		Call:         &g.InvokeScriptResult_Call{Function: i.Call.Name(), Args: args},
+		Payments:     payments,
+		StateChanges: res,
+	}, nil
+}
+
+func argumentsToProtobuf(args Arguments) ([]*g.InvokeScriptResult_Call_Argument, error) {
+	res := make([]*g.InvokeScriptResult_Call_Argument, len(args))
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case *IntegerArgument:
+			res[i] = &g.InvokeScriptResult_Call_Argument{Value: &g.InvokeScriptResult_Call_Argument_IntegerValue{IntegerValue: a.Value}}
+		case *BooleanArgument:
+			res[i] = &g.InvokeScriptResult_Call_Argument{Value: &g.InvokeScriptResult_Call_Argument_BooleanValue{BooleanValue: a.Value}}
+		case *BinaryArgument:
+			res[i] = &g.InvokeScriptResult_Call_Argument{Value: &g.InvokeScriptResult_Call_Argument_BinaryValue{BinaryValue: a.Value}}
+		case *StringArgument:
+			res[i] = &g.InvokeScriptResult_Call_Argument{Value: &g.InvokeScriptResult_Call_Argument_StringValue{StringValue: a.Value}}
+		case *ListArgument:
+			items, err := argumentsToProtobuf(a.Items)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = &g.InvokeScriptResult_Call_Argument{Value: &g.InvokeScriptResult_Call_Argument_List_{
+				List: &g.InvokeScriptResult_Call_Argument_List{Items: items},
+			}}
+		default:
+			return nil, errors.Errorf("unsupported argument type '%T'", arg)
+		}
+	}
+	return res, nil
+}
+
+// ScriptResult is the result of invocation. Actions of nested invocations are stored in the results of Invokes,
+// the result of invocation stored before nested invocations were recorded contains all actions instead.
 type ScriptResult struct {
 	DataEntries  []*DataEntryScriptAction
 	Transfers    []*TransferScriptAction
@@ -331,6 +390,7 @@ type ScriptResult struct {
 	Leases       []*LeaseScriptAction
 	LeaseCancels []*LeaseCancelScriptAction
 	ErrorMsg     ScriptErrorMessage
+	Invokes      []*ScriptInvocation
 }
 
 // NewScriptResult creates correct representation of invocation actions for storage and API.
@@ -422,6 +482,13 @@ func (sr *ScriptResult) ToProtobuf() (*g.InvokeScriptResult, error) {
 	for i := range sr.LeaseCancels {
 		leaseCancels[i] = sr.LeaseCancels[i].ToProtobuf()
 	}
+	invokes := make([]*g.InvokeScriptResult_Invocation, len(sr.Invokes))
+	for i := range sr.Invokes {
+		invokes[i], err = sr.Invokes[i].ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &g.InvokeScriptResult{
 		Data:         data,
 		Transfers:    transfers,
@@ -432,6 +499,7 @@ func (sr *ScriptResult) ToProtobuf() (*g.InvokeScriptResult, error) {
 		Leases:       leases,
 		LeaseCancels: leaseCancels,
 		ErrorMessage: sr.ErrorMsg.ToProtobuf(),
+		Invokes:      invokes,
 	}, nil
 }
 
@@ -483,5 +551,9 @@ func (sr *ScriptResult) FromProtobuf(scheme byte, msg *g.InvokeScriptResult) err
 		return err
 	}
 	sr.ErrorMsg = errMsg
+	sr.Invokes, err = c.ScriptInvocations(scheme, msg.Invokes)
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -503,3 +503,45 @@ func TestNegativePaymentsValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestScriptResultInvokesRoundTrip(t *testing.T) {
+	waves, err := NewOptionalAssetFromString("WAVES")
+	require.NoError(t, err)
+	addr0, err := NewAddressFromString("3PQ8bp1aoqHQo3icNqFv6VM36V1jzPeaG1v")
+	require.NoError(t, err)
+	rcp := NewRecipientFromAddress(addr0)
+	empty := func() *ScriptResult {
+		return &ScriptResult{
+			DataEntries:  make([]*DataEntryScriptAction, 0),
+			Transfers:    make([]*TransferScriptAction, 0),
+			Issues:       make([]*IssueScriptAction, 0),
+			Reissues:     make([]*ReissueScriptAction, 0),
+			Burns:        make([]*BurnScriptAction, 0),
+			Sponsorships: make([]*SponsorshipScriptAction, 0),
+			Leases:       make([]*LeaseScriptAction, 0),
+			LeaseCancels: make([]*LeaseCancelScriptAction, 0),
+		}
+	}
+	nested := empty()
+	nested.Transfers = []*TransferScriptAction{{Amount: 10, Asset: *waves, Recipient: rcp}}
+	test := empty()
+	test.DataEntries = []*DataEntryScriptAction{{Entry: &IntegerDataEntry{Key: "key", Value: 1}}}
+	test.Invokes = []*ScriptInvocation{
+		{
+			DApp:     addr0,
+			Call:     NewFunctionCall("call", Arguments{NewIntegerArgument(1), NewStringArgument("str")}),
+			Payments: ScriptPayments{{Amount: 5, Asset: *waves}},
+			Result:   nested,
+		},
+	}
+
+	msg, err := test.ToProtobuf()
+	require.NoError(t, err)
+	b, err := MarshalToProtobufDeterministic(msg)
+	require.NoError(t, err)
+	in := &g.InvokeScriptResult{}
+	require.NoError(t, pb.Unmarshal(b, in))
+	sr := ScriptResult{}
+	require.NoError(t, sr.FromProtobuf('W', in))
+	assert.EqualValues(t, *test, sr)
+}
@@ -1939,12 +1939,12 @@ func (o *EthereumOrderV4) ethereumTypedDataHash(scheme Scheme) (EthereumHash, er
 	return hash, nil
 }
 
-func (o *EthereumOrderV4) buildEthereumOrderV4TypedData(scheme Scheme) ethereumTypedData {
+func (o *EthereumOrderV4) buildEthereumOrderV4TypedData(scheme Scheme) EthereumTypedData {
 	priceMode := o.PriceMode
 	if priceMode == OrderPriceModeDefault {
 		priceMode = OrderPriceModeFixedDecimals
 	}
-	message := ethereumTypedDataMessage{
+	message := EthereumTypedDataMessage{
 		"version":           int32(o.Version),
 		"matcherPublicKey":  o.MatcherPK.String(),
 		"amountAsset":       o.AssetPair.AmountAsset.String(),
@@ -1959,14 +1959,14 @@ func (o *EthereumOrderV4) buildEthereumOrderV4TypedData(scheme Scheme) ethereumT
 		"priceMode":         priceMode.upperSnakeCaseString(),
 	}
 
-	var orderDomain = ethereumTypedData{
-		Types: ethereumTypedDataTypes{
-			"EIP712Domain": []ethereumTypedDataType{
+	var orderDomain = EthereumTypedData{
+		Types: EthereumTypedDataTypes{
+			"EIP712Domain": []EthereumTypedDataType{
 				{Name: "name", Type: "string"},
 				{Name: "version", Type: "string"},
 				{Name: "chainId", Type: "uint256"},
 			},
-			"Order": []ethereumTypedDataType{
+			"Order": []EthereumTypedDataType{
 				{Name: "version", Type: "int32"},
 				{Name: "matcherPublicKey", Type: "string"},
 				{Name: "amountAsset", Type: "string"},
@@ -1982,7 +1982,7 @@ func (o *EthereumOrderV4) buildEthereumOrderV4TypedData(scheme Scheme) ethereumT
 			},
 		},
 		PrimaryType: "Order",
-		Domain: ethereumTypedDataDomain{
+		Domain: EthereumTypedDataDomain{
 			Name:    "Waves Order",
 			Version: "1",
 			ChainId: newHexOrDecimal256(int64(scheme)),
@@ -3,481 +3,640 @@ package ride
 //go:generate go run ./generate
 
 import (
-	"bytes"
 	"encoding/binary"
+	"fmt"
 	"math"
 
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 )
 
-func Compile(tree *ast.Tree) (RideScript, error) {
-	fCheck, err := selectFunctionChecker(tree.LibVersion)
+// Compile compiles the script to bytecode.
+// Names in the compiled program are resolved lexically while the tree evaluator looks them up in the dynamic scope.
+// To produce identical results scripts that declare the same name more than once in nested scopes,
+// shadow global constants or functions of the standard library are not compiled.
+func Compile(tree *ast.Tree) (*Program, error) {
+	c, err := newCompiler(tree)
 	if err != nil {
 		return nil, errors.Wrap(err, "compile")
 	}
-	cCheck, err := selectConstantsChecker(tree.LibVersion)
+	var p *Program
+	if tree.IsDApp() {
+		p, err = c.compileDAppScript()
+	} else {
+		p, err = c.compileSimpleScript()
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "compile")
 	}
-	c := &compiler{
-		constants:     newRideConstants(),
-		checkFunction: fCheck,
-		checkConstant: cCheck,
-		values:        make([]rideValue, 0),
-		functions:     make([]*localFunction, 0),
-		declarations:  make([]rideDeclaration, 0),
-		patcher:       newPatcher(),
-	}
-	if tree.IsDApp() {
-		return c.compileDAppScript(tree)
-	}
-	return c.compileSimpleScript(tree)
+	return p, nil
 }
 
 type compiler struct {
+	tree          *ast.Tree
+	code          []byte
 	constants     *rideConstants
-	checkFunction func(string) (uint16, bool)
 	checkConstant func(string) (uint16, bool)
-	values        []rideValue
-	functions     []*localFunction
-	declarations  []rideDeclaration
-	patcher       *patcher
-	callable      *rideCallable
-}
-
-func (c *compiler) compileSimpleScript(tree *ast.Tree) (*SimpleScript, error) {
-	bb := new(bytes.Buffer)
-	err := c.compile(bb, tree.Verifier)
+	globals       []rideConstructor
+	system        func(string) (rideFunction, bool)
+	expression    func(string) (rideFunction, bool)
+	costs         [2]map[string]int
+	natives       []nativeFunction
+	nativeIDs     map[string]uint16
+	functions     []userFunction
+	parameters    map[string]struct{}
+	declared      map[string]struct{}
+	contexts      []errorContext
+	jobs          []compilationJob
+}
+
+func newCompiler(tree *ast.Tree) (*compiler, error) {
+	v := tree.LibVersion
+	names, err := selectConstantNames(v)
 	if err != nil {
 		return nil, err
 	}
-	bb.WriteByte(OpHalt)
-	for _, d := range c.declarations {
-		pos := bb.Len()
-		c.patcher.setOrigin(d.buffer(), pos)
-		bb.Write(patchedCode(d.buffer(), pos))
-		bb.WriteByte(OpReturn)
-		for _, ref := range d.references() {
-			c.patcher.setPosition(ref, uint16(pos))
+	checkConstant, err := selectConstantsChecker(v)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := selectConstants(v)
+	if err != nil {
+		return nil, err
+	}
+	globals := make([]rideConstructor, len(names))
+	for _, n := range names {
+		id, ok := checkConstant(n)
+		if !ok || int(id) >= len(globals) {
+			return nil, errors.Errorf("unknown constant '%s'", n)
 		}
+		globals[id] = provider(int(id))
 	}
-	code := bb.Bytes()
-	patches, err := c.patcher.get()
+	system, err := selectFunctionsByName(v, true)
 	if err != nil {
 		return nil, err
 	}
-	for pos, addr := range patches {
-		binary.BigEndian.PutUint16(code[pos:], addr)
+	expression, err := selectFunctionsByName(v, false)
+	if err != nil {
+		return nil, err
 	}
-	return &SimpleScript{
-		LibVersion: tree.LibVersion,
-		EntryPoint: 0,
-		Code:       bb.Bytes(),
-		Constants:  c.constants.items,
-	}, nil
-}
-
-func (c *compiler) compileDAppScript(tree *ast.Tree) (*DAppScript, error) {
-	// Compile global declarations.
-	// Each declaration goes to the declaration stack with code compiled in its own buffer.
-	functions := make(map[string]callable)
-	bb := new(bytes.Buffer)
-	for _, node := range tree.Declarations {
-		err := c.compile(bb, node)
+	c := &compiler{
+		tree:          tree,
+		code:          make([]byte, 0, 256),
+		constants:     newRideConstants(),
+		checkConstant: checkConstant,
+		globals:       globals,
+		system:        system,
+		expression:    expression,
+		nativeIDs:     make(map[string]uint16),
+		parameters:    make(map[string]struct{}),
+		declared:      make(map[string]struct{}),
+	}
+	for i := range c.costs {
+		c.costs[i], err = selectEvaluationCostsProvider(v, i+1)
 		if err != nil {
 			return nil, err
 		}
 	}
-	for _, n := range tree.Functions {
+	return c, nil
+}
+
+// compilationJob is the code of let expression or user function body which is compiled after the code that declares it.
+type compilationJob struct {
+	node     ast.Node
+	scope    *lexicalScope
+	layout   *scopeLayout
+	slot     int
+	function int
+}
+
+// functionScope is the scope of the root expression or a user function that have its own values at runtime.
+type functionScope struct {
+	layout *scopeLayout
+	depth  int
+	entry  bool // Scope of the entry point or of the function declared inside it, invocation parameter is visible
+}
+
+// lexicalScope is a single declaration that is visible in nested expressions.
+type lexicalScope struct {
+	parent   *lexicalScope
+	fs       *functionScope
+	name     string
+	slot     int // Slot of the value or -1 for function declaration
+	function int // ID of user function or -1 for value declaration
+}
+
+func (s *lexicalScope) value(name string) *lexicalScope {
+	for ls := s; ls != nil; ls = ls.parent {
+		if ls.slot >= 0 && ls.name == name {
+			return ls
+		}
+	}
+	return nil
+}
+
+func (s *lexicalScope) userFunction(name string) *lexicalScope {
+	for ls := s; ls != nil; ls = ls.parent {
+		if ls.function >= 0 && ls.name == name {
+			return ls
+		}
+	}
+	return nil
+}
+
+func (s *lexicalScope) withValue(name string, slot int) *lexicalScope {
+	return &lexicalScope{parent: s, fs: s.fs, name: name, slot: slot, function: -1}
+}
+
+func (s *lexicalScope) withFunction(name string, id int) *lexicalScope {
+	return &lexicalScope{parent: s, fs: s.fs, name: name, slot: -1, function: id}
+}
+
+func newRootScope() *lexicalScope {
+	return &lexicalScope{fs: &functionScope{layout: &scopeLayout{}}, slot: -1, function: -1}
+}
+
+func (c *compiler) compileSimpleScript() (*Program, error) {
+	c.collectDeclarations(c.tree.Verifier)
+	root := newRootScope()
+	entry := &entryPoint{entry: len(c.code), layout: root.fs.layout}
+	if err := c.compile(c.tree.Verifier, root); err != nil {
+		return nil, err
+	}
+	c.code = append(c.code, OpHalt)
+	if err := c.compileJobs(); err != nil {
+		return nil, err
+	}
+	p := c.program()
+	p.verifier = entry
+	return p, nil
+}
+
+func (c *compiler) compileDAppScript() (*Program, error) {
+	type callable struct {
+		fn   *ast.FunctionDeclarationNode
+		name string
+	}
+	callables := make([]callable, 0, len(c.tree.Functions)+1)
+	for _, n := range c.tree.Functions {
 		fn, ok := n.(*ast.FunctionDeclarationNode)
 		if !ok {
 			return nil, errors.Errorf("invalid node type %T", n)
 		}
-		c.callable = &rideCallable{
-			name:      fn.Name,
-			parameter: fn.InvocationParameter,
-		}
-		err := c.compile(bb, fn)
-		if err != nil {
-			return nil, err
-		}
+		callables = append(callables, callable{fn: fn, name: fn.Name})
 	}
-	if tree.HasVerifier() {
-		v, ok := tree.Verifier.(*ast.FunctionDeclarationNode)
+	if c.tree.HasVerifier() {
+		v, ok := c.tree.Verifier.(*ast.FunctionDeclarationNode)
 		if !ok {
-			return nil, errors.Errorf("invalid node type for DApp's verifier '%T'", tree.Verifier)
-		}
-		c.callable = &rideCallable{
-			name:      "", // Verifier has empty name
-			parameter: v.InvocationParameter,
-		}
-		err := c.compile(bb, v)
-		if err != nil {
-			return nil, err
+			return nil, errors.Errorf("invalid node type for DApp's verifier '%T'", c.tree.Verifier)
 		}
-		functions[""] = callable{
-			entryPoint:    0,
-			parameterName: v.InvocationParameter,
+		callables = append(callables, callable{fn: v, name: ""}) // Verifier has empty name
+	}
+	for _, d := range c.tree.Declarations {
+		c.collectDeclarations(d)
+	}
+	for _, cl := range callables {
+		c.parameters[cl.fn.InvocationParameter] = struct{}{}
+		c.collectDeclarations(cl.fn.Body)
+	}
+	// All global declarations are visible to each other, because the tree evaluator declares them all before evaluation
+	root := newRootScope()
+	scope := root
+	jobs := make([]compilationJob, 0, len(c.tree.Declarations))
+	for _, d := range c.tree.Declarations {
+		switch n := d.(type) {
+		case *ast.AssignmentNode:
+			if err := c.checkValueName(scope, n.Name); err != nil {
+				return nil, err
+			}
+			slot := root.fs.layout.addLet(n.Name)
+			scope = scope.withValue(n.Name, slot)
+			jobs = append(jobs, compilationJob{node: n.Expression, layout: root.fs.layout, slot: slot, function: -1})
+		case *ast.FunctionDeclarationNode:
+			if err := c.checkFunctionName(scope, n.Name); err != nil {
+				return nil, err
+			}
+			id, err := c.declareFunction(n)
+			if err != nil {
+				return nil, err
+			}
+			scope = scope.withFunction(n.Name, id)
+			jobs = append(jobs, compilationJob{node: n, slot: -1, function: id})
+		default:
+			return nil, errors.Errorf("invalid global declaration '%T'", d)
 		}
 	}
-	// All declarations go here after verifier and public functions
-	for _, d := range c.declarations {
-		pos := bb.Len()
-		c.patcher.setOrigin(d.buffer(), pos)
-		bb.Write(patchedCode(d.buffer(), pos))
-		if c := d.callable(); c != nil {
-			bb.WriteByte(OpHalt)
-			// Add reference to entry point
-			functions[c.name] = callable{
-				entryPoint:    pos,
-				parameterName: c.parameter,
+	for _, j := range jobs {
+		j.scope = scope
+		c.jobs = append(c.jobs, j)
+	}
+	// Global declarations are compiled first, after that the layout of global values is complete
+	if err := c.compileJobs(); err != nil {
+		return nil, err
+	}
+	globals := root.fs.layout
+	entries := make([]*entryPoint, len(callables))
+	for i, cl := range callables {
+		layout := &scopeLayout{
+			names: append([]string(nil), globals.names...),
+			lets:  append([]int(nil), globals.lets...),
+		}
+		s := &lexicalScope{parent: scope, fs: &functionScope{layout: layout, entry: true}, slot: -1, function: -1}
+		for _, a := range cl.fn.Arguments {
+			if err := c.checkValueName(s, a); err != nil {
+				return nil, err
 			}
-		} else {
-			bb.WriteByte(OpReturn)
+			s = s.withValue(a, layout.addArgument(a))
+		}
+		entries[i] = &entryPoint{
+			name:      cl.name,
+			parameter: cl.fn.InvocationParameter,
+			arguments: len(cl.fn.Arguments),
+			offset:    globals.size(),
+			entry:     len(c.code),
+			layout:    layout,
 		}
-		for _, ref := range d.references() {
-			c.patcher.setPosition(ref, uint16(pos))
+		if err := c.compile(cl.fn.Body, s); err != nil {
+			return nil, err
 		}
+		c.code = append(c.code, OpHalt)
 	}
-	code := bb.Bytes()
-	patches, err := c.patcher.get()
-	if err != nil {
+	if err := c.compileJobs(); err != nil {
 		return nil, err
 	}
-	for pos, addr := range patches {
-		binary.BigEndian.PutUint16(code[pos:], addr)
+	p := c.program()
+	p.callables = make(map[string]*entryPoint, len(entries))
+	for _, e := range entries {
+		if e.name == "" {
+			p.verifier = e
+			continue
+		}
+		p.callables[e.name] = e
+	}
+	return p, nil
+}
+
+func (c *compiler) program() *Program {
+	p := &Program{
+		libVersion: c.tree.LibVersion,
+		dApp:       c.tree.IsDApp(),
+		code:       c.code,
+		constants:  c.constants.items,
+		natives:    c.natives,
+		functions:  c.functions,
+		globals:    c.globals,
+		contexts:   c.contexts,
+	}
+	for _, n := range c.natives {
+		if n.invocation {
+			p.invocations = true
+		}
 	}
-	return &DAppScript{
-		LibVersion:  tree.LibVersion,
-		Code:        bb.Bytes(),
-		Constants:   c.constants.items,
-		EntryPoints: functions,
-	}, nil
+	return p
 }
 
-func (c *compiler) compile(bb *bytes.Buffer, node ast.Node) error {
+// collectDeclarations collects names of all user functions declared in the node.
+func (c *compiler) collectDeclarations(node ast.Node) {
 	switch n := node.(type) {
-	case *ast.LongNode:
-		return c.longNode(bb, n)
-	case *ast.BytesNode:
-		return c.bytesNode(bb, n)
-	case *ast.StringNode:
-		return c.stringNode(bb, n)
-	case *ast.BooleanNode:
-		return c.booleanNode(bb, n)
 	case *ast.ConditionalNode:
-		return c.conditionalNode(bb, n)
+		c.collectDeclarations(n.Condition)
+		c.collectDeclarations(n.TrueExpression)
+		c.collectDeclarations(n.FalseExpression)
 	case *ast.AssignmentNode:
-		return c.assignmentNode(bb, n)
-	case *ast.ReferenceNode:
-		return c.referenceNode(bb, n)
+		c.collectDeclarations(n.Expression)
+		c.collectDeclarations(n.Block)
 	case *ast.FunctionDeclarationNode:
-		return c.functionDeclarationNode(bb, n)
+		c.declared[n.Name] = struct{}{}
+		c.collectDeclarations(n.Body)
+		c.collectDeclarations(n.Block)
 	case *ast.FunctionCallNode:
-		return c.callNode(bb, n)
+		for _, a := range n.Arguments {
+			c.collectDeclarations(a)
+		}
 	case *ast.PropertyNode:
-		return c.propertyNode(bb, n)
-	default:
-		return errors.Errorf("unexpected node type '%T'", node)
+		c.collectDeclarations(n.Object)
 	}
 }
 
-func (c *compiler) longNode(bb *bytes.Buffer, node *ast.LongNode) error {
-	cid, err := c.constants.put(rideInt(node.Value))
-	if err != nil {
-		return err
+func (c *compiler) checkValueName(scope *lexicalScope, name string) error {
+	if _, ok := c.checkConstant(name); ok {
+		return errors.Errorf("declaration of '%s' shadows global constant", name)
+	}
+	// Arguments and values of global functions are allowed to have the name of invocation parameter,
+	// because the parameter is not referenced outside the entry points
+	if _, ok := c.parameters[name]; ok && (scope.fs.depth == 0 || scope.fs.entry) {
+		return errors.Errorf("declaration of '%s' shadows invocation parameter", name)
+	}
+	if scope.value(name) != nil {
+		return errors.Errorf("declaration of '%s' shadows another declaration", name)
 	}
-	bb.WriteByte(OpPush)
-	bb.Write(encode(cid))
 	return nil
 }
 
-func (c *compiler) bytesNode(bb *bytes.Buffer, node *ast.BytesNode) error {
-	cid, err := c.constants.put(rideByteVector(node.Value))
-	if err != nil {
-		return err
+func (c *compiler) checkFunctionName(scope *lexicalScope, name string) error {
+	if _, ok := c.system(name); ok {
+		return errors.Errorf("declaration of function '%s' shadows system function", name)
+	}
+	if scope.userFunction(name) != nil {
+		return errors.Errorf("declaration of function '%s' shadows another function", name)
 	}
-	bb.WriteByte(OpPush)
-	bb.Write(encode(cid))
 	return nil
 }
 
-func (c *compiler) stringNode(bb *bytes.Buffer, node *ast.StringNode) error {
-	cid, err := c.constants.put(rideString(node.Value))
-	if err != nil {
-		return err
+func (c *compiler) declareFunction(node *ast.FunctionDeclarationNode) (int, error) {
+	if len(c.functions) >= math.MaxUint16 {
+		return 0, errors.New("max number of functions reached")
 	}
-	bb.WriteByte(OpPush)
-	bb.Write(encode(cid))
-	return nil
+	c.functions = append(c.functions, userFunction{
+		name:      node.Name,
+		arguments: len(node.Arguments),
+		layout:    &scopeLayout{},
+	})
+	return len(c.functions) - 1, nil
 }
 
-func (c *compiler) booleanNode(bb *bytes.Buffer, node *ast.BooleanNode) error {
-	if node.Value {
-		bb.WriteByte(OpTrue)
-	} else {
-		bb.WriteByte(OpFalse)
+func (c *compiler) compileJobs() error {
+	for len(c.jobs) > 0 {
+		j := c.jobs[0]
+		c.jobs = c.jobs[1:]
+		if j.function >= 0 {
+			if err := c.compileFunctionBody(j); err != nil {
+				return err
+			}
+			continue
+		}
+		j.layout.lets[j.slot] = len(c.code)
+		if err := c.compile(j.node, j.scope); err != nil {
+			return err
+		}
+		c.code = append(c.code, OpReturn)
+	}
+	if len(c.code) > math.MaxInt32 {
+		return errors.New("code is too long")
 	}
 	return nil
 }
 
-func (c *compiler) conditionalNode(bb *bytes.Buffer, node *ast.ConditionalNode) error {
-	err := c.compile(bb, node.Condition)
-	if err != nil {
+func (c *compiler) compileFunctionBody(j compilationJob) error {
+	node, ok := j.node.(*ast.FunctionDeclarationNode)
+	if !ok {
+		return errors.Errorf("invalid function declaration '%T'", j.node)
+	}
+	f := &c.functions[j.function]
+	fs := &functionScope{layout: f.layout, depth: j.scope.fs.depth + 1, entry: j.scope.fs.entry}
+	scope := &lexicalScope{parent: j.scope, fs: fs, slot: -1, function: -1}
+	for _, a := range node.Arguments {
+		if err := c.checkValueName(scope, a); err != nil {
+			return err
+		}
+		scope = scope.withValue(a, f.layout.addArgument(a))
+	}
+	f.entry = len(c.code)
+	if err := c.compile(node.Body, scope); err != nil {
 		return err
 	}
-	bb.WriteByte(OpJumpIfFalse)
-	otherwise := bb.Len()
-	bb.Write([]byte{0xff, 0xff})
+	c.code = append(c.code, OpReturn)
+	return nil
+}
 
-	// Truthy branch
-	bb.WriteByte(OpPop) // Remove condition result from stack
+func (c *compiler) compile(node ast.Node, scope *lexicalScope) error {
+	switch n := node.(type) {
+	case *ast.LongNode:
+		return c.push(rideInt(n.Value))
+	case *ast.BytesNode:
+		return c.push(rideByteVector(n.Value))
+	case *ast.StringNode:
+		return c.push(rideString(n.Value))
+	case *ast.BooleanNode:
+		if n.Value {
+			c.code = append(c.code, OpTrue)
+		} else {
+			c.code = append(c.code, OpFalse)
+		}
+		return nil
+	case *ast.ConditionalNode:
+		return c.conditionalNode(n, scope)
+	case *ast.AssignmentNode:
+		return c.assignmentNode(n, scope)
+	case *ast.ReferenceNode:
+		return c.referenceNode(n, scope)
+	case *ast.FunctionDeclarationNode:
+		return c.functionDeclarationNode(n, scope)
+	case *ast.FunctionCallNode:
+		return c.callNode(n, scope)
+	case *ast.PropertyNode:
+		return c.propertyNode(n, scope)
+	default:
+		return errors.Errorf("unexpected node type '%T'", node)
+	}
+}
 
-	err = c.compile(bb, node.TrueExpression)
+func (c *compiler) push(v rideType) error {
+	id, err := c.constants.put(v)
 	if err != nil {
 		return err
 	}
+	c.emit(OpPush, id)
+	return nil
+}
 
-	bb.WriteByte(OpJump)
-	end := bb.Len()
-	bb.Write([]byte{0xff, 0xff})
-
-	// Patch jump to alternative branch
-	code := bb.Bytes()
-	binary.BigEndian.PutUint16(code[otherwise:], uint16(bb.Len()))
-
-	// Alternative branch
-	bb.WriteByte(OpPop) // Remove condition result from stack
-	err = c.compile(bb, node.FalseExpression)
-	if err != nil {
+func (c *compiler) conditionalNode(node *ast.ConditionalNode, scope *lexicalScope) error {
+	c.code = append(c.code, OpCondition)
+	start := len(c.code)
+	if err := c.compile(node.Condition, scope); err != nil {
 		return err
 	}
-
-	// Patch jump to the end of alternative branch
-	code = bb.Bytes()
-	binary.BigEndian.PutUint16(code[end:], uint16(bb.Len()))
-
+	c.context(start, "failed to estimate the condition of if")
+	otherwise := c.jump(OpJumpIfFalse)
+	if err := c.compile(node.TrueExpression, scope); err != nil {
+		return err
+	}
+	end := c.jump(OpJump)
+	c.patch(otherwise)
+	if err := c.compile(node.FalseExpression, scope); err != nil {
+		return err
+	}
+	c.patch(end)
+	c.code = append(c.code, OpEndCondition)
 	return nil
 }
 
-func (c *compiler) assignmentNode(bb *bytes.Buffer, node *ast.AssignmentNode) error {
-	err := c.pushGlobalValue(node.Name, node.Expression, c.callable)
-	if err != nil {
+func (c *compiler) assignmentNode(node *ast.AssignmentNode, scope *lexicalScope) error {
+	if err := c.checkValueName(scope, node.Name); err != nil {
 		return err
 	}
-	if node.Block != nil {
-		err = c.compile(bb, node.Block)
-		if err != nil {
-			return err
-		}
-		err = c.popValue()
-		if err != nil {
-			return err
-		}
-	} else {
-		err = c.peekValue()
-		if err != nil {
-			return err
-		}
+	slot := scope.fs.layout.addLet(node.Name)
+	s := scope.withValue(node.Name, slot)
+	c.jobs = append(c.jobs, compilationJob{node: node.Expression, scope: s, layout: scope.fs.layout, slot: slot, function: -1})
+	start := len(c.code)
+	if err := c.compile(node.Block, s); err != nil {
+		return err
 	}
+	c.context(start, "failed to evaluate block after declaration of variable '%s'", node.Name)
 	return nil
 }
 
-func (c *compiler) referenceNode(bb *bytes.Buffer, node *ast.ReferenceNode) error {
-	if id, ok := c.checkConstant(node.Name); ok {
-		//Globally declared constant
-		bb.WriteByte(OpGlobal)
-		bb.Write(encode(id))
+func (c *compiler) referenceNode(node *ast.ReferenceNode, scope *lexicalScope) error {
+	if v := scope.value(node.Name); v != nil {
+		c.emit(OpLoad, uint16(scope.fs.depth-v.fs.depth), uint16(v.slot))
 		return nil
-	} else {
-		v, err := c.lookupValue(node.Name)
+	}
+	global, isGlobal := c.checkConstant(node.Name)
+	if _, ok := c.parameters[node.Name]; ok {
+		if !scope.fs.entry {
+			return errors.Errorf("reference to invocation parameter '%s' outside of entry point", node.Name)
+		}
+		id, err := c.constants.put(rideString(node.Name))
 		if err != nil {
 			return err
 		}
-		switch value := v.(type) {
-		case *localValue:
-			bb.WriteByte(OpLoadLocal)
-			bb.Write(encode(value.position))
-			return nil
-		case *globalValue:
-			bb.WriteByte(OpLoad)
-			value.refer(c.patcher.add(bb))
-			bb.Write([]byte{0xff, 0xff})
-			return nil
-		default:
-			return errors.Errorf("unexpected value type '%T'", value)
+		if !isGlobal {
+			global = noGlobal
 		}
+		c.emit(OpParameter, id, global)
+		return nil
 	}
+	if isGlobal {
+		c.emit(OpGlobal, global)
+		return nil
+	}
+	return errors.Errorf("value '%s' is not declared", node.Name)
 }
 
-func (c *compiler) functionDeclarationNode(bb *bytes.Buffer, node *ast.FunctionDeclarationNode) error {
-	var args []string
-	if c.callable != nil { // DApp callable function
-		args = make([]string, len(node.Arguments)+1)
-		args[0] = c.callable.parameter
-		copy(args[1:], node.Arguments)
-	} else {
-		args = node.Arguments
+func (c *compiler) functionDeclarationNode(node *ast.FunctionDeclarationNode, scope *lexicalScope) error {
+	if err := c.checkFunctionName(scope, node.Name); err != nil {
+		return err
 	}
-	err := c.pushFunction(node.Name, args, node.Body, c.callable)
+	id, err := c.declareFunction(node)
 	if err != nil {
 		return err
 	}
-	if node.Block != nil {
-		err = c.compile(bb, node.Block)
-		if err != nil {
-			return err
-		}
-		err = c.popFunction()
-		if err != nil {
-			return err
-		}
-	} else {
-		err = c.peekFunction()
-		if err != nil {
-			return err
-		}
+	s := scope.withFunction(node.Name, id)
+	c.jobs = append(c.jobs, compilationJob{node: node, scope: s, slot: -1, function: id})
+	start := len(c.code)
+	if err := c.compile(node.Block, s); err != nil {
+		return err
 	}
+	c.context(start, "failed to evaluate block after declaration of function '%s'", node.Name)
 	return nil
 }
 
-func (c *compiler) callNode(bb *bytes.Buffer, node *ast.FunctionCallNode) error {
-	for _, arg := range node.Arguments {
-		err := c.compile(bb, arg)
-		if err != nil {
-			return err
+func (c *compiler) callNode(node *ast.FunctionCallNode, scope *lexicalScope) error {
+	name := node.Function.Name()
+	if _, ok := node.Function.(ast.UserFunction); ok {
+		if f := scope.userFunction(name); f != nil {
+			return c.userFunctionCall(node, f, scope)
 		}
-	}
-	cnt := encode(uint16(len(node.Arguments)))
-
-	if id, ok := c.checkFunction(node.Function.Name()); ok {
-		//External function
-		bb.WriteByte(OpExternalCall)
-		bb.Write(encode(id))
-		bb.Write(cnt)
-	} else {
-		//Internal function
-		decl, err := c.lookupFunction(node.Function.Name())
-		if err != nil {
-			return err
+		if _, ok := c.declared[name]; ok {
+			return errors.Errorf("function '%s' is not declared in the scope", name)
 		}
-		bb.WriteByte(OpCall)
-		decl.call(c.patcher.add(bb))
-		bb.Write([]byte{0xff, 0xff})
-		bb.Write(cnt)
 	}
-	return nil
-}
-
-func (c *compiler) propertyNode(bb *bytes.Buffer, node *ast.PropertyNode) error {
-	err := c.compile(bb, node.Object)
+	id, err := c.native(name)
 	if err != nil {
 		return err
 	}
-	id, err := c.constants.put(rideString(node.Name))
-	if err != nil {
+	if c.natives[id].invocation {
+		c.emit(OpInvocation, id)
+	}
+	start := len(c.code)
+	if err := c.arguments(node.Arguments, scope); err != nil {
 		return err
 	}
-	bb.WriteByte(OpProperty)
-	bb.Write(encode(id))
+	c.context(start, "failed to call system function '%s'", name)
+	c.emit(OpExternalCall, id, uint16(len(node.Arguments)))
 	return nil
 }
 
-func (c *compiler) pushGlobalValue(name string, node ast.Node, annex *rideCallable) error {
-	d := newGlobalValue(name, annex)
-	c.callable = nil
-	c.values = append(c.values, d)
-	return c.compile(d.bb, node)
-}
-
-func (c *compiler) popValue() error {
-	l := len(c.values)
-	if l == 0 {
-		return errors.New("failed to pop value from empty stack")
+func (c *compiler) userFunctionCall(node *ast.FunctionCallNode, f *lexicalScope, scope *lexicalScope) error {
+	if c.functions[f.function].arguments != len(node.Arguments) {
+		return errors.Errorf("mismatched arguments number of user function '%s'", f.name)
 	}
-	var v rideValue
-	v, c.values = c.values[l-1], c.values[:l-1]
-	if d, ok := v.(rideDeclaration); ok {
-		c.declarations = append(c.declarations, d)
+	start := len(c.code)
+	if err := c.arguments(node.Arguments, scope); err != nil {
+		return err
 	}
+	c.context(start, "failed to evaluate function '%s' body", f.name)
+	c.emit(OpCall, uint16(f.function), uint16(len(node.Arguments)), uint16(scope.fs.depth-f.fs.depth))
 	return nil
 }
 
-func (c *compiler) peekValue() error {
-	l := len(c.values)
-	if l == 0 {
-		return errors.New("failed to peek value from empty stack")
+func (c *compiler) propertyNode(node *ast.PropertyNode, scope *lexicalScope) error {
+	c.code = append(c.code, OpBeginProperty)
+	start := len(c.code)
+	if err := c.compile(node.Object, scope); err != nil {
+		return err
 	}
-	v := c.values[l-1]
-	if d, ok := v.(rideDeclaration); ok {
-		c.declarations = append(c.declarations, d)
+	c.context(start, "failed to evaluate an object to get property '%s' on it", node.Name)
+	id, err := c.constants.put(rideString(node.Name))
+	if err != nil {
+		return err
 	}
+	c.emit(OpProperty, id)
 	return nil
 }
 
-func (c *compiler) lookupValue(name string) (rideValue, error) {
-	for i := len(c.values) - 1; i >= 0; i-- {
-		if c.values[i].id() == name {
-			return c.values[i], nil
+func (c *compiler) arguments(args []ast.Node, scope *lexicalScope) error {
+	for i, arg := range args {
+		start := len(c.code)
+		if err := c.compile(arg, scope); err != nil {
+			return err
 		}
+		c.context(start, "failed to materialize argument %d", i+1)
 	}
-	return nil, errors.Errorf("value '%s' is not declared", name)
+	return nil
 }
 
-func (c *compiler) pushFunction(name string, args []string, node ast.Node, annex *rideCallable) error {
-	d := newLocalFunction(name, args, annex)
-	c.functions = append(c.functions, d)
-	for i, a := range args {
-		c.values = append(c.values, newLocalValue(a, i))
+func (c *compiler) native(name string) (uint16, error) {
+	if id, ok := c.nativeIDs[name]; ok {
+		return id, nil
 	}
-	c.callable = nil
-	err := c.compile(d.bb, node)
-	if err != nil {
-		return err
+	fn, ok := c.system(name)
+	if !ok {
+		return 0, errors.Errorf("system function '%s' is not found", name)
 	}
-	for range args {
-		err := c.popValue()
-		if err != nil {
-			return err
+	nf := nativeFunction{name: name, fn: fn}
+	for i := range c.costs {
+		cost, ok := c.costs[i][name]
+		if !ok {
+			return 0, errors.Errorf("cost of system function '%s' is not found", name)
 		}
+		nf.costs[i] = cost
 	}
-	return nil
+	_, available := c.expression(name)
+	nf.invocation = !available
+	if len(c.natives) >= math.MaxUint16 {
+		return 0, errors.New("max number of system functions reached")
+	}
+	c.natives = append(c.natives, nf)
+	id := uint16(len(c.natives) - 1)
+	c.nativeIDs[name] = id
+	return id, nil
 }
 
-func (c *compiler) popFunction() error {
-	l := len(c.functions)
-	if l == 0 {
-		return errors.New("failed to pop function from empty stack")
+func (c *compiler) emit(op byte, args ...uint16) {
+	c.code = append(c.code, op)
+	for _, a := range args {
+		c.code = binary.BigEndian.AppendUint16(c.code, a)
 	}
-	var f *localFunction
-	f, c.functions = c.functions[l-1], c.functions[:l-1]
-	c.declarations = append(c.declarations, f)
-	return nil
 }
 
-func (c *compiler) peekFunction() error {
-	l := len(c.functions)
-	if l == 0 {
-		return errors.New("failed to peek function from empty stack")
+// context describes the code from the start position to the current one for errors occurred in it.
+func (c *compiler) context(start int, format string, args ...interface{}) {
+	if start == len(c.code) {
+		return
 	}
-	f := c.functions[l-1]
-	c.declarations = append(c.declarations, f)
-	return nil
+	c.contexts = append(c.contexts, errorContext{start: start, end: len(c.code), message: fmt.Sprintf(format, args...)})
 }
 
-func (c *compiler) lookupFunction(name string) (*localFunction, error) {
-	for i := len(c.functions) - 1; i >= 0; i-- {
-		if c.functions[i].name == name {
-			return c.functions[i], nil
-		}
-	}
-	return nil, errors.Errorf("function '%s' is not declared", name)
+// jump emits jump instruction and returns position of its address to patch later.
+func (c *compiler) jump(op byte) int {
+	c.code = append(c.code, op, 0xff, 0xff, 0xff, 0xff)
+	return len(c.code) - 4
 }
 
-type rideCallable struct {
-	name      string
-	parameter string
+// patch sets the address of the jump to the current position.
+func (c *compiler) patch(pos int) {
+	binary.BigEndian.PutUint32(c.code[pos:], uint32(len(c.code)))
 }
 
 type rideConstants struct {
@@ -521,197 +680,3 @@ func (c *rideConstants) append(value rideType) (uint16, error) {
 	c.items = append(c.items, value)
 	return uint16(len(c.items) - 1), nil
 }
-
-type rideDeclaration interface {
-	buffer() *bytes.Buffer
-	references() []int
-	callable() *rideCallable
-}
-
-type rideValue interface {
-	id() string
-}
-
-type localValue struct {
-	name     string
-	position uint16
-}
-
-func newLocalValue(name string, pos int) *localValue {
-	return &localValue{
-		name:     name,
-		position: uint16(pos),
-	}
-}
-
-func (v *localValue) id() string {
-	return v.name
-}
-
-type globalValue struct {
-	name   string
-	annex  *rideCallable
-	bb     *bytes.Buffer
-	usages []int
-	using  []*globalValue
-}
-
-func newGlobalValue(name string, annex *rideCallable) *globalValue {
-	return &globalValue{
-		name:   name,
-		annex:  annex,
-		bb:     new(bytes.Buffer),
-		usages: make([]int, 0),
-		using:  make([]*globalValue, 0),
-	}
-}
-
-func (v *globalValue) buffer() *bytes.Buffer {
-	return v.bb
-}
-
-func (v *globalValue) references() []int {
-	return v.usages
-}
-
-func (v *globalValue) id() string {
-	return v.name
-}
-
-func (v *globalValue) refer(patch int) {
-	v.usages = append(v.usages, patch)
-}
-
-func (v *globalValue) callable() *rideCallable {
-	return v.annex
-}
-
-type localFunction struct {
-	name  string
-	annex *rideCallable
-	bb    *bytes.Buffer
-	calls []int
-	args  []string
-}
-
-func newLocalFunction(name string, args []string, annex *rideCallable) *localFunction {
-	return &localFunction{
-		name:  name,
-		annex: annex,
-		bb:    new(bytes.Buffer),
-		calls: make([]int, 0),
-		args:  args,
-	}
-}
-
-func (f *localFunction) buffer() *bytes.Buffer {
-	return f.bb
-}
-
-func (f *localFunction) references() []int {
-	return f.calls
-}
-
-func (f *localFunction) call(pos int) {
-	f.calls = append(f.calls, pos)
-}
-
-func (f *localFunction) callable() *rideCallable {
-	return f.annex
-}
-
-type patch struct {
-	id     int
-	origin int
-	pos    int
-	addr   uint16
-}
-
-type patcher struct {
-	count   int
-	buffers map[*bytes.Buffer][]patch
-}
-
-func newPatcher() *patcher {
-	return &patcher{
-		count:   0,
-		buffers: make(map[*bytes.Buffer][]patch),
-	}
-}
-
-func (p *patcher) add(bb *bytes.Buffer) int {
-	pt := patch{
-		id:  p.count,
-		pos: bb.Len(),
-	}
-	if bps, ok := p.buffers[bb]; ok {
-		p.buffers[bb] = append(bps, pt)
-	} else {
-		p.buffers[bb] = []patch{pt}
-	}
-	p.count++
-	return pt.id
-}
-
-func (p *patcher) setOrigin(bb *bytes.Buffer, origin int) {
-	if ps, ok := p.buffers[bb]; ok {
-		for i := range ps {
-			ps[i].origin = origin
-		}
-	}
-}
-
-func (p *patcher) setPosition(id int, addr uint16) {
-	for _, v := range p.buffers {
-		for i := range v {
-			if v[i].id == id {
-				v[i].addr = addr
-			}
-		}
-	}
-}
-
-func (p *patcher) get() (map[int]uint16, error) {
-	r := make(map[int]uint16)
-	for _, v := range p.buffers {
-		for i := range v {
-			abs := v[i].origin + v[i].pos
-			if _, ok := r[abs]; ok {
-				return nil, errors.Errorf("duplicate patch at position %d", abs)
-			}
-			r[abs] = v[i].addr
-		}
-	}
-	return r, nil
-}
-
-func encode(v uint16) []byte {
-	b := make([]byte, 2)
-	binary.BigEndian.PutUint16(b, v)
-	return b
-}
-
-func patchedCode(bb *bytes.Buffer, origin int) []byte {
-	bts := bb.Bytes()
-	i := 0
-	for i < bb.Len() {
-		switch bts[i] {
-		case OpJumpIfFalse, OpJump:
-			addr := bts[i+1 : i+3]
-			update(addr, origin)
-			i += 3
-		case OpPush, OpProperty, OpLoad, OpLoadLocal, OpGlobal:
-			i += 3
-		case OpCall, OpExternalCall:
-			i += 5
-		default:
-			i++
-		}
-	}
-	return bts
-}
-
-func update(b []byte, n int) {
-	v := binary.BigEndian.Uint16(b)
-	binary.BigEndian.PutUint16(b, uint16(int(v)+n))
-}
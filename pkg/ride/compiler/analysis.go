@@ -0,0 +1,239 @@
+package compiler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	s "github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
+)
+
+// Position is a zero-based position in the source code, Column counts characters (runes) in the line.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) before(other Position) bool {
+	return p.Line < other.Line || (p.Line == other.Line && p.Column < other.Column)
+}
+
+// Range is a half-open range of the source code.
+type Range struct {
+	Start Position
+	End   Position
+}
+
+// Contains reports whether the position is inside the range, the end of the range is included
+// to match the position of the cursor right after an identifier.
+func (r Range) Contains(p Position) bool {
+	return !p.before(r.Start) && !r.End.before(p)
+}
+
+// Diagnostic is a compilation error bound to the place in the source code.
+type Diagnostic struct {
+	Range   Range
+	Message string
+}
+
+type SymbolKind byte
+
+const (
+	SymbolVariable SymbolKind = iota + 1
+	SymbolArgument
+	SymbolFunction
+)
+
+// Symbol is a declaration of a variable, function argument or function in the source code.
+type Symbol struct {
+	Name string
+	Kind SymbolKind
+	// Type of the variable or the return type of the function.
+	Type s.Type
+	// Arguments of the function, names and types are in the same order.
+	ArgumentNames []string
+	ArgumentTypes []s.Type
+	// Range of the name of the symbol.
+	Range Range
+	// Scope is the part of the code where the symbol is visible.
+	Scope Range
+}
+
+type ReferenceKind byte
+
+const (
+	ReferenceVariable ReferenceKind = iota + 1
+	ReferenceFunction
+	ReferenceField
+)
+
+// Reference is a usage of a variable, function or object field in the source code.
+type Reference struct {
+	Name string
+	Kind ReferenceKind
+	// Type of the variable or field, the return type of the function.
+	Type  s.Type
+	Range Range
+	// Declaration is nil for the variables and functions of the standard library and for the object fields.
+	Declaration *Symbol
+}
+
+// Analysis is the result of the source code analysis.
+type Analysis struct {
+	// Tree is set only if the code compiled without errors.
+	Tree        *ast.Tree
+	LibVersion  ast.LibraryVersion
+	Diagnostics []Diagnostic
+	Symbols     []*Symbol
+	References  []Reference
+	// Globals are the built-in variables available to the script, like `height` or `this`.
+	Globals []s.Variable
+}
+
+// Analyze compiles the code and collects the declarations and references found in the code along with the
+// compilation errors. On syntax error only the error is returned because the syntax tree is not built.
+func Analyze(code string) *Analysis {
+	pp := Parser{Buffer: code}
+	if err := pp.Init(); err != nil {
+		return &Analysis{Diagnostics: []Diagnostic{{Message: err.Error()}}}
+	}
+	positions := newPositionTable(pp.buffer)
+	if err := pp.Parse(); err != nil {
+		d := Diagnostic{Message: err.Error()}
+		if pe, ok := err.(*parseError); ok {
+			d.Message = "Syntax error"
+			d.Range = positions.rangeOf(pe.max)
+		}
+		return &Analysis{Diagnostics: []Diagnostic{d}}
+	}
+	ap := newASTParser(pp.AST(), pp.buffer)
+	ap.symbols = &symbolCollector{positions: positions}
+	ap.parse()
+	res := &Analysis{
+		LibVersion:  ap.tree.LibVersion,
+		Diagnostics: make([]Diagnostic, 0, len(ap.errorsList)),
+		Symbols:     ap.symbols.symbols,
+		References:  ap.symbols.references,
+	}
+	for i, v := range ap.stack.vars {
+		if ap.stack.varSymbols[i] == nil && !strings.HasPrefix(v.Name, "$") {
+			res.Globals = append(res.Globals, v)
+		}
+	}
+	for _, err := range ap.errorsList {
+		d := Diagnostic{Message: err.Error()}
+		if ae, ok := err.(*astError); ok {
+			d.Message = ae.msg
+			if ae.prefix == "" { // Errors in imported files are reported at the beginning of the code
+				d.Range = positions.rangeOf(ae.token)
+			}
+		}
+		res.Diagnostics = append(res.Diagnostics, d)
+	}
+	if len(res.Diagnostics) == 0 {
+		res.Tree = ap.tree
+	}
+	return res
+}
+
+// ReferenceAt returns the reference located at the position.
+func (a *Analysis) ReferenceAt(p Position) (Reference, bool) {
+	for _, r := range a.References {
+		if r.Range.Contains(p) {
+			return r, true
+		}
+	}
+	return Reference{}, false
+}
+
+// SymbolAt returns the symbol declared or referenced at the position.
+func (a *Analysis) SymbolAt(p Position) (*Symbol, bool) {
+	for _, sym := range a.Symbols {
+		if sym.Range.Contains(p) {
+			return sym, true
+		}
+	}
+	if r, ok := a.ReferenceAt(p); ok && r.Declaration != nil {
+		return r.Declaration, true
+	}
+	return nil, false
+}
+
+// VisibleSymbols returns the symbols declared before the position and visible at it.
+func (a *Analysis) VisibleSymbols(p Position) []*Symbol {
+	var res []*Symbol
+	for _, sym := range a.Symbols {
+		if sym.Range.End.before(p) && sym.Scope.Contains(p) {
+			res = append(res, sym)
+		}
+	}
+	return res
+}
+
+// symbolCollector records declarations and references while the AST is built.
+type symbolCollector struct {
+	positions  *positionTable
+	symbols    []*Symbol
+	references []Reference
+	scopes     []token32
+}
+
+func (c *symbolCollector) pushScope(t token32) {
+	c.scopes = append(c.scopes, t)
+}
+
+func (c *symbolCollector) popScope() {
+	if len(c.scopes) > 0 {
+		c.scopes = c.scopes[:len(c.scopes)-1]
+	}
+}
+
+func (c *symbolCollector) declare(kind SymbolKind, name string, t s.Type, token token32) *Symbol {
+	sym := &Symbol{Name: name, Kind: kind, Type: t, Range: c.positions.rangeOf(token)}
+	if len(c.scopes) > 0 {
+		sym.Scope = c.positions.rangeOf(c.scopes[len(c.scopes)-1])
+	} else {
+		sym.Scope = c.positions.all()
+	}
+	c.symbols = append(c.symbols, sym)
+	return sym
+}
+
+func (c *symbolCollector) reference(kind ReferenceKind, name string, t s.Type, token token32, decl *Symbol) {
+	c.references = append(c.references, Reference{
+		Name:        name,
+		Kind:        kind,
+		Type:        t,
+		Range:       c.positions.rangeOf(token),
+		Declaration: decl,
+	})
+}
+
+// positionTable translates offsets in the buffer of runes to the line and column positions.
+type positionTable struct {
+	lineStarts []int
+	length     int
+}
+
+func newPositionTable(buffer []rune) *positionTable {
+	t := &positionTable{lineStarts: []int{0}, length: len(buffer)}
+	for i, c := range buffer {
+		if c == '\n' {
+			t.lineStarts = append(t.lineStarts, i+1)
+		}
+	}
+	return t
+}
+
+func (t *positionTable) position(offset int) Position {
+	line := sort.Search(len(t.lineStarts), func(i int) bool { return t.lineStarts[i] > offset }) - 1
+	return Position{Line: line, Column: offset - t.lineStarts[line]}
+}
+
+func (t *positionTable) rangeOf(token token32) Range {
+	return Range{Start: t.position(int(token.begin)), End: t.position(int(token.end))}
+}
+
+func (t *positionTable) all() Range {
+	return Range{End: t.position(t.length)}
+}
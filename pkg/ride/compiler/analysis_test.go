@@ -0,0 +1,96 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+func TestAnalyze(t *testing.T) {
+	src := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let threshold = 10
+
+func check(amount: Int) = amount > threshold
+
+@Callable(i)
+func deposit(amount: Int) = {
+    let valid = check(amount)
+    if (valid) then [IntegerEntry(toBase58String(i.caller.bytes), amount)] else throw("invalid")
+}
+`
+	a := Analyze(src)
+	require.Empty(t, a.Diagnostics)
+	require.NotNil(t, a.Tree)
+	assert.Equal(t, ast.LibV6, a.LibVersion)
+
+	threshold, ok := a.SymbolAt(Position{Line: 4, Column: 6})
+	require.True(t, ok)
+	assert.Equal(t, "threshold", threshold.Name)
+	assert.Equal(t, SymbolVariable, threshold.Kind)
+	assert.Equal(t, "Int", threshold.Type.String())
+	assert.Equal(t, Range{Start: Position{Line: 4, Column: 4}, End: Position{Line: 4, Column: 13}}, threshold.Range)
+
+	// Reference to the global variable from the function body.
+	r, ok := a.ReferenceAt(Position{Line: 6, Column: 40})
+	require.True(t, ok)
+	assert.Equal(t, ReferenceVariable, r.Kind)
+	assert.Same(t, threshold, r.Declaration)
+
+	// Call of the user function.
+	check, ok := a.SymbolAt(Position{Line: 10, Column: 17})
+	require.True(t, ok)
+	assert.Equal(t, SymbolFunction, check.Kind)
+	assert.Equal(t, []string{"amount"}, check.ArgumentNames)
+	assert.Equal(t, "Boolean", check.Type.String())
+	assert.Equal(t, 6, check.Range.Start.Line)
+
+	// Arguments of the functions with the same name are different symbols.
+	amount, ok := a.SymbolAt(Position{Line: 10, Column: 24})
+	require.True(t, ok)
+	assert.Equal(t, SymbolArgument, amount.Kind)
+	assert.Equal(t, 9, amount.Range.Start.Line)
+
+	// Field of the invocation.
+	r, ok = a.ReferenceAt(Position{Line: 11, Column: 55})
+	require.True(t, ok)
+	assert.Equal(t, ReferenceField, r.Kind)
+	assert.Equal(t, "caller", r.Name)
+	assert.Equal(t, "Address", r.Type.String())
+
+	r, ok = a.ReferenceAt(Position{Line: 11, Column: 35})
+	require.True(t, ok)
+	assert.Equal(t, ReferenceFunction, r.Kind)
+	assert.Equal(t, "toBase58String", r.Name)
+	assert.Nil(t, r.Declaration)
+
+	names := func(symbols []*Symbol) []string {
+		res := make([]string, len(symbols))
+		for i, s := range symbols {
+			res[i] = s.Name
+		}
+		return res
+	}
+	assert.ElementsMatch(t, []string{"threshold", "check", "i", "deposit", "amount", "valid"},
+		names(a.VisibleSymbols(Position{Line: 11, Column: 4})))
+	// Callable functions and their arguments are not visible outside.
+	assert.ElementsMatch(t, []string{"threshold", "check"}, names(a.VisibleSymbols(Position{Line: 13, Column: 0})))
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	a := Analyze("{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\nlet x = y\nx == 1")
+	assert.Nil(t, a.Tree)
+	require.NotEmpty(t, a.Diagnostics)
+	assert.Equal(t, "Variable 'y' doesn't exist", a.Diagnostics[0].Message)
+	assert.Equal(t, Range{Start: Position{Line: 2, Column: 8}, End: Position{Line: 2, Column: 9}}, a.Diagnostics[0].Range)
+
+	a = Analyze("{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\nlet x = \n")
+	assert.Nil(t, a.Tree)
+	require.Len(t, a.Diagnostics, 1)
+	assert.Equal(t, "Syntax error", a.Diagnostics[0].Message)
+}
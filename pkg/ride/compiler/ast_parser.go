@@ -41,6 +41,7 @@ const (
 
 type astError struct {
 	msg    string
+	token  token32
 	begin  textPosition
 	end    textPosition
 	prefix string
@@ -51,7 +52,7 @@ func newASTError(msg string, token token32, buffer []rune, prefix string) error
 	end := int(token.end)
 	positions := []int{begin, end}
 	translations := translatePositions(buffer, positions)
-	return &astError{msg: msg, begin: translations[begin], end: translations[end], prefix: prefix}
+	return &astError{msg: msg, token: token, begin: translations[begin], end: translations[end], prefix: prefix}
 }
 
 func (e *astError) Error() string {
@@ -79,6 +80,9 @@ type astParser struct {
 	importPaths []importPath
 	isLibrary   bool
 	fileName    string
+
+	anyResultType bool             // Allows the expression to return a value of any type, not only Boolean.
+	symbols       *symbolCollector // Collects declarations and references, set only for the code analysis.
 }
 
 func newASTParser(node *node32, buffer []rune) astParser {
@@ -270,7 +274,7 @@ func (p *astParser) ruleScriptRootHandler(node *node32) {
 		p.addError(curNode.token32, "No expression defined")
 		return
 	}
-	if !s.BooleanType.Equal(varType) {
+	if !p.anyResultType && !s.BooleanType.Equal(varType) {
 		p.addError(curNode.token32, "Script should return 'Boolean', but '%s' returned", varType)
 		return
 	}
@@ -490,6 +494,7 @@ func (p *astParser) simpleVariableDeclaration(node *node32) (ast.Node, s.Type) {
 	curNode := skipToNextRule(node.up)
 	// get Variable Name
 	varName := p.nodeValue(curNode)
+	nameNode := curNode
 	curNode = skipToNextRule(curNode.next)
 	expr, varType := p.ruleExprHandler(curNode)
 	if expr == nil {
@@ -500,10 +505,10 @@ func (p *astParser) simpleVariableDeclaration(node *node32) (ast.Node, s.Type) {
 		return nil, nil
 	}
 	expr = ast.NewAssignmentNode(varName, expr, nil)
-	p.stack.pushVariable(s.Variable{
+	p.stack.pushVariableSymbol(s.Variable{
 		Name: varName,
 		Type: varType,
-	})
+	}, p.declareSymbol(SymbolVariable, varName, varType, nameNode))
 	return expr, varType
 }
 
@@ -1392,6 +1397,9 @@ func (p *astParser) ruleIdentifierHandler(node *node32) (ast.Node, s.Type) {
 		p.addError(node.token32, "Variable '%s' doesn't exist", name)
 		return nil, nil
 	}
+	if p.symbols != nil {
+		p.symbols.reference(ReferenceVariable, name, v.Type, node.token32, p.stack.variableSymbol(name))
+	}
 	return ast.NewReferenceNode(name), v.Type
 }
 
@@ -1437,11 +1445,17 @@ func (p *astParser) ruleFunctionCallHandler(node *node32, firstArg ast.Node, fir
 				return nil, nil
 			}
 		}
+		if p.symbols != nil {
+			p.symbols.reference(ReferenceFunction, funcName, funcSign.ReturnType, nameNode.token32, nil)
+		}
 		if argsNodes == nil {
 			argsNodes = []ast.Node{}
 		}
 		return ast.NewFunctionCallNode(funcSign.ID, argsNodes), funcSign.ReturnType
 	}
+	if p.symbols != nil {
+		p.symbols.reference(ReferenceFunction, funcName, funcSign.ReturnType, nameNode.token32, p.stack.functionSymbol(funcName))
+	}
 	if len(argsNodes) != len(funcSign.Arguments) {
 		p.addError(curNode.token32, "Function '%s' requires %d arguments, but %d are provided", funcName, len(funcSign.Arguments), len(argsNodes))
 		return nil, funcSign.ReturnType
@@ -1491,12 +1505,17 @@ func (p *astParser) ruleIdentifierAccessHandler(node *node32, obj ast.Node, objT
 		p.addError(curNode.token32, "Type '%s' has not filed '%s'", objType.String(), fieldName)
 		return nil, nil
 	}
+	if p.symbols != nil {
+		p.symbols.reference(ReferenceField, fieldName, fieldType, curNode.token32, nil)
+	}
 	return ast.NewPropertyNode(fieldName, obj), fieldType
 
 }
 
 func (p *astParser) ruleBlockHandler(node *node32) (ast.Node, s.Type) {
 	p.stack.addFrame()
+	p.pushScope(node)
+	defer p.popScope()
 	curNode := node.up
 	var decls []ast.Node
 	for {
@@ -1532,8 +1551,10 @@ func (p *astParser) ruleBlockHandler(node *node32) (ast.Node, s.Type) {
 
 func (p *astParser) ruleFuncHandler(node *node32) (ast.Node, s.Type, []s.Type) {
 	p.stack.addFrame()
+	p.pushScope(node)
 	curNode := skipToNextRule(node.up)
 	funcName := p.nodeValue(curNode)
+	nameNode := curNode
 	if _, ok := p.stack.function(funcName); ok {
 		p.addError(curNode.token32, "Function '%s' already exists", funcName)
 	}
@@ -1554,15 +1575,21 @@ func (p *astParser) ruleFuncHandler(node *node32) (ast.Node, s.Type, []s.Type) {
 	}
 	argsNames, argsTypes := p.ruleFuncArgSeqHandler(argsNode)
 	expr, varType := p.ruleExprHandler(curNode)
+	p.popScope()
 	if argsTypes == nil || expr == nil {
 		return nil, nil, nil
 	}
 	p.stack.dropFrame()
-	p.stack.pushFunc(s.FunctionParams{
+	sym := p.declareSymbol(SymbolFunction, funcName, varType, nameNode)
+	if sym != nil {
+		sym.ArgumentNames = argsNames
+		sym.ArgumentTypes = argsTypes
+	}
+	p.stack.pushFuncSymbol(s.FunctionParams{
 		ID:         ast.UserFunction(funcName),
 		Arguments:  argsTypes,
 		ReturnType: varType,
-	})
+	}, sym)
 
 	if len(argsNames) == 0 {
 		return &ast.FunctionDeclarationNode{
@@ -1614,10 +1641,10 @@ func (p *astParser) ruleFuncArgHandler(node *node32) (string, s.Type) {
 	if argType == nil {
 		return "", nil
 	}
-	p.stack.pushVariable(s.Variable{
+	p.stack.pushVariableSymbol(s.Variable{
 		Name: argName,
 		Type: argType,
-	})
+	}, p.declareSymbol(SymbolArgument, argName, argType, node.up))
 	return argName, argType
 }
 
@@ -1818,6 +1845,10 @@ func (p *astParser) loadMetaBeforeV6(name string, argsTypes []s.Type) error {
 
 func (p *astParser) ruleAnnotatedFunc(node *node32) {
 	p.stack.addFrame()
+	if funcNode := skipToNextRule(node.next); funcNode != nil {
+		p.pushScope(&node32{token32: token32{begin: node.begin, end: funcNode.end}})
+		defer p.popScope()
+	}
 	curNode := node
 	annotation, annotationParameter := p.ruleAnnotationSeqHandler(curNode)
 	if annotation == "" {
@@ -1877,6 +1908,7 @@ func (p *astParser) ruleAnnotationSeqHandler(node *node32) (string, string) {
 	annotationNode = skipToNextRule(annotationNode)
 	annotationNode = annotationNode.next.up
 	varName := p.nodeValue(annotationNode)
+	varNode := annotationNode
 	annotationNode = annotationNode.next
 	if annotationNode != nil {
 		p.addError(annotationNode.token32, "More then one variable in annotation '%s'", name)
@@ -1888,17 +1920,18 @@ func (p *astParser) ruleAnnotationSeqHandler(node *node32) (string, string) {
 
 	switch name {
 	case "Callable":
-		p.stack.pushVariable(s.Variable{
+		t := s.SimpleType{Type: "Invocation"}
+		p.stack.pushVariableSymbol(s.Variable{
 			Name: varName,
-			Type: s.SimpleType{Type: "Invocation"},
-		})
+			Type: t,
+		}, p.declareSymbol(SymbolArgument, varName, t, varNode))
 	case "Verifier":
 		txType := p.stdTypes["Transaction"].(s.UnionType)
 		txType.AppendType(s.SimpleType{Type: "Order"})
-		p.stack.pushVariable(s.Variable{
+		p.stack.pushVariableSymbol(s.Variable{
 			Name: varName,
 			Type: txType,
-		})
+		}, p.declareSymbol(SymbolArgument, varName, txType, varNode))
 	}
 	return name, varName
 }
@@ -2443,3 +2476,22 @@ func skipToNextRule(node *node32) *node32 {
 		node = node.next
 	}
 }
+
+func (p *astParser) declareSymbol(kind SymbolKind, name string, t s.Type, node *node32) *Symbol {
+	if p.symbols == nil {
+		return nil
+	}
+	return p.symbols.declare(kind, name, t, node.token32)
+}
+
+func (p *astParser) pushScope(node *node32) {
+	if p.symbols != nil {
+		p.symbols.pushScope(node.token32)
+	}
+}
+
+func (p *astParser) popScope() {
+	if p.symbols != nil {
+		p.symbols.popScope()
+	}
+}
@@ -8,6 +8,16 @@ import (
 //go:generate peg -output=parser.peg.go ride.peg
 
 func CompileToTree(code string) (*ast.Tree, []error) {
+	return compileToTree(code, false)
+}
+
+// CompileEvaluatedExpression compiles the expression for evaluation, unlike the expression script
+// the result of such expression may be of any type.
+func CompileEvaluatedExpression(code string) (*ast.Tree, []error) {
+	return compileToTree(code, true)
+}
+
+func compileToTree(code string, anyResultType bool) (*ast.Tree, []error) {
 	pp := Parser{Buffer: code}
 	err := pp.Init()
 	if err != nil {
@@ -18,6 +28,7 @@ func CompileToTree(code string) (*ast.Tree, []error) {
 		return nil, []error{err}
 	}
 	ap := newASTParser(pp.AST(), pp.buffer)
+	ap.anyResultType = anyResultType
 	ap.parse()
 	if len(ap.errorsList) > 0 {
 		return nil, ap.errorsList
@@ -14,6 +14,9 @@ type stack struct {
 	frames []frame
 	vars   []stdlib.Variable
 	funcs  []stdlib.FunctionParams
+	// Declarations of variables and functions in the source code, nil for the built-in ones.
+	varSymbols  []*Symbol
+	funcSymbols []*Symbol
 }
 
 func newStack() *stack {
@@ -38,15 +41,27 @@ func (s *stack) dropFrame() {
 		f, s.frames = s.frames[len(s.frames)-1], s.frames[:len(s.frames)-1]
 		s.vars = s.vars[:f.varsIndex]
 		s.funcs = s.funcs[:f.funcsIndex]
+		s.varSymbols = s.varSymbols[:f.varsIndex]
+		s.funcSymbols = s.funcSymbols[:f.funcsIndex]
 	}
 }
 
 func (s *stack) pushVariable(variable stdlib.Variable) {
+	s.pushVariableSymbol(variable, nil)
+}
+
+func (s *stack) pushVariableSymbol(variable stdlib.Variable, symbol *Symbol) {
 	s.vars = append(s.vars, variable)
+	s.varSymbols = append(s.varSymbols, symbol)
 }
 
 func (s *stack) pushFunc(f stdlib.FunctionParams) {
+	s.pushFuncSymbol(f, nil)
+}
+
+func (s *stack) pushFuncSymbol(f stdlib.FunctionParams, symbol *Symbol) {
 	s.funcs = append(s.funcs, f)
+	s.funcSymbols = append(s.funcSymbols, symbol)
 }
 
 func (s *stack) variable(name string) (stdlib.Variable, bool) {
@@ -58,6 +73,16 @@ func (s *stack) variable(name string) (stdlib.Variable, bool) {
 	return stdlib.Variable{}, false
 }
 
+// variableSymbol returns the declaration of the variable visible in the current frame.
+func (s *stack) variableSymbol(name string) *Symbol {
+	for i := len(s.vars) - 1; i >= 0; i-- {
+		if name == s.vars[i].Name {
+			return s.varSymbols[i]
+		}
+	}
+	return nil
+}
+
 func (s *stack) topMatchName() (string, bool) {
 	for i := len(s.vars) - 1; i >= 0; i-- {
 		if strings.HasPrefix(s.vars[i].Name, "$match") {
@@ -75,3 +100,13 @@ func (s *stack) function(name string) (stdlib.FunctionParams, bool) {
 	}
 	return stdlib.FunctionParams{}, false
 }
+
+// functionSymbol returns the declaration of the function visible in the current frame.
+func (s *stack) functionSymbol(name string) *Symbol {
+	for i := len(s.funcs) - 1; i >= 0; i-- {
+		if name == s.funcs[i].ID.Name() {
+			return s.funcSymbols[i]
+		}
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ type complexityCalculator interface {
 	testPropertyComplexity() (bool, int)
 	addPropertyComplexity()
 	setLimit(limit uint32)
+	clone() complexityCalculator
 }
 
 func newComplexityCalculator(lib ast.LibraryVersion, limit uint32) complexityCalculator {
@@ -120,6 +121,11 @@ func (cc *complexityCalculatorV1) setLimit(limit uint32) {
 	cc.l = int(limit)
 }
 
+func (cc *complexityCalculatorV1) clone() complexityCalculator {
+	c := *cc
+	return &c
+}
+
 type complexityCalculatorV2 struct {
 	o bool
 	c int
@@ -202,3 +208,8 @@ func (cc *complexityCalculatorV2) addPropertyComplexity() {}
 func (cc *complexityCalculatorV2) setLimit(limit uint32) {
 	cc.l = int(limit)
 }
+
+func (cc *complexityCalculatorV2) clone() complexityCalculator {
+	c := *cc
+	return &c
+}
@@ -1122,6 +1122,33 @@ func convertArgument(arg proto.Argument) (rideType, error) {
 	}
 }
 
+// convertToProtoArguments is the reverse of convertProtoArguments, error is returned for the values of types which
+// can't be passed as arguments of invoke transaction.
+func convertToProtoArguments(args rideList) (proto.Arguments, error) {
+	r := make(proto.Arguments, len(args))
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case rideInt:
+			r[i] = &proto.IntegerArgument{Value: int64(a)}
+		case rideBoolean:
+			r[i] = &proto.BooleanArgument{Value: bool(a)}
+		case rideString:
+			r[i] = &proto.StringArgument{Value: string(a)}
+		case rideByteVector:
+			r[i] = &proto.BinaryArgument{Value: common.Dup(a)}
+		case rideList:
+			items, err := convertToProtoArguments(a)
+			if err != nil {
+				return nil, err
+			}
+			r[i] = &proto.ListArgument{Items: items}
+		default:
+			return nil, EvaluationFailure.Errorf("unsupported argument type '%s'", arg.instanceOf())
+		}
+	}
+	return r, nil
+}
+
 func invocationToObject(rideVersion ast.LibraryVersion, scheme byte, tx proto.Transaction) (rideType, error) {
 	var (
 		senderPK crypto.PublicKey
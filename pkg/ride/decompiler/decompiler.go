@@ -0,0 +1,515 @@
+// Package decompiler renders parsed Ride scripts back to the source code.
+//
+// The result is intended for reading and auditing of scripts. It's not always possible to compile it back,
+// because some information is lost during the compilation. For example, types of user function arguments are not
+// stored in the script, and the compiler expands syntactic constructions like `match` or `FOLD` into the plain
+// expressions with generated names.
+package decompiler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
+	"github.com/wavesplatform/gowaves/pkg/ride/meta"
+)
+
+const (
+	compactNameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	indentation      = "    "
+	maxBase58Length  = 64
+	firstTupleID     = 1300
+	lastTupleID      = 1320
+)
+
+var binaryOperators = map[string]string{
+	"0":    "==",
+	"!=":   "!=",
+	"100":  "+",
+	"101":  "-",
+	"102":  ">",
+	"103":  ">=",
+	"104":  "*",
+	"105":  "/",
+	"106":  "%",
+	"203":  "+",
+	"300":  "+",
+	"311":  "+",
+	"312":  "-",
+	"313":  "*",
+	"314":  "/",
+	"315":  "%",
+	"319":  ">",
+	"320":  ">=",
+	"1100": "::",
+	"1101": ":+",
+	"1102": "++",
+}
+
+var unaryOperators = map[string]string{
+	"!":   "!",
+	"-":   "-",
+	"318": "-",
+}
+
+var specialFunctionNames = map[string]string{
+	"1": "_isInstanceOf",
+}
+
+// functionNames maps identifiers of functions to their names in Ride source code.
+var functionNames = buildFunctionNames()
+
+func buildFunctionNames() map[string]string {
+	res := make(map[string]string)
+	for _, sigs := range stdlib.FuncsByVersion() {
+		for name, overloads := range sigs.Funcs {
+			for _, o := range overloads {
+				res[o.ID.Name()] = name
+			}
+		}
+	}
+	for id, name := range specialFunctionNames {
+		res[id] = name
+	}
+	return res
+}
+
+// FunctionName returns the name of the standard library function or operator by its identifier.
+// The identifier itself is returned for unknown functions.
+func FunctionName(id string) string {
+	if op, ok := binaryOperators[id]; ok {
+		return op
+	}
+	if op, ok := unaryOperators[id]; ok {
+		return op
+	}
+	if name, ok := functionNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// Decompile renders the tree as Ride source code.
+func Decompile(tree *ast.Tree) (string, error) {
+	d := &decompiler{tree: tree, originals: originalNames(tree)}
+	if err := d.script(); err != nil {
+		return "", err
+	}
+	return d.sb.String(), nil
+}
+
+type decompiler struct {
+	tree      *ast.Tree
+	originals map[string]string
+	sb        strings.Builder
+}
+
+func (d *decompiler) write(s ...string) {
+	for _, x := range s {
+		d.sb.WriteString(x)
+	}
+}
+
+func (d *decompiler) newLine(indent int) {
+	d.sb.WriteByte('\n')
+	d.sb.WriteString(strings.Repeat(indentation, indent))
+}
+
+// name restores the original name if the script was compacted.
+func (d *decompiler) name(n string) string {
+	if original, err := d.tree.Meta.Abbreviations.CompactToOriginal(n); err == nil {
+		return original
+	}
+	if original, ok := d.originals[n]; ok {
+		return original
+	}
+	return n
+}
+
+// originalNames restores the mapping of compact names to the original ones if the script was compacted
+// in the format that stores only the original names. Compact names are generated sequentially skipping the
+// names of callable functions, which are never compacted.
+func originalNames(tree *ast.Tree) map[string]string {
+	names := tree.Meta.Abbreviations.OriginalNames()
+	if len(names) == 0 {
+		return nil
+	}
+	callables := make(map[string]struct{}, len(tree.Functions))
+	for _, n := range tree.Functions {
+		if fn, ok := n.(*ast.FunctionDeclarationNode); ok {
+			callables[fn.Name] = struct{}{}
+		}
+	}
+	res := make(map[string]string, len(names))
+	counter := 0
+	for _, original := range names {
+		compact := compactName(counter)
+		for _, ok := callables[compact]; ok; _, ok = callables[compact] {
+			counter++
+			compact = compactName(counter)
+		}
+		res[compact] = original
+		counter++
+	}
+	return res
+}
+
+// compactName generates the compact name by its index the same way the compiler does.
+func compactName(n int) string {
+	l := len(compactNameChars)
+	name := ""
+	for n >= l {
+		name = string(compactNameChars[n%l]) + name
+		n = n/l - 1
+	}
+	return string(compactNameChars[n]) + name
+}
+
+func (d *decompiler) script() error {
+	d.write("{-# STDLIB_VERSION ", strconv.Itoa(int(d.tree.LibVersion)), " #-}\n")
+	if !d.tree.IsDApp() {
+		d.write("{-# CONTENT_TYPE EXPRESSION #-}\n")
+		if d.tree.Verifier == nil {
+			return errors.New("empty expression")
+		}
+		if err := d.block(d.tree.Verifier, 0); err != nil {
+			return err
+		}
+		d.write("\n")
+		return nil
+	}
+	d.write("{-# CONTENT_TYPE DAPP #-}\n")
+	d.write("{-# SCRIPT_TYPE ACCOUNT #-}\n")
+	for _, n := range d.tree.Declarations {
+		d.write("\n")
+		if err := d.declaration(n, 0); err != nil {
+			return err
+		}
+		d.write("\n")
+	}
+	for i, n := range d.tree.Functions {
+		fn, ok := n.(*ast.FunctionDeclarationNode)
+		if !ok {
+			return errors.Errorf("unexpected callable function node type %T", n)
+		}
+		var types []meta.Type
+		if i < len(d.tree.Meta.Functions) {
+			types = d.tree.Meta.Functions[i].Arguments
+		}
+		d.write("\n@Callable(", d.name(fn.InvocationParameter), ")\n")
+		if err := d.function(fn, types, 0); err != nil {
+			return err
+		}
+		d.write("\n")
+	}
+	if d.tree.HasVerifier() {
+		fn, ok := d.tree.Verifier.(*ast.FunctionDeclarationNode)
+		if !ok {
+			return errors.Errorf("unexpected verifier function node type %T", d.tree.Verifier)
+		}
+		d.write("\n@Verifier(", d.name(fn.InvocationParameter), ")\n")
+		if err := d.function(fn, nil, 0); err != nil {
+			return err
+		}
+		d.write("\n")
+	}
+	return nil
+}
+
+// declaration renders single declaration without the following block.
+func (d *decompiler) declaration(n ast.Node, indent int) error {
+	switch tn := n.(type) {
+	case *ast.AssignmentNode:
+		d.write("let ", d.name(tn.Name), " = ")
+		return d.expression(tn.Expression, indent)
+	case *ast.FunctionDeclarationNode:
+		return d.function(tn, nil, indent)
+	default:
+		return errors.Errorf("unexpected declaration node type %T", n)
+	}
+}
+
+func (d *decompiler) function(fn *ast.FunctionDeclarationNode, types []meta.Type, indent int) error {
+	args := make([]string, len(fn.Arguments))
+	for i, a := range fn.Arguments {
+		args[i] = d.name(a)
+		if i < len(types) {
+			args[i] += ": " + typeName(types[i])
+		}
+	}
+	d.write("func ", d.name(fn.Name), "(", strings.Join(args, ", "), ") = ")
+	return d.expression(fn.Body, indent)
+}
+
+// block renders the sequence of declarations followed by an expression, each on a separate line.
+func (d *decompiler) block(n ast.Node, indent int) error {
+	for {
+		var next ast.Node
+		switch tn := n.(type) {
+		case *ast.AssignmentNode:
+			next = tn.Block
+		case *ast.FunctionDeclarationNode:
+			next = tn.Block
+		default:
+			return d.expression(n, indent)
+		}
+		if err := d.declaration(n, indent); err != nil {
+			return err
+		}
+		d.newLine(indent)
+		n = next
+	}
+}
+
+func (d *decompiler) expression(n ast.Node, indent int) error {
+	switch tn := n.(type) {
+	case *ast.LongNode:
+		d.write(strconv.FormatInt(tn.Value, 10))
+	case *ast.BooleanNode:
+		d.write(strconv.FormatBool(tn.Value))
+	case *ast.StringNode:
+		d.write(quote(tn.Value))
+	case *ast.BytesNode:
+		d.write(bytesLiteral(tn.Value))
+	case *ast.ReferenceNode:
+		d.write(d.name(tn.Name))
+	case *ast.PropertyNode:
+		if err := d.operand(tn.Object, indent); err != nil {
+			return err
+		}
+		d.write(".", tn.Name)
+	case *ast.ConditionalNode:
+		return d.conditional(tn, indent)
+	case *ast.FunctionCallNode:
+		return d.call(tn, indent)
+	case *ast.AssignmentNode, *ast.FunctionDeclarationNode:
+		d.write("{")
+		d.newLine(indent + 1)
+		if err := d.block(n, indent+1); err != nil {
+			return err
+		}
+		d.newLine(indent)
+		d.write("}")
+	default:
+		return errors.Errorf("unexpected node type %T", n)
+	}
+	return nil
+}
+
+// operand renders expression that is used as an operand of operator or as an object of property access.
+// Conditional expressions and operators are wrapped in parentheses.
+func (d *decompiler) operand(n ast.Node, indent int) error {
+	if isComplex(n) {
+		d.write("(")
+		if err := d.expression(n, indent); err != nil {
+			return err
+		}
+		d.write(")")
+		return nil
+	}
+	if c, ok := n.(*ast.LongNode); ok && c.Value < 0 {
+		d.write("(", strconv.FormatInt(c.Value, 10), ")")
+		return nil
+	}
+	return d.expression(n, indent)
+}
+
+func isComplex(n ast.Node) bool {
+	switch tn := n.(type) {
+	case *ast.ConditionalNode:
+		return true
+	case *ast.FunctionCallNode:
+		id := tn.Function.Name()
+		if _, ok := binaryOperators[id]; ok && len(tn.Arguments) == 2 {
+			return id != "1100" || !isList(tn)
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func (d *decompiler) conditional(n *ast.ConditionalNode, indent int) error {
+	if b, ok := n.FalseExpression.(*ast.BooleanNode); ok && !b.Value {
+		return d.binary("&&", n.Condition, n.TrueExpression, indent)
+	}
+	if b, ok := n.TrueExpression.(*ast.BooleanNode); ok && b.Value {
+		return d.binary("||", n.Condition, n.FalseExpression, indent)
+	}
+	d.write("if (")
+	if err := d.expression(n.Condition, indent); err != nil {
+		return err
+	}
+	d.write(")")
+	d.newLine(indent + 1)
+	d.write("then ")
+	if err := d.expression(n.TrueExpression, indent+1); err != nil {
+		return err
+	}
+	d.newLine(indent + 1)
+	d.write("else ")
+	return d.expression(n.FalseExpression, indent+1)
+}
+
+func (d *decompiler) binary(op string, left, right ast.Node, indent int) error {
+	if err := d.operand(left, indent); err != nil {
+		return err
+	}
+	d.write(" ", op, " ")
+	return d.operand(right, indent)
+}
+
+func (d *decompiler) call(n *ast.FunctionCallNode, indent int) error {
+	id := n.Function.Name()
+	if op, ok := binaryOperators[id]; ok && len(n.Arguments) == 2 {
+		if id == "1100" {
+			if isList(n) {
+				return d.list(n, indent)
+			}
+		}
+		return d.binary(op, n.Arguments[0], n.Arguments[1], indent)
+	}
+	if op, ok := unaryOperators[id]; ok && len(n.Arguments) == 1 {
+		d.write(op)
+		return d.operand(n.Arguments[0], indent)
+	}
+	if _, ok := n.Function.(ast.NativeFunction); ok {
+		if num, err := strconv.Atoi(id); err == nil && num >= firstTupleID && num <= lastTupleID {
+			return d.arguments(n.Arguments, indent)
+		}
+		if id == "401" && len(n.Arguments) == 2 {
+			if err := d.operand(n.Arguments[0], indent); err != nil {
+				return err
+			}
+			d.write("[")
+			if err := d.expression(n.Arguments[1], indent); err != nil {
+				return err
+			}
+			d.write("]")
+			return nil
+		}
+	}
+	d.write(d.functionName(n.Function))
+	return d.arguments(n.Arguments, indent)
+}
+
+// listItems unrolls the chain of `cons` calls. The last returned node is the tail of the chain.
+func listItems(n ast.Node) ([]ast.Node, ast.Node) {
+	var items []ast.Node
+	for {
+		c, ok := n.(*ast.FunctionCallNode)
+		if !ok || c.Function.Name() != "1100" || len(c.Arguments) != 2 {
+			return items, n
+		}
+		items = append(items, c.Arguments[0])
+		n = c.Arguments[1]
+	}
+}
+
+// isList checks that the node is a chain of `cons` calls terminated by `nil`.
+func isList(n ast.Node) bool {
+	_, tail := listItems(n)
+	r, ok := tail.(*ast.ReferenceNode)
+	return ok && r.Name == "nil"
+}
+
+// list renders the chain of `cons` calls terminated by `nil` as a list literal.
+func (d *decompiler) list(n ast.Node, indent int) error {
+	items, _ := listItems(n)
+	d.write("[")
+	for i, item := range items {
+		if i > 0 {
+			d.write(", ")
+		}
+		if err := d.expression(item, indent); err != nil {
+			return err
+		}
+	}
+	d.write("]")
+	return nil
+}
+
+func (d *decompiler) arguments(args []ast.Node, indent int) error {
+	d.write("(")
+	for i, a := range args {
+		if i > 0 {
+			d.write(", ")
+		}
+		if err := d.expression(a, indent); err != nil {
+			return err
+		}
+	}
+	d.write(")")
+	return nil
+}
+
+func (d *decompiler) functionName(f ast.Function) string {
+	if name, ok := functionNames[f.Name()]; ok {
+		return name
+	}
+	if _, ok := f.(ast.NativeFunction); ok {
+		return "Native_" + f.Name()
+	}
+	return d.name(f.Name())
+}
+
+func typeName(t meta.Type) string {
+	switch tt := t.(type) {
+	case meta.SimpleType:
+		switch tt {
+		case meta.Int:
+			return "Int"
+		case meta.Bytes:
+			return "ByteVector"
+		case meta.Boolean:
+			return "Boolean"
+		case meta.String:
+			return "String"
+		}
+	case meta.UnionType:
+		names := make([]string, len(tt))
+		for i, st := range tt {
+			names[i] = typeName(st)
+		}
+		return strings.Join(names, "|")
+	case meta.ListType:
+		return "List[" + typeName(tt.Inner) + "]"
+	}
+	return fmt.Sprintf("Unknown(%v)", t)
+}
+
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func bytesLiteral(b []byte) string {
+	if len(b) <= maxBase58Length {
+		return "base58'" + base58.Encode(b) + "'"
+	}
+	return "base64'" + base64.StdEncoding.EncodeToString(b) + "'"
+}
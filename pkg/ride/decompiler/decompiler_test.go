@@ -0,0 +1,123 @@
+package decompiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+func decompile(t *testing.T, src string, compaction bool) string {
+	bin, errs := compiler.Compile(src, compaction, false)
+	require.Empty(t, errs)
+	tree, err := serialization.Parse(bin)
+	require.NoError(t, err)
+	res, err := Decompile(tree)
+	require.NoError(t, err)
+	return res
+}
+
+func TestDecompileExpression(t *testing.T) {
+	for _, test := range []struct {
+		src      string
+		expected string
+	}{
+		{
+			src: `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+let a = 1
+let b = "x\"y"
+a + 2 > 3 && b != "z" || sigVerify(base58'', base58'', base58'')`,
+			expected: `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+let a = 1
+let b = "x\"y"
+(((a + 2) > 3) && (b != "z")) || sigVerify(base58'', base58'', base58'')
+`,
+		},
+		{
+			src: `{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+func f(x: Int) = if (x > 0) then [x, -x] else []
+size(f(1)) == 2`,
+			expected: `{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+func f(x) = if (x > 0)
+    then [x, -x]
+    else nil
+size(f(1)) == 2
+`,
+		},
+	} {
+		assert.Equal(t, test.expected, decompile(t, test.src, false))
+	}
+}
+
+func TestDecompileDApp(t *testing.T) {
+	src := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let threshold = 10
+
+func check(amount: Int) = amount > threshold
+
+@Callable(i)
+func deposit(amount: Int, memo: String) = {
+    let valid = check(amount)
+    if (valid) then [IntegerEntry(memo, amount)] else throw("invalid amount")
+}
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`
+	expected := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let threshold = 10
+
+func check(amount) = amount > threshold
+
+@Callable(i)
+func deposit(amount: Int, memo: String) = {
+    let valid = check(amount)
+    if (valid)
+        then [IntegerEntry(memo, amount)]
+        else throw("invalid amount")
+}
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`
+	assert.Equal(t, expected, decompile(t, src, false))
+	// Original names are restored from the script meta.
+	assert.Equal(t, expected, decompile(t, src, true))
+}
+
+func TestDecompiledScriptCompiles(t *testing.T) {
+	src := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+let list = [1, 2, 3]
+let x = list[0]
+let y = "y"
+func two() = list[1]
+x + two() - 3 == 0 && y + "z" == "yz" && !(size(list) < 1)`
+	original, errs := compiler.Compile(src, false, false)
+	require.Empty(t, errs)
+	res := decompile(t, src, false)
+	restored, errs := compiler.Compile(res, false, false)
+	require.Empty(t, errs, res)
+	assert.Equal(t, original, restored)
+}
+
+func TestCompactName(t *testing.T) {
+	assert.Equal(t, "a", compactName(0))
+	assert.Equal(t, "Z", compactName(51))
+	assert.Equal(t, "aa", compactName(52))
+	assert.Equal(t, "ab", compactName(53))
+	assert.Equal(t, "ba", compactName(104))
+}
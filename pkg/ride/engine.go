@@ -0,0 +1,277 @@
+package ride
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"go.uber.org/zap"
+)
+
+// Engine is the way the scripts are executed.
+type Engine byte
+
+const (
+	TreeEngine         Engine = iota // Scripts are evaluated by the tree evaluator
+	VMEngine                         // Scripts are compiled to bytecode and executed by the virtual machine
+	DifferentialEngine               // Scripts are executed by both engines and divergences of results are reported
+)
+
+var engineNames = map[Engine]string{
+	TreeEngine:         "tree",
+	VMEngine:           "vm",
+	DifferentialEngine: "differential",
+}
+
+// NewEngine returns the engine by its name: "tree", "vm" or "differential".
+func NewEngine(name string) (Engine, error) {
+	for e, n := range engineNames {
+		if strings.EqualFold(n, name) {
+			return e, nil
+		}
+	}
+	return TreeEngine, errors.Errorf("unknown Ride engine '%s'", name)
+}
+
+func (e Engine) String() string {
+	if n, ok := engineNames[e]; ok {
+		return n
+	}
+	return fmt.Sprintf("unknown engine %d", e)
+}
+
+// engineEnvironment is implemented by environments that select the engine to execute scripts.
+type engineEnvironment interface {
+	engine() Engine
+}
+
+// detachableEnvironment is implemented by environments that can be copied to execute a script
+// without changing the complexity of the original environment.
+type detachableEnvironment interface {
+	detached() environment
+}
+
+func environmentEngine(env environment) Engine {
+	if environmentTrace(env) != nil {
+		return TreeEngine // Only tree evaluator records the trace
+	}
+	if ee, ok := env.(engineEnvironment); ok {
+		return ee.engine()
+	}
+	return TreeEngine
+}
+
+type evaluator interface {
+	evaluate() (Result, error)
+	walkRoot() (rideType, error)
+	complexity() int
+}
+
+// verifierEvaluator returns the evaluator of the verifier or the expression selected by the engine of environment.
+// Scripts that can't be compiled to bytecode are evaluated by the tree evaluator.
+func verifierEvaluator(env environment, tree *ast.Tree) (evaluator, error) {
+	switch environmentEngine(env) {
+	case VMEngine:
+		if p, err := programs.get(tree); err == nil {
+			return vmVerifierEvaluator(env, p)
+		}
+	case DifferentialEngine:
+		te, err := treeVerifierEvaluator(env, tree)
+		if err != nil {
+			return nil, err
+		}
+		de := &differentialEvaluator{tree: te, script: tree, name: verifierTraceName}
+		if denv, p, ok := differentialProgram(env, tree); ok {
+			if ve, err := vmVerifierEvaluator(denv, p); err == nil {
+				de.vm = ve
+			}
+		}
+		return de, nil
+	}
+	return treeVerifierEvaluator(env, tree)
+}
+
+// functionEvaluator returns the evaluator of the callable function selected by the engine of environment.
+// Scripts that can't be compiled to bytecode are evaluated by the tree evaluator.
+func functionEvaluator(env environment, tree *ast.Tree, name string, args []rideType) (evaluator, error) {
+	switch environmentEngine(env) {
+	case VMEngine:
+		if p, err := programs.get(tree); err == nil {
+			return vmFunctionEvaluator(env, p, name, args)
+		}
+	case DifferentialEngine:
+		te, err := treeFunctionEvaluator(env, tree, name, args)
+		if err != nil {
+			return nil, err
+		}
+		de := &differentialEvaluator{tree: te, script: tree, name: name}
+		if denv, p, ok := differentialProgram(env, tree); ok {
+			if ve, err := vmFunctionEvaluator(denv, p, name, args); err == nil {
+				de.vm = ve
+			}
+		}
+		return de, nil
+	}
+	return treeFunctionEvaluator(env, tree, name, args)
+}
+
+// differentialProgram returns the compiled program and the detached environment to execute it alongside
+// with the tree evaluator. Programs that call other DApps change the state, so they are not executed twice.
+func differentialProgram(env environment, tree *ast.Tree) (environment, *Program, bool) {
+	de, ok := env.(detachableEnvironment)
+	if !ok {
+		return nil, nil, false
+	}
+	p, err := programs.get(tree)
+	if err != nil || p.invocations {
+		return nil, nil, false
+	}
+	return de.detached(), p, true
+}
+
+var divergences uint64
+
+// Divergences returns the number of divergences between the tree evaluator and the virtual machine
+// found by the differential engine.
+func Divergences() uint64 {
+	return atomic.LoadUint64(&divergences)
+}
+
+// differentialEvaluator executes the script by the virtual machine in detached environment
+// and after that evaluates it by the tree evaluator. The result of the tree evaluator is returned.
+type differentialEvaluator struct {
+	tree   *treeEvaluator
+	vm     *vmEvaluator
+	script *ast.Tree
+	name   string
+}
+
+func (e *differentialEvaluator) complexity() int {
+	return e.tree.complexity()
+}
+
+func (e *differentialEvaluator) evaluate() (Result, error) {
+	if e.vm == nil {
+		return e.tree.evaluate()
+	}
+	var vr Result
+	vErr := recoverPanic(func() (err error) {
+		vr, err = e.vm.evaluate()
+		return err
+	})
+	r, err := e.tree.evaluate()
+	e.compare(r, err, vr, vErr)
+	return r, err
+}
+
+func (e *differentialEvaluator) walkRoot() (rideType, error) {
+	if e.vm == nil {
+		return e.tree.walkRoot()
+	}
+	var vr rideType
+	vErr := recoverPanic(func() (err error) {
+		vr, err = e.vm.walkRoot()
+		return err
+	})
+	r, err := e.tree.walkRoot()
+	e.compare(r, err, vr, vErr)
+	return r, err
+}
+
+// recoverPanic turns the panic of the virtual machine into an error, so the divergence is reported
+// instead of the node shutdown.
+func recoverPanic(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = EvaluationFailure.Errorf("virtual machine panic: %v", r)
+		}
+	}()
+	return f()
+}
+
+func (e *differentialEvaluator) compare(r interface{}, err error, vr interface{}, vErr error) {
+	d := divergence(r, err, e.tree.complexity(), vr, vErr, e.vm.complexity())
+	if d == "" {
+		return
+	}
+	atomic.AddUint64(&divergences, 1)
+	zap.S().Warnf("Ride engines diverged on function '%s' of script '%s': %s",
+		e.name, crypto.Digest(e.script.Digest).String(), d)
+}
+
+// divergence describes the difference between the results of tree evaluator and virtual machine,
+// empty string is returned if the results are the same.
+func divergence(r interface{}, err error, complexity int, vr interface{}, vErr error, vComplexity int) string {
+	switch {
+	case (err == nil) != (vErr == nil):
+		return fmt.Sprintf("tree error '%v', vm error '%v'", err, vErr)
+	case err != nil && GetEvaluationErrorType(err) != GetEvaluationErrorType(vErr):
+		return fmt.Sprintf("tree error type %d, vm error type %d", GetEvaluationErrorType(err), GetEvaluationErrorType(vErr))
+	case err != nil && err.Error() != vErr.Error():
+		return fmt.Sprintf("tree error '%v', vm error '%v'", err, vErr)
+	case err != nil && !reflect.DeepEqual(EvaluationErrorCallStack(err), EvaluationErrorCallStack(vErr)):
+		return fmt.Sprintf("tree error call stack '%s', vm error call stack '%s'",
+			strings.Join(EvaluationErrorCallStack(err), ";"), strings.Join(EvaluationErrorCallStack(vErr), ";"))
+	case complexity != vComplexity:
+		return fmt.Sprintf("tree complexity %d, vm complexity %d", complexity, vComplexity)
+	case !reflect.DeepEqual(r, vr):
+		return fmt.Sprintf("tree result '%v', vm result '%v'", r, vr)
+	default:
+		return ""
+	}
+}
+
+// programs is the cache of compiled scripts.
+var programs = newProgramCache(1024)
+
+type programCacheEntry struct {
+	key     crypto.Digest
+	program *Program
+	err     error
+}
+
+type programCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[crypto.Digest]*list.Element
+	order *list.List
+}
+
+func newProgramCache(size int) *programCache {
+	return &programCache{size: size, items: make(map[crypto.Digest]*list.Element), order: list.New()}
+}
+
+// get returns the compiled program of the script. Scripts are identified by digest, scripts without digest
+// are compiled every time. Compilation errors are cached too.
+func (c *programCache) get(tree *ast.Tree) (*Program, error) {
+	key := crypto.Digest(tree.Digest)
+	if key == (crypto.Digest{}) {
+		return Compile(tree)
+	}
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		e := el.Value.(*programCacheEntry)
+		c.mu.Unlock()
+		return e.program, e.err
+	}
+	c.mu.Unlock()
+	p, err := Compile(tree)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; !ok {
+		c.items[key] = c.order.PushFront(&programCacheEntry{key: key, program: p, err: err})
+		if c.order.Len() > c.size {
+			last := c.order.Back()
+			c.order.Remove(last)
+			delete(c.items, last.Value.(*programCacheEntry).key)
+		}
+	}
+	return p, err
+}
@@ -0,0 +1,233 @@
+package ride
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+// engineTestEnvironment selects the engine for the mock environment and makes it detachable.
+type engineTestEnvironment struct {
+	*mockRideEnvironment
+	en Engine
+	cc complexityCalculator
+}
+
+func (e *engineTestEnvironment) engine() Engine {
+	return e.en
+}
+
+func (e *engineTestEnvironment) complexityCalculator() complexityCalculator {
+	if e.cc != nil {
+		return e.cc
+	}
+	return e.mockRideEnvironment.complexityCalculator()
+}
+
+func (e *engineTestEnvironment) detached() environment {
+	return &engineTestEnvironment{mockRideEnvironment: e.mockRideEnvironment, en: e.en, cc: e.complexityCalculator().clone()}
+}
+
+func TestNewEngine(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		engine Engine
+		ok     bool
+	}{
+		{"tree", TreeEngine, true},
+		{"vm", VMEngine, true},
+		{"VM", VMEngine, true},
+		{"differential", DifferentialEngine, true},
+		{"", TreeEngine, false},
+		{"bytecode", TreeEngine, false},
+	} {
+		e, err := NewEngine(test.name)
+		if !test.ok {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.engine, e, test.name)
+		assert.True(t, strings.EqualFold(test.name, e.String()), test.name)
+	}
+}
+
+func TestEngineFallbackToTreeEvaluator(t *testing.T) {
+	for _, test := range []struct {
+		comment string
+		source  string
+	}{
+		{`V3: let ref = 999; func g(a: Int) = ref; func f(ref: Int) = g(ref); f(1) == 999`, "AwQAAAADcmVmAAAAAAAAAAPnCgEAAAABZwAAAAEAAAABYQUAAAADcmVmCgEAAAABZgAAAAEAAAADcmVmCQEAAAABZwAAAAEFAAAAA3JlZgkAAAAAAAACCQEAAAABZgAAAAEAAAAAAAAAAAEAAAAAAAAAA+fjknmW"},
+		{`V3: let b = base16'0000000000000001'; func add(b: ByteVector) = toInt(b) + 1; add(b) == 2`, "AwQAAAABYgEAAAAIAAAAAAAAAAEKAQAAAANhZGQAAAABAAAAAWIJAABkAAAAAgkABLEAAAABBQAAAAFiAAAAAAAAAAABCQAAAAAAAAIJAQAAAANhZGQAAAABBQAAAAFiAAAAAAAAAAACX00biA=="},
+		{`V3: let data = base64'...'; func getStock(data:ByteVector) = toInt(take(drop(data, 8), 8)); getStock(data) == 1`, "AwQAAAAEZGF0YQEAAABwAAAAAAABhqAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWyt9GyysOW84u/u5V5Ah/SzLfef4c28UqXxowxFZS4SLiC6+XBh8D7aJDXyTTjpkPPED06ZPOzUE23V6VYCsLwoBAAAACGdldFN0b2NrAAAAAQAAAARkYXRhCQAEsQAAAAEJAADJAAAAAgkAAMoAAAACBQAAAARkYXRhAAAAAAAAAAAIAAAAAAAAAAAICQAAAAAAAAIJAQAAAAhnZXRTdG9jawAAAAEFAAAABGRhdGEAAAAAAAAAAAFCtabi"},
+	} {
+		_, tree := parseBase64Script(t, test.source)
+		_, err := Compile(tree)
+		assert.Error(t, err, test.comment)
+
+		env := &engineTestEnvironment{mockRideEnvironment: engineTestEnv(t, tree, ""), en: VMEngine}
+		e, err := verifierEvaluator(env, tree)
+		require.NoError(t, err, test.comment)
+		_, ok := e.(*treeEvaluator)
+		require.True(t, ok, test.comment)
+		r, err := e.evaluate()
+		require.NoError(t, err, test.comment)
+		assert.True(t, r.Result(), test.comment)
+	}
+}
+
+func TestEngineSelection(t *testing.T) {
+	// V3: func abs(i:Int) = if (i >= 0) then i else -i; abs(-10) == 10
+	_, tree := parseBase64Script(t, "AwoBAAAAA2FicwAAAAEAAAABaQMJAABnAAAAAgUAAAABaQAAAAAAAAAAAAUAAAABaQkBAAAAAS0AAAABBQAAAAFpCQAAAAAAAAIJAQAAAANhYnMAAAABAP/////////2AAAAAAAAAAAKmp8BWw==")
+
+	tEnv := &engineTestEnvironment{mockRideEnvironment: engineTestEnv(t, tree, ""), en: TreeEngine}
+	te, err := verifierEvaluator(tEnv, tree)
+	require.NoError(t, err)
+	_, ok := te.(*treeEvaluator)
+	require.True(t, ok)
+	tr, err := te.evaluate()
+	require.NoError(t, err)
+
+	vEnv := &engineTestEnvironment{mockRideEnvironment: engineTestEnv(t, tree, ""), en: VMEngine}
+	ve, err := verifierEvaluator(vEnv, tree)
+	require.NoError(t, err)
+	_, ok = ve.(*vmEvaluator)
+	require.True(t, ok)
+	vr, err := ve.evaluate()
+	require.NoError(t, err)
+	assert.Equal(t, tr, vr)
+
+	before := Divergences()
+	dEnv := &engineTestEnvironment{mockRideEnvironment: engineTestEnv(t, tree, ""), en: DifferentialEngine}
+	de, err := verifierEvaluator(dEnv, tree)
+	require.NoError(t, err)
+	d, ok := de.(*differentialEvaluator)
+	require.True(t, ok)
+	require.NotNil(t, d.vm)
+	dr, err := de.evaluate()
+	require.NoError(t, err)
+	assert.Equal(t, tr, dr)
+	// Complexity of the virtual machine execution is not added to the original environment
+	assert.Equal(t, te.complexity(), de.complexity())
+	assert.Equal(t, te.complexity(), dEnv.complexityCalculator().complexity())
+	assert.Equal(t, before, Divergences())
+}
+
+func TestDivergence(t *testing.T) {
+	err := RuntimeError.New("failure")
+	for _, test := range []struct {
+		comment     string
+		r           interface{}
+		err         error
+		complexity  int
+		vr          interface{}
+		vErr        error
+		vComplexity int
+		diverged    bool
+	}{
+		{"same results", rideInt(1), nil, 10, rideInt(1), nil, 10, false},
+		{"same errors", nil, EvaluationErrorPush(err, "in function"), 10, nil, EvaluationErrorPush(err, "in function"), 10, false},
+		{"different call stacks", nil, EvaluationErrorPush(err, "in tree"), 10, nil, EvaluationErrorPush(err, "in vm"), 10, true},
+		{"different results", rideInt(1), nil, 10, rideInt(2), nil, 10, true},
+		{"different complexities", rideInt(1), nil, 10, rideInt(1), nil, 11, true},
+		{"error in vm only", rideInt(1), nil, 10, nil, err, 10, true},
+		{"error in tree only", nil, err, 10, rideInt(1), nil, 10, true},
+		{"different error types", nil, err, 10, nil, UserError.New("failure"), 10, true},
+		{"different error messages", nil, err, 10, nil, RuntimeError.New("other failure"), 10, true},
+	} {
+		d := divergence(test.r, test.err, test.complexity, test.vr, test.vErr, test.vComplexity)
+		assert.Equal(t, test.diverged, d != "", test.comment)
+	}
+}
+
+func TestProgramCache(t *testing.T) {
+	_, t1 := parseBase64Script(t, "AQa3b8tH")                     // V1: true
+	_, t2 := parseBase64Script(t, "AwQAAAABeAAAAAAAAAAAAQbtAkXn") // V3: let x = 1; true
+	_, t3 := parseBase64Script(t, "AwQAAAADcmVmAAAAAAAAAAPnCgEAAAABZwAAAAEAAAABYQUAAAADcmVmCgEAAAABZgAAAAEAAAADcmVmCQEAAAABZwAAAAEFAAAAA3JlZgkAAAAAAAACCQEAAAABZgAAAAEAAAAAAAAAAAEAAAAAAAAAA+fjknmW")
+
+	c := newProgramCache(2)
+	p1, err := c.get(t1)
+	require.NoError(t, err)
+	p, err := c.get(t1)
+	require.NoError(t, err)
+	assert.Same(t, p1, p)
+
+	_, err = c.get(t3)
+	assert.Error(t, err)
+	_, err = c.get(t3)
+	assert.Error(t, err)
+	assert.Equal(t, 2, c.order.Len())
+
+	// The least recently used program is evicted
+	_, err = c.get(t2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.order.Len())
+	p, err = c.get(t1)
+	require.NoError(t, err)
+	assert.NotSame(t, p1, p)
+
+	// Scripts without digest are not cached
+	t4 := *t1
+	t4.Digest = [32]byte{}
+	_, err = c.get(&t4)
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.order.Len())
+}
+
+// TestEnginesOnStateTestdataScripts executes the verifiers and callable functions of the scripts used in state tests
+// by both engines and checks that the results are the same.
+func TestEnginesOnStateTestdataScripts(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "state", "testdata", "scripts", "*.base64"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		require.NoError(t, err, file)
+		// Files start with the source code of the script in comments
+		var lines []string
+		for _, l := range strings.Split(string(src), "\n") {
+			if !strings.HasPrefix(l, "#") {
+				lines = append(lines, strings.TrimSpace(l))
+			}
+		}
+		_, tree := parseBase64Script(t, strings.Join(lines, ""))
+		if _, err := Compile(tree); err != nil {
+			t.Logf("Script '%s' is not compiled, it's evaluated by the tree evaluator: %v", filepath.Base(file), err)
+			continue
+		}
+		if tree.HasVerifier() || !tree.IsDApp() {
+			requireSameExecution(t, file, tree, "", nil, stateTestEnv)
+		}
+		for _, n := range tree.Functions {
+			fn, ok := n.(*ast.FunctionDeclarationNode)
+			require.True(t, ok, file)
+			args := make([]rideType, len(fn.Arguments))
+			for i := range args {
+				args[i] = rideInt(0)
+			}
+			requireSameExecution(t, file+": "+fn.Name, tree, fn.Name, args, stateTestEnv)
+		}
+	}
+}
+
+// stateTestEnv creates the environment with wrapped state to execute the verifier or the callable function.
+func stateTestEnv(t *testing.T, tree *ast.Tree, name string) *mockRideEnvironment {
+	sender := newTestAccount(t, "SENDER")
+	dApp := newTestAccount(t, "DAPP1")
+	te := newTestEnv(t).withLibVersion(tree.LibVersion).withComplexityLimit(tree.LibVersion, 52000).
+		withSender(sender).withDApp(dApp).withThis(dApp).withHeight(100).withBlockV5Activated().
+		withWavesBalance(dApp, 1000_00000000).withDataEntries(dApp, &proto.IntegerDataEntry{Key: "k", Value: 1})
+	if name == "" {
+		te = te.withTransaction(testTransferWithProofs(t))
+	} else {
+		te = te.withInvocation(name, withTransactionID(crypto.MustDigestFromBase58("H5C8bRzbUTMePSDVVxjiNKDUwk6CKzfZGTP2Rs7aCjsV")))
+	}
+	return te.withWrappedState().toEnv()
+}
@@ -28,6 +28,8 @@ type WrappedState struct {
 	dataEntriesSize           int
 	rootScriptLibVersion      ast.LibraryVersion
 	rootActionsCountValidator proto.ActionsCountValidator
+	invocations               []*Invocation
+	invocationsStack          []*Invocation
 }
 
 func newWrappedState(env *EvaluationEnvironment, rootScriptLibVersion ast.LibraryVersion) *WrappedState {
@@ -45,6 +47,22 @@ func (ws *WrappedState) appendActions(actions []proto.ScriptAction) {
 	ws.act = append(ws.act, actions...)
 }
 
+// pushInvocation records the invocation as made by the current one, invocations of the root dApp are kept
+// in the wrapped state.
+func (ws *WrappedState) pushInvocation(inv *Invocation) {
+	if n := len(ws.invocationsStack); n > 0 {
+		parent := ws.invocationsStack[n-1]
+		parent.Invocations = append(parent.Invocations, inv)
+	} else {
+		ws.invocations = append(ws.invocations, inv)
+	}
+	ws.invocationsStack = append(ws.invocationsStack, inv)
+}
+
+func (ws *WrappedState) popInvocation() {
+	ws.invocationsStack = ws.invocationsStack[:len(ws.invocationsStack)-1]
+}
+
 func (ws *WrappedState) callee() proto.WavesAddress {
 	return proto.WavesAddress(ws.cle)
 }
@@ -1002,6 +1020,8 @@ type EvaluationEnvironment struct {
 	isProtobufTransaction              bool
 	mds                                int
 	cc                                 complexityCalculator
+	tr                                 *Trace
+	en                                 Engine
 }
 
 func bytesSizeCheckV1V2(l int) bool {
@@ -1235,6 +1255,30 @@ func (e *EvaluationEnvironment) SetLimit(limit uint32) {
 	e.cc.setLimit(limit)
 }
 
+// SetTrace enables recording of evaluation steps to the given trace.
+func (e *EvaluationEnvironment) SetTrace(t *Trace) {
+	e.tr = t
+}
+
+func (e *EvaluationEnvironment) trace() *Trace {
+	return e.tr
+}
+
+// SetEngine selects the engine to execute scripts, by default scripts are evaluated by the tree evaluator.
+func (e *EvaluationEnvironment) SetEngine(engine Engine) {
+	e.en = engine
+}
+
+func (e *EvaluationEnvironment) engine() Engine {
+	return e.en
+}
+
+func (e *EvaluationEnvironment) detached() environment {
+	d := *e
+	d.cc = e.cc.clone()
+	return &d
+}
+
 func (e *EvaluationEnvironment) timestamp() uint64 {
 	return e.time
 }
@@ -9,7 +9,7 @@ func invokeFunctionFromDApp(env environment, tree *ast.Tree, fnName rideString,
 	if err != nil {
 		return nil, EvaluationFailure.Wrapf(err, "failed to invoke function '%s'", fnName)
 	}
-	e, err := treeFunctionEvaluator(env, tree, string(fnName), args)
+	e, err := functionEvaluator(env, tree, string(fnName), args)
 	if err != nil {
 		return nil, EvaluationFailure.Wrapf(err, "failed to call function '%s'", fnName)
 	}
@@ -293,6 +293,13 @@ func performInvoke(invocation invocation, env environment, args ...rideType) (ri
 		}
 	}
 
+	// Arguments of types which can't be passed in transaction are possible before RideV6, such call is recorded
+	// without arguments
+	callArgs, _ := convertToProtoArguments(arguments)
+	inv := &Invocation{DApp: recipientAddr, Call: proto.NewFunctionCall(string(fn), callArgs), Payments: attachedPayments}
+	ws.pushInvocation(inv)
+	defer ws.popInvocation()
+
 	res, err := invokeFunctionFromDApp(env, tree, fn, arguments)
 	if err != nil {
 		return nil, EvaluationErrorPush(err, "%s at '%s' function %s with arguments %v", invocation.name(), recipientAddr, fn, arguments)
@@ -304,6 +311,7 @@ func performInvoke(invocation invocation, env environment, args ...rideType) (ri
 		}
 	}
 
+	applied := len(ws.act)
 	err = ws.smartAppendActions(res.ScriptActions(), env, &localActionsCountValidator)
 	if err != nil {
 		if GetEvaluationErrorType(err) == Undefined {
@@ -311,6 +319,7 @@ func performInvoke(invocation invocation, env environment, args ...rideType) (ri
 		}
 		return nil, err
 	}
+	inv.Actions = append([]proto.ScriptAction(nil), ws.act[applied:]...)
 
 	if env.validateInternalPayments() || env.rideV6Activated() {
 		err = ws.validateBalances(env.rideV6Activated())
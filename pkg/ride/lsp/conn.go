@@ -0,0 +1,55 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const maxMessageSize = 16 << 20
+
+// conn reads and writes JSON-RPC messages framed with the `Content-Length` header.
+type conn struct {
+	r *textproto.Reader
+	w io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: textproto.NewReader(bufio.NewReader(r)), w: w}
+}
+
+func (c *conn) read() ([]byte, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	l, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Content-Length header")
+	}
+	if l <= 0 || l > maxMessageSize {
+		return nil, errors.Errorf("invalid message size %d", l)
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(c.r.R, b); err != nil {
+		return nil, errors.Wrap(err, "failed to read message")
+	}
+	return b, nil
+}
+
+func (c *conn) write(msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(b)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(b)
+	return err
+}
@@ -0,0 +1,327 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	s "github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
+)
+
+var keywords = []string{"let", "strict", "func", "if", "then", "else", "match", "case", "true", "false", "FOLD"}
+
+type document struct {
+	uri      string
+	lines    [][]rune
+	analysis *compiler.Analysis
+	// semantic is the last analysis that passed the syntax check, it is used for hover and completion
+	// while the code is being edited and can't be parsed.
+	semantic *compiler.Analysis
+}
+
+func (d *document) update(text string) {
+	lines := strings.Split(text, "\n")
+	d.lines = make([][]rune, len(lines))
+	for i, l := range lines {
+		d.lines[i] = []rune(l)
+	}
+	d.analysis = compiler.Analyze(text)
+	if d.analysis.LibVersion != 0 || d.semantic == nil {
+		d.semantic = d.analysis
+	}
+}
+
+func (d *document) libVersion() ast.LibraryVersion {
+	if d.semantic.LibVersion == 0 {
+		return ast.LibV6
+	}
+	return d.semantic.LibVersion
+}
+
+func (d *document) line(n int) []rune {
+	if n < 0 || n >= len(d.lines) {
+		return nil
+	}
+	return d.lines[n]
+}
+
+func (d *document) toLSPPosition(p compiler.Position) position {
+	l := d.line(p.Line)
+	if p.Column > len(l) {
+		return position{Line: p.Line, Character: len(utf16.Encode(l))}
+	}
+	return position{Line: p.Line, Character: len(utf16.Encode(l[:p.Column]))}
+}
+
+func (d *document) toLSPRange(r compiler.Range) textRange {
+	return textRange{Start: d.toLSPPosition(r.Start), End: d.toLSPPosition(r.End)}
+}
+
+func (d *document) fromLSPPosition(p position) compiler.Position {
+	l := d.line(p.Line)
+	units := 0
+	for i, r := range l {
+		if units >= p.Character {
+			return compiler.Position{Line: p.Line, Column: i}
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return compiler.Position{Line: p.Line, Column: len(l)}
+}
+
+func (d *document) hover(p compiler.Position) (*hover, bool) {
+	var text string
+	if r, ok := d.semantic.ReferenceAt(p); ok {
+		switch {
+		case r.Declaration != nil:
+			text = symbolSignature(r.Declaration)
+		case r.Kind == compiler.ReferenceFunction:
+			text = d.functionSignatures(r.Name)
+		default:
+			text = fmt.Sprintf("%s: %s", r.Name, typeString(r.Type))
+		}
+		rng := d.toLSPRange(r.Range)
+		return &hover{Contents: markupContent{Kind: markupKindMarkdown, Value: codeBlock(text)}, Range: &rng}, true
+	}
+	if sym, ok := d.semantic.SymbolAt(p); ok {
+		rng := d.toLSPRange(sym.Range)
+		return &hover{Contents: markupContent{Kind: markupKindMarkdown, Value: codeBlock(symbolSignature(sym))}, Range: &rng}, true
+	}
+	// The code may be broken, try to describe the function of the standard library by name.
+	if name := identifierAt(d.line(p.Line), p.Column); name != "" {
+		if text = d.functionSignatures(name); text != "" {
+			return &hover{Contents: markupContent{Kind: markupKindMarkdown, Value: codeBlock(text)}}, true
+		}
+	}
+	return nil, false
+}
+
+// functionSignatures describes all overloads of the standard library function or the constructor of an object.
+func (d *document) functionSignatures(name string) string {
+	var sb strings.Builder
+	funcs := s.FuncsByVersion()[d.libVersion()]
+	for _, f := range funcs.Funcs[name] {
+		args := make([]string, len(f.Arguments))
+		for i, a := range f.Arguments {
+			args[i] = typeString(a)
+		}
+		sb.WriteString(fmt.Sprintf("func %s(%s): %s\n", name, strings.Join(args, ", "), typeString(f.ReturnType)))
+	}
+	objects := s.ObjectsByVersion()[d.libVersion()]
+	if info, ok := objects.Obj[name]; ok && !info.NotConstruct {
+		fields := make([]string, len(info.Fields))
+		for i, f := range info.Fields {
+			fields[i] = fmt.Sprintf("%s: %s", f.Name, typeString(f.Type))
+		}
+		sb.WriteString(fmt.Sprintf("%s(%s)\n", name, strings.Join(fields, ", ")))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (d *document) completion(p compiler.Position) []completionItem {
+	l := d.line(p.Line)
+	if p.Column > len(l) {
+		p.Column = len(l)
+	}
+	start := p.Column
+	for start > 0 && isIdentifierRune(l[start-1]) {
+		start--
+	}
+	prefix := string(l[start:p.Column])
+	var items []completionItem
+	if start > 0 && l[start-1] == '.' {
+		t := d.expressionType(l[:start-1], p)
+		if t == nil {
+			return []completionItem{}
+		}
+		items = d.memberItems(t)
+	} else {
+		items = d.scopeItems(p)
+	}
+	res := make([]completionItem, 0, len(items))
+	for _, it := range items {
+		if strings.HasPrefix(it.Label, prefix) {
+			res = append(res, it)
+		}
+	}
+	return res
+}
+
+// expressionType resolves the type of the chain of identifiers like `i.caller.bytes` at the end of the text.
+func (d *document) expressionType(text []rune, p compiler.Position) s.Type {
+	var chain []string
+	end := len(text)
+	for {
+		start := end
+		for start > 0 && isIdentifierRune(text[start-1]) {
+			start--
+		}
+		if start == end {
+			return nil
+		}
+		chain = append([]string{string(text[start:end])}, chain...)
+		if start == 0 || text[start-1] != '.' {
+			break
+		}
+		end = start - 1
+	}
+	t := d.variableType(chain[0], p)
+	objects := s.ObjectsByVersion()[d.libVersion()]
+	for _, field := range chain[1:] {
+		if t == nil {
+			return nil
+		}
+		t, _ = objects.GetField(t, field)
+	}
+	return t
+}
+
+func (d *document) variableType(name string, p compiler.Position) s.Type {
+	symbols := d.semantic.VisibleSymbols(p)
+	for i := len(symbols) - 1; i >= 0; i-- {
+		if symbols[i].Name == name && symbols[i].Kind != compiler.SymbolFunction {
+			return symbols[i].Type
+		}
+	}
+	for _, v := range d.semantic.Globals {
+		if v.Name == name {
+			return v.Type
+		}
+	}
+	return nil
+}
+
+// memberItems lists the fields of the type and the functions that can be called on the value of the type.
+func (d *document) memberItems(t s.Type) []completionItem {
+	var items []completionItem
+	objects := s.ObjectsByVersion()[d.libVersion()]
+	var candidates []s.ObjectField
+	switch tt := t.(type) {
+	case s.SimpleType:
+		candidates = objects.Obj[tt.Type].Fields
+	case s.UnionType:
+		if len(tt.Types) > 0 {
+			if st, ok := tt.Types[0].(s.SimpleType); ok {
+				candidates = objects.Obj[st.Type].Fields
+			}
+		}
+	}
+	for _, f := range candidates {
+		if ft, ok := objects.GetField(t, f.Name); ok {
+			items = append(items, completionItem{Label: f.Name, Kind: completionItemKindField, Detail: typeString(ft)})
+		}
+	}
+	funcs := s.FuncsByVersion()[d.libVersion()]
+	for _, name := range sortedFunctionNames(funcs.Funcs) {
+		for _, f := range funcs.Funcs[name] {
+			if len(f.Arguments) > 0 && f.Arguments[0].EqualWithEntry(t) {
+				items = append(items, completionItem{Label: name, Kind: completionItemKindFunction, Detail: typeString(f.ReturnType)})
+				break
+			}
+		}
+	}
+	return items
+}
+
+// scopeItems lists the variables and functions visible at the position, the standard library and the keywords.
+func (d *document) scopeItems(p compiler.Position) []completionItem {
+	var items []completionItem
+	seen := make(map[string]struct{})
+	add := func(it completionItem) {
+		if _, ok := seen[it.Label]; ok {
+			return
+		}
+		seen[it.Label] = struct{}{}
+		items = append(items, it)
+	}
+	symbols := d.semantic.VisibleSymbols(p)
+	for i := len(symbols) - 1; i >= 0; i-- {
+		sym := symbols[i]
+		kind := completionItemKindVariable
+		if sym.Kind == compiler.SymbolFunction {
+			kind = completionItemKindFunction
+		}
+		add(completionItem{Label: sym.Name, Kind: kind, Detail: symbolSignature(sym)})
+	}
+	for _, v := range d.semantic.Globals {
+		add(completionItem{Label: v.Name, Kind: completionItemKindVariable, Detail: typeString(v.Type)})
+	}
+	for _, name := range sortedFunctionNames(s.FuncsByVersion()[d.libVersion()].Funcs) {
+		add(completionItem{Label: name, Kind: completionItemKindFunction})
+	}
+	objects := s.ObjectsByVersion()[d.libVersion()].Obj
+	for _, name := range sortedObjectNames(objects) {
+		if !objects[name].NotConstruct {
+			add(completionItem{Label: name, Kind: completionItemKindFunction})
+		}
+	}
+	for _, k := range keywords {
+		add(completionItem{Label: k, Kind: completionItemKindKeyword})
+	}
+	return items
+}
+
+func sortedFunctionNames(m map[string][]s.FunctionParams) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedObjectNames(m map[string]s.ObjectInfo) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func symbolSignature(sym *compiler.Symbol) string {
+	switch sym.Kind {
+	case compiler.SymbolFunction:
+		args := make([]string, len(sym.ArgumentNames))
+		for i := range sym.ArgumentNames {
+			args[i] = fmt.Sprintf("%s: %s", sym.ArgumentNames[i], typeString(sym.ArgumentTypes[i]))
+		}
+		return fmt.Sprintf("func %s(%s): %s", sym.Name, strings.Join(args, ", "), typeString(sym.Type))
+	case compiler.SymbolVariable:
+		return fmt.Sprintf("let %s: %s", sym.Name, typeString(sym.Type))
+	default:
+		return fmt.Sprintf("%s: %s", sym.Name, typeString(sym.Type))
+	}
+}
+
+func typeString(t s.Type) string {
+	if t == nil {
+		return "Unknown"
+	}
+	return t.String()
+}
+
+func codeBlock(text string) string {
+	return "```ride\n" + text + "\n```"
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func identifierAt(l []rune, col int) string {
+	if col > len(l) {
+		col = len(l)
+	}
+	start, end := col, col
+	for start > 0 && isIdentifierRune(l[start-1]) {
+		start--
+	}
+	for end < len(l) && isIdentifierRune(l[end]) {
+		end++
+	}
+	return string(l[start:end])
+}
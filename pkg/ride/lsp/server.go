@@ -0,0 +1,186 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+)
+
+const serverName = "ride-lsp"
+
+// Server is a language server for Ride, it serves one client over the given reader and writer.
+// Requests are processed sequentially in order of arrival.
+type Server struct {
+	conn      *conn
+	documents map[string]*document
+	shutdown  bool
+}
+
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{conn: newConn(r, w), documents: make(map[string]*document)}
+}
+
+// Run serves the client until the `exit` notification is received or the input is closed.
+func (s *Server) Run() error {
+	for {
+		b, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to read message")
+		}
+		var req request
+		if err := json.Unmarshal(b, &req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(&req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req *request) error {
+	result, err := s.dispatch(req)
+	if req.ID == nil { // Notifications have no response
+		if err != nil {
+			zap.S().Debugf("Failed to handle notification '%s': %v", req.Method, err)
+		}
+		return nil
+	}
+	if err != nil {
+		var re *responseError
+		if errors.As(err, &re) {
+			return s.replyError(req.ID, re.Code, re.Message)
+		}
+		return s.replyError(req.ID, codeInvalidParams, err.Error())
+	}
+	return s.conn.write(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, msg string) error {
+	return s.conn.write(errorResponse{JSONRPC: "2.0", ID: id, Error: responseError{Code: code, Message: msg}})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.conn.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) dispatch(req *request) (interface{}, error) {
+	if s.shutdown && req.Method != "shutdown" {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shut down"}
+	}
+	switch req.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   textDocumentSyncFull,
+				HoverProvider:      true,
+				CompletionProvider: completionOptions{TriggerCharacters: []string{"."}},
+				DefinitionProvider: true,
+			},
+			ServerInfo: serverInfo{Name: serverName},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "workspace/didChangeConfiguration":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var p didOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.update(p.TextDocument.URI, p.TextDocument.Text)
+	case "textDocument/didChange":
+		var p didChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if len(p.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// Full synchronization, the last change contains the whole text.
+		return nil, s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var p didCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, err
+		}
+		delete(s.documents, p.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics",
+			publishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/hover":
+		d, p, err := s.documentPosition(req.Params)
+		if err != nil {
+			return nil, err
+		}
+		if h, ok := d.hover(p); ok {
+			return h, nil
+		}
+		return nil, nil
+	case "textDocument/completion":
+		d, p, err := s.documentPosition(req.Params)
+		if err != nil {
+			return nil, err
+		}
+		return d.completion(p), nil
+	case "textDocument/definition":
+		d, p, err := s.documentPosition(req.Params)
+		if err != nil {
+			return nil, err
+		}
+		sym, ok := d.semantic.SymbolAt(p)
+		if !ok {
+			return nil, nil
+		}
+		return location{URI: d.uri, Range: d.toLSPRange(sym.Range)}, nil
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + req.Method}
+	}
+}
+
+func (s *Server) update(uri, text string) error {
+	d, ok := s.documents[uri]
+	if !ok {
+		d = &document{uri: uri}
+		s.documents[uri] = d
+	}
+	d.update(text)
+	diagnostics := make([]diagnostic, len(d.analysis.Diagnostics))
+	for i, dg := range d.analysis.Diagnostics {
+		diagnostics[i] = diagnostic{
+			Range:    d.toLSPRange(dg.Range),
+			Severity: diagnosticSeverityError,
+			Source:   serverName,
+			Message:  dg.Message,
+		}
+	}
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+func (s *Server) documentPosition(params json.RawMessage) (*document, compiler.Position, error) {
+	var p textDocumentPositionParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, compiler.Position{}, err
+	}
+	d, ok := s.documents[p.TextDocument.URI]
+	if !ok {
+		return nil, compiler.Position{}, errors.Errorf("unknown document '%s'", p.TextDocument.URI)
+	}
+	return d, d.fromLSPPosition(p.Position), nil
+}
@@ -0,0 +1,140 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testURI = "file:///test.ride"
+
+const testScript = `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let threshold = 10
+
+@Callable(i)
+func deposit(amount: Int) = {
+    let valid = amount > threshold
+    if (valid) then [IntegerEntry(toBase58String(i.caller.bytes), amount)] else throw("invalid")
+}
+`
+
+type testMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func writeMessage(t *testing.T, buf *bytes.Buffer, id int, method string, params interface{}) {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	b, err := json.Marshal(msg)
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(buf, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	require.NoError(t, err)
+}
+
+func run(t *testing.T, messages func(buf *bytes.Buffer)) []testMessage {
+	in := new(bytes.Buffer)
+	messages(in)
+	out := new(bytes.Buffer)
+	require.NoError(t, NewServer(in, out).Run())
+	c := newConn(out, nil)
+	var res []testMessage
+	for {
+		b, err := c.read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		var m testMessage
+		require.NoError(t, json.Unmarshal(b, &m))
+		res = append(res, m)
+	}
+	return res
+}
+
+func positionParams(line, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": testURI},
+		"position":     map[string]int{"line": line, "character": character},
+	}
+}
+
+func TestServer(t *testing.T) {
+	messages := run(t, func(buf *bytes.Buffer) {
+		writeMessage(t, buf, 1, "initialize", map[string]interface{}{})
+		writeMessage(t, buf, 0, "initialized", map[string]interface{}{})
+		writeMessage(t, buf, 0, "textDocument/didOpen", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": testURI, "languageId": "ride", "version": 1, "text": testScript},
+		})
+		writeMessage(t, buf, 2, "textDocument/hover", positionParams(8, 26))      // threshold
+		writeMessage(t, buf, 3, "textDocument/hover", positionParams(9, 36))      // toBase58String
+		writeMessage(t, buf, 4, "textDocument/definition", positionParams(8, 26)) // threshold
+		writeMessage(t, buf, 0, "textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": testURI, "version": 2},
+			"contentChanges": []map[string]string{{"text": testScript + "let x = this."}},
+		})
+		writeMessage(t, buf, 5, "textDocument/completion", positionParams(11, 13))
+		writeMessage(t, buf, 6, "unknown/method", nil)
+		writeMessage(t, buf, 7, "shutdown", nil)
+		writeMessage(t, buf, 0, "exit", nil)
+	})
+	require.Len(t, messages, 9)
+
+	var init initializeResult
+	require.NoError(t, json.Unmarshal(messages[0].Result, &init))
+	assert.True(t, init.Capabilities.HoverProvider)
+	assert.True(t, init.Capabilities.DefinitionProvider)
+
+	assert.Equal(t, "textDocument/publishDiagnostics", messages[1].Method)
+	var diagnostics publishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(messages[1].Params, &diagnostics))
+	assert.Equal(t, testURI, diagnostics.URI)
+	assert.Empty(t, diagnostics.Diagnostics)
+
+	var h hover
+	require.NoError(t, json.Unmarshal(messages[2].Result, &h))
+	assert.Equal(t, "```ride\nlet threshold: Int\n```", h.Contents.Value)
+	require.NoError(t, json.Unmarshal(messages[3].Result, &h))
+	assert.Equal(t, "```ride\nfunc toBase58String(ByteVector): String\n```", h.Contents.Value)
+
+	var loc location
+	require.NoError(t, json.Unmarshal(messages[4].Result, &loc))
+	assert.Equal(t, textRange{Start: position{Line: 4, Character: 4}, End: position{Line: 4, Character: 13}}, loc.Range)
+
+	// The changed code can't be parsed, so the syntax error is published.
+	require.NoError(t, json.Unmarshal(messages[5].Params, &diagnostics))
+	require.Len(t, diagnostics.Diagnostics, 1)
+	assert.Equal(t, "Syntax error", diagnostics.Diagnostics[0].Message)
+
+	// Fields of the Address are completed using the last successful analysis.
+	var items []completionItem
+	require.NoError(t, json.Unmarshal(messages[6].Result, &items))
+	require.NotEmpty(t, items)
+	assert.Equal(t, completionItem{Label: "bytes", Kind: completionItemKindField, Detail: "ByteVector"}, items[0])
+
+	require.NotNil(t, messages[7].Error)
+	assert.Equal(t, codeMethodNotFound, messages[7].Error.Code)
+	assert.Nil(t, messages[8].Error)
+}
+
+func TestDocumentPositions(t *testing.T) {
+	d := &document{}
+	d.update("let s = \"\U0001F600\" # x")
+	p := d.fromLSPPosition(position{Line: 0, Character: 13})
+	assert.Equal(t, 12, p.Column)
+	assert.Equal(t, position{Line: 0, Character: 13}, d.toLSPPosition(p))
+}
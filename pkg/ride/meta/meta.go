@@ -80,6 +80,12 @@ func (a *Abbreviations) CompactToOriginal(compact string) (string, error) {
 	return "", errors.Errorf("short name '%s' not found", compact)
 }
 
+// OriginalNames returns the list of original names stored in the new compaction format.
+// Compact names are not stored in this format, they are generated in order of the original names.
+func (a *Abbreviations) OriginalNames() []string {
+	return a.names
+}
+
 func Convert(meta *g.DAppMeta) (DApp, error) {
 	v := int(meta.GetVersion())
 	abbreviations := convertAbbreviations(meta.GetCompactNameAndOriginalNamePairList(), meta.GetOriginalNames())
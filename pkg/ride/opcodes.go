@@ -1,22 +1,27 @@
 package ride
 
 // Operation code is 1 byte.
-// Parameter is 2 bytes length
+// Parameters are 2 bytes long, except code addresses of jumps that are 4 bytes long.
 
 const (
-	OpHalt         byte = iota //00 - Halts program execution. No parameters.
-	OpReturn                   //01 - Returns from declaration to stored position. No parameters.
-	OpPush                     //02 - Put constant on stack. One parameter: constant ID.
-	OpPop                      //03 - Removes value from stack. No parameters.
-	OpTrue                     //04 - Put True value on stack. No parameters.
-	OpFalse                    //05 - Put False value on stack. No parameters.
-	OpJump                     //06 - Moves instruction pointer to new position. One parameter: new position.
-	OpJumpIfFalse              //07 - Moves instruction pointer to new position if value on stack is False. One parameter: new position.
-	OpProperty                 //08 - Puts value of object's property on stack. One parameter: constant ID that holds name of the property.
-	OpExternalCall             //09 - Call a standard library function. Two parameters: function ID, number of arguments.
-	OpCall                     //0a - Call a function declared at given address. Two parameters: position of function declaration, number of arguments.
-	OpGlobal                   //0b - Load global constant. One parameter: global constant ID.
-	OpLoad                     //0c - Evaluates an expression that declared at address. One parameter: position of declaration.
-	OpLoadLocal                //0d - Load an argument of function call on stack. One parameter: argument number.
-	OpRef                      //0e - Put reference to expression/function on stack. One parameter: position of declaration.
+	OpHalt          byte = iota //00 - Halts program execution, the result is on the top of the stack. No parameters.
+	OpReturn                    //01 - Returns from let expression or user function. No parameters.
+	OpPush                      //02 - Put constant on stack. One parameter: constant ID.
+	OpTrue                      //03 - Put True value on stack. No parameters.
+	OpFalse                     //04 - Put False value on stack. No parameters.
+	OpJump                      //05 - Moves instruction pointer to new position. One parameter: new position.
+	OpJumpIfFalse               //06 - Pops boolean from stack and moves instruction pointer to new position if it is False. One parameter: new position.
+	OpCondition                 //07 - Begins conditional expression, tests its complexity. No parameters.
+	OpEndCondition              //08 - Ends conditional expression, adds its complexity. No parameters.
+	OpBeginProperty             //09 - Begins property expression, tests its complexity. No parameters.
+	OpProperty                  //0a - Puts value of object's property on stack. One parameter: constant ID that holds name of the property.
+	OpExternalCall              //0b - Call a standard library function. Two parameters: function ID, number of arguments.
+	OpInvocation                //0c - Checks that the invocation function is available. One parameter: function ID.
+	OpCall                      //0d - Call a user function. Three parameters: function ID, number of arguments, number of scopes to the declaration.
+	OpGlobal                    //0e - Load global constant. One parameter: global constant ID.
+	OpParameter                 //0f - Load invocation parameter or global constant. Two parameters: constant ID that holds name of the parameter, global constant ID.
+	OpLoad                      //10 - Load value of let or argument, evaluates let expression if necessary. Two parameters: number of scopes to the declaration, slot.
 )
+
+// noGlobal is the global constant ID of OpParameter in case of absent global constant with the same name.
+const noGlobal = 0xffff
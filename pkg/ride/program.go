@@ -1,103 +1,77 @@
 package ride
 
 import (
-	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 )
 
-type callable struct {
-	entryPoint    int
-	parameterName string
+// nativeFunction is a standard library function used by the program.
+type nativeFunction struct {
+	name       string
+	fn         rideFunction
+	costs      [2]int // Costs of the function for the first and the second versions of evaluator
+	invocation bool   // Function is available only for callable functions, it's `invoke` or `reentrantInvoke`
 }
 
-type RideScript interface {
-	Run(env environment) (Result, error)
-	code() []byte
+// userFunction is a function declared in the script.
+type userFunction struct {
+	name      string
+	arguments int
+	entry     int
+	layout    *scopeLayout
 }
 
-type SimpleScript struct {
-	LibVersion ast.LibraryVersion
-	EntryPoint int
-	Code       []byte
-	Constants  []rideType
+// scopeLayout describes the values of the root expression or of a user function call.
+// Arguments of functions take first slots, the rest are taken by lets.
+type scopeLayout struct {
+	names []string
+	lets  []int // Code addresses of let expressions by slots, -1 for arguments
 }
 
-func (s *SimpleScript) Run(env environment) (Result, error) {
-	fs, err := selectFunctions(s.LibVersion)
-	if err != nil {
-		return nil, RuntimeError.Wrap(err, "simple script execution failed")
-	}
-	gcs, err := selectConstants(s.LibVersion)
-	if err != nil {
-		return nil, RuntimeError.Wrap(err, "simple script execution failed")
-	}
-	np, err := selectFunctionNameProvider(s.LibVersion)
-	if err != nil {
-		return nil, RuntimeError.Wrap(err, "simple script execution failed")
-	}
-	m := vm{
-		env:          env,
-		code:         s.Code,
-		ip:           0,
-		constants:    s.Constants,
-		functions:    fs,
-		globals:      gcs,
-		stack:        make([]rideType, 0, 2),
-		calls:        make([]frame, 0, 2),
-		functionName: np,
-	}
-	r, err := m.run()
-	if err != nil {
-		return nil, errors.Wrap(err, "simple script execution failed")
-	}
-	return r, nil
+func (l *scopeLayout) size() int {
+	return len(l.names)
 }
 
-func (s *SimpleScript) code() []byte {
-	return s.Code
+func (l *scopeLayout) addArgument(name string) int {
+	l.names = append(l.names, name)
+	l.lets = append(l.lets, -1)
+	return len(l.names) - 1
 }
 
-type DAppScript struct {
-	LibVersion  ast.LibraryVersion
-	Code        []byte
-	Constants   []rideType
-	EntryPoints map[string]callable
+func (l *scopeLayout) addLet(name string) int {
+	l.names = append(l.names, name)
+	l.lets = append(l.lets, 0) // Address is set after compilation of let expression
+	return len(l.names) - 1
 }
 
-func (s *DAppScript) Run(env environment) (Result, error) {
-	if _, ok := s.EntryPoints[""]; !ok {
-		return nil, errors.Errorf("no verifier")
-	}
-	fs, err := selectFunctions(s.LibVersion)
-	if err != nil {
-		return nil, errors.Wrap(err, "script execution failed")
-	}
-	gcs, err := selectConstants(s.LibVersion)
-	if err != nil {
-		return nil, errors.Wrap(err, "script execution failed")
-	}
-	np, err := selectFunctionNameProvider(s.LibVersion)
-	if err != nil {
-		return nil, errors.Wrap(err, "script execution failed")
-	}
-	m := vm{
-		env:          env,
-		code:         s.Code,
-		ip:           0,
-		constants:    s.Constants,
-		functions:    fs,
-		globals:      gcs,
-		stack:        make([]rideType, 0, 2),
-		calls:        make([]frame, 0, 2),
-		functionName: np,
-	}
-	r, err := m.run()
-	if err != nil {
-		return nil, errors.Wrap(err, "script execution failed")
-	}
-	return r, nil
+// entryPoint is the verifier or a callable function of the script.
+type entryPoint struct {
+	name      string
+	parameter string // Name of invocation parameter, empty for expressions
+	arguments int
+	offset    int // Slot of the first argument
+	entry     int
+	layout    *scopeLayout
 }
 
-func (s *DAppScript) code() []byte {
-	return s.Code
+// errorContext is the description of the code that is added to the call stack of evaluation error
+// that occurred inside it, the same way as it's done by the tree evaluator.
+type errorContext struct {
+	start   int
+	end     int
+	message string
+}
+
+// Program is a script compiled to bytecode.
+type Program struct {
+	libVersion  ast.LibraryVersion
+	dApp        bool
+	code        []byte
+	constants   []rideType
+	natives     []nativeFunction
+	functions   []userFunction
+	globals     []rideConstructor
+	verifier    *entryPoint
+	callables   map[string]*entryPoint
+	contexts    []errorContext // Nested contexts go before enclosing ones
+	invocations bool           // Program calls functions that change the state of environment
 }
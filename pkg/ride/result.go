@@ -31,10 +31,22 @@ func (r ScriptResult) Complexity() int {
 	return r.complexity
 }
 
+// Invocation is the invocation of dApp made by another dApp during the evaluation.
+type Invocation struct {
+	DApp     proto.WavesAddress
+	Call     proto.FunctionCall
+	Payments proto.ScriptPayments
+	// Actions are the actions of the invoked function, the actions of its own invocations are not included.
+	Actions     []proto.ScriptAction
+	Invocations []*Invocation
+}
+
 type DAppResult struct {
-	actions    []proto.ScriptAction
-	param      rideType
-	complexity int
+	actions     []proto.ScriptAction
+	param       rideType
+	complexity  int
+	ownActions  []proto.ScriptAction
+	invocations []*Invocation
 }
 
 func (r DAppResult) Result() bool {
@@ -52,3 +64,45 @@ func (r DAppResult) ScriptActions() []proto.ScriptAction {
 func (r DAppResult) Complexity() int {
 	return r.complexity
 }
+
+// Invocations returns the actions of the called function and the invocations of other dApps made by it.
+// ScriptActions returns all actions instead, including the actions of invocations and attached payments.
+func (r DAppResult) Invocations() ([]proto.ScriptAction, []*Invocation) {
+	if r.invocations == nil {
+		return r.actions, nil
+	}
+	return r.ownActions, r.invocations
+}
+
+// ExpressionResult is a result of an arbitrary expression evaluation, unlike the verifier the result may be of any type.
+type ExpressionResult struct {
+	value      rideType
+	complexity int
+}
+
+func (r ExpressionResult) Result() bool {
+	return true
+}
+
+func (r ExpressionResult) userResult() rideType {
+	return r.value
+}
+
+func (r ExpressionResult) ScriptActions() []proto.ScriptAction {
+	return nil
+}
+
+func (r ExpressionResult) Complexity() int {
+	return r.complexity
+}
+
+// ResultValue returns the value produced by the script in a form suitable for JSON encoding.
+// Values are encoded as objects with type name and value, like `{"type": "Int", "value": 1}`.
+// Nil is returned if there is no value, for example, if the callable function returns only actions.
+func ResultValue(r Result) interface{} {
+	v := r.userResult()
+	if v == nil {
+		return nil
+	}
+	return valueToJSON(v)
+}
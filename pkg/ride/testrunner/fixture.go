@@ -0,0 +1,112 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// Suite describes the initial state of the blockchain and the invocations to test against it.
+// Suites are written in YAML or JSON, the format is chosen by the file extension.
+type Suite struct {
+	// Scheme is the address scheme byte, `W` (MainNet) is used by default.
+	Scheme    string    `json:"scheme"`
+	Height    uint64    `json:"height"`
+	Timestamp uint64    `json:"timestamp"`
+	Accounts  []Account `json:"accounts"`
+	Assets    []Asset   `json:"assets"`
+	// DApp is the name of the account invoked by the tests that don't set their own dApp.
+	DApp  string `json:"dApp"`
+	Tests []Test `json:"tests"`
+
+	dir string // Directory of the suite file, paths to scripts are relative to it.
+}
+
+// Account is an account in the initial state. Either Seed or PublicKey must be set.
+type Account struct {
+	Name      string `json:"name"`
+	Seed      string `json:"seed"`
+	PublicKey string `json:"publicKey"`
+	// Script is a path to the source code of the account script.
+	Script  string            `json:"script"`
+	Balance uint64            `json:"balance"`
+	Assets  map[string]uint64 `json:"assets"` // Balances by asset name.
+	Aliases []string          `json:"aliases"`
+	// Data entries in JSON format of the node API, like `{"key": "k", "type": "integer", "value": 1}`.
+	Data []json.RawMessage `json:"data"`
+}
+
+// Asset is an asset issued in the initial state. The identifier of the asset is derived from its name.
+type Asset struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Issuer      string `json:"issuer"`
+	Quantity    uint64 `json:"quantity"`
+	Decimals    byte   `json:"decimals"`
+	Reissuable  bool   `json:"reissuable"`
+}
+
+// Payment is an attached payment, Asset is a name of the asset from the suite or empty for Waves.
+type Payment struct {
+	Amount uint64 `json:"amount"`
+	Asset  string `json:"asset"`
+}
+
+// Test is an invocation of the callable function and the expected outcome.
+type Test struct {
+	Name     string             `json:"name"`
+	DApp     string             `json:"dApp"`
+	Caller   string             `json:"caller"`
+	Call     proto.FunctionCall `json:"call"`
+	Payments []Payment          `json:"payments"`
+	Expect   Expectation        `json:"expect"`
+}
+
+// Transfer is an expected transfer, Recipient is a name of the account or an address.
+type Transfer struct {
+	Recipient string `json:"recipient"`
+	Amount    int64  `json:"amount"`
+	Asset     string `json:"asset"`
+}
+
+// Expectation describes the outcome of the invocation, only the set fields are checked.
+type Expectation struct {
+	// Error is a substring of the expected evaluation error. If empty the invocation is expected to succeed.
+	Error     string            `json:"error"`
+	Data      []json.RawMessage `json:"data"`
+	Transfers []Transfer        `json:"transfers"`
+	// Actions is the expected number of all produced actions.
+	Actions       *int `json:"actions"`
+	MaxComplexity int  `json:"maxComplexity"`
+}
+
+// LoadSuite reads the suite from the YAML or JSON file.
+func LoadSuite(path string) (*Suite, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read suite")
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		// Types of the node API implement only JSON unmarshalling, so YAML is converted to JSON first.
+		var v interface{}
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, errors.Wrap(err, "failed to parse suite")
+		}
+		b, err = json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse suite")
+		}
+	}
+	s := new(Suite)
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrap(err, "failed to parse suite")
+	}
+	s.dir = filepath.Dir(path)
+	return s, nil
+}
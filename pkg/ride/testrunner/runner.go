@@ -0,0 +1,433 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+const (
+	defaultHeight    = 1000
+	defaultTimestamp = 1672531200000 // 2023-01-01T00:00:00Z
+	invokeFee        = 500000
+)
+
+// Result is the outcome of a single test.
+type Result struct {
+	Name       string
+	Complexity int
+	// Failure describes the reason of the test failure, it's nil if the test passed.
+	Failure error
+}
+
+func (r Result) Passed() bool {
+	return r.Failure == nil
+}
+
+// Runner runs the tests of the suite. The state is built once and shared by all tests because invocations
+// never modify it: all changes are accumulated in the wrapped state of the evaluation environment.
+type Runner struct {
+	suite    *Suite
+	scheme   proto.Scheme
+	state    *memoryState
+	accounts map[string]*account
+	assets   map[string]crypto.Digest
+}
+
+// SetScript sets the path to the script of the account, it overrides the path from the suite.
+// Unlike paths in the suite, relative path is resolved against the working directory.
+func (s *Suite) SetScript(accountName, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for i := range s.Accounts {
+		if s.Accounts[i].Name == accountName {
+			s.Accounts[i].Script = abs
+			return nil
+		}
+	}
+	return errors.Errorf("account '%s' is not defined", accountName)
+}
+
+func NewRunner(suite *Suite) (*Runner, error) {
+	r := &Runner{
+		suite:    suite,
+		scheme:   proto.MainNetScheme,
+		accounts: make(map[string]*account),
+		assets:   make(map[string]crypto.Digest),
+	}
+	if suite.Scheme != "" {
+		if len(suite.Scheme) != 1 {
+			return nil, errors.Errorf("invalid scheme '%s'", suite.Scheme)
+		}
+		r.scheme = suite.Scheme[0]
+	}
+	height, timestamp := suite.Height, suite.Timestamp
+	if height == 0 {
+		height = defaultHeight
+	}
+	if timestamp == 0 {
+		timestamp = defaultTimestamp
+	}
+	r.state = newMemoryState(r.scheme, height, timestamp)
+	for i := range suite.Accounts {
+		if err := r.addAccount(&suite.Accounts[i]); err != nil {
+			return nil, errors.Wrapf(err, "invalid account '%s'", suite.Accounts[i].Name)
+		}
+	}
+	for i := range suite.Assets {
+		if err := r.addAsset(&suite.Assets[i]); err != nil {
+			return nil, errors.Wrapf(err, "invalid asset '%s'", suite.Assets[i].Name)
+		}
+	}
+	// Balances are set after the assets are known.
+	for _, a := range suite.Accounts {
+		for name, amount := range a.Assets {
+			id, err := r.assetID(name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid account '%s'", a.Name)
+			}
+			r.accounts[a.Name].assets[id] = amount
+		}
+	}
+	return r, nil
+}
+
+func (r *Runner) addAccount(a *Account) error {
+	if a.Name == "" {
+		return errors.New("empty name")
+	}
+	if _, ok := r.accounts[a.Name]; ok {
+		return errors.New("duplicate name")
+	}
+	var (
+		pk  crypto.PublicKey
+		err error
+	)
+	switch {
+	case a.PublicKey != "":
+		pk, err = crypto.NewPublicKeyFromBase58(a.PublicKey)
+	case a.Seed != "":
+		_, pk, err = crypto.GenerateKeyPair([]byte(a.Seed))
+	default:
+		err = errors.New("seed or public key must be set")
+	}
+	if err != nil {
+		return err
+	}
+	addr, err := proto.NewAddressFromPublicKey(r.scheme, pk)
+	if err != nil {
+		return err
+	}
+	acc := &account{
+		pk:      pk,
+		address: addr,
+		balance: a.Balance,
+		assets:  make(map[crypto.Digest]uint64),
+		data:    make(map[string]proto.DataEntry, len(a.Data)),
+	}
+	for _, d := range a.Data {
+		e, err := proto.NewDataEntryFromJSON(d)
+		if err != nil {
+			return err
+		}
+		acc.data[e.GetKey()] = e
+	}
+	if a.Script != "" {
+		acc.script, acc.tree, err = r.compile(a.Script)
+		if err != nil {
+			return err
+		}
+	}
+	for _, alias := range a.Aliases {
+		r.state.aliases[alias] = addr
+	}
+	r.accounts[a.Name] = acc
+	r.state.accounts[addr] = acc
+	return nil
+}
+
+func (r *Runner) compile(path string) (proto.Script, *ast.Tree, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.suite.dir, path)
+	}
+	src, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read script")
+	}
+	script, errs := compiler.Compile(string(src), false, false)
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return nil, nil, errors.Errorf("failed to compile script '%s': %s", path, strings.Join(msgs, "; "))
+	}
+	tree, err := serialization.Parse(script)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse script '%s'", path)
+	}
+	return script, tree, nil
+}
+
+func (r *Runner) addAsset(a *Asset) error {
+	if a.Name == "" {
+		return errors.New("empty name")
+	}
+	if _, ok := r.assets[a.Name]; ok {
+		return errors.New("duplicate name")
+	}
+	issuer, ok := r.accounts[a.Issuer]
+	if !ok {
+		return errors.Errorf("issuer '%s' is not defined", a.Issuer)
+	}
+	id, err := crypto.FastHash([]byte(a.Name))
+	if err != nil {
+		return err
+	}
+	r.assets[a.Name] = id
+	r.state.assets[id] = &proto.FullAssetInfo{
+		AssetInfo: proto.AssetInfo{
+			ID:              id,
+			Quantity:        a.Quantity,
+			Decimals:        a.Decimals,
+			Issuer:          issuer.address,
+			IssuerPublicKey: issuer.pk,
+			Reissuable:      a.Reissuable,
+			IssueHeight:     1,
+		},
+		Name:        a.Name,
+		Description: a.Description,
+	}
+	return nil
+}
+
+// assetID resolves the name of the asset from the suite, base58 encoded identifiers are accepted too.
+func (r *Runner) assetID(name string) (crypto.Digest, error) {
+	if id, ok := r.assets[name]; ok {
+		return id, nil
+	}
+	id, err := crypto.NewDigestFromBase58(name)
+	if err != nil {
+		return crypto.Digest{}, errors.Errorf("unknown asset '%s'", name)
+	}
+	return id, nil
+}
+
+func (r *Runner) optionalAsset(name string) (proto.OptionalAsset, error) {
+	if name == "" || strings.EqualFold(name, "WAVES") {
+		return proto.NewOptionalAssetWaves(), nil
+	}
+	id, err := r.assetID(name)
+	if err != nil {
+		return proto.OptionalAsset{}, err
+	}
+	return *proto.NewOptionalAssetFromDigest(id), nil
+}
+
+// address resolves the name of the account from the suite, addresses are accepted too.
+func (r *Runner) address(name string) (proto.WavesAddress, error) {
+	if acc, ok := r.accounts[name]; ok {
+		return acc.address, nil
+	}
+	addr, err := proto.NewAddressFromString(name)
+	if err != nil {
+		return proto.WavesAddress{}, errors.Errorf("unknown account '%s'", name)
+	}
+	return addr, nil
+}
+
+// Run runs all tests of the suite in order.
+func (r *Runner) Run() []Result {
+	results := make([]Result, len(r.suite.Tests))
+	for i := range r.suite.Tests {
+		t := &r.suite.Tests[i]
+		results[i] = Result{Name: t.Name}
+		if results[i].Name == "" {
+			results[i].Name = fmt.Sprintf("#%d", i+1)
+		}
+		results[i].Complexity, results[i].Failure = r.run(t)
+	}
+	return results
+}
+
+func (r *Runner) run(t *Test) (int, error) {
+	dAppName := t.DApp
+	if dAppName == "" {
+		dAppName = r.suite.DApp
+	}
+	dApp, ok := r.accounts[dAppName]
+	if !ok {
+		return 0, errors.Errorf("dApp '%s' is not defined", dAppName)
+	}
+	if dApp.tree == nil || !dApp.tree.IsDApp() {
+		return 0, errors.Errorf("account '%s' has no dApp script", dAppName)
+	}
+	caller, ok := r.accounts[t.Caller]
+	if !ok {
+		return 0, errors.Errorf("caller '%s' is not defined", t.Caller)
+	}
+	payments := make(proto.ScriptPayments, len(t.Payments))
+	for i, p := range t.Payments {
+		asset, err := r.optionalAsset(p.Asset)
+		if err != nil {
+			return 0, err
+		}
+		payments[i] = proto.ScriptPayment{Amount: p.Amount, Asset: asset}
+	}
+	tx := proto.NewUnsignedInvokeScriptWithProofs(2, caller.pk, proto.NewRecipientFromAddress(dApp.address), t.Call,
+		payments, proto.NewOptionalAssetWaves(), invokeFee, r.state.timestamp)
+	tx.Proofs = proto.NewProofs() // Transaction is not signed, scripts see an empty list of proofs.
+	if err := tx.GenerateID(r.scheme); err != nil {
+		return 0, err
+	}
+	env, err := r.environment(dApp, caller, tx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create environment")
+	}
+	res, err := ride.CallFunction(env, dApp.tree, t.Call)
+	if err != nil {
+		complexity := ride.EvaluationErrorSpentComplexity(err)
+		if t.Expect.Error == "" {
+			return complexity, errors.Wrap(err, "invocation failed")
+		}
+		if !strings.Contains(err.Error(), t.Expect.Error) {
+			return complexity, errors.Errorf("expected error containing '%s', got '%s'", t.Expect.Error, err.Error())
+		}
+		return complexity, nil
+	}
+	if t.Expect.Error != "" {
+		return res.Complexity(), errors.Errorf("expected error containing '%s', but invocation succeeded", t.Expect.Error)
+	}
+	return res.Complexity(), r.check(&t.Expect, res)
+}
+
+// environment creates the evaluation environment the same way the node does for the invoke script transaction.
+func (r *Runner) environment(dApp, caller *account, tx *proto.InvokeScriptWithProofs) (*ride.EvaluationEnvironment, error) {
+	env, err := ride.NewEnvironment(r.scheme, r.state, 0, true, true, true, true, false)
+	if err != nil {
+		return nil, err
+	}
+	env.SetThisFromAddress(dApp.address)
+	env.ChooseSizeCheck(dApp.tree.LibVersion)
+	blockInfo, err := r.state.NewestBlockInfoByHeight(r.state.height)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.SetLastBlockFromBlockInfo(blockInfo); err != nil {
+		return nil, err
+	}
+	env.SetTimestamp(tx.Timestamp)
+	env.ChooseTakeString(true)
+	env.ChooseMaxDataEntriesSize(true)
+	limit, err := ride.MaxChainInvokeComplexityByVersion(dApp.tree.LibVersion)
+	if err != nil {
+		return nil, err
+	}
+	env.SetLimit(limit)
+	if err := env.SetTransaction(tx); err != nil {
+		return nil, err
+	}
+	if err := env.SetInvoke(tx, dApp.tree.LibVersion); err != nil {
+		return nil, err
+	}
+	if dApp.tree.LibVersion >= ast.LibV5 {
+		return ride.NewEnvironmentWithWrappedState(env, tx.Payments, caller.address, true, dApp.tree.LibVersion, true)
+	}
+	return env, nil
+}
+
+func (r *Runner) check(e *Expectation, res ride.Result) error {
+	if e.MaxComplexity > 0 && res.Complexity() > e.MaxComplexity {
+		return errors.Errorf("complexity %d exceeds %d", res.Complexity(), e.MaxComplexity)
+	}
+	actions := res.ScriptActions()
+	if e.Actions != nil && len(actions) != *e.Actions {
+		return errors.Errorf("expected %d actions, got %d", *e.Actions, len(actions))
+	}
+	var (
+		entries   []proto.DataEntry
+		transfers []*proto.TransferScriptAction
+	)
+	for _, a := range actions {
+		switch ta := a.(type) {
+		case *proto.DataEntryScriptAction:
+			entries = append(entries, ta.Entry)
+		case *proto.TransferScriptAction:
+			transfers = append(transfers, ta)
+		}
+	}
+	if e.Data != nil {
+		if err := checkData(e.Data, entries); err != nil {
+			return err
+		}
+	}
+	if e.Transfers != nil {
+		if err := r.checkTransfers(e.Transfers, transfers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkData(expected []json.RawMessage, actual []proto.DataEntry) error {
+	if len(expected) != len(actual) {
+		return errors.Errorf("expected %d data entries, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		e, err := proto.NewDataEntryFromJSON(expected[i])
+		if err != nil {
+			return errors.Wrap(err, "invalid expected data entry")
+		}
+		eb, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		ab, err := json.Marshal(actual[i])
+		if err != nil {
+			return err
+		}
+		if string(eb) != string(ab) {
+			return errors.Errorf("data entry #%d: expected %s, got %s", i+1, eb, ab)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) checkTransfers(expected []Transfer, actual []*proto.TransferScriptAction) error {
+	if len(expected) != len(actual) {
+		return errors.Errorf("expected %d transfers, got %d", len(expected), len(actual))
+	}
+	for i, e := range expected {
+		addr, err := r.address(e.Recipient)
+		if err != nil {
+			return err
+		}
+		asset, err := r.optionalAsset(e.Asset)
+		if err != nil {
+			return err
+		}
+		a := actual[i]
+		actualAddr, err := r.state.NewestRecipientToAddress(a.Recipient)
+		if err != nil {
+			return errors.Wrapf(err, "transfer #%d: failed to resolve recipient", i+1)
+		}
+		if actualAddr != addr || a.Amount != e.Amount || a.Asset != asset {
+			return errors.Errorf("transfer #%d: expected %d of %s to %s, got %d of %s to %s", i+1,
+				e.Amount, asset.String(), addr.String(), a.Amount, a.Asset.String(), actualAddr.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner(t *testing.T) {
+	suite, err := LoadSuite("testdata/wallet.yaml")
+	require.NoError(t, err)
+	r, err := NewRunner(suite)
+	require.NoError(t, err)
+	results := r.Run()
+	require.Len(t, results, 5)
+	for _, res := range results[:4] {
+		assert.True(t, res.Passed(), "%s: %v", res.Name, res.Failure)
+		assert.Positive(t, res.Complexity, res.Name)
+	}
+	assert.False(t, results[4].Passed())
+	assert.EqualError(t, results[4].Failure, "transfer #1: expected 200 of WAVES to 3P3Pvk3nLkpihvKfLixUHWZkYHbuhGv5Nt2, got 200 of WAVES to 3PPJ1KuhKqwtfig27c9xjVmeACYPA3ytF6d")
+}
+
+func TestNewRunnerErrors(t *testing.T) {
+	for _, test := range []struct {
+		suite *Suite
+		err   string
+	}{
+		{&Suite{Accounts: []Account{{Name: "a"}}}, "invalid account 'a': seed or public key must be set"},
+		{&Suite{Accounts: []Account{{Name: "a", Seed: "a"}, {Name: "a", Seed: "b"}}}, "invalid account 'a': duplicate name"},
+		{&Suite{Assets: []Asset{{Name: "t", Issuer: "a"}}}, "invalid asset 't': issuer 'a' is not defined"},
+		{&Suite{Accounts: []Account{{Name: "a", Seed: "a", Assets: map[string]uint64{"t": 1}}}}, "invalid account 'a': unknown asset 't'"},
+	} {
+		_, err := NewRunner(test.suite)
+		assert.EqualError(t, err, test.err)
+	}
+}
@@ -0,0 +1,301 @@
+package testrunner
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+var errNotFound = errors.New("not found")
+
+const (
+	estimatorVersion = 4
+	blockInterval    = 60000
+)
+
+type account struct {
+	pk      crypto.PublicKey
+	address proto.WavesAddress
+	balance uint64
+	assets  map[crypto.Digest]uint64
+	data    map[string]proto.DataEntry
+	script  proto.Script
+	tree    *ast.Tree
+}
+
+// memoryState is the read-only state built from the suite, it implements types.SmartState.
+// Changes made by the invocations are kept in the wrapped state of the environment and never reach it.
+type memoryState struct {
+	scheme    proto.Scheme
+	height    proto.Height
+	timestamp uint64
+	accounts  map[proto.WavesAddress]*account
+	aliases   map[string]proto.WavesAddress
+	assets    map[crypto.Digest]*proto.FullAssetInfo
+}
+
+func newMemoryState(scheme proto.Scheme, height proto.Height, timestamp uint64) *memoryState {
+	return &memoryState{
+		scheme:    scheme,
+		height:    height,
+		timestamp: timestamp,
+		accounts:  make(map[proto.WavesAddress]*account),
+		aliases:   make(map[string]proto.WavesAddress),
+		assets:    make(map[crypto.Digest]*proto.FullAssetInfo),
+	}
+}
+
+func (s *memoryState) account(rcp proto.Recipient) (*account, error) {
+	addr, err := s.NewestRecipientToAddress(rcp)
+	if err != nil {
+		return nil, err
+	}
+	acc, ok := s.accounts[addr]
+	if !ok {
+		return nil, errNotFound
+	}
+	return acc, nil
+}
+
+func (s *memoryState) NewestScriptPKByAddr(addr proto.WavesAddress) (crypto.PublicKey, error) {
+	acc, ok := s.accounts[addr]
+	if !ok {
+		return crypto.PublicKey{}, errNotFound
+	}
+	return acc.pk, nil
+}
+
+func (s *memoryState) AddingBlockHeight() (uint64, error) {
+	return s.height, nil
+}
+
+func (s *memoryState) NewestTransactionByID([]byte) (proto.Transaction, error) {
+	return nil, errNotFound
+}
+
+func (s *memoryState) NewestTransactionHeightByID([]byte) (uint64, error) {
+	return 0, errNotFound
+}
+
+func (s *memoryState) NewestScriptByAccount(rcp proto.Recipient) (*ast.Tree, error) {
+	acc, err := s.account(rcp)
+	if err != nil {
+		return nil, err
+	}
+	if acc.tree == nil {
+		return nil, errNotFound
+	}
+	return acc.tree, nil
+}
+
+func (s *memoryState) NewestScriptBytesByAccount(rcp proto.Recipient) (proto.Script, error) {
+	acc, err := s.account(rcp)
+	if err != nil {
+		return nil, err
+	}
+	if acc.script == nil {
+		return proto.Script{}, nil
+	}
+	return acc.script, nil
+}
+
+func (s *memoryState) NewestRecipientToAddress(rcp proto.Recipient) (proto.WavesAddress, error) {
+	if addr := rcp.Address(); addr != nil {
+		return *addr, nil
+	}
+	if alias := rcp.Alias(); alias != nil {
+		return s.NewestAddrByAlias(*alias)
+	}
+	return proto.WavesAddress{}, errors.New("empty recipient")
+}
+
+func (s *memoryState) NewestAddrByAlias(alias proto.Alias) (proto.WavesAddress, error) {
+	addr, ok := s.aliases[alias.Alias]
+	if !ok {
+		return proto.WavesAddress{}, errNotFound
+	}
+	return addr, nil
+}
+
+func (s *memoryState) NewestLeasingInfo(crypto.Digest) (*proto.LeaseInfo, error) {
+	return nil, errNotFound
+}
+
+func (s *memoryState) IsStateUntouched(rcp proto.Recipient) (bool, error) {
+	acc, err := s.account(rcp)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return len(acc.data) == 0, nil
+}
+
+func (s *memoryState) NewestAssetBalance(rcp proto.Recipient, assetID crypto.Digest) (uint64, error) {
+	acc, err := s.account(rcp)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return acc.assets[assetID], nil
+}
+
+func (s *memoryState) NewestWavesBalance(rcp proto.Recipient) (uint64, error) {
+	acc, err := s.account(rcp)
+	if err != nil {
+		if s.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return acc.balance, nil
+}
+
+func (s *memoryState) NewestFullWavesBalance(rcp proto.Recipient) (*proto.FullWavesBalance, error) {
+	b, err := s.NewestWavesBalance(rcp)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.FullWavesBalance{Regular: b, Generating: b, Available: b, Effective: b}, nil
+}
+
+func (s *memoryState) entry(rcp proto.Recipient, key string) (proto.DataEntry, error) {
+	acc, err := s.account(rcp)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := acc.data[key]
+	if !ok {
+		return nil, errNotFound
+	}
+	return e, nil
+}
+
+func (s *memoryState) RetrieveNewestIntegerEntry(rcp proto.Recipient, key string) (*proto.IntegerDataEntry, error) {
+	e, err := s.entry(rcp, key)
+	if err != nil {
+		return nil, err
+	}
+	if ie, ok := e.(*proto.IntegerDataEntry); ok {
+		return ie, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *memoryState) RetrieveNewestBooleanEntry(rcp proto.Recipient, key string) (*proto.BooleanDataEntry, error) {
+	e, err := s.entry(rcp, key)
+	if err != nil {
+		return nil, err
+	}
+	if be, ok := e.(*proto.BooleanDataEntry); ok {
+		return be, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *memoryState) RetrieveNewestStringEntry(rcp proto.Recipient, key string) (*proto.StringDataEntry, error) {
+	e, err := s.entry(rcp, key)
+	if err != nil {
+		return nil, err
+	}
+	if se, ok := e.(*proto.StringDataEntry); ok {
+		return se, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *memoryState) RetrieveNewestBinaryEntry(rcp proto.Recipient, key string) (*proto.BinaryDataEntry, error) {
+	e, err := s.entry(rcp, key)
+	if err != nil {
+		return nil, err
+	}
+	if be, ok := e.(*proto.BinaryDataEntry); ok {
+		return be, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *memoryState) NewestAssetIsSponsored(assetID crypto.Digest) (bool, error) {
+	info, ok := s.assets[assetID]
+	if !ok {
+		return false, errNotFound
+	}
+	return info.Sponsored, nil
+}
+
+func (s *memoryState) NewestAssetInfo(assetID crypto.Digest) (*proto.AssetInfo, error) {
+	info, ok := s.assets[assetID]
+	if !ok {
+		return nil, errNotFound
+	}
+	ai := info.AssetInfo
+	return &ai, nil
+}
+
+func (s *memoryState) NewestFullAssetInfo(assetID crypto.Digest) (*proto.FullAssetInfo, error) {
+	info, ok := s.assets[assetID]
+	if !ok {
+		return nil, errNotFound
+	}
+	fi := *info
+	return &fi, nil
+}
+
+func (s *memoryState) NewestScriptByAsset(crypto.Digest) (*ast.Tree, error) {
+	return nil, errNotFound
+}
+
+func (s *memoryState) NewestBlockInfoByHeight(height proto.Height) (*proto.BlockInfo, error) {
+	if height == 0 || height > s.height {
+		return nil, errNotFound
+	}
+	// Blocks before the current one are generated every minute.
+	timestamp := s.timestamp
+	if d := (s.height - height) * blockInterval; d < timestamp {
+		timestamp -= d
+	}
+	return &proto.BlockInfo{
+		Version:   proto.ProtobufBlockVersion,
+		Timestamp: timestamp,
+		Height:    height,
+	}, nil
+}
+
+func (s *memoryState) EstimatorVersion() (int, error) {
+	return estimatorVersion, nil
+}
+
+func (s *memoryState) IsNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+func (s *memoryState) accountByID(id proto.AddressID) (*account, bool) {
+	addr, err := id.ToWavesAddress(s.scheme)
+	if err != nil {
+		return nil, false
+	}
+	acc, ok := s.accounts[addr]
+	return acc, ok
+}
+
+func (s *memoryState) WavesBalanceProfile(id proto.AddressID) (*types.WavesBalanceProfile, error) {
+	acc, ok := s.accountByID(id)
+	if !ok {
+		return &types.WavesBalanceProfile{}, nil
+	}
+	return &types.WavesBalanceProfile{Balance: acc.balance, Generating: acc.balance}, nil
+}
+
+func (s *memoryState) NewestAssetBalanceByAddressID(id proto.AddressID, asset crypto.Digest) (uint64, error) {
+	acc, ok := s.accountByID(id)
+	if !ok {
+		return 0, nil
+	}
+	return acc.assets[asset], nil
+}
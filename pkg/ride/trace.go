@@ -0,0 +1,101 @@
+package ride
+
+import (
+	"strings"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/decompiler"
+)
+
+type TraceStepKind string
+
+const (
+	// TraceEvaluation is the root step of a script evaluation, the name of the step is the name of called function.
+	TraceEvaluation TraceStepKind = "evaluation"
+	// TraceLet is an evaluation of a variable, variables are evaluated lazily on the first reference.
+	TraceLet TraceStepKind = "let"
+	// TraceUserFunction is a call of a function declared in the script.
+	TraceUserFunction TraceStepKind = "userFunction"
+	// TraceNativeFunction is a call of a function from the standard library.
+	TraceNativeFunction TraceStepKind = "nativeFunction"
+	// TraceStateRead is a call of a function from the standard library that reads the blockchain state.
+	TraceStateRead TraceStepKind = "stateRead"
+)
+
+const verifierTraceName = "@Verifier"
+
+// stateReadFunctions is a set of identifiers of the functions that read the blockchain state.
+var stateReadFunctions = map[string]struct{}{
+	"1000": {}, "1001": {}, "1003": {}, "1004": {}, "1005": {}, "1006": {}, "1007": {}, "1008": {}, "1009": {},
+	"1050": {}, "1051": {}, "1052": {}, "1053": {}, "1054": {}, "1055": {}, "1056": {}, "1057": {}, "1058": {},
+	"1060": {}, "wavesBalance": {},
+}
+
+// TraceStep describes a single step of the script evaluation.
+// Values are rendered in the same text form as in evaluation errors.
+type TraceStep struct {
+	Kind       TraceStepKind `json:"kind"`
+	Name       string        `json:"name"`
+	Depth      int           `json:"depth"`
+	This       string        `json:"this,omitempty"`
+	Arguments  []string      `json:"args,omitempty"`
+	Result     string        `json:"result,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Complexity int           `json:"complexity"` // Complexity spent by the step including the nested steps.
+}
+
+// Trace records the steps of script evaluations in order of their beginning.
+// Nested steps follow the parent step and have greater depth. Arguments of a function call are evaluated
+// before the call, so the steps of arguments evaluation precede the step of the call on the same depth.
+type Trace struct {
+	Steps []TraceStep `json:"steps"`
+	depth int
+}
+
+func NewTrace() *Trace {
+	return &Trace{Steps: make([]TraceStep, 0)}
+}
+
+func (t *Trace) begin(kind TraceStepKind, name string, args []rideType) int {
+	step := TraceStep{Kind: kind, Name: name, Depth: t.depth}
+	if len(args) > 0 {
+		step.Arguments = make([]string, len(args))
+		for i, a := range args {
+			step.Arguments[i] = a.String()
+		}
+	}
+	t.Steps = append(t.Steps, step)
+	t.depth++
+	return len(t.Steps) - 1
+}
+
+func (t *Trace) end(i int, r rideType, err error, complexity int) {
+	t.depth--
+	step := &t.Steps[i]
+	if err != nil {
+		step.Error = err.Error()
+	} else if r != nil {
+		step.Result = r.String()
+	}
+	step.Complexity = complexity
+}
+
+// tracingEnvironment is implemented by environments that record the evaluation trace.
+type tracingEnvironment interface {
+	trace() *Trace
+}
+
+func environmentTrace(env environment) *Trace {
+	if te, ok := env.(tracingEnvironment); ok {
+		return te.trace()
+	}
+	return nil
+}
+
+func nativeFunctionTraceStep(id string) (TraceStepKind, string) {
+	// Functions that throw on absent value are wrapped around the original ones, like `@extrNative(1050)`.
+	base := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(id, "@extrNative("), "@extrUser("), ")")
+	if _, ok := stateReadFunctions[base]; ok {
+		return TraceStateRead, decompiler.FunctionName(id)
+	}
+	return TraceNativeFunction, decompiler.FunctionName(id)
+}
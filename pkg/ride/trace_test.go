@@ -0,0 +1,102 @@
+package ride
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	ridec "github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+type tracingTestEnvironment struct {
+	*mockRideEnvironment
+	tr *Trace
+}
+
+func (e *tracingTestEnvironment) trace() *Trace {
+	return e.tr
+}
+
+func TestTrace(t *testing.T) {
+	src := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+let x = 1 + 2
+func double(a: Int) = a * 2
+double(x) == 6 && getIntegerValue(this, "key") == 1`
+	bin, errs := ridec.Compile(src, false, false)
+	require.Empty(t, errs)
+	tree, err := serialization.Parse(bin)
+	require.NoError(t, err)
+
+	acc := newTestAccount(t, "TEST")
+	te := newTestEnv(t).withLibVersion(tree.LibVersion).withComplexityLimit(tree.LibVersion, 2000).
+		withThis(acc).withDataEntries(acc, &proto.IntegerDataEntry{Key: "key", Value: 1})
+	env := &tracingTestEnvironment{mockRideEnvironment: te.toEnv(), tr: NewTrace()}
+
+	res, err := CallVerifier(env, tree)
+	require.NoError(t, err)
+	require.True(t, res.Result())
+
+	steps := env.tr.Steps
+	require.NotEmpty(t, steps)
+	root := steps[0]
+	assert.Equal(t, TraceEvaluation, root.Kind)
+	assert.Equal(t, verifierTraceName, root.Name)
+	assert.Equal(t, acc.address().String(), root.This)
+	assert.Equal(t, "true", root.Result)
+	assert.Equal(t, res.Complexity(), root.Complexity)
+
+	find := func(kind TraceStepKind, name string) TraceStep {
+		for _, s := range steps {
+			if s.Kind == kind && s.Name == name {
+				return s
+			}
+		}
+		require.Failf(t, "step not found", "%s %s", kind, name)
+		return TraceStep{}
+	}
+	let := find(TraceLet, "x")
+	assert.Equal(t, 1, let.Depth)
+	assert.Equal(t, "3", let.Result)
+	double := find(TraceUserFunction, "double")
+	assert.Equal(t, 1, double.Depth)
+	assert.Equal(t, []string{"3"}, double.Arguments)
+	assert.Equal(t, "6", double.Result)
+	mul := find(TraceNativeFunction, "*")
+	assert.Equal(t, 2, mul.Depth)
+	assert.Equal(t, 1, mul.Complexity)
+	read := find(TraceStateRead, "getIntegerValue")
+	assert.Equal(t, "1", read.Result)
+	for _, s := range steps[1:] {
+		assert.Greater(t, s.Depth, 0)
+	}
+}
+
+func TestTraceError(t *testing.T) {
+	src := `{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+func check(a: Int) = if (a > 0) then true else throw("negative")
+check(-1)`
+	bin, errs := ridec.Compile(src, false, false)
+	require.Empty(t, errs)
+	tree, err := serialization.Parse(bin)
+	require.NoError(t, err)
+
+	te := newTestEnv(t).withLibVersion(tree.LibVersion).withComplexityLimit(tree.LibVersion, 2000).
+		withThis(newTestAccount(t, "TEST"))
+	env := &tracingTestEnvironment{mockRideEnvironment: te.toEnv(), tr: NewTrace()}
+
+	_, err = CallVerifier(env, tree)
+	require.Error(t, err)
+	steps := env.tr.Steps
+	require.NotEmpty(t, steps)
+	assert.NotEmpty(t, steps[0].Error)
+	assert.Empty(t, steps[0].Result)
+	last := steps[len(steps)-1]
+	assert.Equal(t, TraceNativeFunction, last.Kind)
+	assert.Equal(t, "throw", last.Name)
+	assert.Contains(t, last.Error, "negative")
+}
@@ -7,13 +7,30 @@ import (
 )
 
 func CallVerifier(env environment, tree *ast.Tree) (Result, error) {
-	e, err := treeVerifierEvaluator(env, tree)
+	e, err := verifierEvaluator(env, tree)
 	if err != nil {
 		return nil, RuntimeError.Wrap(err, "failed to call verifier")
 	}
 	return e.evaluate()
 }
 
+// EvaluateExpression evaluates the expression script. Unlike CallVerifier the result of the expression may be
+// of any type, it's returned as ExpressionResult.
+func EvaluateExpression(env environment, tree *ast.Tree) (Result, error) {
+	if tree.IsDApp() {
+		return nil, EvaluationFailure.New("unable to evaluate DApp as an expression")
+	}
+	e, err := verifierEvaluator(env, tree)
+	if err != nil {
+		return nil, RuntimeError.Wrap(err, "failed to evaluate expression")
+	}
+	r, err := e.walkRoot()
+	if err != nil {
+		return nil, EvaluationErrorSetComplexity(err, e.complexity())
+	}
+	return ExpressionResult{value: r, complexity: e.complexity()}, nil
+}
+
 func CallFunction(env environment, tree *ast.Tree, fc proto.FunctionCall) (Result, error) {
 	var (
 		name = fc.Name()
@@ -23,7 +40,7 @@ func CallFunction(env environment, tree *ast.Tree, fc proto.FunctionCall) (Resul
 	if err != nil {
 		return nil, EvaluationFailure.Wrapf(err, "failed to call function '%s'", name)
 	}
-	e, err := treeFunctionEvaluator(env, tree, name, arguments)
+	e, err := functionEvaluator(env, tree, name, arguments)
 	if err != nil {
 		return nil, EvaluationFailure.Wrapf(err, "failed to call function '%s'", name)
 	}
@@ -55,6 +72,10 @@ func CallFunction(env environment, tree *ast.Tree, fc proto.FunctionCall) (Resul
 	if tree.LibVersion < ast.LibV5 { // Shortcut because no wrapped state before version 5
 		return rideResult, nil
 	}
+	if ws, ok := env.state().(*WrappedState); ok && len(ws.invocations) > 0 {
+		dAppResult.ownActions = dAppResult.actions
+		dAppResult.invocations = ws.invocations
+	}
 	// Add actions from wrapped state
 	// Append actions of the original call to the end of actions collected in wrapped state
 	dAppResult.actions = append(wrappedStateActions(env.state()), dAppResult.actions...)
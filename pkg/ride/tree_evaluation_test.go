@@ -702,6 +702,15 @@ func TestInvokeDAppFromDAppAllActions(t *testing.T) {
 
 	assert.Equal(t, expectedActionsResult, sr)
 
+	own, invocations := r.Invocations()
+	assert.Equal(t, []proto.ScriptAction{&proto.DataEntryScriptAction{Entry: &proto.IntegerDataEntry{Key: "key", Value: 1}}}, own)
+	require.Len(t, invocations, 1)
+	assert.Equal(t, dApp2.address(), invocations[0].DApp)
+	assert.Equal(t, "testActions", invocations[0].Call.Name())
+	assert.Equal(t, proto.ScriptPayments{{Amount: 1234}, {Amount: 1234}}, invocations[0].Payments)
+	assert.Len(t, invocations[0].Actions, 10)
+	assert.Empty(t, invocations[0].Invocations)
+
 	fullBalanceExpected := &proto.FullWavesBalance{
 		Regular:    7533,
 		Generating: 0,
@@ -5661,3 +5670,30 @@ func TestRideBlockInfoV7(t *testing.T) {
 	}
 	assert.Equal(t, expectedResult, sr)
 }
+
+func TestEvaluateExpression(t *testing.T) {
+	acc := newTestAccount(t, "TEST")
+	for _, test := range []struct {
+		src      string
+		expected interface{}
+	}{
+		{`getIntegerValue(this, "key") + 1`, jsonValue{Type: "Int", Value: int64(2)}},
+		{`(toBigInt(1), "a", base58'2')`, jsonValue{Type: "Tuple", Value: []jsonValue{
+			{Type: "BigInt", Value: "1"}, {Type: "String", Value: "a"}, {Type: "ByteVector", Value: "2"},
+		}}},
+		{`[true, false]`, jsonValue{Type: "Array", Value: []jsonValue{
+			{Type: "Boolean", Value: true}, {Type: "Boolean", Value: false},
+		}}},
+		{`unit`, jsonValue{Type: "Unit", Value: struct{}{}}},
+	} {
+		src := "{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\n" + test.src
+		tree, errs := ridec.CompileEvaluatedExpression(src)
+		require.Empty(t, errs, test.src)
+		env := newTestEnv(t).withLibVersion(tree.LibVersion).withComplexityLimit(tree.LibVersion, 2000).
+			withThis(acc).withDataEntries(acc, &proto.IntegerDataEntry{Key: "key", Value: 1}).toEnv()
+		res, err := EvaluateExpression(env, tree)
+		require.NoError(t, err, test.src)
+		assert.Equal(t, test.expected, ResultValue(res), test.src)
+		assert.Empty(t, res.ScriptActions())
+	}
+}
@@ -186,10 +186,13 @@ func selectConstantNames(v ast.LibraryVersion) ([]string, error) {
 }
 
 type treeEvaluator struct {
-	dapp bool
-	f    ast.Node
-	s    evaluationScope
-	env  environment
+	dapp  bool
+	f     ast.Node
+	s     evaluationScope
+	env   environment
+	name  string     // Name of the called function, used for tracing only.
+	args  []rideType // Arguments of the called function, used for tracing only.
+	trace *Trace
 }
 
 func (e *treeEvaluator) complexity() int {
@@ -197,36 +200,41 @@ func (e *treeEvaluator) complexity() int {
 }
 
 func (e *treeEvaluator) evaluate() (Result, error) {
-	r, err := e.walk(e.f)
+	r, err := e.walkRoot()
 	if err != nil {
 		return nil, err // Evaluation failed somehow, then result just an error
 	}
+	return newEvaluationResult(e.env, r)
+}
 
+// newEvaluationResult converts the value returned by the script to the result of evaluation.
+func newEvaluationResult(env environment, r rideType) (Result, error) {
+	complexity := env.complexityCalculator().complexity()
 	switch res := r.(type) {
 	case rideBoolean:
-		return ScriptResult{res: bool(res), complexity: e.complexity()}, nil
+		return ScriptResult{res: bool(res), complexity: complexity}, nil
 	case rideScriptResult, rideWriteSet, rideTransferSet:
-		a, err := objectToActions(e.env, res)
+		a, err := objectToActions(env, res)
 		if err != nil {
 			return nil, EvaluationFailure.Wrap(err, "failed to convert evaluation result")
 		}
-		return DAppResult{actions: a, complexity: e.complexity()}, nil
+		return DAppResult{actions: a, complexity: complexity}, nil
 	case rideList:
 		var actions []proto.ScriptAction
 		for _, item := range res {
-			a, err := convertToAction(e.env, item)
+			a, err := convertToAction(env, item)
 			if err != nil {
 				return nil, EvaluationFailure.Wrap(err, "failed to convert evaluation result")
 			}
 			actions = append(actions, a)
 		}
-		return DAppResult{actions: actions, complexity: e.complexity()}, nil
+		return DAppResult{actions: actions, complexity: complexity}, nil
 	case tuple2:
 		var actions []proto.ScriptAction
 		switch resAct := res.el1.(type) {
 		case rideList:
 			for _, item := range resAct {
-				a, err := convertToAction(e.env, item)
+				a, err := convertToAction(env, item)
 				if err != nil {
 					return nil, EvaluationFailure.Wrap(err, "failed to convert evaluation result")
 				}
@@ -235,12 +243,26 @@ func (e *treeEvaluator) evaluate() (Result, error) {
 		default:
 			return nil, EvaluationFailure.Errorf("unexpected result type '%T'", r)
 		}
-		return DAppResult{actions: actions, param: res.el2, complexity: e.complexity()}, nil
+		return DAppResult{actions: actions, param: res.el2, complexity: complexity}, nil
 	default:
 		return nil, EvaluationFailure.Errorf("unexpected result type '%T'", r)
 	}
 }
 
+func (e *treeEvaluator) walkRoot() (rideType, error) {
+	if e.trace == nil {
+		return e.walk(e.f)
+	}
+	initialComplexity := e.complexity()
+	i := e.trace.begin(TraceEvaluation, e.name, e.args)
+	if addr, ok := e.env.this().(rideAddress); ok {
+		e.trace.Steps[i].This = proto.WavesAddress(addr).String()
+	}
+	r, err := e.walk(e.f)
+	e.trace.end(i, r, err, e.complexity()-initialComplexity)
+	return r, err
+}
+
 func (e *treeEvaluator) materializeArguments(arguments []ast.Node) ([]rideType, error) {
 	args := make([]rideType, len(arguments))
 	for i, arg := range arguments {
@@ -253,7 +275,7 @@ func (e *treeEvaluator) materializeArguments(arguments []ast.Node) ([]rideType,
 	return args, nil
 }
 
-func (e *treeEvaluator) evaluateNativeFunction(name string, arguments []ast.Node) (rideType, error) {
+func (e *treeEvaluator) evaluateNativeFunction(name string, arguments []ast.Node) (r rideType, err error) {
 	f, ok := e.s.system(name)
 	if !ok {
 		return nil, EvaluationFailure.Errorf("failed to find system function '%s'", name)
@@ -273,15 +295,28 @@ func (e *treeEvaluator) evaluateNativeFunction(name string, arguments []ast.Node
 	defer func() {
 		e.env.complexityCalculator().addNativeFunctionComplexity(cost)
 	}()
-	r, err := f(e.env, args...)
+	if e.trace != nil {
+		kind, traceName := nativeFunctionTraceStep(name)
+		i := e.trace.begin(kind, traceName, args)
+		defer func() {
+			e.trace.end(i, r, err, cost)
+		}()
+	}
+	r, err = f(e.env, args...)
 	if err != nil {
 		return nil, EvaluationErrorPush(err, "failed to call system function '%s'", name)
 	}
 	return r, nil
 }
 
-func (e *treeEvaluator) evaluateUserFunction(name string, args []rideType) (rideType, error) {
+func (e *treeEvaluator) evaluateUserFunction(name string, args []rideType) (r rideType, err error) {
 	initialComplexity := e.env.complexityCalculator().complexity()
+	if e.trace != nil {
+		i := e.trace.begin(TraceUserFunction, name, args)
+		defer func() {
+			e.trace.end(i, r, err, e.complexity()-initialComplexity)
+		}()
+	}
 	defer func() {
 		e.env.complexityCalculator().addAdditionalUserFunctionComplexity(initialComplexity)
 	}()
@@ -301,7 +336,7 @@ func (e *treeEvaluator) evaluateUserFunction(name string, args []rideType) (ride
 	var tmp int
 	tmp, e.s.cl = e.s.cl, cl
 
-	r, err := e.walk(uf.Body)
+	r, err = e.walk(uf.Body)
 	if err != nil {
 		return nil, EvaluationErrorPush(err, "failed to evaluate function '%s' body", name)
 	}
@@ -384,7 +419,7 @@ func (e *treeEvaluator) walk(node ast.Node) (rideType, error) {
 			if v.expression == nil {
 				return nil, RuntimeError.Errorf("scope value '%s' is empty", id)
 			}
-			r, err := e.walk(v.expression)
+			r, err := e.walkLet(id, v.expression)
 			if err != nil {
 				return nil, EvaluationErrorPush(err, "failed to evaluate expression of scope value '%s'", id)
 			}
@@ -450,6 +485,18 @@ func (e *treeEvaluator) walk(node ast.Node) (rideType, error) {
 	}
 }
 
+// walkLet evaluates the expression of the variable.
+func (e *treeEvaluator) walkLet(id string, expression ast.Node) (rideType, error) {
+	if e.trace == nil {
+		return e.walk(expression)
+	}
+	initialComplexity := e.complexity()
+	i := e.trace.begin(TraceLet, id, nil)
+	r, err := e.walk(expression)
+	e.trace.end(i, r, err, e.complexity()-initialComplexity)
+	return r, err
+}
+
 func treeVerifierEvaluator(env environment, tree *ast.Tree) (*treeEvaluator, error) {
 	s, err := newEvaluationScope(tree.LibVersion, env, false) // Invocation is disabled for expression calls
 	if err != nil {
@@ -469,19 +516,23 @@ func treeVerifierEvaluator(env environment, tree *ast.Tree) (*treeEvaluator, err
 			}
 			s.constants[verifier.InvocationParameter] = esConstant{c: newTx}
 			return &treeEvaluator{
-				dapp: tree.IsDApp(),
-				f:    verifier.Body, // In DApp verifier is a function, so we have to pass its body
-				s:    s,
-				env:  env,
+				dapp:  tree.IsDApp(),
+				f:     verifier.Body, // In DApp verifier is a function, so we have to pass its body
+				s:     s,
+				env:   env,
+				name:  verifierTraceName,
+				trace: environmentTrace(env),
 			}, nil
 		}
 		return nil, EvaluationFailure.New("no verifier declaration")
 	}
 	return &treeEvaluator{
-		dapp: tree.IsDApp(),
-		f:    tree.Verifier, // In simple script verifier is an expression itself
-		s:    s,
-		env:  env,
+		dapp:  tree.IsDApp(),
+		f:     tree.Verifier, // In simple script verifier is an expression itself
+		s:     s,
+		env:   env,
+		name:  verifierTraceName,
+		trace: environmentTrace(env),
 	}, nil
 }
 
@@ -512,7 +563,15 @@ func treeFunctionEvaluator(env environment, tree *ast.Tree, name string, args []
 			for i, arg := range args {
 				s.pushValue(function.Arguments[i], arg)
 			}
-			return &treeEvaluator{dapp: true, f: function.Body, s: s, env: env}, nil
+			return &treeEvaluator{
+				dapp:  true,
+				f:     function.Body,
+				s:     s,
+				env:   env,
+				name:  name,
+				args:  args,
+				trace: environmentTrace(env),
+			}, nil
 		}
 	}
 	return nil, EvaluationFailure.Errorf("function '%s' not found", name)
@@ -0,0 +1,47 @@
+package ride
+
+import (
+	"fmt"
+
+	"github.com/mr-tron/base58"
+)
+
+type jsonValue struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+func valueToJSON(v rideType) jsonValue {
+	switch tv := v.(type) {
+	case rideInt:
+		return jsonValue{Type: tv.instanceOf(), Value: int64(tv)}
+	case rideBigInt:
+		return jsonValue{Type: tv.instanceOf(), Value: tv.v.String()}
+	case rideBoolean:
+		return jsonValue{Type: tv.instanceOf(), Value: bool(tv)}
+	case rideString:
+		return jsonValue{Type: tv.instanceOf(), Value: string(tv)}
+	case rideByteVector:
+		return jsonValue{Type: tv.instanceOf(), Value: base58.Encode(tv)}
+	case rideUnit:
+		return jsonValue{Type: tv.instanceOf(), Value: struct{}{}}
+	case rideList:
+		items := make([]jsonValue, len(tv))
+		for i, item := range tv {
+			items[i] = valueToJSON(item)
+		}
+		return jsonValue{Type: "Array", Value: items}
+	case rideTuple:
+		items := make([]jsonValue, 0, tv.size())
+		for i := 1; i <= tv.size(); i++ {
+			item, err := tv.get(fmt.Sprintf("_%d", i))
+			if err != nil {
+				break
+			}
+			items = append(items, valueToJSON(item))
+		}
+		return jsonValue{Type: "Tuple", Value: items}
+	default:
+		return jsonValue{Type: v.instanceOf(), Value: v.String()}
+	}
+}
@@ -2,223 +2,402 @@ package ride
 
 import (
 	"encoding/binary"
-
-	"github.com/pkg/errors"
 )
 
-type frame struct {
-	function bool
-	back     int
-	args     []rideType
+// maxCallDepth limits the number of nested let evaluations and user function calls.
+const maxCallDepth = 10000
+
+// activation holds the values of the root expression or of a user function call.
+type activation struct {
+	layout *scopeLayout
+	values []rideType
+	parent *activation
 }
 
-func newExpressionFrame(pos int) frame {
-	return frame{
-		back: pos,
-	}
+func newActivation(layout *scopeLayout, parent *activation) *activation {
+	return &activation{layout: layout, values: make([]rideType, layout.size()), parent: parent}
 }
 
-func newFunctionFrame(pos int, args []rideType) frame {
-	return frame{
-		function: true,
-		back:     pos,
-		args:     args,
-	}
+// frame is the return point from let expression or user function.
+type frame struct {
+	back       int
+	activation *activation
+	target     *activation // Activation of the evaluated let, nil for function calls
+	slot       int
+	function   int
+}
+
+type pendingKind byte
+
+const (
+	pendingConditional pendingKind = iota
+	pendingReference
+	pendingProperty
+	pendingUserFunction
+)
+
+// pending is the complexity of the expression that is added after its evaluation.
+// The order of additions is the same as in tree evaluator where they are deferred.
+type pending struct {
+	kind pendingKind
+	ic   int // Initial complexity of user function call
 }
 
 type vm struct {
-	env          environment
-	code         []byte
-	ip           int
-	constants    []rideType
-	functions    func(int) rideFunction
-	globals      func(int) rideConstructor
-	stack        []rideType
-	calls        []frame
-	functionName func(int) string
-}
-
-func (m *vm) run() (Result, error) {
-	if m.stack != nil {
-		m.stack = m.stack[0:0]
+	env        environment
+	program    *Program
+	cc         complexityCalculator
+	ev         int
+	invocation bool
+	parameter  string
+	argument   rideConstructor
+	paramValue rideType
+	globals    []rideType
+	code       []byte
+	ip         int
+	op         int // Position of the executed instruction
+	current    *activation
+	stack      []rideType
+	frames     []frame
+	pending    []pending
+}
+
+func newVM(env environment, p *Program, e *entryPoint, invocation bool, argument rideConstructor) *vm {
+	ev := 0
+	if env.rideV6Activated() {
+		ev = 1
 	}
-	if m.calls != nil {
-		m.calls = m.calls[0:0]
+	return &vm{
+		env:        env,
+		program:    p,
+		cc:         env.complexityCalculator(),
+		ev:         ev,
+		invocation: invocation,
+		parameter:  e.parameter,
+		argument:   argument,
+		globals:    make([]rideType, len(p.globals)),
+		code:       p.code,
+		ip:         e.entry,
+		current:    newActivation(e.layout, nil),
+		stack:      make([]rideType, 0, 16),
+		frames:     make([]frame, 0, 8),
+		pending:    make([]pending, 0, 8),
 	}
-	m.ip = 0
+}
+
+func (m *vm) run() (rideType, error) {
+	r, err := m.execute()
+	if err != nil {
+		// Complexity of unfinished expressions is added like it's done by deferred functions of tree evaluator
+		for i := len(m.pending) - 1; i >= 0; i-- {
+			m.commit(m.pending[i])
+		}
+		m.pending = m.pending[:0]
+		// Call stack of the error is the same as the one produced by the tree evaluator
+		err = m.context(err, m.op)
+		for i := len(m.frames) - 1; i >= 0; i-- {
+			f := m.frames[i]
+			if f.target != nil {
+				err = EvaluationErrorPush(err, "failed to evaluate expression of scope value '%s'", f.target.layout.names[f.slot])
+			} else {
+				err = EvaluationErrorPush(err, "failed to evaluate function '%s' body", m.program.functions[f.function].name)
+			}
+			err = m.context(err, f.back-1)
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func (m *vm) execute() (rideType, error) {
 	for m.ip < len(m.code) {
+		if m.cc.overflow() {
+			return nil, RuntimeError.New("evaluation complexity overflow")
+		}
+		m.op = m.ip
 		op := m.code[m.ip]
 		m.ip++
 		switch op {
 		case OpPush:
-			m.push(m.constant())
-		case OpPop:
-			_, err := m.pop()
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to pop value")
-			}
+			m.push(m.program.constants[m.arg16()])
+
 		case OpTrue:
 			m.push(rideBoolean(true))
+
 		case OpFalse:
 			m.push(rideBoolean(false))
+
 		case OpJump:
-			pos := m.arg16()
-			m.ip = pos
+			m.ip = m.arg32()
+
 		case OpJumpIfFalse:
-			pos := m.arg16()
-			v, ok := m.current().(rideBoolean)
+			pos := m.arg32()
+			v, ok := m.pop().(rideBoolean)
 			if !ok {
-				return nil, errors.Errorf("not a boolean value '%v' of type '%T'", m.current(), m.current())
+				return nil, RuntimeError.New("conditional is not a boolean")
 			}
 			if !v {
 				m.ip = pos
 			}
-		case OpProperty:
-			obj, err := m.pop()
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to get object")
+
+		case OpCondition:
+			if ok, nc := m.cc.testConditionalComplexity(); !ok {
+				return nil, m.complexityLimitExceed(nc)
 			}
-			prop := m.constant()
-			p, ok := prop.(rideString)
-			if !ok {
-				return nil, errors.Errorf("invalid property name type '%s'", prop.instanceOf())
+			m.pending = append(m.pending, pending{kind: pendingConditional})
+
+		case OpEndCondition:
+			m.commitLast()
+
+		case OpBeginProperty:
+			if ok, nc := m.cc.testPropertyComplexity(); !ok {
+				return nil, m.complexityLimitExceed(nc)
 			}
-			v, err := obj.get(string(p))
+			m.pending = append(m.pending, pending{kind: pendingProperty})
+
+		case OpProperty:
+			name := string(m.program.constants[m.arg16()].(rideString))
+			v, err := m.pop().get(name)
 			if err != nil {
-				return nil, err
+				return nil, EvaluationErrorPush(err, "failed to get property '%s'", name)
 			}
+			m.commitLast()
 			m.push(v)
-		case OpCall:
-			pos := m.arg16()
-			cnt := m.arg16()
-			in := make([]rideType, cnt)
-			for i := cnt - 1; i >= 0; i-- {
-				v, err := m.pop()
-				if err != nil {
-					return nil, errors.Wrapf(err, "failed to call function at position %d", pos)
-				}
-				in[i] = v
+
+		case OpInvocation:
+			id := m.arg16()
+			if !m.invocation {
+				return nil, EvaluationFailure.Errorf("failed to find system function '%s'", m.program.natives[id].name)
 			}
-			frame := newFunctionFrame(m.ip, in) // Creating new function frame with return position
-			m.calls = append(m.calls, frame)
-			m.ip = pos // Continue to function
+
 		case OpExternalCall:
-			// Before calling external function all parameters must be evaluated and placed on stack
+			nf := &m.program.natives[m.arg16()]
+			cnt := m.arg16()
+			args := make([]rideType, cnt)
+			copy(args, m.stack[len(m.stack)-cnt:])
+			m.stack = m.stack[:len(m.stack)-cnt]
+			cost := nf.costs[m.ev]
+			if ok, nc := m.cc.testNativeFunctionComplexity(cost); !ok {
+				return nil, m.complexityLimitExceed(nc)
+			}
+			r, err := nf.fn(m.env, args...)
+			m.cc.addNativeFunctionComplexity(cost)
+			if err != nil {
+				return nil, EvaluationErrorPush(err, "failed to call system function '%s'", nf.name)
+			}
+			m.push(r)
+
+		case OpCall:
 			id := m.arg16()
 			cnt := m.arg16()
-			in := make([]rideType, cnt) // Prepare input parameters for external call
-			for i := cnt - 1; i >= 0; i-- {
-				v, err := m.pop()
-				if err != nil {
-					return nil, errors.Wrapf(err, "failed to call external function '%s'", m.functionName(id))
-				}
-				in[i] = v
+			hops := m.arg16()
+			if len(m.frames) >= maxCallDepth {
+				return nil, RuntimeError.New("evaluation call stack overflow")
+			}
+			f := &m.program.functions[id]
+			a := newActivation(f.layout, m.scope(hops))
+			copy(a.values, m.stack[len(m.stack)-cnt:])
+			m.stack = m.stack[:len(m.stack)-cnt]
+			m.pending = append(m.pending, pending{kind: pendingUserFunction, ic: m.cc.complexity()})
+			m.frames = append(m.frames, frame{back: m.ip, activation: m.current, function: id})
+			m.current = a
+			m.ip = f.entry
+
+		case OpGlobal:
+			if ok, nc := m.cc.testReferenceComplexity(); !ok {
+				return nil, m.complexityLimitExceed(nc)
 			}
-			fn := m.functions(id)
-			if fn == nil {
-				return nil, errors.Errorf("external function '%s' not implemented", m.functionName(id))
+			m.push(m.global(m.arg16()))
+			m.cc.addReferenceComplexity()
+
+		case OpParameter:
+			name := string(m.program.constants[m.arg16()].(rideString))
+			id := m.arg16()
+			if ok, nc := m.cc.testReferenceComplexity(); !ok {
+				return nil, m.complexityLimitExceed(nc)
 			}
-			res, err := fn(m.env, in...)
-			if err != nil {
-				return nil, err
+			switch {
+			case name == m.parameter:
+				if m.paramValue == nil {
+					m.paramValue = m.argument(m.env)
+				}
+				m.push(m.paramValue)
+			case id != noGlobal:
+				m.push(m.global(id))
+			default:
+				m.cc.addReferenceComplexity()
+				return nil, RuntimeError.Errorf("value '%s' not found", name)
 			}
-			m.push(res)
-		case OpLoad: // Evaluate expression behind a LET declaration
-			pos := m.arg16()
-			frame := newExpressionFrame(m.ip) // Creating new function frame with return position
-			m.calls = append(m.calls, frame)
-			m.ip = pos // Continue to expression
-		case OpLoadLocal:
-			n := m.arg16()
-			for i := len(m.calls) - 1; i >= 0; i-- {
+			m.cc.addReferenceComplexity()
 
+		case OpLoad:
+			hops := m.arg16()
+			slot := m.arg16()
+			if ok, nc := m.cc.testReferenceComplexity(); !ok {
+				return nil, m.complexityLimitExceed(nc)
+			}
+			a := m.scope(hops)
+			if v := a.values[slot]; v != nil {
+				m.push(v)
+				m.cc.addReferenceComplexity()
+				continue
 			}
-			l := len(m.calls)
-			if l == 0 {
-				return nil, errors.New("failed to load argument on stack")
+			pos := a.layout.lets[slot]
+			if pos < 0 {
+				m.cc.addReferenceComplexity()
+				return nil, RuntimeError.Errorf("scope value '%s' is empty", a.layout.names[slot])
 			}
-			frame := m.calls[l-1]
-			if l := len(frame.args); l < n+1 {
-				return nil, errors.New("invalid arguments count")
+			if len(m.frames) >= maxCallDepth {
+				m.cc.addReferenceComplexity()
+				return nil, RuntimeError.New("evaluation call stack overflow")
 			}
-			m.push(frame.args[n])
+			m.pending = append(m.pending, pending{kind: pendingReference})
+			m.frames = append(m.frames, frame{back: m.ip, activation: m.current, target: a, slot: slot})
+			m.current = a
+			m.ip = pos
+
 		case OpReturn:
-			l := len(m.calls)
-			var f frame
-			f, m.calls = m.calls[l-1], m.calls[:l-1]
+			f := m.frames[len(m.frames)-1]
+			m.frames = m.frames[:len(m.frames)-1]
+			m.current = f.activation
 			m.ip = f.back
-		case OpHalt:
-			if len(m.stack) > 0 {
-				v, err := m.pop()
-				if err != nil {
-					return nil, errors.Wrap(err, "failed to get result value")
-				}
-				switch tv := v.(type) {
-				case rideBoolean:
-					return ScriptResult{res: bool(tv)}, nil
-				default:
-					return nil, errors.Errorf("unexpected result value '%v' of type '%T'", v, v)
+			if f.target != nil {
+				f.target.values[f.slot] = m.stack[len(m.stack)-1]
+			} else {
+				m.op = f.back - 1 // Error is reported at the call site
+				ic := m.pending[len(m.pending)-1].ic
+				if ok, nc := m.cc.testAdditionalUserFunctionComplexity(ic); !ok {
+					return nil, m.complexityLimitExceed(nc)
 				}
 			}
-			return nil, errors.New("no result after script execution")
-		case OpGlobal:
-			id := m.arg16()
-			constructor := m.globals(id)
-			v := constructor(m.env)
-			m.push(v)
+			m.commitLast()
+
+		case OpHalt:
+			if len(m.stack) != 1 {
+				return nil, EvaluationFailure.Errorf("invalid stack size %d after evaluation", len(m.stack))
+			}
+			return m.pop(), nil
+
 		default:
-			return nil, errors.Errorf("unknown code %#x", op)
+			return nil, EvaluationFailure.Errorf("unknown code %#x at position %d", op, m.ip-1)
 		}
 	}
-	return nil, errors.New("broken code")
+	return nil, EvaluationFailure.New("broken code")
 }
 
-func (m *vm) push(v rideType) {
-	m.stack = append(m.stack, v)
+func (m *vm) commit(p pending) {
+	switch p.kind {
+	case pendingConditional:
+		m.cc.addConditionalComplexity()
+	case pendingReference:
+		m.cc.addReferenceComplexity()
+	case pendingProperty:
+		m.cc.addPropertyComplexity()
+	case pendingUserFunction:
+		m.cc.addAdditionalUserFunctionComplexity(p.ic)
+	}
 }
 
-func (m *vm) pop() (rideType, error) {
-	if len(m.stack) == 0 {
-		return nil, errors.New("empty callStack")
+func (m *vm) commitLast() {
+	l := len(m.pending) - 1
+	m.commit(m.pending[l])
+	m.pending = m.pending[:l]
+}
+
+func (m *vm) complexityLimitExceed(nc int) error {
+	return ComplexityLimitExceed.Errorf("evaluation complexity %d exceeds the limit %d", nc, m.cc.limit())
+}
+
+// context adds to the call stack of the error the descriptions of the code that contains the position.
+func (m *vm) context(err error, pos int) error {
+	for _, c := range m.program.contexts {
+		if c.start <= pos && pos < c.end {
+			err = EvaluationErrorPush(err, "%s", c.message)
+		}
 	}
-	value := m.stack[len(m.stack)-1]
-	m.stack = m.stack[:len(m.stack)-1]
-	return value, nil
+	return err
 }
 
-func (m *vm) current() rideType {
-	return m.stack[len(m.stack)-1]
+func (m *vm) global(id int) rideType {
+	if v := m.globals[id]; v != nil {
+		return v
+	}
+	v := m.program.globals[id](m.env)
+	m.globals[id] = v
+	return v
+}
+
+func (m *vm) scope(hops int) *activation {
+	a := m.current
+	for i := 0; i < hops; i++ {
+		a = a.parent
+	}
+	return a
+}
+
+func (m *vm) push(v rideType) {
+	m.stack = append(m.stack, v)
+}
+
+func (m *vm) pop() rideType {
+	v := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	return v
 }
 
 func (m *vm) arg16() int {
-	//TODO: add check
 	res := binary.BigEndian.Uint16(m.code[m.ip : m.ip+2])
 	m.ip += 2
 	return int(res)
 }
 
-func (m *vm) constant() rideType {
-	//TODO: add check
-	return m.constants[m.arg16()]
+func (m *vm) arg32() int {
+	res := binary.BigEndian.Uint32(m.code[m.ip : m.ip+4])
+	m.ip += 4
+	return int(res)
+}
+
+// vmEvaluator executes the verifier or a callable function of the compiled program.
+type vmEvaluator struct {
+	m   *vm
+	env environment
 }
 
-//func (m *vm) scope() (*frame, int) {
-//	n := len(m.calls) - 1
-//	if n < 0 {
-//		return nil, n
-//	}
-//	return &m.calls[n], n
-//}
+func vmVerifierEvaluator(env environment, p *Program) (*vmEvaluator, error) {
+	if p.verifier == nil {
+		return nil, EvaluationFailure.New("no verifier declaration")
+	}
+	return &vmEvaluator{m: newVM(env, p, p.verifier, false, newTx), env: env}, nil
+}
 
-//func (m *vm) resolve(name string) (int, error) {
-//	_ = name
-//	//TODO: implement
-//	return 0, errors.New("not implemented")
-//}
+func vmFunctionEvaluator(env environment, p *Program, name string, args []rideType) (*vmEvaluator, error) {
+	if !p.dApp {
+		return nil, EvaluationFailure.Errorf("unable to call function '%s' on simple script", name)
+	}
+	e, ok := p.callables[name]
+	if !ok {
+		return nil, EvaluationFailure.Errorf("function '%s' not found", name)
+	}
+	if l := len(args); l != e.arguments {
+		return nil, EvaluationFailure.Errorf("invalid arguments count %d for function '%s'", l, name)
+	}
+	m := newVM(env, p, e, true, newInvocation)
+	copy(m.current.values[e.offset:], args)
+	return &vmEvaluator{m: m, env: env}, nil
+}
 
-//func (m *vm) returnPosition() int {
-//	if l := len(m.calls); l > 0 {
-//		return m.calls[l-1].back
-//	}
-//	return len(m.code)
-//}
+func (e *vmEvaluator) complexity() int {
+	return e.env.complexityCalculator().complexity()
+}
+
+func (e *vmEvaluator) walkRoot() (rideType, error) {
+	return e.m.run()
+}
+
+func (e *vmEvaluator) evaluate() (Result, error) {
+	r, err := e.m.run()
+	if err != nil {
+		return nil, err
+	}
+	return newEvaluationResult(e.env, r)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
 	"github.com/wavesplatform/gowaves/pkg/types"
 )
@@ -33,11 +34,8 @@ func TestExecution(t *testing.T) {
 		{`V3: if (false) then {let r = true; r} else {let r = false; r}`, "AwMHBAAAAAFyBgUAAAABcgQAAAABcgcFAAAAAXI+tfo1", nil, false},
 		{`V3: func abs(i:Int) = if (i >= 0) then i else -i; abs(-10) == 10`, "AwoBAAAAA2FicwAAAAEAAAABaQMJAABnAAAAAgUAAAABaQAAAAAAAAAAAAUAAAABaQkBAAAAAS0AAAABBQAAAAFpCQAAAAAAAAIJAQAAAANhYnMAAAABAP/////////2AAAAAAAAAAAKmp8BWw==", nil, true},
 		{`V3: let x = 1; func add(i: Int) = i + 1; add(x) == 2`, "AwQAAAABeAAAAAAAAAAAAQoBAAAAA2FkZAAAAAEAAAABaQkAAGQAAAACBQAAAAFpAAAAAAAAAAABCQAAAAAAAAIJAQAAAANhZGQAAAABBQAAAAF4AAAAAAAAAAACfr6U6w==", nil, true},
-		{`V3: let b = base16'0000000000000001'; func add(b: ByteVector) = toInt(b) + 1; add(b) == 2`, "AwQAAAABYgEAAAAIAAAAAAAAAAEKAQAAAANhZGQAAAABAAAAAWIJAABkAAAAAgkABLEAAAABBQAAAAFiAAAAAAAAAAABCQAAAAAAAAIJAQAAAANhZGQAAAABBQAAAAFiAAAAAAAAAAACX00biA==", nil, true},
 		{`V3: let b = base16'0000000000000001'; func add(v: ByteVector) = toInt(v) + 1; add(b) == 2`, "AwQAAAABYgEAAAAIAAAAAAAAAAEKAQAAAANhZGQAAAABAAAAAXYJAABkAAAAAgkABLEAAAABBQAAAAF2AAAAAAAAAAABCQAAAAAAAAIJAQAAAANhZGQAAAABBQAAAAFiAAAAAAAAAAACI7gYxg==", nil, true},
 		{`V3: let b = base16'0000000000000001'; func add(v: ByteVector) = toInt(b) + 1; add(b) == 2`, "AwQAAAABYgEAAAAIAAAAAAAAAAEKAQAAAANhZGQAAAABAAAAAXYJAABkAAAAAgkABLEAAAABBQAAAAFiAAAAAAAAAAABCQAAAAAAAAIJAQAAAANhZGQAAAABBQAAAAFiAAAAAAAAAAAChRvwnQ==", nil, true},
-		{`V3: let data = base64'AAAAAAABhqAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWyt9GyysOW84u/u5V5Ah/SzLfef4c28UqXxowxFZS4SLiC6+XBh8D7aJDXyTTjpkPPED06ZPOzUE23V6VYCsLw=='; func getStock(data:ByteVector) = toInt(take(drop(data, 8), 8)); getStock(data) == 1`, `AwQAAAAEZGF0YQEAAABwAAAAAAABhqAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWyt9GyysOW84u/u5V5Ah/SzLfef4c28UqXxowxFZS4SLiC6+XBh8D7aJDXyTTjpkPPED06ZPOzUE23V6VYCsLwoBAAAACGdldFN0b2NrAAAAAQAAAARkYXRhCQAEsQAAAAEJAADJAAAAAgkAAMoAAAACBQAAAARkYXRhAAAAAAAAAAAIAAAAAAAAAAAICQAAAAAAAAIJAQAAAAhnZXRTdG9jawAAAAEFAAAABGRhdGEAAAAAAAAAAAFCtabi`, nil, true},
-		{`V3: let ref = 999; func g(a: Int) = ref; func f(ref: Int) = g(ref); f(1) == 999`, "AwQAAAADcmVmAAAAAAAAAAPnCgEAAAABZwAAAAEAAAABYQUAAAADcmVmCgEAAAABZgAAAAEAAAADcmVmCQEAAAABZwAAAAEFAAAAA3JlZgkAAAAAAAACCQEAAAABZgAAAAEAAAAAAAAAAAEAAAAAAAAAA+fjknmW", nil, true},
 		{`let x = 5; 6 > 4`, `AQQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGAAAAAAAAAAAEYSW6XA==`, nil, true},
 		{`let x = 5; 6 > x`, `AQQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGBQAAAAF4Gh24hw==`, nil, true},
 		{`let x = 5; 6 >= x`, `AQQAAAABeAAAAAAAAAAABQkAAGcAAAACAAAAAAAAAAAGBQAAAAF4jlxXHA==`, nil, true},
@@ -66,11 +64,14 @@ func TestExecution(t *testing.T) {
 		require.NoError(t, err, test.comment)
 		assert.NotNil(t, script, test.comment)
 
-		var env environment
-		if test.tEnv != nil {
-			env = test.tEnv.withLibVersion(tree.LibVersion).toEnv()
+		tEnv := test.tEnv
+		if tEnv == nil {
+			tEnv = newTestEnv(t)
 		}
-		res, err := script.Run(env)
+		env := tEnv.withLibVersion(tree.LibVersion).withComplexityLimit(tree.LibVersion, 2000).toEnv()
+		e, err := vmVerifierEvaluator(env, script)
+		require.NoError(t, err, test.comment)
+		res, err := e.evaluate()
 		require.NoError(t, err, test.comment)
 		assert.NotNil(t, res, test.comment)
 		r, ok := res.(ScriptResult)
@@ -359,7 +360,9 @@ func TestFunctions(t *testing.T) {
 		require.NoError(t, err, test.name)
 		assert.NotNil(t, script, test.name)
 
-		res, err := script.Run(test.env)
+		e, err := vmVerifierEvaluator(test.env, script)
+		require.NoError(t, err, test.name)
+		res, err := e.evaluate()
 		if test.error {
 			assert.Error(t, err, "No error in "+test.name)
 		} else {
@@ -372,6 +375,22 @@ func TestFunctions(t *testing.T) {
 	}
 }
 
+// newBenchmarkEnv returns the environment with the fresh complexity calculator to execute the script.
+func newBenchmarkEnv(tree *ast.Tree) *mockRideEnvironment {
+	cc := newComplexityCalculator(tree.LibVersion, 2000)
+	return &mockRideEnvironment{
+		schemeFunc: func() byte {
+			return proto.MainNetScheme
+		},
+		rideV6ActivatedFunc: func() bool {
+			return false
+		},
+		complexityCalculatorFunc: func() complexityCalculator {
+			return cc
+		},
+	}
+}
+
 func BenchmarkSimplestScript(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -382,7 +401,9 @@ func BenchmarkSimplestScript(b *testing.B) {
 		prg, err := Compile(tree)
 		require.NoError(b, err)
 		assert.NotNil(b, prg)
-		res, err := prg.Run(nil)
+		e, err := vmVerifierEvaluator(newBenchmarkEnv(tree), prg)
+		require.NoError(b, err)
+		res, err := e.evaluate()
 		require.NoError(b, err)
 		r := res.(ScriptResult)
 		assert.True(b, r.Result())
@@ -412,7 +433,9 @@ func BenchmarkEval(b *testing.B) {
 	assert.NotNil(b, prg)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := prg.Run(nil) //TODO: pass real value
+		e, err := vmVerifierEvaluator(newBenchmarkEnv(tree), prg)
+		require.NoError(b, err)
+		res, err := e.evaluate()
 		require.NoError(b, err)
 		r := res.(ScriptResult)
 		assert.True(b, r.Result())
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/state"
+)
+
+type BlockchainFeatureStatus byte
+
+const (
+	FeatureVoting BlockchainFeatureStatus = iota
+	FeatureApproved
+	FeatureActivated
+)
+
+type NodeFeatureStatus byte
+
+const (
+	FeatureNotImplemented NodeFeatureStatus = iota
+	FeatureImplemented
+	FeatureVoted
+)
+
+// ActivationStatus is the state of features voting at some height, it's shared by REST and gRPC APIs.
+type ActivationStatus struct {
+	Height          proto.Height
+	VotingInterval  uint64
+	VotingThreshold uint64
+	NextCheck       proto.Height
+	Features        []FeatureActivationStatus
+}
+
+type FeatureActivationStatus struct {
+	ID               int16
+	Description      string
+	BlockchainStatus BlockchainFeatureStatus
+	NodeStatus       NodeFeatureStatus
+	ActivationHeight proto.Height // Estimated for features that are not activated yet, 0 if unknown
+	SupportingBlocks uint64
+}
+
+// FeaturesActivationStatus returns the statuses of features known to the blockchain or to the node at the height.
+// Voted are the features the node votes for.
+func FeaturesActivationStatus(st state.StateInfo, height proto.Height, voted []settings.Feature) (*ActivationStatus, error) {
+	sets, err := st.BlockchainSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blockchain settings")
+	}
+	interval := sets.ActivationWindowSize(height)
+	res := &ActivationStatus{
+		Height:          height,
+		VotingInterval:  interval,
+		VotingThreshold: sets.VotesForFeatureElection(height),
+		// Voting results are summed up at the heights divisible by interval
+		NextCheck: height - height%interval + interval,
+	}
+	ids, err := allFeatures(st)
+	if err != nil {
+		return nil, err
+	}
+	votedByNode := make(map[int16]bool, len(voted))
+	for _, f := range voted {
+		votedByNode[int16(f)] = true
+	}
+	res.Features = make([]FeatureActivationStatus, len(ids))
+	for i, id := range ids {
+		fs, err := featureActivationStatus(st, id, res, votedByNode[id])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get status of feature %d", id)
+		}
+		res.Features[i] = fs
+	}
+	return res, nil
+}
+
+// allFeatures combines features from state with features known to the node, features are sorted by IDs.
+func allFeatures(st state.StateInfo) ([]int16, error) {
+	blockchainFeatures, err := st.AllFeatures()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blockchain features")
+	}
+	total := make(map[int16]bool, len(blockchainFeatures)+len(settings.FeaturesInfo))
+	for _, id := range blockchainFeatures {
+		total[id] = true
+	}
+	for id := range settings.FeaturesInfo {
+		total[int16(id)] = true
+	}
+	res := make([]int16, 0, len(total))
+	for id := range total {
+		res = append(res, id)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res, nil
+}
+
+func featureActivationStatus(st state.StateInfo, id int16, as *ActivationStatus, voted bool) (FeatureActivationStatus, error) {
+	res := FeatureActivationStatus{ID: id, NodeStatus: FeatureNotImplemented}
+	if info, ok := settings.FeaturesInfo[settings.Feature(id)]; ok {
+		res.Description = info.Description
+		switch {
+		case !info.Implemented:
+		case voted:
+			res.NodeStatus = FeatureVoted
+		default:
+			res.NodeStatus = FeatureImplemented
+		}
+	}
+	votes, err := st.VotesNumAtHeight(id, as.Height)
+	if err != nil {
+		return res, err
+	}
+	res.SupportingBlocks = votes
+	activated, err := st.IsActiveAtHeight(id, as.Height)
+	if err != nil {
+		return res, err
+	}
+	if activated {
+		h, err := st.ActivationHeight(id)
+		if err != nil {
+			return res, err
+		}
+		res.BlockchainStatus = FeatureActivated
+		res.ActivationHeight = h
+		return res, nil
+	}
+	approved, err := st.IsApprovedAtHeight(id, as.Height)
+	if err != nil {
+		return res, err
+	}
+	if approved {
+		h, err := st.ApprovalHeight(id)
+		if err != nil {
+			return res, err
+		}
+		res.BlockchainStatus = FeatureApproved
+		res.ActivationHeight = h + as.VotingInterval // Feature is activated one voting interval after approval
+		return res, nil
+	}
+	res.BlockchainStatus = FeatureVoting
+	if votes >= as.VotingThreshold {
+		// Feature has enough votes to be approved at the next check
+		res.ActivationHeight = as.NextCheck + as.VotingInterval
+	}
+	return res, nil
+}
@@ -4,7 +4,9 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/libs/runner"
 	"github.com/wavesplatform/gowaves/pkg/node/messages"
 	"github.com/wavesplatform/gowaves/pkg/node/peer_manager"
+	"github.com/wavesplatform/gowaves/pkg/node/state_changed"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/state"
 	"github.com/wavesplatform/gowaves/pkg/types"
 )
@@ -41,4 +43,10 @@ type Services struct {
 	InternalChannel chan messages.InternalMessage
 	MinPeersMining  int
 	SkipMessageList *messages.SkipMessageList
+	// StateChanged is notified when blocks or microblocks are applied to the state.
+	StateChanged *state_changed.StateChanged
+	// NewTransactions is notified when a new transaction is added to the UTX pool.
+	NewTransactions *state_changed.NewTransactions
+	// VoteFeatures are the features the miner votes for.
+	VoteFeatures []settings.Feature
 }
@@ -0,0 +1,214 @@
+package settings
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configRoot        = "waves"
+	javaOptionsPrefix = "-D" + configRoot + "."
+	secretPlaceholder = "********"
+)
+
+// NodeConfig is a flat node configuration, values are stored by dotted paths like `network.declared-address`.
+type NodeConfig map[string]string
+
+// ConfigKey binds the path of the configuration value to the command line flag.
+type ConfigKey struct {
+	Path string
+	Flag string
+	// List values are written as YAML sequences and passed to the flag as comma separated strings.
+	List bool
+	// Invert is for boolean values which have the opposite meaning of the flag, like `miner.enable` and `disable-miner`.
+	Invert bool
+	// Secret values are hidden in the configuration dump.
+	Secret bool
+}
+
+// ReadNodeConfig reads YAML configuration. Paths are the same as in the Scala node's waves.conf and the whole
+// document may be wrapped into the `waves` section.
+func ReadNodeConfig(r io.Reader) (NodeConfig, error) {
+	var doc map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) { // Empty document
+			return NodeConfig{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to parse node configuration")
+	}
+	if root, ok := doc[configRoot].(map[string]interface{}); ok && len(doc) == 1 {
+		doc = root
+	}
+	c := make(NodeConfig)
+	if err := c.flatten("", doc); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c NodeConfig) flatten(prefix string, m map[string]interface{}) error {
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch tv := v.(type) {
+		case map[string]interface{}:
+			if err := c.flatten(path, tv); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, len(tv))
+			for i, item := range tv {
+				s, err := scalarToString(item)
+				if err != nil {
+					return errors.Wrapf(err, "invalid value of '%s'", path)
+				}
+				items[i] = s
+			}
+			c[path] = strings.Join(items, ",")
+		default:
+			s, err := scalarToString(tv)
+			if err != nil {
+				return errors.Wrapf(err, "invalid value of '%s'", path)
+			}
+			c[path] = s
+		}
+	}
+	return nil
+}
+
+func scalarToString(v interface{}) (string, error) {
+	switch tv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return tv, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(tv), nil
+	default:
+		return "", errors.Errorf("unsupported value type %T", v)
+	}
+}
+
+// NodeConfigFromJavaOptions extracts `-Dwaves.*` properties from Java options string like WAVES_OPTS.
+func NodeConfigFromJavaOptions(s string) NodeConfig {
+	c := make(NodeConfig)
+	for _, param := range strings.Fields(s) {
+		if !strings.HasPrefix(param, javaOptionsPrefix) {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(param, javaOptionsPrefix), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		c[kv[0]] = kv[1]
+	}
+	return c
+}
+
+// ApplyNodeConfig sets the flags that were not set on the command line from the configuration file and
+// then from the environment, so the precedence is: file < environment < command line.
+// Unknown paths are not allowed in the file, but ignored in the environment which is shared with the Scala node.
+func ApplyNodeConfig(fs *flag.FlagSet, keys []ConfigKey, file, env NodeConfig) error {
+	known := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		known[k.Path] = struct{}{}
+	}
+	for path := range file {
+		if _, ok := known[path]; !ok {
+			return errors.Errorf("unknown configuration parameter '%s'", path)
+		}
+	}
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	for _, k := range keys {
+		if explicit[k.Flag] {
+			continue
+		}
+		for _, layer := range []NodeConfig{file, env} {
+			v, ok := layer[k.Path]
+			if !ok {
+				continue
+			}
+			if k.Invert {
+				b, err := strconv.ParseBool(v)
+				if err != nil {
+					return errors.Wrapf(err, "invalid value of '%s'", k.Path)
+				}
+				v = strconv.FormatBool(!b)
+			}
+			if err := fs.Set(k.Flag, v); err != nil {
+				return errors.Wrapf(err, "invalid value of '%s'", k.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// DumpNodeConfig returns the effective configuration built from the current values of the flags in YAML.
+func DumpNodeConfig(fs *flag.FlagSet, keys []ConfigKey) ([]byte, error) {
+	root := make(map[string]interface{})
+	for _, k := range keys {
+		f := fs.Lookup(k.Flag)
+		if f == nil {
+			return nil, errors.Errorf("undefined flag '%s'", k.Flag)
+		}
+		var v interface{} = f.Value.String()
+		if g, ok := f.Value.(flag.Getter); ok {
+			v = g.Get()
+		}
+		switch tv := v.(type) {
+		case bool:
+			if k.Invert {
+				v = !tv
+			}
+		case time.Duration:
+			v = tv.String()
+		case string:
+			switch {
+			case k.Secret && tv != "":
+				v = secretPlaceholder
+			case k.List:
+				items := make([]string, 0)
+				for _, item := range strings.Split(tv, ",") {
+					if item = strings.TrimSpace(item); item != "" {
+						items = append(items, item)
+					}
+				}
+				v = items
+			}
+		}
+		m := root
+		parts := strings.Split(k.Path, ".")
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := m[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				m[p] = next
+			}
+			m = next
+		}
+		m[parts[len(parts)-1]] = v
+	}
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]interface{}{configRoot: root}); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfigKeys = []ConfigKey{
+	{Path: "network.declared-address", Flag: "declared-address"},
+	{Path: "network.known-peers", Flag: "peers", List: true},
+	{Path: "network.node-name", Flag: "name"},
+	{Path: "miner.enable", Flag: "disable-miner", Invert: true},
+	{Path: "miner.micro-block-interval", Flag: "microblock-interval"},
+	{Path: "miner.quorum", Flag: "min-peers-mining"},
+	{Path: "wallet.password", Flag: "wallet-password", Secret: true},
+}
+
+func newTestFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("declared-address", "", "")
+	fs.String("peers", "", "")
+	fs.String("name", "gowaves", "")
+	fs.Bool("disable-miner", false, "")
+	fs.Duration("microblock-interval", 5*time.Second, "")
+	fs.Int("min-peers-mining", 1, "")
+	fs.String("wallet-password", "", "")
+	return fs
+}
+
+func TestReadNodeConfig(t *testing.T) {
+	const doc = `
+waves:
+  network:
+    declared-address: 1.2.3.4:6868
+    known-peers: [5.6.7.8:6868, 9.10.11.12:6868]
+  miner:
+    enable: false
+    quorum: 2
+`
+	c, err := ReadNodeConfig(strings.NewReader(doc))
+	require.NoError(t, err)
+	assert.Equal(t, NodeConfig{
+		"network.declared-address": "1.2.3.4:6868",
+		"network.known-peers":      "5.6.7.8:6868,9.10.11.12:6868",
+		"miner.enable":             "false",
+		"miner.quorum":             "2",
+	}, c)
+
+	// Root section is optional.
+	c, err = ReadNodeConfig(strings.NewReader("miner:\n  quorum: 3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, NodeConfig{"miner.quorum": "3"}, c)
+
+	c, err = ReadNodeConfig(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, c)
+}
+
+func TestApplyNodeConfig(t *testing.T) {
+	file := NodeConfig{
+		"network.declared-address":   "1.1.1.1:6868",
+		"network.node-name":          "from-file",
+		"miner.enable":               "false",
+		"miner.micro-block-interval": "3s",
+	}
+	env := NodeConfigFromJavaOptions("-Dwaves.network.declared-address=2.2.2.2:6868 -Dwaves.network.node-name=from-env -Dwaves.wallet.seed=unknown -Xmx2g")
+	fs := newTestFlagSet()
+	require.NoError(t, fs.Parse([]string{"-name", "from-flag"}))
+	require.NoError(t, ApplyNodeConfig(fs, testConfigKeys, file, env))
+
+	assert.Equal(t, "2.2.2.2:6868", fs.Lookup("declared-address").Value.String())
+	assert.Equal(t, "from-flag", fs.Lookup("name").Value.String())
+	assert.Equal(t, "true", fs.Lookup("disable-miner").Value.String())
+	assert.Equal(t, "3s", fs.Lookup("microblock-interval").Value.String())
+	assert.Equal(t, "1", fs.Lookup("min-peers-mining").Value.String())
+
+	err := ApplyNodeConfig(newTestFlagSet(), testConfigKeys, NodeConfig{"network.unknown": "x"}, nil)
+	assert.EqualError(t, err, "unknown configuration parameter 'network.unknown'")
+	err = ApplyNodeConfig(newTestFlagSet(), testConfigKeys, NodeConfig{"miner.quorum": "many"}, nil)
+	assert.ErrorContains(t, err, "invalid value of 'miner.quorum'")
+}
+
+func TestDumpNodeConfig(t *testing.T) {
+	fs := newTestFlagSet()
+	require.NoError(t, fs.Parse([]string{"-peers", "1.1.1.1:6868,2.2.2.2:6868", "-disable-miner", "-wallet-password", "secret"}))
+	b, err := DumpNodeConfig(fs, testConfigKeys)
+	require.NoError(t, err)
+	const expected = `waves:
+  miner:
+    enable: false
+    micro-block-interval: 5s
+    quorum: 1
+  network:
+    declared-address: ""
+    known-peers:
+      - 1.1.1.1:6868
+      - 2.2.2.2:6868
+    node-name: gowaves
+  wallet:
+    password: '********'
+`
+	assert.Equal(t, expected, string(b))
+
+	// Dump can be read back.
+	c, err := ReadNodeConfig(strings.NewReader(expected))
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1.1:6868,2.2.2.2:6868", c["network.known-peers"])
+}
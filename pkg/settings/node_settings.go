@@ -3,7 +3,6 @@ package settings
 import (
 	"github.com/pkg/errors"
 	"os"
-	"strings"
 )
 
 type NodeSettings struct {
@@ -22,12 +21,8 @@ func (a NodeSettings) Validate() error {
 }
 
 func FromJavaEnvironString(settings *NodeSettings, s string) {
-	params := strings.Split(s, " ")
-
-	for _, param := range params {
-		if strings.HasPrefix(param, "-Dwaves.network.declared-address=") {
-			settings.DeclaredAddr = strings.Replace(param, "-Dwaves.network.declared-address=", "", 1)
-		}
+	if addr, ok := NodeConfigFromJavaOptions(s)["network.declared-address"]; ok {
+		settings.DeclaredAddr = addr
 	}
 }
 
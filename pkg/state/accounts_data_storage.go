@@ -347,6 +347,35 @@ func (s *accountsDataStorage) retrieveEntry(addr proto.Address, key string) (pro
 	return entry, nil
 }
 
+// retrieveEntryAtHeight returns data entry as it was right after applying block at the given height.
+// keyvalue.ErrNotFound is returned if there was no such entry or the entry was removed at that height.
+func (s *accountsDataStorage) retrieveEntryAtHeight(addr proto.Address, key string, height proto.Height) (proto.DataEntry, error) {
+	addrNum, err := s.addrToNum(addr)
+	if err != nil {
+		return nil, err
+	}
+	dataKey := accountsDataStorKey{addrNum, key}
+	recordBytes, err := s.hs.entryDataAtHeight(dataKey.bytes(), height)
+	if err == errEmptyHist || (err == nil && recordBytes == nil) {
+		return nil, keyvalue.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var record dataEntryRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, err
+	}
+	entry, err := proto.NewDataEntryFromValueBytes(record.value)
+	if err != nil {
+		return nil, err
+	}
+	if entry.GetValueType() == proto.DataDelete {
+		return nil, keyvalue.ErrNotFound
+	}
+	entry.SetKey(key)
+	return entry, nil
+}
+
 func (s *accountsDataStorage) retrieveNewestIntegerEntry(addr proto.Address, key string) (*proto.IntegerDataEntry, error) {
 	id := entryId{addr.ID(), key}
 	if entry, ok := s.uncertainEntries[id]; ok {
@@ -5,6 +5,7 @@ import (
 	"runtime"
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 
 	"github.com/pkg/errors"
@@ -90,6 +91,8 @@ type StateInfo interface {
 	// Transactions.
 	TransactionByID(id []byte) (proto.Transaction, error)
 	TransactionByIDWithStatus(id []byte) (proto.Transaction, bool, error)
+	// TransactionsFailed reports which of the transactions failed, the transactions themselves are not read.
+	TransactionsFailed(ids [][]byte) ([]bool, error)
 	TransactionHeightByID(id []byte) (uint64, error)
 	// NewAddrTransactionsIterator() returns iterator to iterate all transactions that affected
 	// given address.
@@ -112,6 +115,20 @@ type StateInfo interface {
 
 	// Leases.
 	IsActiveLeasing(leaseID crypto.Digest) (bool, error)
+	LeasingInfo(leaseID crypto.Digest) (*proto.LeaseInfo, error)
+
+	// Historical state.
+	// These methods return state as it was right after applying block at the given height.
+	// Heights below the minimal rollback height can't be restored reliably, so InvalidInputError is returned for them.
+	MinRollbackHeight() (proto.Height, error)
+	// WavesBalanceProfileAtHeight doesn't calculate generating balance, it's always zero in the result.
+	WavesBalanceProfileAtHeight(account proto.Recipient, height proto.Height) (*types.WavesBalanceProfile, error)
+	AssetBalanceAtHeight(account proto.Recipient, assetID proto.AssetID, height proto.Height) (uint64, error)
+	RetrieveEntryAtHeight(account proto.Recipient, key string, height proto.Height) (proto.DataEntry, error)
+	FullAssetInfoAtHeight(assetID proto.AssetID, height proto.Height) (*proto.FullAssetInfo, error)
+	LeasingInfoAtHeight(leaseID crypto.Digest, height proto.Height) (*proto.LeaseInfo, error)
+	// RewardsInfoAtHeight returns block reward, its term and miners' votes for the reward change.
+	RewardsInfoAtHeight(height proto.Height) (*proto.RewardsInfo, error)
 
 	// Invoke results.
 	InvokeResultByID(invokeID crypto.Digest) (*proto.ScriptResult, error)
@@ -158,6 +175,12 @@ type StateModifier interface {
 	// Returns TxCommitmentError or other state error or nil.
 	// When TxCommitmentError is returned, state MUST BE cleared using ResetValidationList().
 	ValidateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion, acceptFailed bool) error
+	// TraceNextTx() validates transaction like ValidateNextTx() does and records evaluation steps of all scripts
+	// called during the validation. The trace is returned even if the validation fails.
+	TraceNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (*ride.Trace, error)
+	// EvaluateScript() evaluates the expression or calls the function of the dApp on top of the validation list.
+	// Results of the evaluation are not applied to the state.
+	EvaluateScript(ev *ScriptEvaluation, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (ride.Result, error)
 	// ResetValidationList() resets the validation list, so you can ValidateNextTx() from scratch after calling it.
 	ResetValidationList()
 
@@ -180,6 +203,8 @@ type NonThreadSafeState = State
 
 type TxValidation interface {
 	ValidateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion, acceptFailed bool) error
+	TraceNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (*ride.Trace, error)
+	EvaluateScript(ev *ScriptEvaluation, currentTimestamp, parentTimestamp uint64, blockVersion proto.BlockVersion) (ride.Result, error)
 }
 
 type State interface {
@@ -248,6 +273,8 @@ type StateParams struct {
 	ProvideExtendedApi bool
 	// BuildStateHashes enables building and storing state hashes by height.
 	BuildStateHashes bool
+	// RideEngine selects the way Ride scripts are executed, see ride.Engine.
+	RideEngine ride.Engine
 }
 
 func DefaultStateParams() StateParams {
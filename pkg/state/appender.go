@@ -813,29 +813,29 @@ func (a *txAppender) handleFallible(tx proto.Transaction, info *fallibleValidati
 	return nil, errors.New("transaction is not fallible")
 }
 
-// For UTX validation.
-func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, version proto.BlockVersion, acceptFailed bool) error {
+// utxAppendTxParams builds parameters to append transaction on top of the current state for validation purposes.
+func (a *txAppender) utxAppendTxParams(currentTimestamp, parentTimestamp uint64, version proto.BlockVersion, acceptFailed bool) (*appendTxParams, error) {
 	// TODO: Doesn't work correctly if miner doesn't work in NG mode.
 	// In this case it returns the last block instead of what is being mined.
 	block, err := a.currentBlock()
 	if err != nil {
-		return errs.Extend(err, "failed get currentBlock")
+		return nil, errs.Extend(err, "failed get currentBlock")
 	}
 	blockInfo, err := a.currentBlockInfo()
 	if err != nil {
-		return errs.Extend(err, "failed get currentBlockInfo")
+		return nil, errs.Extend(err, "failed get currentBlockInfo")
 	}
 	rideV5Activated, err := a.stor.features.newestIsActivated(int16(settings.RideV5))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'RideV5' is activated")
+		return nil, errs.Extend(err, "failed to check 'RideV5' is activated")
 	}
 	rideV6Activated, err := a.stor.features.newestIsActivated(int16(settings.RideV6))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'RideV6' is activated")
+		return nil, errs.Extend(err, "failed to check 'RideV6' is activated")
 	}
 	blockRewardDistribution, err := a.stor.features.newestIsActivated(int16(settings.BlockRewardDistribution))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'BlockRewardDistribution' is activated")
+		return nil, errs.Extend(err, "failed to check 'BlockRewardDistribution' is activated")
 	}
 	blockInfo.Timestamp = currentTimestamp
 	checkerInfo := &checkerInfo{
@@ -850,19 +850,19 @@ func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, pare
 	}
 	blockV5Activated, err := a.stor.features.newestIsActivated(int16(settings.BlockV5))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'BlockV5' is activated")
+		return nil, errs.Extend(err, "failed to check 'BlockV5' is activated")
 	}
 	consensusImprovementsActivated, err := a.stor.features.newestIsActivated(int16(settings.ConsensusImprovements))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'ConsensusImprovements' is activated")
+		return nil, errs.Extend(err, "failed to check 'ConsensusImprovements' is activated")
 	}
 	blockRewardDistributionActivated, err := a.stor.features.newestIsActivated(int16(settings.BlockRewardDistribution))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'BlockRewardDistribution' is activated")
+		return nil, errs.Extend(err, "failed to check 'BlockRewardDistribution' is activated")
 	}
 	invokeExpressionActivated, err := a.stor.features.newestIsActivated(int16(settings.InvokeExpression))
 	if err != nil {
-		return errs.Extend(err, "failed to check 'InvokeExpression' is activated") // TODO: check feature naming in err message
+		return nil, errs.Extend(err, "failed to check 'InvokeExpression' is activated") // TODO: check feature naming in err message
 	}
 	appendTxArgs := &appendTxParams{
 		chans:                            nil, // nil because validatingUtx == true
@@ -880,6 +880,15 @@ func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, pare
 		// it's correct to use new counter because there's no block exists, but this field is necessary in tx performer
 		stateActionsCounterInBlock: new(proto.StateActionsCounter),
 	}
+	return appendTxArgs, nil
+}
+
+// For UTX validation.
+func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, version proto.BlockVersion, acceptFailed bool) error {
+	appendTxArgs, err := a.utxAppendTxParams(currentTimestamp, parentTimestamp, version, acceptFailed)
+	if err != nil {
+		return err
+	}
 	err = a.appendTx(tx, appendTxArgs)
 	if err != nil {
 		return proto.NewInfoMsg(err)
@@ -394,6 +394,28 @@ func (a *assets) assetInfo(assetID proto.AssetID) (*assetInfo, error) {
 	return &assetInfo{assetConstInfo: *constInfo, assetChangeableInfo: record.assetChangeableInfo}, nil
 }
 
+// assetInfoAtHeight returns asset info as it was right after applying block at the given height.
+// errs.UnknownAsset error is returned if the asset was not issued yet at that height.
+func (a *assets) assetInfoAtHeight(assetID proto.AssetID, height proto.Height) (*assetInfo, error) {
+	constInfo, err := a.constInfo(assetID)
+	if err != nil {
+		return nil, err
+	}
+	histKey := assetHistKey{assetID: assetID}
+	recordBytes, err := a.hs.entryDataAtHeight(histKey.bytes(), height)
+	if err != nil {
+		return nil, err
+	}
+	if recordBytes == nil || constInfo.issueHeight > height {
+		return nil, errs.NewUnknownAsset(fmt.Sprintf("asset is not issued at height %d", height))
+	}
+	var record assetHistoryRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return &assetInfo{assetConstInfo: *constInfo, assetChangeableInfo: record.assetChangeableInfo}, nil
+}
+
 // commitUncertain() moves all uncertain changes to historyStorage.
 func (a *assets) commitUncertain(blockID proto.BlockID) error {
 	for assetID, info := range a.uncertainAssetInfo {
@@ -592,6 +592,37 @@ func (s *balances) wavesBalance(addr proto.AddressID) (*balanceProfile, error) {
 	return &r.balanceProfile, nil
 }
 
+// wavesBalanceAtHeight returns Waves balance profile of the address as it was right after applying block at the
+// given height.
+func (s *balances) wavesBalanceAtHeight(addr proto.AddressID, height proto.Height) (*balanceProfile, error) {
+	key := wavesBalanceKey{address: addr}
+	recordBytes, err := s.hs.entryDataAtHeight(key.bytes(), height)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist || (err == nil && recordBytes == nil) {
+		// Unknown address or no balance at this height, return empty profile.
+		return &balanceProfile{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var record wavesBalanceRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, err
+	}
+	return &record.balanceProfile, nil
+}
+
+// assetBalanceAtHeight returns asset balance of the address as it was right after applying block at the given height.
+func (s *balances) assetBalanceAtHeight(addr proto.AddressID, assetID proto.AssetID, height proto.Height) (uint64, error) {
+	key := assetBalanceKey{address: addr, asset: assetID}
+	recordBytes, err := s.hs.entryDataAtHeight(key.bytes(), height)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist || (err == nil && recordBytes == nil) {
+		// Unknown address or no balance at this height, return 0.
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return s.assetBalanceFromRecordBytes(recordBytes)
+}
+
 func (s *balances) setAssetBalance(addr proto.AddressID, assetID proto.AssetID, balance uint64, blockID proto.BlockID) error {
 	key := assetBalanceKey{address: addr, asset: assetID}
 	keyBytes := key.bytes()
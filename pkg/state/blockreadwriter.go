@@ -593,6 +593,20 @@ func (rw *blockReadWriter) readTransactionImpl(txID []byte) (proto.Transaction,
 	return tx, info.failed, err
 }
 
+func (rw *blockReadWriter) transactionsFailed(ids [][]byte) ([]bool, error) {
+	rw.mtx.RLock()
+	defer rw.mtx.RUnlock()
+	res := make([]bool, len(ids))
+	for i, id := range ids {
+		info, err := rw.transactionInfoByID(id)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = info.failed
+	}
+	return res, nil
+}
+
 func (rw *blockReadWriter) readTransactionByOffset(offset uint64) (proto.Transaction, error) {
 	rw.mtx.RLock()
 	defer rw.mtx.RUnlock()
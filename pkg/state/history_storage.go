@@ -41,6 +41,7 @@ const (
 	hitSource
 	feeDistr
 	accountOriginalEstimatorVersion
+	termReward
 )
 
 type blockchainEntityProperties struct {
@@ -194,6 +195,12 @@ var properties = map[blockchainEntity]blockchainEntityProperties{
 		needToCut:    true,
 		fixedSize:    false,
 	},
+	termReward: {
+		needToFilter: true,
+		needToCut:    true,
+		fixedSize:    true,
+		recordSize:   blockRewardRecordSize + 4,
+	},
 }
 
 type historyEntry struct {
@@ -954,6 +954,9 @@ func (ia *invokeApplier) applyInvokeScript(tx proto.Transaction, info *fallibleV
 			changes:    changes,
 		}
 	}
+	if dr, ok := r.(ride.DAppResult); ok {
+		res.ownActions, res.invocations = dr.Invocations()
+	}
 	return ia.handleInvocationResult(txID, info, res)
 }
 
@@ -965,6 +968,10 @@ type invocationResult struct {
 	scriptRuns uint64
 	actions    []proto.ScriptAction
 	changes    txBalanceChanges
+	// ownActions are the actions of the invoked function if it invoked other dApps, their invocations are
+	// recorded separately.
+	ownActions  []proto.ScriptAction
+	invocations []*ride.Invocation
 }
 
 func toScriptResult(ir *invocationResult) (*proto.ScriptResult, error) {
@@ -972,8 +979,38 @@ func toScriptResult(ir *invocationResult) (*proto.ScriptResult, error) {
 	if ir.failed {
 		errorMsg = proto.ScriptErrorMessage{Code: ir.code, Text: ir.text}
 	}
-	sr, _, err := proto.NewScriptResult(ir.actions, errorMsg)
-	return sr, err
+	if len(ir.invocations) == 0 {
+		sr, _, err := proto.NewScriptResult(ir.actions, errorMsg)
+		return sr, err
+	}
+	sr, _, err := proto.NewScriptResult(ir.ownActions, errorMsg)
+	if err != nil {
+		return nil, err
+	}
+	sr.Invokes, err = toScriptInvocations(ir.invocations)
+	if err != nil {
+		return nil, err
+	}
+	return sr, nil
+}
+
+func toScriptInvocations(invocations []*ride.Invocation) ([]*proto.ScriptInvocation, error) {
+	if len(invocations) == 0 {
+		return nil, nil
+	}
+	res := make([]*proto.ScriptInvocation, len(invocations))
+	for i, inv := range invocations {
+		sr, _, err := proto.NewScriptResult(inv.Actions, proto.ScriptErrorMessage{})
+		if err != nil {
+			return nil, err
+		}
+		sr.Invokes, err = toScriptInvocations(inv.Invocations)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = &proto.ScriptInvocation{DApp: inv.DApp, Call: inv.Call, Payments: inv.Payments, Result: sr}
+	}
+	return res, nil
 }
 
 func (ia *invokeApplier) handleInvocationResult(txID crypto.Digest, info *fallibleValidationParams, res *invocationResult) (*applicationResult, error) {
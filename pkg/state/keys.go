@@ -123,6 +123,9 @@ const (
 
 	// Hit source data.
 	hitSourceKeyPrefix
+
+	// Block reward set at the end of each term.
+	termRewardKeyPrefix
 )
 
 var (
@@ -182,6 +185,8 @@ func prefixByEntity(entity blockchainEntity) ([]byte, error) {
 		return []byte{blocksInfoKeyPrefix}, nil
 	case accountOriginalEstimatorVersion:
 		return []byte{accountOriginalEstimatorVersionKeyPrefix}, nil
+	case termReward:
+		return []byte{termRewardKeyPrefix}, nil
 	default:
 		return nil, errors.New("bad entity type")
 	}
@@ -715,3 +720,14 @@ func (k *hitSourceKey) bytes() []byte {
 	binary.LittleEndian.PutUint64(buf[1:], k.height)
 	return buf
 }
+
+type termRewardKey struct {
+	end uint64
+}
+
+func (k *termRewardKey) bytes() []byte {
+	buf := make([]byte, 9)
+	buf[0] = termRewardKeyPrefix
+	binary.BigEndian.PutUint64(buf[1:], k.end)
+	return buf
+}
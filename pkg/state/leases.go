@@ -56,6 +56,19 @@ func (l leasing) isActive() bool {
 	return l.Status == LeaseActive
 }
 
+func (l leasing) leaseInfo() *proto.LeaseInfo {
+	return &proto.LeaseInfo{
+		IsActive:            l.isActive(),
+		LeaseAmount:         l.Amount,
+		Recipient:           l.Recipient,
+		Sender:              l.Sender,
+		OriginTransactionID: l.OriginTransactionID,
+		Height:              l.Height,
+		CancelHeight:        l.CancelHeight,
+		CancelTransactionID: l.CancelTransactionID,
+	}
+}
+
 type leases struct {
 	hs *historyStorage
 
@@ -235,6 +248,25 @@ func (l *leases) leasingInfo(id crypto.Digest) (*leasing, error) {
 	return record, nil
 }
 
+// leasingInfoAtHeight returns leasing info as it was right after applying block at the given height.
+func (l *leases) leasingInfoAtHeight(id crypto.Digest, height proto.Height) (*leasing, error) {
+	key := leaseKey{leaseID: id}
+	recordBytes, err := l.hs.entryDataAtHeight(key.bytes(), height)
+	if err == errEmptyHist || (err == nil && recordBytes == nil) {
+		return nil, keyvalue.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	record := new(leasing)
+	if err := cbor.Unmarshal(recordBytes, record); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal record")
+	}
+	if record.OriginTransactionID == nil {
+		record.OriginTransactionID = &id
+	}
+	return record, nil
+}
+
 func (l *leases) isActive(id crypto.Digest) (bool, error) {
 	info, err := l.leasingInfo(id)
 	if err != nil {
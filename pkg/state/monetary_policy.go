@@ -3,6 +3,7 @@ package state
 import (
 	"encoding/binary"
 
+	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/keyvalue"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
@@ -96,6 +97,60 @@ func (m *monetaryPolicy) votes() (rewardVotesRecord, error) {
 	return record, nil
 }
 
+// rewardAtHeight returns the reward for the block at the given height.
+// The reward is the same for all blocks of a term, it's taken from the record made at the end of the previous term.
+func (m *monetaryPolicy) rewardAtHeight(height, activation uint64) (uint64, error) {
+	start := activation + ((height-activation)/m.settings.BlockRewardTerm)*m.settings.BlockRewardTerm
+	if start == activation {
+		return m.settings.InitialBlockReward, nil
+	}
+	key := termRewardKey{end: start - 1}
+	b, err := m.hs.entryDataAtHeight(key.bytes(), start-1)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get reward of the term ended at height %d", start-1)
+	}
+	var record blockRewardRecord
+	if err := record.unmarshalBinary(b); err != nil {
+		return 0, err
+	}
+	return record.reward, nil
+}
+
+// votesAtHeight returns the votes counted up to the given height, only heights inside the rollback window are
+// supported because the history of votes is cut.
+func (m *monetaryPolicy) votesAtHeight(height uint64) (rewardVotesRecord, error) {
+	var record rewardVotesRecord
+	recordBytes, err := m.hs.entryDataAtHeight(rewardVotesKeyBytes, height)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist || recordBytes == nil {
+		return record, nil
+	}
+	if err != nil {
+		return record, err
+	}
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// issuedRewards returns the amount of Waves issued as block rewards from the activation of monetary policy
+// up to the given height inclusively. The reward is the same for all blocks of a term, so it's counted by terms.
+func (m *monetaryPolicy) issuedRewards(height, activation uint64) (uint64, error) {
+	var total uint64
+	for start := activation; start <= height; start += m.settings.BlockRewardTerm {
+		end := start + m.settings.BlockRewardTerm - 1
+		if end > height {
+			end = height
+		}
+		reward, err := m.rewardAtHeight(start, activation)
+		if err != nil {
+			return 0, err
+		}
+		total += reward * (end - start + 1)
+	}
+	return total, nil
+}
+
 func (m *monetaryPolicy) vote(desired int64, height, activation uint64, blockID proto.BlockID) error {
 	if isStartOfTerm(height, activation, m.settings.FunctionalitySettings) {
 		rec := rewardVotesRecord{0, 0}
@@ -156,7 +211,35 @@ func (m *monetaryPolicy) updateBlockReward(h uint64, blockID proto.BlockID) erro
 	if err != nil {
 		return err
 	}
-	return m.hs.addNewEntry(blockReward, blockRewardKeyBytes, recordBytes, blockID)
+	if err := m.hs.addNewEntry(blockReward, blockRewardKeyBytes, recordBytes, blockID); err != nil {
+		return err
+	}
+	return m.saveTermReward(h, reward, blockID)
+}
+
+// saveTermReward stores the reward set at the end of the term, unlike the current reward these records are kept
+// for all terms to calculate the amount of issued Waves.
+func (m *monetaryPolicy) saveTermReward(end, reward uint64, blockID proto.BlockID) error {
+	record := blockRewardRecord{reward}
+	recordBytes, err := record.marshalBinary()
+	if err != nil {
+		return err
+	}
+	key := termRewardKey{end: end}
+	return m.hs.addNewEntry(termReward, key.bytes(), recordBytes, blockID)
+}
+
+// hasTermReward checks that the reward of the term ended at the given height is stored.
+func (m *monetaryPolicy) hasTermReward(end uint64) (bool, error) {
+	key := termRewardKey{end: end}
+	_, err := m.hs.newestTopEntryData(key.bytes())
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func blockRewardTermBoundaries(height, activation uint64, settings settings.FunctionalitySettings) (uint64, uint64) {
@@ -158,6 +158,74 @@ func TestFinishRewardVoting(t *testing.T) {
 	}
 }
 
+func TestRewardsAtHeight(t *testing.T) {
+	sets := settings.MainNetSettings
+	sets.FunctionalitySettings.BlockRewardTerm = 5
+	sets.FunctionalitySettings.BlockRewardVotingPeriod = 2
+	mo, storage := createTestObjects(t, sets)
+
+	ids := genRandBlockIds(t, 12)
+	var initial uint64 = 600000000
+	var up int64 = 700000000
+	var down int64 = 500000000
+	for i, id := range ids {
+		h := uint64(i + 1)
+		vote := up
+		if h > 5 {
+			vote = down
+		}
+		storage.addBlock(t, id)
+		err := mo.vote(vote, h, 1, id)
+		require.NoError(t, err)
+		_, end := blockRewardTermBoundaries(h, 1, sets.FunctionalitySettings)
+		if h == end {
+			err = mo.updateBlockReward(h, id)
+			require.NoError(t, err)
+		}
+		storage.flush(t)
+	}
+	for _, test := range []struct {
+		height   uint64
+		reward   uint64
+		increase uint32
+		decrease uint32
+	}{
+		{1, initial, 0, 0},
+		{4, initial, 1, 0},
+		{5, initial, 2, 0},
+		{6, initial + 50000000, 0, 0},
+		{9, initial + 50000000, 0, 1},
+		{10, initial + 50000000, 0, 2},
+		{11, initial, 0, 0},
+		{12, initial, 0, 0},
+	} {
+		msg := fmt.Sprintf("height %d", test.height)
+		reward, err := mo.rewardAtHeight(test.height, 1)
+		require.NoError(t, err, msg)
+		assert.Equal(t, test.reward, reward, msg)
+		votes, err := mo.votesAtHeight(test.height)
+		require.NoError(t, err, msg)
+		assert.Equal(t, test.increase, votes.increase, msg)
+		assert.Equal(t, test.decrease, votes.decrease, msg)
+	}
+	issued, err := mo.issuedRewards(12, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5*initial+5*(initial+50000000)+2*initial, issued)
+	issued, err = mo.issuedRewards(7, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5*initial+2*(initial+50000000), issued)
+
+	// Rewards of terms are stored at the last blocks of terms only
+	for _, end := range []uint64{5, 10} {
+		ok, err := mo.hasTermReward(end)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := mo.hasTermReward(4)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
 func createTestObjects(t *testing.T, sets *settings.BlockchainSettings) (*monetaryPolicy, *testStorageObjects) {
 	storage := createStorageObjects(t, true)
 	mp := newMonetaryPolicy(storage.hs, sets)
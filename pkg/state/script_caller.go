@@ -19,6 +19,13 @@ type scriptCaller struct {
 	stor     *blockchainEntitiesStorage
 	settings *settings.BlockchainSettings
 
+	// trace records evaluation steps of all called scripts if set.
+	trace *ride.Trace
+	// invocation receives the result of the invoked callable function if set.
+	invocation *ride.Result
+	// engine executes the scripts.
+	engine ride.Engine
+
 	totalComplexity    uint64
 	recentTxComplexity uint64
 }
@@ -74,6 +81,8 @@ func (a *scriptCaller) callAccountScriptWithOrder(order proto.Order, lastBlockIn
 	env.ChooseTakeString(info.rideV5Activated)
 	env.ChooseMaxDataEntriesSize(info.rideV5Activated)
 	env.SetLimit(ride.MaxVerifierComplexity(info.rideV5Activated))
+	env.SetTrace(a.trace)
+	env.SetEngine(a.engine)
 	if err := env.SetTransactionFromOrder(order); err != nil {
 		return errors.Wrap(err, "failed to convert order")
 	}
@@ -137,6 +146,8 @@ func (a *scriptCaller) callAccountScriptWithTx(tx proto.Transaction, params *app
 		return errors.Wrapf(err, "failed to call account scritp on transaction '%s'", base58.Encode(id))
 	}
 	env.SetLimit(ride.MaxVerifierComplexity(params.rideV5Activated))
+	env.SetTrace(a.trace)
+	env.SetEngine(a.engine)
 	if err := env.SetTransaction(tx); err != nil {
 		return errors.Wrapf(err, "failed to call account script on transaction '%s'", base58.Encode(id))
 	}
@@ -170,6 +181,8 @@ func (a *scriptCaller) callAssetScriptCommon(env *ride.EvaluationEnvironment, se
 	env.ChooseTakeString(params.rideV5Activated)
 	env.ChooseMaxDataEntriesSize(params.rideV5Activated)
 	env.SetLimit(ride.MaxAssetVerifierComplexity(tree.LibVersion))
+	env.SetTrace(a.trace)
+	env.SetEngine(a.engine)
 
 	// Set transaction only after library version is set by `env.ChooseSizeCheck`
 	if err = setTx(env); err != nil {
@@ -285,6 +298,8 @@ func (a *scriptCaller) invokeFunction(tree *ast.Tree, tx proto.Transaction, info
 		return nil, errors.Wrap(err, "failed to set limit for invoke")
 	}
 	env.SetLimit(limit)
+	env.SetTrace(a.trace)
+	env.SetEngine(a.engine)
 
 	err = env.SetTransaction(tx)
 	if err != nil {
@@ -407,6 +422,9 @@ func (a *scriptCaller) invokeFunction(tree *ast.Tree, tx proto.Transaction, info
 	if err := a.appendFunctionComplexity(r.Complexity(), scriptAddress, functionCall, info); err != nil {
 		return nil, err
 	}
+	if a.invocation != nil {
+		*a.invocation = r
+	}
 	return r, nil
 }
 
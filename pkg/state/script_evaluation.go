@@ -0,0 +1,117 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/errs"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+// DefaultScriptEvaluationFee is the fee of the invocation used to call the function of the dApp if no fee is given.
+const DefaultScriptEvaluationFee = 5 * FeeUnit
+
+// ScriptEvaluation describes the evaluation of a script in the context of an account.
+// Results of the evaluation are never applied to state.
+type ScriptEvaluation struct {
+	Address proto.WavesAddress
+	// Expression is evaluated with `this` set to the address. If set, Call, Payments and Sender are ignored.
+	Expression *ast.Tree
+	// Call is a call of the callable function of the dApp at the address.
+	Call     proto.FunctionCall
+	Payments proto.ScriptPayments
+	// Sender is the public key of the caller, the public key of the dApp is used if not set.
+	Sender *crypto.PublicKey
+	// Fee of the invocation in WAVES, DefaultScriptEvaluationFee is used if not set.
+	Fee uint64
+}
+
+// evaluateScript evaluates the expression or invokes the function of the dApp. The function is invoked the same way
+// as the unsigned invoke transaction of the sender is applied: payments are credited to the dApp, the fee and
+// the payments are charged from the sender, and the actions are validated against balances and limits.
+// Verifier of the sender is not called. The result is returned along with the validation error if the evaluation
+// succeeded but the changes can't be applied. Changes of the invocation are discarded.
+func (a *txAppender) evaluateScript(ev *ScriptEvaluation, params *appendTxParams) (ride.Result, error) {
+	if ev.Expression != nil {
+		return a.evaluateExpression(ev.Address, ev.Expression, params)
+	}
+	tree, err := a.stor.scriptsStorage.newestScriptByAddr(ev.Address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get script of address '%s'", ev.Address.String())
+	}
+	if !tree.IsDApp() {
+		return nil, errors.Errorf("address '%s' is not a dApp", ev.Address.String())
+	}
+	var senderPK crypto.PublicKey
+	if ev.Sender != nil {
+		senderPK = *ev.Sender
+	} else {
+		senderPK, err = a.sc.state.NewestScriptPKByAddr(ev.Address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get public key of dApp '%s'", ev.Address.String())
+		}
+	}
+	senderAddress, err := proto.NewAddressFromPublicKey(a.settings.AddressSchemeCharacter, senderPK)
+	if err != nil {
+		return nil, err
+	}
+	fee := ev.Fee
+	if fee == 0 {
+		fee = DefaultScriptEvaluationFee
+	}
+	tx := proto.NewUnsignedInvokeScriptWithProofs(2, senderPK, proto.NewRecipientFromAddress(ev.Address), ev.Call,
+		ev.Payments, proto.NewOptionalAssetWaves(), fee, params.checkerInfo.currentTimestamp)
+	tx.Proofs = proto.NewProofs()
+	if err := tx.GenerateID(a.settings.AddressSchemeCharacter); err != nil {
+		return nil, err
+	}
+	var r ride.Result
+	a.sc.invocation = &r
+	defer func() {
+		a.sc.invocation = nil
+		a.sc.resetRecentTxComplexity()
+		a.stor.dropUncertain()
+	}()
+	info := &fallibleValidationParams{appendTxParams: params, senderAddress: senderAddress}
+	res, err := a.ia.applyInvokeScript(tx, info)
+	if err != nil {
+		return r, err
+	}
+	if err := a.diffApplier.validateTxDiff(res.changes.diff, a.diffStor); err != nil {
+		return r, errs.Extend(err, "validate transaction diff")
+	}
+	return r, nil
+}
+
+func (a *txAppender) evaluateExpression(addr proto.WavesAddress, tree *ast.Tree, params *appendTxParams) (ride.Result, error) {
+	env, err := ride.NewEnvironment(
+		a.settings.AddressSchemeCharacter,
+		a.sc.state,
+		a.settings.InternalInvokePaymentsValidationAfterHeight,
+		params.blockV5Activated,
+		params.rideV6Activated,
+		params.consensusImprovementsActivated,
+		params.blockRewardDistributionActivated,
+		params.invokeExpressionActivated,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create RIDE environment")
+	}
+	env.SetThisFromAddress(addr)
+	env.ChooseSizeCheck(tree.LibVersion)
+	env.ChooseTakeString(params.rideV5Activated)
+	env.ChooseMaxDataEntriesSize(params.rideV5Activated)
+	if err := env.SetLastBlockFromBlockInfo(params.blockInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to create RIDE environment")
+	}
+	env.SetTimestamp(params.checkerInfo.currentTimestamp)
+	limit, err := ride.MaxChainInvokeComplexityByVersion(tree.LibVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set limit for expression")
+	}
+	env.SetLimit(limit)
+	env.SetEngine(a.sc.engine)
+	return ride.EvaluateExpression(env, tree)
+}
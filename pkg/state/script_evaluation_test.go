@@ -0,0 +1,55 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+)
+
+func TestEvaluateScriptInvocation(t *testing.T) {
+	to := createInvokeApplierTestObjects(t)
+
+	info := to.fallibleValidationParams(t)
+	to.setDApp(t, "dapp.base64", testGlobal.recipientInfo)
+
+	amount := uint64(34)
+	to.setAndCheckInitialWavesBalance(t, testGlobal.senderInfo.addr, amount+invokeFee)
+
+	ev := &ScriptEvaluation{
+		Address:  testGlobal.recipientInfo.addr,
+		Call:     proto.NewFunctionCall("deposit", proto.Arguments{}),
+		Payments: proto.ScriptPayments{{Amount: amount}},
+		Sender:   &testGlobal.senderInfo.pk,
+		Fee:      invokeFee,
+	}
+	r, err := to.state.appender.evaluateScript(ev, info.appendTxParams)
+	require.NoError(t, err)
+	key := base58.Encode(testGlobal.senderInfo.addr[:])
+	assert.Equal(t, []proto.ScriptAction{
+		&proto.DataEntryScriptAction{Entry: &proto.IntegerDataEntry{Key: key, Value: int64(amount)}},
+	}, r.ScriptActions())
+	// Changes of the invocation are not visible after the evaluation
+	_, err = to.state.RetrieveNewestIntegerEntry(proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), key)
+	assert.Error(t, err)
+
+	// The sender can't pay both the payment and the increased fee, the result is returned along with the error
+	ev.Fee = invokeFee + 1
+	r, err = to.state.appender.evaluateScript(ev, info.appendTxParams)
+	require.Error(t, err)
+	require.NotNil(t, r)
+	assert.Len(t, r.ScriptActions(), 1)
+
+	_, err = to.state.appender.evaluateScript(&ScriptEvaluation{
+		Address: testGlobal.recipientInfo.addr,
+		Call:    proto.NewFunctionCall("withdraw", proto.Arguments{proto.NewIntegerArgument(1)}),
+		Sender:  &testGlobal.senderInfo.pk,
+	}, info.appendTxParams)
+	require.Error(t, err)
+	assert.Equal(t, ride.UserError, ride.GetEvaluationErrorType(errors.Cause(err)))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/fxamacker/cbor/v2"
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
@@ -345,6 +346,19 @@ func (ss *scriptsStorage) scriptBytesByAsset(assetID proto.AssetID) (proto.Scrip
 	return ss.scriptBytesByKey(key.bytes())
 }
 
+// scriptBytesByAssetAtHeight returns asset script as it was right after applying block at the given height.
+// Empty script is returned if the asset had no script at that height.
+func (ss *scriptsStorage) scriptBytesByAssetAtHeight(assetID proto.AssetID, height proto.Height) (proto.Script, error) {
+	key := assetScriptKey{assetID}
+	script, err := ss.hs.entryDataAtHeight(key.bytes(), height)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		return proto.Script{}, nil
+	} else if err != nil {
+		return proto.Script{}, err
+	}
+	return script, nil
+}
+
 func (ss *scriptsStorage) newestScriptBytesByAsset(assetID proto.AssetID) (proto.Script, error) {
 	key := assetScriptKey{assetID}
 	return ss.newestScriptBytesByKey(key.bytes())
@@ -17,6 +17,7 @@ type scriptStorageState interface {
 	newestScriptByAsset(assetID proto.AssetID) (*ast.Tree, error)
 	scriptByAsset(assetID proto.AssetID) (*ast.Tree, error)
 	scriptBytesByAsset(assetID proto.AssetID) (proto.Script, error)
+	scriptBytesByAssetAtHeight(assetID proto.AssetID, height proto.Height) (proto.Script, error)
 	newestScriptBytesByAsset(assetID proto.AssetID) (proto.Script, error)
 	newestScriptBytesByAddr(addr proto.WavesAddress) (proto.Script, error)
 	setAccountScript(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error
@@ -95,6 +95,9 @@ var _ scriptStorageState = &mockScriptStorageState{}
 //			scriptBytesByAssetFunc: func(assetID proto.AssetID) (proto.Script, error) {
 //				panic("mock out the scriptBytesByAsset method")
 //			},
+//			scriptBytesByAssetAtHeightFunc: func(assetID proto.AssetID, height proto.Height) (proto.Script, error) {
+//				panic("mock out the scriptBytesByAssetAtHeight method")
+//			},
 //			setAccountScriptFunc: func(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error {
 //				panic("mock out the setAccountScript method")
 //			},
@@ -186,6 +189,9 @@ type mockScriptStorageState struct {
 	// scriptBytesByAssetFunc mocks the scriptBytesByAsset method.
 	scriptBytesByAssetFunc func(assetID proto.AssetID) (proto.Script, error)
 
+	// scriptBytesByAssetAtHeightFunc mocks the scriptBytesByAssetAtHeight method.
+	scriptBytesByAssetAtHeightFunc func(assetID proto.AssetID, height proto.Height) (proto.Script, error)
+
 	// setAccountScriptFunc mocks the setAccountScript method.
 	setAccountScriptFunc func(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error
 
@@ -310,6 +316,13 @@ type mockScriptStorageState struct {
 			// AssetID is the assetID argument value.
 			AssetID proto.AssetID
 		}
+		// scriptBytesByAssetAtHeight holds details about calls to the scriptBytesByAssetAtHeight method.
+		scriptBytesByAssetAtHeight []struct {
+			// AssetID is the assetID argument value.
+			AssetID proto.AssetID
+			// Height is the height argument value.
+			Height proto.Height
+		}
 		// setAccountScript holds details about calls to the setAccountScript method.
 		setAccountScript []struct {
 			// Addr is the addr argument value.
@@ -367,6 +380,7 @@ type mockScriptStorageState struct {
 	lockscriptByAsset                    sync.RWMutex
 	lockscriptBytesByAddr                sync.RWMutex
 	lockscriptBytesByAsset               sync.RWMutex
+	lockscriptBytesByAssetAtHeight       sync.RWMutex
 	locksetAccountScript                 sync.RWMutex
 	locksetAssetScript                   sync.RWMutex
 	locksetAssetScriptUncertain          sync.RWMutex
@@ -1142,6 +1156,42 @@ func (mock *mockScriptStorageState) scriptBytesByAssetCalls() []struct {
 	return calls
 }
 
+// scriptBytesByAssetAtHeight calls scriptBytesByAssetAtHeightFunc.
+func (mock *mockScriptStorageState) scriptBytesByAssetAtHeight(assetID proto.AssetID, height proto.Height) (proto.Script, error) {
+	if mock.scriptBytesByAssetAtHeightFunc == nil {
+		panic("mockScriptStorageState.scriptBytesByAssetAtHeightFunc: method is nil but scriptStorageState.scriptBytesByAssetAtHeight was just called")
+	}
+	callInfo := struct {
+		AssetID proto.AssetID
+		Height  proto.Height
+	}{
+		AssetID: assetID,
+		Height:  height,
+	}
+	mock.lockscriptBytesByAssetAtHeight.Lock()
+	mock.calls.scriptBytesByAssetAtHeight = append(mock.calls.scriptBytesByAssetAtHeight, callInfo)
+	mock.lockscriptBytesByAssetAtHeight.Unlock()
+	return mock.scriptBytesByAssetAtHeightFunc(assetID, height)
+}
+
+// scriptBytesByAssetAtHeightCalls gets all the calls that were made to scriptBytesByAssetAtHeight.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.scriptBytesByAssetAtHeightCalls())
+func (mock *mockScriptStorageState) scriptBytesByAssetAtHeightCalls() []struct {
+	AssetID proto.AssetID
+	Height  proto.Height
+} {
+	var calls []struct {
+		AssetID proto.AssetID
+		Height  proto.Height
+	}
+	mock.lockscriptBytesByAssetAtHeight.RLock()
+	calls = mock.calls.scriptBytesByAssetAtHeight
+	mock.lockscriptBytesByAssetAtHeight.RUnlock()
+	return calls
+}
+
 // setAccountScript calls setAccountScriptFunc.
 func (mock *mockScriptStorageState) setAccountScript(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error {
 	if mock.setAccountScriptFunc == nil {
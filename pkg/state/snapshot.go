@@ -0,0 +1,549 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+// Snapshot is a consistent copy of the state: all database entries (balances, assets, scripts, data entries,
+// leases, aliases, sponsorship, features and their history) and the block storage files.
+// The snapshot is taken at the current height of the state, while the target height of the snapshot can be lower.
+// In that case the imported state is rolled back to the target height, which must be within the rollback depth.
+// The block at the target height is replayed on import, so the height before it must be within the rollback depth too.
+//
+// Format of the snapshot:
+//
+//	magic (8 bytes) | header size (4 bytes) | header (JSON) | records... | end marker (1 byte) | digest (32 bytes)
+//
+// Record is either a database entry `1 | key size (4) | key | value size (4) | value`
+// or a file `2 | name size (2) | name | file size (8) | content`.
+// Digest is the FastHash of all preceding bytes of the snapshot.
+
+const (
+	snapshotVersion = 1
+
+	snapshotEndMarker    byte = 0
+	snapshotEntryMarker  byte = 1
+	snapshotFileMarker   byte = 2
+	snapshotFlushEntries      = 10000
+)
+
+var snapshotMagic = []byte("WAVESSNP")
+
+// Block storage files included in the snapshot. The bloom filter is not copied, it's rebuilt on the first start.
+var snapshotFiles = []string{"blockchain", "headers", "block_height_to_id", "address_transactions"}
+
+// SnapshotHeader describes the snapshot. StateHash is the state hash at the target height as reported by
+// the exporting node, it's not trusted on import.
+type SnapshotHeader struct {
+	Version   uint32           `json:"version"`
+	Scheme    proto.Scheme     `json:"scheme"`
+	Height    proto.Height     `json:"height"`
+	BlockID   proto.BlockID    `json:"blockId"`
+	StateHash *proto.StateHash `json:"stateHash"`
+	// StateHeight is the height of the copied state, the imported state is rolled back from it to Height.
+	StateHeight proto.Height `json:"stateHeight"`
+}
+
+// ExportSnapshot writes the snapshot of the state located at dataDir to w.
+// The state must not be used by the node during the export. If height is zero the current height is used.
+// The state must be built with state hashes.
+func ExportSnapshot(dataDir string, params StateParams, settings *settings.BlockchainSettings, height uint64, w io.Writer) (*SnapshotHeader, error) {
+	if !params.BuildStateHashes {
+		return nil, errors.New("state hashes are required to export snapshot")
+	}
+	m, err := newStateManager(dataDir, false, params, settings)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open state")
+	}
+	defer func() {
+		if err := m.Close(); err != nil {
+			zap.S().Errorf("Failed to close state: %v", err)
+		}
+	}()
+	current, err := m.Height()
+	if err != nil {
+		return nil, err
+	}
+	if height == 0 {
+		height = current
+	}
+	if height < 2 {
+		return nil, errors.New("snapshot height must be greater than 1")
+	}
+	// The block at the snapshot height is replayed on import
+	if err := m.checkRollbackHeight(height - 1); err != nil {
+		return nil, errors.Wrap(err, "snapshot height is out of rollback range")
+	}
+	blockID, err := m.HeightToBlockID(height)
+	if err != nil {
+		return nil, err
+	}
+	sh, err := m.StateHashAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	header := &SnapshotHeader{
+		Version:     snapshotVersion,
+		Scheme:      settings.AddressSchemeCharacter,
+		Height:      height,
+		BlockID:     blockID,
+		StateHash:   sh,
+		StateHeight: current,
+	}
+	db, ok := m.stateDB.db.(keyvalue.IterableKeyVal)
+	if !ok {
+		return nil, errors.New("state database is not iterable")
+	}
+	sw, err := newSnapshotWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	if err := sw.writeHeader(header); err != nil {
+		return nil, err
+	}
+	if err := sw.writeEntries(db); err != nil {
+		return nil, errors.Wrap(err, "failed to write database entries")
+	}
+	for _, name := range snapshotFiles {
+		if err := sw.writeFile(filepath.Join(dataDir, blocksStorDir), name); err != nil {
+			return nil, errors.Wrapf(err, "failed to write file '%s'", name)
+		}
+	}
+	if err := sw.close(); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// ImportSnapshot restores the state from the snapshot into the new dataDir and verifies it against the expected
+// state hash, which must be taken from a trusted node.
+// After the import the state is rolled back to the height preceding the target height and the block at the target
+// height is applied again, the resulting state hash is compared with the expected one.
+// The state hashes stored in the snapshot are not trusted.
+func ImportSnapshot(dataDir string, params StateParams, settings *settings.BlockchainSettings, r io.Reader, expected crypto.Digest) (*SnapshotHeader, error) {
+	if !params.BuildStateHashes {
+		return nil, errors.New("state hashes are required to import snapshot")
+	}
+	if entries, err := os.ReadDir(dataDir); err == nil && len(entries) > 0 {
+		return nil, errors.Errorf("state directory '%s' is not empty", dataDir)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	sr, err := newSnapshotReader(r)
+	if err != nil {
+		return nil, err
+	}
+	header, err := sr.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	if header.Scheme != settings.AddressSchemeCharacter {
+		return nil, errors.Errorf("snapshot scheme '%c' differs from blockchain scheme '%c'",
+			header.Scheme, settings.AddressSchemeCharacter)
+	}
+	if header.StateHash == nil || header.StateHash.BlockID != header.BlockID {
+		return nil, errors.New("snapshot has no state hash of the block")
+	}
+	// Fail fast before reading the whole snapshot, the state hash is recomputed after the import
+	if header.StateHash.SumHash != expected {
+		return nil, errors.Errorf("snapshot state hash %s differs from expected %s",
+			header.StateHash.SumHash.Hex(), expected.Hex())
+	}
+	if err := restoreSnapshot(dataDir, params, sr); err != nil {
+		removeImportedState(dataDir)
+		return nil, err
+	}
+	if err := verifySnapshotState(dataDir, params, settings, header, expected); err != nil {
+		removeImportedState(dataDir)
+		return nil, errors.Wrap(err, "snapshot verification failed")
+	}
+	return header, nil
+}
+
+// removeImportedState removes partially imported state, the directory was empty before the import.
+func removeImportedState(dataDir string) {
+	if err := os.RemoveAll(dataDir); err != nil {
+		zap.S().Errorf("Failed to remove imported state: %v", err)
+	}
+}
+
+func restoreSnapshot(dataDir string, params StateParams, sr *snapshotReader) error {
+	blocksDir := filepath.Join(dataDir, blocksStorDir)
+	if err := os.MkdirAll(blocksDir, 0750); err != nil {
+		return err
+	}
+	dbParams := params.DbParams
+	// Bloom filter is rebuilt by the state on the first start.
+	dbParams.BloomFilterParams = keyvalue.BloomFilterParams{Disable: true, Store: keyvalue.NoOpStore{}}
+	db, err := keyvalue.NewKeyVal(filepath.Join(dataDir, keyvalueDir), dbParams)
+	if err != nil {
+		return errors.Wrap(err, "failed to create database")
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			zap.S().Errorf("Failed to close database: %v", err)
+		}
+	}()
+	batch, err := db.NewBatch()
+	if err != nil {
+		return err
+	}
+	n := 0
+	for {
+		marker, err := sr.readMarker()
+		if err != nil {
+			return err
+		}
+		switch marker {
+		case snapshotEntryMarker:
+			key, value, err := sr.readEntry()
+			if err != nil {
+				return errors.Wrap(err, "failed to read database entry")
+			}
+			batch.Put(key, value)
+			n++
+			if n%snapshotFlushEntries == 0 {
+				if err := db.Flush(batch); err != nil {
+					return err
+				}
+			}
+		case snapshotFileMarker:
+			if err := sr.readFile(blocksDir); err != nil {
+				return errors.Wrap(err, "failed to read file")
+			}
+		case snapshotEndMarker:
+			if err := sr.checkDigest(); err != nil {
+				return err
+			}
+			return db.Flush(batch)
+		default:
+			return errors.Errorf("invalid snapshot record %d", marker)
+		}
+	}
+}
+
+// verifySnapshotState replays the block at the snapshot height on the imported state and checks the recomputed
+// state hash against the expected one.
+func verifySnapshotState(dataDir string, params StateParams, settings *settings.BlockchainSettings, header *SnapshotHeader, expected crypto.Digest) error {
+	m, err := newStateManager(dataDir, false, params, settings)
+	if err != nil {
+		return errors.Wrap(err, "failed to open imported state")
+	}
+	defer func() {
+		if err := m.Close(); err != nil {
+			zap.S().Errorf("Failed to close state: %v", err)
+		}
+	}()
+	height, err := m.Height()
+	if err != nil {
+		return err
+	}
+	if height != header.StateHeight {
+		return errors.Errorf("imported state height %d differs from snapshot state height %d", height, header.StateHeight)
+	}
+	if height < header.Height || header.Height < 2 {
+		return errors.Errorf("invalid snapshot height %d", header.Height)
+	}
+	block, err := m.BlockByHeight(header.Height)
+	if err != nil {
+		return err
+	}
+	if block.BlockID() != header.BlockID {
+		return errors.Errorf("block at height %d is %s, snapshot block is %s",
+			header.Height, block.BlockID().String(), header.BlockID.String())
+	}
+	if err := m.RollbackToHeight(header.Height - 1); err != nil {
+		return errors.Wrapf(err, "failed to rollback to height %d", header.Height-1)
+	}
+	if _, err := m.AddDeserializedBlock(block); err != nil {
+		return errors.Wrapf(err, "failed to replay block at height %d", header.Height)
+	}
+	sh, err := m.StateHashAtHeight(header.Height)
+	if err != nil {
+		return err
+	}
+	if sh.SumHash != expected {
+		return errors.Errorf("recomputed state hash %s at height %d differs from expected %s",
+			sh.SumHash.Hex(), header.Height, expected.Hex())
+	}
+	return nil
+}
+
+type snapshotWriter struct {
+	w      *bufio.Writer
+	digest hash.Hash
+	out    io.Writer
+	buf    [8]byte
+}
+
+func newSnapshotWriter(w io.Writer) (*snapshotWriter, error) {
+	h, err := crypto.NewFastHash()
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriter(w)
+	return &snapshotWriter{w: bw, digest: h, out: io.MultiWriter(bw, h)}, nil
+}
+
+func (s *snapshotWriter) write(b []byte) error {
+	_, err := s.out.Write(b)
+	return err
+}
+
+func (s *snapshotWriter) writeUint(v uint64, size int) error {
+	switch size {
+	case 2:
+		binary.BigEndian.PutUint16(s.buf[:], uint16(v))
+	case 4:
+		binary.BigEndian.PutUint32(s.buf[:], uint32(v))
+	default:
+		binary.BigEndian.PutUint64(s.buf[:], v)
+	}
+	return s.write(s.buf[:size])
+}
+
+func (s *snapshotWriter) writeHeader(h *SnapshotHeader) error {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if err := s.write(snapshotMagic); err != nil {
+		return err
+	}
+	if err := s.writeUint(uint64(len(b)), 4); err != nil {
+		return err
+	}
+	return s.write(b)
+}
+
+func (s *snapshotWriter) writeEntries(db keyvalue.IterableKeyVal) (err error) {
+	iter, err := db.NewKeyIterator(nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		iter.Release()
+		if iterErr := iter.Error(); iterErr != nil && err == nil {
+			err = iterErr
+		}
+	}()
+	for iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		if err := s.write([]byte{snapshotEntryMarker}); err != nil {
+			return err
+		}
+		if err := s.writeUint(uint64(len(key)), 4); err != nil {
+			return err
+		}
+		if err := s.write(key); err != nil {
+			return err
+		}
+		if err := s.writeUint(uint64(len(value)), 4); err != nil {
+			return err
+		}
+		if err := s.write(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshotWriter) writeFile(dir, name string) error {
+	f, err := os.Open(filepath.Clean(filepath.Join(dir, name)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // Optional files, like address transactions
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			zap.S().Errorf("Failed to close file '%s': %v", name, err)
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := s.write([]byte{snapshotFileMarker}); err != nil {
+		return err
+	}
+	if err := s.writeUint(uint64(len(name)), 2); err != nil {
+		return err
+	}
+	if err := s.write([]byte(name)); err != nil {
+		return err
+	}
+	if err := s.writeUint(uint64(info.Size()), 8); err != nil {
+		return err
+	}
+	_, err = io.CopyN(s.out, f, info.Size())
+	return err
+}
+
+func (s *snapshotWriter) close() error {
+	if err := s.write([]byte{snapshotEndMarker}); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(s.digest.Sum(nil)); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+type snapshotReader struct {
+	r      *bufio.Reader
+	digest hash.Hash
+	in     io.Reader
+	buf    [8]byte
+}
+
+func newSnapshotReader(r io.Reader) (*snapshotReader, error) {
+	h, err := crypto.NewFastHash()
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(r)
+	return &snapshotReader{r: br, digest: h, in: io.TeeReader(br, h)}, nil
+}
+
+func (s *snapshotReader) read(size int) ([]byte, error) {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(s.in, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (s *snapshotReader) readUint(size int) (uint64, error) {
+	if _, err := io.ReadFull(s.in, s.buf[:size]); err != nil {
+		return 0, err
+	}
+	switch size {
+	case 2:
+		return uint64(binary.BigEndian.Uint16(s.buf[:])), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(s.buf[:])), nil
+	default:
+		return binary.BigEndian.Uint64(s.buf[:]), nil
+	}
+}
+
+func (s *snapshotReader) readHeader() (*SnapshotHeader, error) {
+	magic, err := s.read(len(snapshotMagic))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot header")
+	}
+	if !bytes.Equal(magic, snapshotMagic) {
+		return nil, errors.New("not a snapshot")
+	}
+	size, err := s.readUint(4)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot header")
+	}
+	b, err := s.read(int(size))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot header")
+	}
+	h := new(SnapshotHeader)
+	if err := json.Unmarshal(b, h); err != nil {
+		return nil, errors.Wrap(err, "invalid snapshot header")
+	}
+	if h.Version != snapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d", h.Version)
+	}
+	return h, nil
+}
+
+func (s *snapshotReader) readMarker() (byte, error) {
+	b, err := s.read(1)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read snapshot record")
+	}
+	return b[0], nil
+}
+
+func (s *snapshotReader) readEntry() ([]byte, []byte, error) {
+	ks, err := s.readUint(4)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := s.read(int(ks))
+	if err != nil {
+		return nil, nil, err
+	}
+	vs, err := s.readUint(4)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := s.read(int(vs))
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+func (s *snapshotReader) readFile(dir string) error {
+	ns, err := s.readUint(2)
+	if err != nil {
+		return err
+	}
+	nb, err := s.read(int(ns))
+	if err != nil {
+		return err
+	}
+	name := string(nb)
+	if !isSnapshotFile(name) {
+		return errors.Errorf("unexpected file '%s'", name)
+	}
+	size, err := s.readUint(8)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, s.in, int64(size)); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *snapshotReader) checkDigest() error {
+	expected := s.digest.Sum(nil)
+	actual := make([]byte, crypto.DigestSize)
+	if _, err := io.ReadFull(s.r, actual); err != nil {
+		return errors.Wrap(err, "failed to read snapshot digest")
+	}
+	if !bytes.Equal(expected, actual) {
+		return errors.New("snapshot is corrupted: invalid digest")
+	}
+	return nil
+}
+
+func isSnapshotFile(name string) bool {
+	for _, n := range snapshotFiles {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
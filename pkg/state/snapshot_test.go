@@ -0,0 +1,102 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/importer"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestSnapshotExportImport(t *testing.T) {
+	params := DefaultTestingStateParams()
+	params.BuildStateHashes = true
+	dataDir := t.TempDir()
+	manager, err := newStateManager(dataDir, false, params, settings.MainNetSettings)
+	require.NoError(t, err)
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	require.NoError(t, importer.ApplyFromFile(manager, blocksPath, 99, 1))
+	expected, err := manager.StateHashAtHeight(95)
+	require.NoError(t, err)
+	top, err := manager.StateHashAtHeight(100)
+	require.NoError(t, err)
+	require.NoError(t, manager.Close())
+
+	buf := new(bytes.Buffer)
+	header, err := ExportSnapshot(dataDir, params, settings.MainNetSettings, 95, buf)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(95), header.Height)
+	assert.Equal(t, uint64(100), header.StateHeight)
+	assert.Equal(t, expected, header.StateHash)
+	snapshot := buf.Bytes()
+
+	t.Run("import", func(t *testing.T) {
+		importDir := filepath.Join(t.TempDir(), "state")
+		h, err := ImportSnapshot(importDir, params, settings.MainNetSettings, bytes.NewReader(snapshot), expected.SumHash)
+		require.NoError(t, err)
+		assert.Equal(t, header, h)
+
+		imported, err := newStateManager(importDir, false, params, settings.MainNetSettings)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, imported.Close())
+		}()
+		height, err := imported.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(95), height)
+		sh, err := imported.StateHashAtHeight(95)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sh)
+		// Blocks after the snapshot can be applied to the imported state.
+		require.NoError(t, importer.ApplyFromFile(imported, blocksPath, 5, 96))
+		sh, err = imported.StateHashAtHeight(100)
+		require.NoError(t, err)
+		assert.Equal(t, top, sh)
+	})
+	t.Run("unexpected state hash", func(t *testing.T) {
+		importDir := t.TempDir()
+		_, err := ImportSnapshot(importDir, params, settings.MainNetSettings, bytes.NewReader(snapshot), crypto.Digest{})
+		assert.ErrorContains(t, err, "differs from expected")
+	})
+	t.Run("forged state hash", func(t *testing.T) {
+		// The header claims the expected state hash for another block
+		forged := *header
+		forged.Height = 100
+		forged.BlockID = top.BlockID
+		sh := *expected
+		sh.BlockID = top.BlockID
+		forged.StateHash = &sh
+		size := binary.BigEndian.Uint32(snapshot[len(snapshotMagic):])
+		records := snapshot[len(snapshotMagic)+4+int(size) : len(snapshot)-1-crypto.DigestSize]
+		buf := new(bytes.Buffer)
+		sw, err := newSnapshotWriter(buf)
+		require.NoError(t, err)
+		require.NoError(t, sw.writeHeader(&forged))
+		require.NoError(t, sw.write(records))
+		require.NoError(t, sw.close())
+
+		importDir := filepath.Join(t.TempDir(), "state")
+		_, err = ImportSnapshot(importDir, params, settings.MainNetSettings, bytes.NewReader(buf.Bytes()), expected.SumHash)
+		assert.ErrorContains(t, err, "recomputed state hash")
+		assert.NoDirExists(t, importDir)
+	})
+	t.Run("corrupted", func(t *testing.T) {
+		corrupted := append([]byte(nil), snapshot...)
+		corrupted[len(corrupted)-100] ^= 0xff
+		importDir := filepath.Join(t.TempDir(), "state")
+		_, err := ImportSnapshot(importDir, params, settings.MainNetSettings, bytes.NewReader(corrupted), expected.SumHash)
+		assert.EqualError(t, err, "snapshot is corrupted: invalid digest")
+		assert.NoDirExists(t, importDir)
+	})
+	t.Run("wrong scheme", func(t *testing.T) {
+		_, err := ImportSnapshot(t.TempDir(), params, settings.TestNetSettings, bytes.NewReader(snapshot), expected.SumHash)
+		assert.EqualError(t, err, "snapshot scheme 'W' differs from blockchain scheme 'T'")
+	})
+}
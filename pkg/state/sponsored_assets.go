@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 )
@@ -181,6 +182,23 @@ func (s *sponsoredAssets) assetCost(assetID proto.AssetID) (uint64, error) {
 	return record.assetCost, nil
 }
 
+// assetCostAtHeight returns sponsorship asset cost as it was right after applying block at the given height.
+// Zero cost is returned if the asset was not sponsored at that height.
+func (s *sponsoredAssets) assetCostAtHeight(assetID proto.AssetID, height proto.Height) (uint64, error) {
+	key := sponsorshipKey{assetID: assetID}
+	recordBytes, err := s.hs.entryDataAtHeight(key.bytes(), height)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist || (err == nil && recordBytes == nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var record sponsorshipRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return 0, errors.Errorf("failed to unmarshal sponsorship record: %v\n", err)
+	}
+	return record.assetCost, nil
+}
+
 func (s *sponsoredAssets) sponsoredAssetToWaves(assetID proto.AssetID, assetAmount uint64) (uint64, error) {
 	cost, err := s.newestAssetCost(assetID)
 	if err != nil {
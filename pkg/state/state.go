@@ -20,6 +20,7 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/errs"
 	"github.com/wavesplatform/gowaves/pkg/keyvalue"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/types"
@@ -465,6 +466,7 @@ func newStateManager(dataDir string, amend bool, params StateParams, settings *s
 	if err != nil {
 		return nil, wrapErr(Other, err)
 	}
+	appender.sc.engine = params.RideEngine
 	state.appender = appender
 	state.cv = consensus.NewValidator(state, settings, params.Time)
 
@@ -509,6 +511,9 @@ func newStateManager(dataDir string, amend bool, params StateParams, settings *s
 		return nil, wrapErr(Other, err)
 	}
 	state.checkProtobufActivation(h + 1)
+	if err := state.restoreTermRewards(); err != nil {
+		return nil, wrapErr(Other, errors.Wrap(err, "failed to restore block rewards of terms"))
+	}
 	return state, nil
 }
 
@@ -728,13 +733,7 @@ func (s *stateManager) NewestLeasingInfo(id crypto.Digest) (*proto.LeaseInfo, er
 	if err != nil {
 		return nil, err
 	}
-	leaseInfo := proto.LeaseInfo{
-		Sender:      leaseFromStore.Sender,
-		Recipient:   leaseFromStore.Recipient,
-		IsActive:    leaseFromStore.isActive(),
-		LeaseAmount: leaseFromStore.Amount,
-	}
-	return &leaseInfo, nil
+	return leaseFromStore.leaseInfo(), nil
 }
 
 func (s *stateManager) NewestScriptPKByAddr(addr proto.WavesAddress) (crypto.PublicKey, error) {
@@ -1647,6 +1646,23 @@ func (s *stateManager) ValidateNextTx(tx proto.Transaction, currentTimestamp, pa
 	return nil
 }
 
+func (s *stateManager) TraceNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64, v proto.BlockVersion) (*ride.Trace, error) {
+	trace := ride.NewTrace()
+	s.appender.sc.trace = trace
+	defer func() {
+		s.appender.sc.trace = nil
+	}()
+	return trace, s.appender.validateNextTx(tx, currentTimestamp, parentTimestamp, v, false)
+}
+
+func (s *stateManager) EvaluateScript(ev *ScriptEvaluation, currentTimestamp, parentTimestamp uint64, v proto.BlockVersion) (ride.Result, error) {
+	params, err := s.appender.utxAppendTxParams(currentTimestamp, parentTimestamp, v, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.appender.evaluateScript(ev, params)
+}
+
 func (s *stateManager) NewestAddrByAlias(alias proto.Alias) (proto.WavesAddress, error) {
 	addr, err := s.stor.aliases.newestAddrByAlias(alias.Alias)
 	if err != nil {
@@ -1949,6 +1965,14 @@ func (s *stateManager) TransactionByIDWithStatus(id []byte) (proto.Transaction,
 	return tx, failed, nil
 }
 
+func (s *stateManager) TransactionsFailed(ids [][]byte) ([]bool, error) {
+	failed, err := s.rw.transactionsFailed(ids)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return failed, nil
+}
+
 // NewestTransactionHeightByID returns transaction's height by given ID. This function must be used only in Ride evaluator.
 // WARNING! Function returns error if a transaction exists but failed.
 func (s *stateManager) NewestTransactionHeightByID(id []byte) (uint64, error) {
@@ -2323,6 +2347,300 @@ func (s *stateManager) IsActiveLeasing(leaseID crypto.Digest) (bool, error) {
 	return isActive, nil
 }
 
+func (s *stateManager) LeasingInfo(leaseID crypto.Digest) (*proto.LeaseInfo, error) {
+	l, err := s.stor.leases.leasingInfo(leaseID)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return l.leaseInfo(), nil
+}
+
+// checkHistoricalHeight checks that state at the given height can be reliably restored from histories.
+func (s *stateManager) MinRollbackHeight() (proto.Height, error) {
+	height, err := s.stateDB.getRollbackMinHeight()
+	if err != nil {
+		return 0, wrapErr(RetrievalError, err)
+	}
+	return height, nil
+}
+
+func (s *stateManager) checkHistoricalHeight(height proto.Height) error {
+	if err := s.checkRollbackHeight(height); err != nil {
+		return wrapErr(InvalidInputError, err)
+	}
+	return nil
+}
+
+func (s *stateManager) WavesBalanceProfileAtHeight(account proto.Recipient, height proto.Height) (*types.WavesBalanceProfile, error) {
+	if err := s.checkHistoricalHeight(height); err != nil {
+		return nil, err
+	}
+	addr, err := s.recipientToAddress(account)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	profile, err := s.stor.balances.wavesBalanceAtHeight(addr.ID(), height)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return &types.WavesBalanceProfile{
+		Balance:  profile.balance,
+		LeaseIn:  profile.leaseIn,
+		LeaseOut: profile.leaseOut,
+	}, nil
+}
+
+func (s *stateManager) AssetBalanceAtHeight(account proto.Recipient, assetID proto.AssetID, height proto.Height) (uint64, error) {
+	if err := s.checkHistoricalHeight(height); err != nil {
+		return 0, err
+	}
+	addr, err := s.recipientToAddress(account)
+	if err != nil {
+		return 0, wrapErr(RetrievalError, err)
+	}
+	balance, err := s.stor.balances.assetBalanceAtHeight(addr.ID(), assetID, height)
+	if err != nil {
+		return 0, wrapErr(RetrievalError, err)
+	}
+	return balance, nil
+}
+
+func (s *stateManager) RetrieveEntryAtHeight(account proto.Recipient, key string, height proto.Height) (proto.DataEntry, error) {
+	if err := s.checkHistoricalHeight(height); err != nil {
+		return nil, err
+	}
+	addr, err := s.recipientToAddress(account)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	entry, err := s.stor.accountsDataStor.retrieveEntryAtHeight(addr, key, height)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return entry, nil
+}
+
+func (s *stateManager) FullAssetInfoAtHeight(assetID proto.AssetID, height proto.Height) (*proto.FullAssetInfo, error) {
+	if err := s.checkHistoricalHeight(height); err != nil {
+		return nil, err
+	}
+	info, err := s.stor.assets.assetInfoAtHeight(assetID, height)
+	if err != nil {
+		if errors.Is(err, errs.UnknownAsset{}) {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	if !info.quantity.IsUint64() {
+		return nil, wrapErr(Other, errors.New("asset quantity overflows uint64"))
+	}
+	issuer, err := proto.NewAddressFromPublicKey(s.settings.AddressSchemeCharacter, info.issuer)
+	if err != nil {
+		return nil, wrapErr(Other, err)
+	}
+	assetCost, err := s.stor.sponsoredAssets.assetCostAtHeight(assetID, height)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	scriptBytes, err := s.stor.scriptsStorage.scriptBytesByAssetAtHeight(assetID, height)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	res := &proto.FullAssetInfo{
+		AssetInfo: proto.AssetInfo{
+			ID:              proto.ReconstructDigest(assetID, info.tail),
+			Quantity:        info.quantity.Uint64(),
+			Decimals:        info.decimals,
+			Issuer:          issuer,
+			IssuerPublicKey: info.issuer,
+			Reissuable:      info.reissuable,
+			Scripted:        !scriptBytes.IsEmpty(),
+			Sponsored:       assetCost != 0,
+			IssueHeight:     info.issueHeight,
+		},
+		Name:            info.name,
+		Description:     info.description,
+		SponsorshipCost: assetCost,
+	}
+	if !scriptBytes.IsEmpty() {
+		version, err := proto.VersionFromScriptBytes(scriptBytes)
+		if err != nil {
+			return nil, wrapErr(Other, err)
+		}
+		res.ScriptInfo = proto.ScriptInfo{Version: version, Bytes: scriptBytes}
+	}
+	return res, nil
+}
+
+func (s *stateManager) LeasingInfoAtHeight(leaseID crypto.Digest, height proto.Height) (*proto.LeaseInfo, error) {
+	if err := s.checkHistoricalHeight(height); err != nil {
+		return nil, err
+	}
+	l, err := s.stor.leases.leasingInfoAtHeight(leaseID, height)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return l.leaseInfo(), nil
+}
+
+// RewardsInfoAtHeight is available for any height of the blockchain. Outside the rollback window the votes
+// are counted from the headers of blocks, because the history of votes is cut.
+func (s *stateManager) RewardsInfoAtHeight(height proto.Height) (*proto.RewardsInfo, error) {
+	maxHeight, err := s.Height()
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	if height < 1 || height > maxHeight {
+		return nil, wrapErr(InvalidInputError, errors.Errorf("invalid height; valid range is: [1, %d]", maxHeight))
+	}
+	feature := int16(settings.BlockReward)
+	if !s.stor.features.isActivatedAtHeight(feature, height) {
+		return nil, wrapErr(NotFoundError, errors.Errorf("block reward feature is not activated at height %d", height))
+	}
+	activation, err := s.stor.features.activationHeight(feature)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	reward, err := s.stor.monetaryPolicy.rewardAtHeight(height, activation)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	start, end := blockRewardTermBoundaries(height, activation, s.settings.FunctionalitySettings)
+	votes, err := s.rewardVotesAtHeight(height, start, reward)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	issued, err := s.stor.monetaryPolicy.issuedRewards(height, activation)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	var genesisAmount uint64
+	for _, tx := range s.genesis.Transactions {
+		if g, ok := tx.(*proto.Genesis); ok {
+			genesisAmount += g.Amount
+		}
+	}
+	return &proto.RewardsInfo{
+		Height:              height,
+		TotalWavesAmount:    genesisAmount + issued,
+		CurrentReward:       reward,
+		MinIncrement:        s.settings.BlockRewardIncrement,
+		Term:                s.settings.BlockRewardTerm,
+		NextCheck:           end,
+		VotingIntervalStart: start,
+		VotingInterval:      s.settings.BlockRewardVotingPeriod,
+		VotingThreshold:     s.settings.BlockRewardVotingPeriod/2 + 1,
+		IncreaseVotes:       votes.increase,
+		DecreaseVotes:       votes.decrease,
+	}, nil
+}
+
+func (s *stateManager) rewardVotesAtHeight(height, votingStart, reward uint64) (rewardVotesRecord, error) {
+	minRollbackHeight, err := s.stateDB.getRollbackMinHeight()
+	if err != nil {
+		return rewardVotesRecord{}, err
+	}
+	if height >= minRollbackHeight {
+		return s.stor.monetaryPolicy.votesAtHeight(height)
+	}
+	if height < votingStart {
+		return rewardVotesRecord{}, nil
+	}
+	return s.countRewardVotes(votingStart, height, reward)
+}
+
+// countRewardVotes counts the votes for the reward change in the headers of blocks in the given range of heights.
+func (s *stateManager) countRewardVotes(start, end, reward uint64) (rewardVotesRecord, error) {
+	var votes rewardVotesRecord
+	for h := start; h <= end; h++ {
+		header, err := s.HeaderByHeight(h)
+		if err != nil {
+			return rewardVotesRecord{}, err
+		}
+		if header.RewardVote < 0 {
+			continue
+		}
+		switch target := uint64(header.RewardVote); {
+		case target > reward:
+			votes.increase++
+		case target < reward:
+			votes.decrease++
+		}
+	}
+	return votes, nil
+}
+
+// restoreTermRewards recalculates the rewards of terms from the votes in the headers of blocks if the state was
+// created before the rewards of terms were stored.
+func (s *stateManager) restoreTermRewards() error {
+	height, err := s.Height()
+	if err != nil {
+		return err
+	}
+	feature := int16(settings.BlockReward)
+	if !s.stor.features.newestIsActivatedAtHeight(feature, height) {
+		return nil
+	}
+	activation, err := s.stor.features.newestActivationHeight(feature)
+	if err != nil {
+		return err
+	}
+	first := activation + s.settings.BlockRewardTerm - 1
+	if height <= first {
+		return nil
+	}
+	restored, err := s.stor.monetaryPolicy.hasTermReward(first)
+	if err != nil {
+		return err
+	}
+	if restored {
+		return nil
+	}
+	zap.S().Info("Restoring block rewards of terms from the headers of blocks, it may take a while")
+	reward := s.settings.InitialBlockReward
+	threshold := uint32(s.settings.BlockRewardVotingPeriod)/2 + 1
+	for end := first; end < height; end += s.settings.BlockRewardTerm {
+		votes, err := s.countRewardVotes(end+1-s.settings.BlockRewardVotingPeriod, end, reward)
+		if err != nil {
+			return err
+		}
+		switch {
+		case votes.increase >= threshold:
+			reward += s.settings.BlockRewardIncrement
+		case votes.decrease >= threshold:
+			reward -= s.settings.BlockRewardIncrement
+		}
+		blockID, err := s.HeightToBlockID(end)
+		if err != nil {
+			return err
+		}
+		if err := s.stor.monetaryPolicy.saveTermReward(end, reward, blockID); err != nil {
+			return err
+		}
+	}
+	current, err := s.stor.monetaryPolicy.reward()
+	if err != nil {
+		return err
+	}
+	if current != reward {
+		return errors.Errorf("restored block reward %d differs from the current reward %d", reward, current)
+	}
+	if err := s.flush(); err != nil {
+		return err
+	}
+	s.reset()
+	return nil
+}
+
 func (s *stateManager) InvokeResultByID(invokeID crypto.Digest) (*proto.ScriptResult, error) {
 	hasData, err := s.storesExtendedApiData()
 	if err != nil {
@@ -7,8 +7,10 @@ import (
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/types"
 )
 
 type ThreadSafeReadWrapper struct {
@@ -242,6 +244,12 @@ func (a *ThreadSafeReadWrapper) TransactionByIDWithStatus(id []byte) (proto.Tran
 	return a.s.TransactionByIDWithStatus(id)
 }
 
+func (a *ThreadSafeReadWrapper) TransactionsFailed(ids [][]byte) ([]bool, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.TransactionsFailed(ids)
+}
+
 func (a *ThreadSafeReadWrapper) TransactionHeightByID(id []byte) (uint64, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -326,6 +334,54 @@ func (a *ThreadSafeReadWrapper) IsActiveLeasing(leaseID crypto.Digest) (bool, er
 	return a.s.IsActiveLeasing(leaseID)
 }
 
+func (a *ThreadSafeReadWrapper) WavesBalanceProfileAtHeight(account proto.Recipient, height proto.Height) (*types.WavesBalanceProfile, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.WavesBalanceProfileAtHeight(account, height)
+}
+
+func (a *ThreadSafeReadWrapper) AssetBalanceAtHeight(account proto.Recipient, assetID proto.AssetID, height proto.Height) (uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AssetBalanceAtHeight(account, assetID, height)
+}
+
+func (a *ThreadSafeReadWrapper) RetrieveEntryAtHeight(account proto.Recipient, key string, height proto.Height) (proto.DataEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.RetrieveEntryAtHeight(account, key, height)
+}
+
+func (a *ThreadSafeReadWrapper) FullAssetInfoAtHeight(assetID proto.AssetID, height proto.Height) (*proto.FullAssetInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.FullAssetInfoAtHeight(assetID, height)
+}
+
+func (a *ThreadSafeReadWrapper) LeasingInfo(leaseID crypto.Digest) (*proto.LeaseInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.LeasingInfo(leaseID)
+}
+
+func (a *ThreadSafeReadWrapper) MinRollbackHeight() (proto.Height, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.MinRollbackHeight()
+}
+
+func (a *ThreadSafeReadWrapper) LeasingInfoAtHeight(leaseID crypto.Digest, height proto.Height) (*proto.LeaseInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.LeasingInfoAtHeight(leaseID, height)
+}
+
+func (a *ThreadSafeReadWrapper) RewardsInfoAtHeight(height proto.Height) (*proto.RewardsInfo, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.RewardsInfoAtHeight(height)
+}
+
 func (a *ThreadSafeReadWrapper) InvokeResultByID(invokeID crypto.Digest) (*proto.ScriptResult, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -379,6 +435,14 @@ func (a *ThreadSafeWriteWrapper) ValidateNextTx(_ proto.Transaction, _, _ uint64
 	panic("Invalid ValidateNextTx usage on thread safe wrapper. Should call TxValidation")
 }
 
+func (a *ThreadSafeWriteWrapper) TraceNextTx(_ proto.Transaction, _, _ uint64, _ proto.BlockVersion) (*ride.Trace, error) {
+	panic("Invalid TraceNextTx usage on thread safe wrapper. Should call TxValidation")
+}
+
+func (a *ThreadSafeWriteWrapper) EvaluateScript(_ *ScriptEvaluation, _, _ uint64, _ proto.BlockVersion) (ride.Result, error) {
+	panic("Invalid EvaluateScript usage on thread safe wrapper. Should call TxValidation")
+}
+
 func (a *ThreadSafeWriteWrapper) ResetValidationList() {
 	panic("invalid ResetValidationList usage")
 }
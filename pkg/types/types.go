@@ -18,13 +18,27 @@ type Handler interface {
 	Handle()
 }
 
+// TransactionHandler is an abstract function that called when a new transaction is received.
+type TransactionHandler interface {
+	HandleTransaction(tx proto.Transaction)
+}
+
 // UtxPool storage interface
 type UtxPool interface {
 	Add(t proto.Transaction) error
 	AddWithBytes(t proto.Transaction, b []byte) error
 	Exists(t proto.Transaction) bool
 	Pop() *TransactionWithBytes
+	// ReleasePopped removes for good the popped transactions which were not added back to the pool.
+	ReleasePopped()
 	AllTransactions() []*TransactionWithBytes
+	// Transactions returns the transactions of the pool which match the filter ordered by priority.
+	Transactions(filter *UtxFilter) []*TransactionWithBytes
+	TransactionByID(id crypto.Digest) (*TransactionWithBytes, bool)
+	// Subscribe returns the subscription to the pool changes which match the filter along with the snapshot
+	// of matching transactions taken at the moment of subscription.
+	Subscribe(filter *UtxFilter) (UtxSubscription, []UtxEvent)
+	Unsubscribe(sub UtxSubscription)
 	Count() int
 	ExistsByID(id []byte) bool
 }
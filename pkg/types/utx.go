@@ -0,0 +1,101 @@
+package types
+
+import (
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type UtxEventType byte
+
+const (
+	UtxTransactionAdded UtxEventType = iota
+	UtxTransactionRemoved
+)
+
+// UtxEvent describes the change of the UTX pool content.
+type UtxEvent struct {
+	Type UtxEventType
+	ID   crypto.Digest
+	Tx   *TransactionWithBytes
+	// Fee is the fee of transaction in WAVES.
+	Fee uint64
+}
+
+// UtxSubscription receives events of the UTX pool which match the filter until it's closed.
+type UtxSubscription interface {
+	// Events returns the channel of pool events.
+	Events() <-chan UtxEvent
+	// Overflow returns the channel which is closed when the subscription is dropped because of the full buffer.
+	Overflow() <-chan struct{}
+}
+
+// UtxFilter selects unconfirmed transactions, conditions which are not set are not checked.
+type UtxFilter struct {
+	Sender    *proto.WavesAddress
+	Recipient *proto.Recipient
+	IDs       []crypto.Digest
+	Types     []proto.TransactionType
+	// MinFee is the minimal fee in WAVES, fees in sponsored assets are converted by the sponsorship rate.
+	MinFee uint64
+}
+
+// Match checks the transaction with the given ID and fee in WAVES against the filter.
+func (f *UtxFilter) Match(scheme proto.Scheme, id crypto.Digest, t proto.Transaction, fee uint64) bool {
+	if f == nil {
+		return true
+	}
+	if fee < f.MinFee {
+		return false
+	}
+	if len(f.IDs) > 0 && !containsID(f.IDs, id) {
+		return false
+	}
+	if len(f.Types) > 0 && !containsType(f.Types, t.GetTypeInfo().Type) {
+		return false
+	}
+	if f.Sender != nil {
+		sender, err := t.GetSender(scheme)
+		if err != nil || !f.Sender.Equal(sender) {
+			return false
+		}
+	}
+	if f.Recipient != nil && !hasRecipient(t, *f.Recipient) {
+		return false
+	}
+	return true
+}
+
+func containsID(ids []crypto.Digest, id crypto.Digest) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(types []proto.TransactionType, t proto.TransactionType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRecipient(t proto.Transaction, r proto.Recipient) bool {
+	switch tx := t.(type) {
+	case *proto.TransferWithSig:
+		return tx.Recipient.Eq(r)
+	case *proto.TransferWithProofs:
+		return tx.Recipient.Eq(r)
+	case *proto.LeaseWithSig:
+		return tx.Recipient.Eq(r)
+	case *proto.LeaseWithProofs:
+		return tx.Recipient.Eq(r)
+	case *proto.MassTransferWithProofs:
+		return tx.HasRecipient(r)
+	default:
+		return false
+	}
+}